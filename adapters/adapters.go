@@ -2,6 +2,8 @@
 // sub-packages.
 package adapters
 
+import "time"
+
 // Adapter interface is the minimal implementation for an adapter.
 type Adapter interface {
 	// DriverName must return the driver name to be used with sql.Open()
@@ -67,10 +69,167 @@ type SQLPart struct {
 	Arguments []interface{}
 }
 
+// PaginationStyle is the syntax used to limit the count of rows of a select
+// statement, and to skip its first rows.
+type PaginationStyle int
+
+const (
+	// LimitOffset uses 'LIMIT ? OFFSET ?' (the default).
+	LimitOffset PaginationStyle = iota
+	// OffsetFetch uses the standard 'OFFSET ? ROWS FETCH NEXT ? ROWS ONLY',
+	// the OFFSET clause and an ORDER BY clause being always written.
+	OffsetFetch
+	// Top uses 'SELECT TOP (?) ...', the rows can't be skipped.
+	Top
+	// RowNum wraps the query with 'SELECT * FROM (...) WHERE ROWNUM <= ?',
+	// the rows can't be skipped.
+	RowNum
+	// OffsetLimit uses 'OFFSET ? LIMIT ?'.
+	OffsetLimit
+	// OffsetFetchUnordered uses 'OFFSET ? ROWS FETCH NEXT ? ROWS ONLY' like
+	// OffsetFetch, without adding an ORDER BY clause.
+	OffsetFetchUnordered
+)
+
+// Paginator is an interface wrapping the optional PaginationStyle method.
+//
+// PaginationStyle returns the syntax limiting and skipping the rows of the
+// select statements. It takes precedence over the LimitBuilder,
+// OffsetBuilder and LimitOffsetOrderer interfaces.
+type Paginator interface {
+	PaginationStyle() PaginationStyle
+}
+
+// ReadOnlyChecker is an interface wrapping the optional IsReadOnly method.
+//
+// IsReadOnly returns true if the database is only queried, like a query
+// engine federating others sources. The statements changing data are then
+// rejected before being executed, like in the read only mode of godb.
+type ReadOnlyChecker interface {
+	IsReadOnly() bool
+}
+
+// LastInsertIDChecker is an interface wrapping the optional
+// SupportsLastInsertID method.
+//
+// SupportsLastInsertID returns false if the driver does not give the id of
+// an inserted row (sql.Result LastInsertId), and if the adapter has no
+// RETURNING clause either : the auto fields of the inserted structs are then
+// left unchanged, without error.
+type LastInsertIDChecker interface {
+	SupportsLastInsertID() bool
+}
+
+// LargeObjectChecker is an interface wrapping the optional
+// SupportsLargeObjects method.
+//
+// SupportsLargeObjects returns true if the database stores large objects
+// with the server functions of PostgreSQL (lo_create, lo_open, loread,
+// lowrite, lo_lseek64, lo_truncate64, lo_close and lo_unlink).
+type LargeObjectChecker interface {
+	SupportsLargeObjects() bool
+}
+
+// ApplicationNamer is an interface wrapping the optional
+// DataSourceWithApplicationName method.
+//
+// DataSourceWithApplicationName returns the given data source with a
+// parameter naming the application in the sessions shown by the server.
+type ApplicationNamer interface {
+	DataSourceWithApplicationName(dataSourceName string, name string) string
+}
+
+// Qualifier is an interface wrapping the optional QualifyKeyword method.
+//
+// QualifyKeyword returns the keyword of the clause filtering the rows once
+// the window functions are computed (QUALIFY). Without it, the select
+// statements having such a filter are wrapped by a subquery.
+type Qualifier interface {
+	QualifyKeyword() string
+}
+
+// ValuesListBuilder is an interface wrapping the optional BuildValuesList
+// method.
+//
+// BuildValuesList returns a subquery (with its parenthesis) giving the
+// given rows (groups of placeholders like "(?, ?)") with the given columns
+// (already quoted), using a VALUES list. Without it, the rows are selected
+// one by one and joined with UNION ALL.
+type ValuesListBuilder interface {
+	BuildValuesList(rows []string, columns []string) string
+}
+
+// TableSampleBuilder is an interface wrapping the optional BuildTableSample
+// method.
+//
+// BuildTableSample returns the clause reading a sample of a table with the
+// given method (SYSTEM or BERNOULLI), having a placeholder for the
+// percentage of rows. It returns an empty string if the method is not
+// supported.
+type TableSampleBuilder interface {
+	BuildTableSample(method string) string
+}
+
+// FunctionBuilder is an interface wrapping the optional BuildFunction method.
+//
+// BuildFunction returns the call of the named function (in upper case) with
+// the given arguments (their SQL), for the functions having a specific
+// name or syntax with the database, like NOW or CONCAT. It returns false for
+// the others, called as is.
+type FunctionBuilder interface {
+	BuildFunction(name string, args []string) (string, bool)
+}
+
+// DateArithmeticBuilder is an interface wrapping the optional BuildDateAdd
+// and BuildDateTrunc methods.
+//
+// BuildDateAdd returns the expression adding the given amount (a
+// placeholder) of units to the given date column (already quoted).
+// BuildDateTrunc returns the expression truncating the given date column
+// (already quoted) to the given unit.
+// The unit is second, minute, hour, day, month or year.
+type DateArithmeticBuilder interface {
+	BuildDateAdd(column string, amount string, unit string) string
+	BuildDateTrunc(unit string, column string) string
+}
+
+// StringAggBuilder is an interface wrapping the optional BuildStringAgg
+// method.
+//
+// BuildStringAgg returns the aggregate function concatenating the values of
+// the given column (already quoted), separated by the given separator (a
+// string literal), and ordered by the given expression if not empty.
+type StringAggBuilder interface {
+	BuildStringAgg(column string, separator string, orderBy string) string
+}
+
+// ArrayAggBuilder is an interface wrapping the optional BuildArrayAgg
+// method.
+//
+// BuildArrayAgg returns the aggregate function collecting the values of the
+// given column (already quoted) into an array, ordered by the given
+// expression if not empty.
+type ArrayAggBuilder interface {
+	BuildArrayAgg(column string, orderBy string) string
+}
+
+// StatisticalAggregateBuilder is an interface wrapping the optional
+// BuildStatisticalAggregate method.
+//
+// BuildStatisticalAggregate returns the named aggregate function of the
+// given column (already quoted) : PERCENTILE_CONT and PERCENTILE_DISC,
+// with a placeholder for the fraction, STDDEV and VARIANCE of a sample. It
+// returns an empty string if the aggregate is not supported.
+type StatisticalAggregateBuilder interface {
+	BuildStatisticalAggregate(name string, column string, fraction string) string
+}
+
 // LimitBuilder is an interface wrapping the optional BuildLimit method.
 //
 // BuildLimit get an integer and returns a string containing a LIMIT sql clause
 // or its equivalent for the adapter, and an array of sql arguments.
+//
+// Deprecated: use Paginator.
 type LimitBuilder interface {
 	BuildLimit(int) *SQLPart
 }
@@ -79,6 +238,8 @@ type LimitBuilder interface {
 //
 // BuildOffset get an integer and returns a string containing an OFFSET sql
 // clause or its equivalent for the adapter, and an array of sql arguments.
+//
+// Deprecated: use Paginator.
 type OffsetBuilder interface {
 	BuildOffset(int) *SQLPart
 }
@@ -88,6 +249,241 @@ type OffsetBuilder interface {
 //
 // The IsOffsetFirst returns true is the OFFSET clause has to precede the
 // LIMIT clause. By default the LIMIT is before the OFFSET.
+//
+// Deprecated: use Paginator.
 type LimitOffsetOrderer interface {
 	IsOffsetFirst() bool
 }
+
+// ProcedureCallBuilder is an interface wrapping the optional
+// BuildProcedureCall method.
+//
+// BuildProcedureCall gets a procedure name (already quoted) and the call
+// arguments, and returns the sql statement calling the procedure, using '?'
+// as placeholder. By default the statement is 'CALL name(?, ?, ...)'.
+type ProcedureCallBuilder interface {
+	BuildProcedureCall(string, []interface{}) string
+}
+
+// WithinDistanceBuilder is an interface wrapping the optional
+// BuildWithinDistance method.
+//
+// BuildWithinDistance gets a spatial column name (already quoted) and returns
+// a predicate true if the column is within a given distance in meters of a
+// point. The predicate uses three '?' placeholders : the point longitude, its
+// latitude, and the distance.
+type WithinDistanceBuilder interface {
+	BuildWithinDistance(string) string
+}
+
+// JSONPathBuilder is an interface wrapping the optional BuildJSONPath method.
+//
+// BuildJSONPath gets a JSON column name (already quoted) and a checked path
+// like '$.customer.id' or '$.items[0]', and returns an expression extracting
+// the value at the given path as a scalar (text for PostgreSQL).
+type JSONPathBuilder interface {
+	BuildJSONPath(string, string) string
+}
+
+// ILikeBuilder is an interface wrapping the optional BuildILike method.
+//
+// BuildILike gets a column name (already quoted) and returns a case
+// insensitive LIKE predicate, using a single '?' placeholder for the pattern.
+// By default the predicate is 'LOWER(column) LIKE LOWER(?)'.
+type ILikeBuilder interface {
+	BuildILike(string) string
+}
+
+// UnaccentILikeBuilder is an interface wrapping the optional
+// BuildUnaccentILike method.
+//
+// BuildUnaccentILike is like BuildILike, but the predicate is also accent
+// insensitive. There is no default.
+type UnaccentILikeBuilder interface {
+	BuildUnaccentILike(string) string
+}
+
+// RowLockBuilder is an interface wrapping the optional BuildRowLock method.
+//
+// BuildRowLock returns the clause added at the end of a SELECT statement to
+// lock the selected rows until the end of the transaction. If skipLocked is
+// true the rows already locked by others transactions are skipped. There is
+// no default.
+type RowLockBuilder interface {
+	BuildRowLock(skipLocked bool) string
+}
+
+// NamedLockBuilder is an interface wrapping the optional BuildTryLock and
+// BuildUnlock methods, for the locks shared by all the sessions (advisory
+// locks).
+//
+// BuildTryLock returns a statement trying to acquire the lock without
+// waiting, using a single '?' placeholder for the lock name, and returning
+// a single row with a single boolean or integer column, true or 1 if the
+// lock is acquired. The lock is owned by the session.
+//
+// BuildUnlock returns a statement releasing the lock of the session, using a
+// single '?' placeholder for the lock name.
+type NamedLockBuilder interface {
+	BuildTryLock() string
+	BuildUnlock() string
+}
+
+// ReplicationLagQuerier is an interface wrapping the optional
+// ReplicationLagQuery method.
+//
+// ReplicationLagQuery returns a query executed on a read replica, returning a
+// single row with a single numeric column : the replication lag in seconds
+// (0 on a primary).
+type ReplicationLagQuerier interface {
+	ReplicationLagQuery() string
+}
+
+// CountEstimator is an interface wrapping the optional methods estimating
+// the count of rows of a query with the statistics of the planner.
+//
+// BuildTableRowsEstimate returns a query giving the estimated count of rows
+// of a table, as single row and column. Its only argument is the unquoted
+// name of the table. The query gives no row, NULL or a negative count if
+// there is no estimate.
+//
+// BuildPlanRowsEstimate returns a query giving the plan of the given SELECT
+// statement (having the same arguments), or a blank string if the plans
+// aren't used. ParsePlanRows returns the estimated count of rows of the
+// plan, given by the first column of the first row.
+type CountEstimator interface {
+	BuildTableRowsEstimate() string
+	BuildPlanRowsEstimate(query string) string
+	ParsePlanRows(plan string) (int64, error)
+}
+
+// TimeoutHinter is an interface wrapping the optional HintTimeout method.
+//
+// HintTimeout returns the query with a hint limiting its execution time on
+// the server side, or the query unchanged if it can't be limited this way.
+// It's used when the cancellation of the context does not stop the query on
+// the server.
+type TimeoutHinter interface {
+	HintTimeout(query string, timeout time.Duration) string
+}
+
+// TwoPhaseCommitBuilder is an interface wrapping the optional methods of the
+// two-phase commit, implemented by the PostgreSQL adapter.
+//
+// BuildPrepareTransaction returns the statement preparing the current
+// transaction with the given global identifier. Once prepared the
+// transaction is no longer bound to the session.
+//
+// BuildCommitPrepared and BuildRollbackPrepared return the statements
+// committing or rolling back a prepared transaction, executed outside a
+// transaction.
+//
+// BuildListPrepared returns a query listing the identifiers of the prepared
+// transactions of the current database.
+type TwoPhaseCommitBuilder interface {
+	BuildPrepareTransaction(id string) string
+	BuildCommitPrepared(id string) string
+	BuildRollbackPrepared(id string) string
+	BuildListPrepared() string
+}
+
+// SavepointBuilder is an interface wrapping the optional methods of the
+// savepoints, for the databases without the standard statements (SAVEPOINT,
+// ROLLBACK TO SAVEPOINT and RELEASE SAVEPOINT).
+//
+// BuildSavepoint, BuildRollbackToSavepoint and BuildReleaseSavepoint return
+// the statements creating a savepoint having the given name (an identifier
+// without quotes needed), rolling back to it, and releasing it. BuildReleaseSavepoint
+// returns a blank string if the savepoints aren't released.
+type SavepointBuilder interface {
+	BuildSavepoint(name string) string
+	BuildRollbackToSavepoint(name string) string
+	BuildReleaseSavepoint(name string) string
+}
+
+// RecursiveQueryBuilder is an interface wrapping the optional RecursiveWith
+// method.
+//
+// RecursiveWith returns the keyword starting a recursive common table
+// expression (WITH RECURSIVE for most databases).
+type RecursiveQueryBuilder interface {
+	RecursiveWith() string
+}
+
+// MaterializedViewRefresher is an interface wrapping the optional
+// BuildRefreshMaterializedView method.
+//
+// BuildRefreshMaterializedView returns the statement refreshing the given
+// materialized view (its name is already quoted). A concurrent refresh
+// doesn't lock the reads of the view.
+type MaterializedViewRefresher interface {
+	BuildRefreshMaterializedView(name string, concurrently bool) string
+}
+
+// TempTableBuilder is an interface wrapping the optional methods creating
+// and dropping temporary tables.
+//
+// BuildCreateTempTable returns the statement creating an empty temporary
+// table having the columns of the source table (both names are already
+// quoted). BuildDropTempTable returns the statement dropping it, it must not
+// end the current transaction.
+type TempTableBuilder interface {
+	BuildCreateTempTable(name string, source string) string
+	BuildDropTempTable(name string) string
+}
+
+// PartitionBuilder is an interface wrapping the optional methods managing
+// the range partitions of a table.
+//
+// BuildCreatePartition returns the statement creating (if it does not exist)
+// the partition of the parent table holding the rows from the lower bound
+// (inclusive) to the upper bound (exclusive). BuildAttachPartition and
+// BuildDetachPartition return the statements attaching an existing table as
+// a partition, and detaching a partition (which remains as a table). The
+// names are already quoted.
+//
+// BuildListPartitions returns a query listing the partitions of the parent
+// table given as single argument, with two columns : name and bound, the
+// latter being parsed by ParsePartitionBound. ParsePartitionBound returns
+// false if the bound is not a range of times.
+type PartitionBuilder interface {
+	BuildCreatePartition(name string, parent string, from time.Time, to time.Time) string
+	BuildAttachPartition(name string, parent string, from time.Time, to time.Time) string
+	BuildDetachPartition(name string, parent string) string
+	BuildListPartitions() string
+	ParsePartitionBound(bound string) (time.Time, time.Time, bool)
+}
+
+// SequenceBuilder is an interface wrapping the optional methods of the
+// native sequences.
+//
+// BuildCreateSequence returns the statement creating the sequence starting
+// at 1, if it does not exist. BuildNextSequenceValue returns the query giving
+// the next value of the sequence, as single row and column. The names are
+// already quoted.
+type SequenceBuilder interface {
+	BuildCreateSequence(name string) string
+	BuildNextSequenceValue(name string) string
+}
+
+// ConflictIgnorer is an interface wrapping the optional
+// IgnoreConflictsClause method.
+//
+// IgnoreConflictsClause returns the clause written after the values of an
+// INSERT statement, skipping the rows conflicting with a unique constraint
+// instead of failing.
+type ConflictIgnorer interface {
+	IgnoreConflictsClause() string
+}
+
+// LiteralFormatter is an interface wrapping the optional FormatLiteral
+// method.
+//
+// FormatLiteral gets a value of one of the driver.Value types (nil, int64,
+// float64, bool, []byte, string or time.Time) and returns it as a SQL
+// literal, or false to use the default format. By default the strings are
+// quoted with their single quotes doubled, the booleans are 1 and 0, the
+// bytes are X'hex' and the times are quoted with their offset.
+type LiteralFormatter interface {
+	FormatLiteral(value interface{}) (string, bool)
+}
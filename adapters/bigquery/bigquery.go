@@ -0,0 +1,129 @@
+package bigquery
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// BigQuery is the adapter of Google BigQuery, used to mirror the models into
+// a warehouse with the struct tools and to query them with the select
+// builder. The placeholders are named (@p1, @p2, ...), and BigQuery has
+// neither RETURNING clause nor last inserted id : the auto fields of the
+// inserted structs are left unchanged. A database/sql driver of BigQuery
+// registered as "bigquery" has to be imported by the application.
+type BigQuery struct{}
+
+var Adapter = BigQuery{}
+
+func init() {
+	adapters.Register("bigquery", func() adapters.Adapter { return Adapter })
+}
+
+func (BigQuery) DriverName() string {
+	return "bigquery"
+}
+
+func (BigQuery) Quote(identifier string) string {
+	return "`" + identifier + "`"
+}
+
+// ReplacePlaceholders replaces the placeholders with named parameters (@p1,
+// @p2, ...).
+func (BigQuery) ReplacePlaceholders(originalPlaceholder string, sql string) string {
+	sqlBuffer := bytes.NewBuffer(make([]byte, 0, len(sql)))
+	count := 1
+	for {
+		pp := strings.Index(sql, originalPlaceholder)
+		if pp == -1 {
+			break
+		}
+		sqlBuffer.WriteString(sql[:pp])
+		sqlBuffer.WriteString("@p")
+		sqlBuffer.WriteString(strconv.Itoa(count))
+		count++
+		sql = sql[pp+len(originalPlaceholder):]
+	}
+	sqlBuffer.WriteString(sql)
+	return sqlBuffer.String()
+}
+
+// ParseError returns the error as is, BigQuery has no constraint enforced.
+func (BigQuery) ParseError(err error) error {
+	return err
+}
+
+// SupportsLastInsertID is always false, BigQuery does not generate ids.
+func (BigQuery) SupportsLastInsertID() bool {
+	return false
+}
+
+// QualifyKeyword uses the native QUALIFY clause.
+func (BigQuery) QualifyKeyword() string {
+	return "QUALIFY"
+}
+
+// FormatLiteral escapes the quotes and backslashes of the strings with a
+// backslash, and writes the booleans, bytes and timestamps of BigQuery.
+func (BigQuery) FormatLiteral(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v)
+		return "'" + escaped + "'", true
+	case bool:
+		if v {
+			return "TRUE", true
+		}
+		return "FALSE", true
+	case []byte:
+		return "FROM_HEX('" + hex.EncodeToString(v) + "')", true
+	case time.Time:
+		return "TIMESTAMP '" + v.Format("2006-01-02 15:04:05.999999-07:00") + "'", true
+	}
+	return "", false
+}
+
+// BuildFunction writes NOW as CURRENT_TIMESTAMP().
+func (BigQuery) BuildFunction(name string, args []string) (string, bool) {
+	if name == "NOW" && len(args) == 0 {
+		return "CURRENT_TIMESTAMP()", true
+	}
+	return "", false
+}
+
+// BuildStringAgg uses STRING_AGG.
+func (BigQuery) BuildStringAgg(column string, separator string, orderBy string) string {
+	if orderBy != "" {
+		separator += " ORDER BY " + orderBy
+	}
+	return "STRING_AGG(" + column + ", " + separator + ")"
+}
+
+// BuildArrayAgg uses ARRAY_AGG.
+func (BigQuery) BuildArrayAgg(column string, orderBy string) string {
+	if orderBy != "" {
+		column += " ORDER BY " + orderBy
+	}
+	return "ARRAY_AGG(" + column + ")"
+}
+
+// BuildStatisticalAggregate uses STDDEV_SAMP and VAR_SAMP, the percentiles
+// being analytic functions only in BigQuery.
+func (BigQuery) BuildStatisticalAggregate(name string, column string, fraction string) string {
+	switch name {
+	case "STDDEV":
+		return "STDDEV_SAMP(" + column + ")"
+	case "VARIANCE":
+		return "VAR_SAMP(" + column + ")"
+	}
+	return ""
+}
+
+// RecursiveWith uses WITH RECURSIVE.
+func (BigQuery) RecursiveWith() string {
+	return "WITH RECURSIVE"
+}
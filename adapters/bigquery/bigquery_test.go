@@ -0,0 +1,41 @@
+package bigquery
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReplacePlaceholders(t *testing.T) {
+	Convey("ReplacePlaceholders replaces the placeholders with named parameters", t, func() {
+		sql := "SELECT * FROM `books` WHERE `id` > ? AND `author` IN (?, ?)"
+		So(Adapter.ReplacePlaceholders("?", sql), ShouldEqual, "SELECT * FROM `books` WHERE `id` > @p1 AND `author` IN (@p2, @p3)")
+	})
+}
+
+func TestFormatLiteral(t *testing.T) {
+	Convey("FormatLiteral writes the literals of BigQuery", t, func() {
+		literal, ok := Adapter.FormatLiteral(`it's a \ path`)
+		So(ok, ShouldBeTrue)
+		So(literal, ShouldEqual, `'it\'s a \\ path'`)
+		literal, ok = Adapter.FormatLiteral(true)
+		So(ok, ShouldBeTrue)
+		So(literal, ShouldEqual, "TRUE")
+		literal, ok = Adapter.FormatLiteral([]byte{0x01, 0xab})
+		So(ok, ShouldBeTrue)
+		So(literal, ShouldEqual, "FROM_HEX('01ab')")
+		literal, ok = Adapter.FormatLiteral(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))
+		So(ok, ShouldBeTrue)
+		So(literal, ShouldEqual, "TIMESTAMP '2021-03-04 05:06:07+00:00'")
+		_, ok = Adapter.FormatLiteral(12)
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func TestBuildStringAgg(t *testing.T) {
+	Convey("BuildStringAgg uses STRING_AGG, with an optional order", t, func() {
+		So(Adapter.BuildStringAgg("`title`", "','", ""), ShouldEqual, "STRING_AGG(`title`, ',')")
+		So(Adapter.BuildStringAgg("`title`", "','", "id"), ShouldEqual, "STRING_AGG(`title`, ',' ORDER BY id)")
+	})
+}
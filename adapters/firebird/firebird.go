@@ -0,0 +1,93 @@
+package firebird
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/dberror"
+)
+
+// Firebird is the adapter of Firebird (3.0 or later). The driver
+// (github.com/nakagami/firebirdsql) has to be imported by the application.
+// The bulk inserts are not supported, Firebird having no multi-row VALUES
+// clause.
+type Firebird struct{}
+
+var Adapter = Firebird{}
+
+func init() {
+	adapters.Register("firebird", func() adapters.Adapter { return Adapter })
+}
+
+func (Firebird) DriverName() string {
+	return "firebirdsql"
+}
+
+func (Firebird) Quote(identifier string) string {
+	return "\"" + identifier + "\""
+}
+
+func (f Firebird) ReturningBuild(columns []string) string {
+	suffixBuffer := bytes.NewBuffer(make([]byte, 0, 16*len(columns)+1))
+	suffixBuffer.WriteString("RETURNING ")
+	for i, column := range columns {
+		if i > 0 {
+			suffixBuffer.WriteString(", ")
+		}
+		suffixBuffer.WriteString(column)
+	}
+	return suffixBuffer.String()
+}
+
+func (f Firebird) FormatForNewValues(columns []string) []string {
+	formatedColumns := make([]string, 0, len(columns))
+	for _, column := range columns {
+		formatedColumns = append(formatedColumns, f.Quote(column))
+	}
+	return formatedColumns
+}
+
+func (f Firebird) GetReturningPosition() adapters.ReturningPosition {
+	return adapters.ReturningPostgreSQL
+}
+
+// PaginationStyle uses OFFSET and FETCH, without ORDER BY.
+func (Firebird) PaginationStyle() adapters.PaginationStyle {
+	return adapters.OffsetFetchUnordered
+}
+
+// ParseError recognizes the violated constraints from the messages of the
+// errors, the driver giving only their text.
+func (Firebird) ParseError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "violation of PRIMARY or UNIQUE KEY constraint \""):
+		return dberror.UniqueConstraint{Message: message, Field: dberror.ExtractStr(message, "constraint \"", "\""), Err: err}
+	case strings.Contains(message, "violation of FOREIGN KEY constraint \""):
+		return dberror.ForeignKeyConstraint{Message: message, Field: dberror.ExtractStr(message, "constraint \"", "\""), Err: err}
+	case strings.Contains(message, "CHECK constraint \""):
+		return dberror.CheckConstraint{Message: message, Field: dberror.ExtractStr(message, "constraint \"", "\""), Err: err}
+	}
+	return err
+}
+
+// FormatLiteral writes the booleans as TRUE and FALSE.
+func (Firebird) FormatLiteral(value interface{}) (string, bool) {
+	if v, ok := value.(bool); ok {
+		if v {
+			return "TRUE", true
+		}
+		return "FALSE", true
+	}
+	return "", false
+}
+
+// RecursiveWith uses WITH RECURSIVE.
+func (Firebird) RecursiveWith() string {
+	return "WITH RECURSIVE"
+}
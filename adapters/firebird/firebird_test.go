@@ -0,0 +1,33 @@
+package firebird
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samonzeweb/godb/dberror"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseError(t *testing.T) {
+	Convey("ParseError recognizes the violated constraints", t, func() {
+		err := Adapter.ParseError(errors.New(`violation of PRIMARY or UNIQUE KEY constraint "INTEG_12" on table "BOOKS"`))
+		uniqueConstraint, ok := err.(dberror.UniqueConstraint)
+		So(ok, ShouldBeTrue)
+		So(uniqueConstraint.Field, ShouldEqual, "INTEG_12")
+
+		err = Adapter.ParseError(errors.New(`violation of FOREIGN KEY constraint "FK_BOOKS_AUTHORS" on table "BOOKS"`))
+		foreignKeyConstraint, ok := err.(dberror.ForeignKeyConstraint)
+		So(ok, ShouldBeTrue)
+		So(foreignKeyConstraint.Field, ShouldEqual, "FK_BOOKS_AUTHORS")
+
+		other := errors.New("lock conflict on no wait transaction")
+		So(Adapter.ParseError(other), ShouldEqual, other)
+	})
+}
+
+func TestReturningBuild(t *testing.T) {
+	Convey("ReturningBuild uses RETURNING", t, func() {
+		columns := Adapter.FormatForNewValues([]string{"id", "version"})
+		So(Adapter.ReturningBuild(columns), ShouldEqual, `RETURNING "id", "version"`)
+	})
+}
@@ -2,6 +2,9 @@ package mssql
 
 import (
 	"bytes"
+	"database/sql"
+	"encoding/hex"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -12,9 +15,11 @@ import (
 	_ "github.com/denisenkom/go-mssqldb"
 )
 
-// init registers types of mssql package corresponding to fields values
+// init registers types of mssql package corresponding to fields values, and
+// the adapter
 func init() {
 	dbreflect.RegisterScannableStruct(Rowversion{})
+	adapters.Register("mssql", func() adapters.Adapter { return Adapter })
 }
 
 type MSSQL struct{}
@@ -71,30 +76,54 @@ func (m MSSQL) GetReturningPosition() adapters.ReturningPosition {
 	return adapters.ReturningSQLServer
 }
 
-func (MSSQL) BuildLimit(limit int) *adapters.SQLPart {
-	sqlPart := adapters.SQLPart{}
-	sqlPart.Sql = "FETCH NEXT ? ROWS ONLY"
-	sqlPart.Arguments = make([]interface{}, 0, 1)
-	sqlPart.Arguments = append(sqlPart.Arguments, limit)
-	return &sqlPart
+// PaginationStyle uses OFFSET and FETCH.
+func (MSSQL) PaginationStyle() adapters.PaginationStyle {
+	return adapters.OffsetFetch
 }
 
-func (MSSQL) BuildOffset(offset int) *adapters.SQLPart {
-	sqlPart := adapters.SQLPart{}
-	sqlPart.Sql = "OFFSET ? ROWS"
-	sqlPart.Arguments = make([]interface{}, 0, 1)
-	sqlPart.Arguments = append(sqlPart.Arguments, offset)
-	return &sqlPart
-}
-
-func (MSSQL) IsOffsetFirst() bool {
-	return true
+// BuildProcedureCall uses EXEC, except if named arguments are given : the
+// driver then does a RPC call with the procedure name alone, allowing OUTPUT
+// parameters with sql.Out.
+func (MSSQL) BuildProcedureCall(name string, arguments []interface{}) string {
+	for _, argument := range arguments {
+		if _, ok := argument.(sql.NamedArg); ok {
+			return name
+		}
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(arguments)), ", ")
+	if placeholders == "" {
+		return "EXEC " + name
+	}
+	return "EXEC " + name + " " + placeholders
 }
 
 type ErrorWithNumber interface {
 	SQLErrorNumber() int32
 }
 
+// BuildJSONPath uses the JSON_VALUE function.
+func (MSSQL) BuildJSONPath(column string, path string) string {
+	return "JSON_VALUE(" + column + ", '" + path + "')"
+}
+
+// BuildUnaccentILike uses an accent and case insensitive collation.
+func (MSSQL) BuildUnaccentILike(column string) string {
+	return column + " COLLATE Latin1_General_CI_AI LIKE ?"
+}
+
+// BuildTryLock uses sp_getapplock with a session owner and without timeout,
+// a positive status meaning the lock is acquired.
+func (MSSQL) BuildTryLock() string {
+	return "DECLARE @status int; " +
+		"EXEC @status = sp_getapplock @Resource = ?, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = 0; " +
+		"SELECT CASE WHEN @status >= 0 THEN 1 ELSE 0 END"
+}
+
+// BuildUnlock uses sp_releaseapplock.
+func (MSSQL) BuildUnlock() string {
+	return "EXEC sp_releaseapplock @Resource = ?, @LockOwner = 'Session'"
+}
+
 func (MSSQL) ParseError(err error) error {
 	if err == nil {
 		return nil
@@ -112,3 +141,141 @@ func (MSSQL) ParseError(err error) error {
 
 	return err
 }
+
+// ReplicationLagQuery uses the time of the last transaction committed on the
+// local secondary replica of an availability group. The lag grows when there
+// is no activity on the primary.
+func (MSSQL) ReplicationLagQuery() string {
+	return "SELECT ISNULL(DATEDIFF(millisecond, MAX(last_commit_time), SYSDATETIME()) / 1000.0, 0) " +
+		"FROM sys.dm_hadr_database_replica_states " +
+		"WHERE is_local = 1 AND is_primary_replica = 0 AND database_id = DB_ID()"
+}
+
+// BuildSavepoint uses SAVE TRANSACTION.
+func (MSSQL) BuildSavepoint(name string) string {
+	return "SAVE TRANSACTION " + name
+}
+
+// BuildRollbackToSavepoint uses ROLLBACK TRANSACTION.
+func (MSSQL) BuildRollbackToSavepoint(name string) string {
+	return "ROLLBACK TRANSACTION " + name
+}
+
+// BuildReleaseSavepoint returns a blank string, SQL Server doesn't release
+// the savepoints.
+func (MSSQL) BuildReleaseSavepoint(name string) string {
+	return ""
+}
+
+// RecursiveWith returns WITH, SQL Server has no RECURSIVE keyword.
+func (MSSQL) RecursiveWith() string {
+	return "WITH"
+}
+
+// BuildCreateTempTable uses SELECT INTO, the name of a temporary table
+// starts with #.
+func (MSSQL) BuildCreateTempTable(name string, source string) string {
+	return "SELECT * INTO " + name + " FROM " + source + " WHERE 1 = 0"
+}
+
+// BuildDropTempTable uses DROP TABLE.
+func (MSSQL) BuildDropTempTable(name string) string {
+	return "DROP TABLE " + name
+}
+
+// BuildCreateSequence creates a bigint sequence if it does not exist (the
+// default start is the minimum value of the type).
+func (MSSQL) BuildCreateSequence(name string) string {
+	return "IF OBJECT_ID('" + strings.Replace(name, "'", "''", -1) + "', 'SO') IS NULL " +
+		"CREATE SEQUENCE " + name + " AS bigint START WITH 1"
+}
+
+// BuildNextSequenceValue uses NEXT VALUE FOR.
+func (MSSQL) BuildNextSequenceValue(name string) string {
+	return "SELECT NEXT VALUE FOR " + name
+}
+
+// BuildTableSample uses TABLESAMPLE, with the SYSTEM method only.
+func (MSSQL) BuildTableSample(method string) string {
+	if method != "SYSTEM" {
+		return ""
+	}
+	return "TABLESAMPLE SYSTEM (? PERCENT)"
+}
+
+// BuildFunction uses SYSDATETIME for NOW, and LEN for LENGTH.
+func (MSSQL) BuildFunction(name string, args []string) (string, bool) {
+	switch name {
+	case "NOW":
+		return "SYSDATETIME()", true
+	case "LENGTH":
+		return "LEN(" + strings.Join(args, ", ") + ")", true
+	}
+	return "", false
+}
+
+// BuildDateAdd uses DATEADD.
+func (MSSQL) BuildDateAdd(column string, amount string, unit string) string {
+	return "DATEADD(" + unit + ", " + amount + ", " + column + ")"
+}
+
+// BuildDateTrunc counts the units since a reference date, then adds them to
+// it (the start of the day for the seconds, to avoid an overflow), without
+// DATETRUNC which needs SQL Server 2022.
+func (MSSQL) BuildDateTrunc(unit string, column string) string {
+	if unit == "second" {
+		day := "CAST(" + column + " AS date)"
+		return "DATEADD(second, DATEDIFF(second, " + day + ", " + column + "), CAST(" + day + " AS datetime2))"
+	}
+	return "DATEADD(" + unit + ", DATEDIFF(" + unit + ", 0, " + column + "), 0)"
+}
+
+// BuildStringAgg uses STRING_AGG (SQL Server 2017 or later), ordered with
+// WITHIN GROUP.
+func (MSSQL) BuildStringAgg(column string, separator string, orderBy string) string {
+	aggregate := "STRING_AGG(" + column + ", " + separator + ")"
+	if orderBy != "" {
+		aggregate += " WITHIN GROUP (ORDER BY " + orderBy + ")"
+	}
+	return aggregate
+}
+
+// BuildStatisticalAggregate uses STDEV and VAR, the percentiles being only
+// window functions with SQL Server.
+func (MSSQL) BuildStatisticalAggregate(name string, column string, fraction string) string {
+	switch name {
+	case "STDDEV":
+		return "STDEV(" + column + ")"
+	case "VARIANCE":
+		return "VAR(" + column + ")"
+	}
+	return ""
+}
+
+// FormatLiteral writes the strings as unicode literals, and the bytes as an
+// hexadecimal constant.
+func (MSSQL) FormatLiteral(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return "N'" + strings.Replace(v, "'", "''", -1) + "'", true
+	case []byte:
+		return "0x" + hex.EncodeToString(v), true
+	}
+	return "", false
+}
+
+// DataSourceWithApplicationName adds the app name parameter, giving the
+// program_name of the sessions, in a URL or in an ADO connection string.
+func (MSSQL) DataSourceWithApplicationName(dataSourceName string, name string) string {
+	if strings.HasPrefix(dataSourceName, "sqlserver://") {
+		separator := "?"
+		if strings.Contains(dataSourceName, "?") {
+			separator = "&"
+		}
+		return dataSourceName + separator + "app+name=" + url.QueryEscape(name)
+	}
+	if dataSourceName != "" && !strings.HasSuffix(dataSourceName, ";") {
+		dataSourceName += ";"
+	}
+	return dataSourceName + "app name=" + strings.NewReplacer(";", "", "=", "").Replace(name)
+}
@@ -1,6 +1,7 @@
 package mssql
 
 import (
+	"database/sql"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -38,3 +39,81 @@ func TestFormatForNewValues(t *testing.T) {
 		})
 	})
 }
+
+func TestBuildProcedureCall(t *testing.T) {
+	Convey("Given a procedure name and arguments", t, func() {
+		Convey("BuildProcedureCall uses EXEC with placeholders", func() {
+			So(Adapter.BuildProcedureCall("[foo]", []interface{}{1, "bar"}), ShouldEqual, "EXEC [foo] ?, ?")
+			So(Adapter.BuildProcedureCall("[foo]", nil), ShouldEqual, "EXEC [foo]")
+		})
+
+		Convey("BuildProcedureCall returns only the name with named arguments", func() {
+			var out int
+			arguments := []interface{}{sql.Named("a", 1), sql.Named("b", sql.Out{Dest: &out})}
+			So(Adapter.BuildProcedureCall("[foo]", arguments), ShouldEqual, "[foo]")
+		})
+	})
+}
+
+func TestBuildJSONPath(t *testing.T) {
+	Convey("BuildJSONPath uses the JSON_VALUE function", t, func() {
+		sql := Adapter.BuildJSONPath("[payload]", "$.customer.id")
+		So(sql, ShouldEqual, "JSON_VALUE([payload], '$.customer.id')")
+	})
+}
+
+func TestBuildUnaccentILike(t *testing.T) {
+	Convey("BuildUnaccentILike uses an accent insensitive collation", t, func() {
+		sql := Adapter.BuildUnaccentILike("[title]")
+		So(sql, ShouldEqual, "[title] COLLATE Latin1_General_CI_AI LIKE ?")
+	})
+}
+
+func TestBuildCreateTempTable(t *testing.T) {
+	Convey("BuildCreateTempTable uses SELECT INTO", t, func() {
+		So(Adapter.BuildCreateTempTable("[#staging]", "[books]"), ShouldEqual, "SELECT * INTO [#staging] FROM [books] WHERE 1 = 0")
+		So(Adapter.BuildDropTempTable("[#staging]"), ShouldEqual, "DROP TABLE [#staging]")
+	})
+}
+
+func TestBuildSequence(t *testing.T) {
+	Convey("The sequences use the native sequences", t, func() {
+		So(Adapter.BuildCreateSequence("[invoice_seq]"), ShouldEqual, "IF OBJECT_ID('[invoice_seq]', 'SO') IS NULL CREATE SEQUENCE [invoice_seq] AS bigint START WITH 1")
+		So(Adapter.BuildNextSequenceValue("[invoice_seq]"), ShouldEqual, "SELECT NEXT VALUE FOR [invoice_seq]")
+	})
+}
+
+func TestFormatLiteral(t *testing.T) {
+	Convey("FormatLiteral writes the strings and bytes of SQL Server", t, func() {
+		literal, ok := Adapter.FormatLiteral("l'été")
+		So(ok, ShouldBeTrue)
+		So(literal, ShouldEqual, "N'l''été'")
+		literal, ok = Adapter.FormatLiteral([]byte{0x01, 0xab})
+		So(ok, ShouldBeTrue)
+		So(literal, ShouldEqual, "0x01ab")
+		_, ok = Adapter.FormatLiteral(int64(1))
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func TestBuildTableSample(t *testing.T) {
+	Convey("BuildTableSample supports the SYSTEM method only", t, func() {
+		So(Adapter.BuildTableSample("SYSTEM"), ShouldEqual, "TABLESAMPLE SYSTEM (? PERCENT)")
+		So(Adapter.BuildTableSample("BERNOULLI"), ShouldEqual, "")
+	})
+}
+
+func TestBuildDateArithmetic(t *testing.T) {
+	Convey("The date arithmetic uses DATEADD and DATEDIFF", t, func() {
+		So(Adapter.BuildDateAdd("[at]", "?", "day"), ShouldEqual, "DATEADD(day, ?, [at])")
+		So(Adapter.BuildDateTrunc("month", "[at]"), ShouldEqual, "DATEADD(month, DATEDIFF(month, 0, [at]), 0)")
+		So(Adapter.BuildDateTrunc("second", "[at]"), ShouldEqual, "DATEADD(second, DATEDIFF(second, CAST([at] AS date), [at]), CAST(CAST([at] AS date) AS datetime2))")
+	})
+}
+
+func TestDataSourceWithApplicationName(t *testing.T) {
+	Convey("DataSourceWithApplicationName adds app name", t, func() {
+		So(Adapter.DataSourceWithApplicationName("sqlserver://sa@db?database=app", "billing worker"), ShouldEqual, "sqlserver://sa@db?database=app&app+name=billing+worker")
+		So(Adapter.DataSourceWithApplicationName("server=db;database=app", "billing"), ShouldEqual, "server=db;database=app;app name=billing")
+	})
+}
@@ -1,7 +1,12 @@
 package mysql
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/go-sql-driver/mysql"
+	"github.com/samonzeweb/godb/adapters"
 	"github.com/samonzeweb/godb/dberror"
 )
 
@@ -9,6 +14,10 @@ type MySQL struct{}
 
 var Adapter = MySQL{}
 
+func init() {
+	adapters.Register("mysql", func() adapters.Adapter { return Adapter })
+}
+
 func (MySQL) DriverName() string {
 	return "mysql"
 }
@@ -17,6 +26,55 @@ func (MySQL) Quote(identifier string) string {
 	return "`" + identifier + "`"
 }
 
+// FormatLiteral escapes the backslashes of the strings (unless the
+// NO_BACKSLASH_ESCAPES mode is set, they are escape characters), and writes
+// the times in UTC without offset like the driver.
+func (MySQL) FormatLiteral(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return "'" + literalReplacer.Replace(v) + "'", true
+	case time.Time:
+		return "'" + v.UTC().Format("2006-01-02 15:04:05.999999") + "'", true
+	}
+	return "", false
+}
+
+// literalReplacer escapes the quotes and backslashes of a string literal.
+var literalReplacer = strings.NewReplacer("\\", "\\\\", "'", "''")
+
+// BuildWithinDistance uses the spherical distance in meters.
+func (MySQL) BuildWithinDistance(column string) string {
+	return "ST_Distance_Sphere(" + column + ", POINT(?, ?)) <= ?"
+}
+
+// BuildJSONPath uses the ->> operator (unquoted JSON_EXTRACT).
+func (MySQL) BuildJSONPath(column string, path string) string {
+	return column + "->>'" + path + "'"
+}
+
+// BuildUnaccentILike uses an accent and case insensitive collation.
+func (MySQL) BuildUnaccentILike(column string) string {
+	return column + " COLLATE utf8mb4_0900_ai_ci LIKE ?"
+}
+
+// BuildRowLock uses FOR UPDATE, with SKIP LOCKED if needed.
+func (MySQL) BuildRowLock(skipLocked bool) string {
+	if skipLocked {
+		return "FOR UPDATE SKIP LOCKED"
+	}
+	return "FOR UPDATE"
+}
+
+// BuildTryLock uses GET_LOCK without timeout.
+func (MySQL) BuildTryLock() string {
+	return "SELECT GET_LOCK(?, 0)"
+}
+
+// BuildUnlock uses RELEASE_LOCK.
+func (MySQL) BuildUnlock() string {
+	return "SELECT RELEASE_LOCK(?)"
+}
+
 func (MySQL) ParseError(err error) error {
 	if err == nil {
 		return nil
@@ -32,3 +90,110 @@ func (MySQL) ParseError(err error) error {
 
 	return err
 }
+
+// ReplicationLagQuery uses the transactions being applied by the replication
+// workers (MySQL 8).
+func (MySQL) ReplicationLagQuery() string {
+	return "SELECT COALESCE(MAX(TIMESTAMPDIFF(MICROSECOND, APPLYING_TRANSACTION_ORIGINAL_COMMIT_TIMESTAMP, NOW(6))), 0) / 1000000 " +
+		"FROM performance_schema.replication_applier_status_by_worker " +
+		"WHERE APPLYING_TRANSACTION <> ''"
+}
+
+// BuildTableRowsEstimate uses the TABLE_ROWS column of
+// information_schema.TABLES, an approximation for InnoDB.
+func (MySQL) BuildTableRowsEstimate() string {
+	return "SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?"
+}
+
+// BuildPlanRowsEstimate returns a blank string, the plans are not used.
+func (MySQL) BuildPlanRowsEstimate(query string) string {
+	return ""
+}
+
+// ParsePlanRows is unused, see BuildPlanRowsEstimate.
+func (MySQL) ParsePlanRows(plan string) (int64, error) {
+	return 0, fmt.Errorf("the plans are not used")
+}
+
+// HintTimeout adds a MAX_EXECUTION_TIME optimizer hint to a SELECT
+// statement, as the driver does not kill the query when the context is
+// cancelled. The others statements are not changed.
+func (MySQL) HintTimeout(query string, timeout time.Duration) string {
+	trimmed := strings.TrimLeft(query, " \t\r\n")
+	if len(trimmed) < 6 || !strings.EqualFold(trimmed[:6], "SELECT") {
+		return query
+	}
+	milliseconds := int64(timeout / time.Millisecond)
+	if milliseconds < 1 {
+		milliseconds = 1
+	}
+	return fmt.Sprintf("SELECT /*+ MAX_EXECUTION_TIME(%d) */%s", milliseconds, trimmed[6:])
+}
+
+// RecursiveWith needs MySQL 8 or MariaDB 10.2.
+func (MySQL) RecursiveWith() string {
+	return "WITH RECURSIVE"
+}
+
+// BuildCreateTempTable uses LIKE, the columns and indexes are copied.
+func (MySQL) BuildCreateTempTable(name string, source string) string {
+	return "CREATE TEMPORARY TABLE " + name + " LIKE " + source
+}
+
+// BuildDropTempTable uses DROP TEMPORARY TABLE, which doesn't commit the
+// current transaction.
+func (MySQL) BuildDropTempTable(name string) string {
+	return "DROP TEMPORARY TABLE " + name
+}
+
+// BuildFunction uses CHAR_LENGTH for LENGTH, counting the characters
+// rather than the bytes.
+func (MySQL) BuildFunction(name string, args []string) (string, bool) {
+	if name == "LENGTH" {
+		return "CHAR_LENGTH(" + strings.Join(args, ", ") + ")", true
+	}
+	return "", false
+}
+
+// dateTruncFormats are the formats of DATE_FORMAT keeping a date up to a
+// unit.
+var dateTruncFormats = map[string]string{
+	"second": "%Y-%m-%d %H:%i:%s",
+	"minute": "%Y-%m-%d %H:%i:00",
+	"hour":   "%Y-%m-%d %H:00:00",
+	"day":    "%Y-%m-%d 00:00:00",
+	"month":  "%Y-%m-01 00:00:00",
+	"year":   "%Y-01-01 00:00:00",
+}
+
+// BuildDateAdd uses DATE_ADD.
+func (MySQL) BuildDateAdd(column string, amount string, unit string) string {
+	return "DATE_ADD(" + column + ", INTERVAL " + amount + " " + strings.ToUpper(unit) + ")"
+}
+
+// BuildDateTrunc formats the date up to the unit, then reads it as a
+// timestamp.
+func (MySQL) BuildDateTrunc(unit string, column string) string {
+	return "TIMESTAMP(DATE_FORMAT(" + column + ", '" + dateTruncFormats[unit] + "'))"
+}
+
+// BuildStringAgg uses GROUP_CONCAT, its result is truncated to
+// group_concat_max_len.
+func (MySQL) BuildStringAgg(column string, separator string, orderBy string) string {
+	if orderBy != "" {
+		column += " ORDER BY " + orderBy
+	}
+	return "GROUP_CONCAT(" + column + " SEPARATOR " + separator + ")"
+}
+
+// BuildStatisticalAggregate uses STDDEV_SAMP and VAR_SAMP, the percentiles
+// are not supported.
+func (MySQL) BuildStatisticalAggregate(name string, column string, fraction string) string {
+	switch name {
+	case "STDDEV":
+		return "STDDEV_SAMP(" + column + ")"
+	case "VARIANCE":
+		return "VAR_SAMP(" + column + ")"
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+package odbc
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// PlaceholderStyle is the syntax of the parameters of the statements.
+type PlaceholderStyle int
+
+const (
+	// QuestionMark uses '?' (the default).
+	QuestionMark PlaceholderStyle = iota
+	// DollarNumber uses '$1', '$2', ...
+	DollarNumber
+	// ColonNumber uses ':1', ':2', ...
+	ColonNumber
+	// AtNumber uses '@p1', '@p2', ...
+	AtNumber
+)
+
+// Config is the dialect of the database reached through ODBC. The zero
+// values are replaced by the defaults.
+type Config struct {
+	// QuoteStart and QuoteEnd surround the identifiers (default '"').
+	QuoteStart string
+	QuoteEnd   string
+	// Placeholder is the syntax of the parameters (default QuestionMark).
+	Placeholder PlaceholderStyle
+	// Pagination is the syntax limiting and skipping the rows (default
+	// adapters.LimitOffset).
+	Pagination adapters.PaginationStyle
+}
+
+// ODBC is a generic adapter of the databases reached through an ODBC bridge
+// (github.com/alexbrainman/odbc has to be imported by the application). It
+// gives the builders and the scanning to the databases without adapter, but
+// not the optional features : the ids of the inserted rows are not read, and
+// the errors are returned as is.
+type ODBC struct {
+	config Config
+}
+
+// Adapter uses the SQL standard : double quotes, '?' placeholders and LIMIT
+// with OFFSET.
+var Adapter = New(Config{})
+
+func init() {
+	adapters.Register("odbc", func() adapters.Adapter { return Adapter })
+}
+
+// New returns an adapter using the given dialect.
+//
+// Example :
+// 	// IBM Db2 through ODBC
+// 	adapter := odbc.New(odbc.Config{Pagination: adapters.OffsetFetchUnordered})
+// 	db, err := godb.Open(adapter, dsn)
+func New(config Config) ODBC {
+	if config.QuoteStart == "" {
+		config.QuoteStart = "\""
+	}
+	if config.QuoteEnd == "" {
+		config.QuoteEnd = config.QuoteStart
+	}
+	return ODBC{config: config}
+}
+
+func (ODBC) DriverName() string {
+	return "odbc"
+}
+
+func (o ODBC) Quote(identifier string) string {
+	return o.config.QuoteStart + identifier + o.config.QuoteEnd
+}
+
+// ReplacePlaceholders writes the placeholders in the configured style.
+func (o ODBC) ReplacePlaceholders(originalPlaceholder string, sql string) string {
+	var prefix string
+	switch o.config.Placeholder {
+	case DollarNumber:
+		prefix = "$"
+	case ColonNumber:
+		prefix = ":"
+	case AtNumber:
+		prefix = "@p"
+	default:
+		return sql
+	}
+
+	sqlBuffer := bytes.NewBuffer(make([]byte, 0, len(sql)))
+	count := 1
+	for {
+		pp := strings.Index(sql, originalPlaceholder)
+		if pp == -1 {
+			break
+		}
+		sqlBuffer.WriteString(sql[:pp])
+		sqlBuffer.WriteString(prefix)
+		sqlBuffer.WriteString(strconv.Itoa(count))
+		count++
+		sql = sql[pp+len(originalPlaceholder):]
+	}
+	sqlBuffer.WriteString(sql)
+	return sqlBuffer.String()
+}
+
+// PaginationStyle returns the configured style.
+func (o ODBC) PaginationStyle() adapters.PaginationStyle {
+	return o.config.Pagination
+}
+
+// SupportsLastInsertID is always false, few ODBC drivers give the ids.
+func (ODBC) SupportsLastInsertID() bool {
+	return false
+}
+
+// ParseError returns the error as is.
+func (ODBC) ParseError(err error) error {
+	return err
+}
@@ -0,0 +1,30 @@
+package odbc
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDefaultAdapter(t *testing.T) {
+	Convey("The default adapter uses the SQL standard", t, func() {
+		So(Adapter.Quote("name"), ShouldEqual, `"name"`)
+		So(Adapter.ReplacePlaceholders("?", "a = ? AND b = ?"), ShouldEqual, "a = ? AND b = ?")
+		So(Adapter.PaginationStyle(), ShouldEqual, adapters.LimitOffset)
+	})
+}
+
+func TestConfiguredAdapter(t *testing.T) {
+	Convey("New uses the given dialect", t, func() {
+		adapter := New(Config{QuoteStart: "[", QuoteEnd: "]", Placeholder: AtNumber, Pagination: adapters.OffsetFetch})
+		So(adapter.Quote("name"), ShouldEqual, "[name]")
+		So(adapter.ReplacePlaceholders("?", "a = ? AND b = ?"), ShouldEqual, "a = @p1 AND b = @p2")
+		So(adapter.PaginationStyle(), ShouldEqual, adapters.OffsetFetch)
+
+		adapter = New(Config{QuoteStart: "`", Placeholder: ColonNumber})
+		So(adapter.Quote("name"), ShouldEqual, "`name`")
+		So(adapter.ReplacePlaceholders("?", "a = ?"), ShouldEqual, "a = :1")
+		So(New(Config{Placeholder: DollarNumber}).ReplacePlaceholders("?", "a = ?"), ShouldEqual, "a = $1")
+	})
+}
@@ -2,8 +2,14 @@ package postgresql
 
 import (
 	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	pq "github.com/lib/pq"
 	"github.com/samonzeweb/godb/adapters"
@@ -14,6 +20,10 @@ type PostgreSQL struct{}
 
 var Adapter = PostgreSQL{}
 
+func init() {
+	adapters.Register("postgresql", func() adapters.Adapter { return Adapter })
+}
+
 func (PostgreSQL) DriverName() string {
 	return "postgres"
 }
@@ -64,6 +74,54 @@ func (p PostgreSQL) GetReturningPosition() adapters.ReturningPosition {
 	return adapters.ReturningPostgreSQL
 }
 
+// BuildProcedureCall calls a function as a set returning function, allowing
+// the scan of its results.
+func (PostgreSQL) BuildProcedureCall(name string, arguments []interface{}) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(arguments)), ", ")
+	return "SELECT * FROM " + name + "(" + placeholders + ")"
+}
+
+// BuildWithinDistance uses PostGIS geography to compute distances in meters.
+func (PostgreSQL) BuildWithinDistance(column string) string {
+	return "ST_DWithin(" + column + "::geography, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography, ?)"
+}
+
+// BuildJSONPath uses the #>> operator, the path being converted to an array
+// of keys.
+func (PostgreSQL) BuildJSONPath(column string, path string) string {
+	path = strings.NewReplacer("[", ".", "]", "").Replace(strings.TrimPrefix(path, "$"))
+	return column + " #>> '{" + strings.Join(strings.FieldsFunc(path, func(r rune) bool { return r == '.' }), ",") + "}'"
+}
+
+// BuildILike uses the ILIKE operator.
+func (PostgreSQL) BuildILike(column string) string {
+	return column + " ILIKE ?"
+}
+
+// BuildUnaccentILike uses the unaccent extension, which has to be installed.
+func (PostgreSQL) BuildUnaccentILike(column string) string {
+	return "unaccent(" + column + ") ILIKE unaccent(?)"
+}
+
+// BuildRowLock uses FOR UPDATE, with SKIP LOCKED if needed.
+func (PostgreSQL) BuildRowLock(skipLocked bool) string {
+	if skipLocked {
+		return "FOR UPDATE SKIP LOCKED"
+	}
+	return "FOR UPDATE"
+}
+
+// BuildTryLock uses a session advisory lock, the name is hashed to get the
+// key of the lock.
+func (PostgreSQL) BuildTryLock() string {
+	return "SELECT pg_try_advisory_lock(hashtext(?))"
+}
+
+// BuildUnlock releases the session advisory lock.
+func (PostgreSQL) BuildUnlock() string {
+	return "SELECT pg_advisory_unlock(hashtext(?))"
+}
+
 func (p PostgreSQL) ParseError(err error) error {
 	if err == nil {
 		return nil
@@ -82,3 +140,269 @@ func (p PostgreSQL) ParseError(err error) error {
 
 	return err
 }
+
+// ReplicationLagQuery uses the timestamp of the last transaction replayed.
+// The lag grows when there is no activity on the primary.
+func (PostgreSQL) ReplicationLagQuery() string {
+	return "SELECT CASE WHEN pg_is_in_recovery() " +
+		"THEN COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0) " +
+		"ELSE 0 END"
+}
+
+// BuildTableRowsEstimate uses the reltuples column of pg_class, updated by
+// VACUUM and ANALYZE (-1 before, or 0 before PostgreSQL 14).
+func (PostgreSQL) BuildTableRowsEstimate() string {
+	return "SELECT CASE WHEN relpages > 0 OR reltuples > 0 THEN reltuples::bigint ELSE -1 END " +
+		"FROM pg_class WHERE oid = to_regclass(?)"
+}
+
+// BuildPlanRowsEstimate uses EXPLAIN with the JSON format.
+func (PostgreSQL) BuildPlanRowsEstimate(query string) string {
+	return "EXPLAIN (FORMAT JSON) " + query
+}
+
+// ParsePlanRows returns the rows of the top node of a JSON plan.
+func (PostgreSQL) ParsePlanRows(plan string) (int64, error) {
+	var plans []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		}
+	}
+	if err := json.Unmarshal([]byte(plan), &plans); err != nil {
+		return 0, err
+	}
+	if len(plans) == 0 {
+		return 0, fmt.Errorf("the plan has no node")
+	}
+	return int64(plans[0].Plan.Rows), nil
+}
+
+// BuildPrepareTransaction uses PREPARE TRANSACTION, the server needs a
+// positive max_prepared_transactions setting.
+func (PostgreSQL) BuildPrepareTransaction(id string) string {
+	return "PREPARE TRANSACTION " + quoteLiteral(id)
+}
+
+// BuildCommitPrepared uses COMMIT PREPARED.
+func (PostgreSQL) BuildCommitPrepared(id string) string {
+	return "COMMIT PREPARED " + quoteLiteral(id)
+}
+
+// BuildRollbackPrepared uses ROLLBACK PREPARED.
+func (PostgreSQL) BuildRollbackPrepared(id string) string {
+	return "ROLLBACK PREPARED " + quoteLiteral(id)
+}
+
+// BuildListPrepared uses the pg_prepared_xacts view.
+func (PostgreSQL) BuildListPrepared() string {
+	return "SELECT gid FROM pg_prepared_xacts WHERE database = current_database() ORDER BY prepared"
+}
+
+// DataSourceWithApplicationName adds the application_name parameter, in a
+// URL or in a list of keywords and values.
+func (PostgreSQL) DataSourceWithApplicationName(dataSourceName string, name string) string {
+	if strings.HasPrefix(dataSourceName, "postgres://") || strings.HasPrefix(dataSourceName, "postgresql://") {
+		separator := "?"
+		if strings.Contains(dataSourceName, "?") {
+			separator = "&"
+		}
+		return dataSourceName + separator + "application_name=" + url.QueryEscape(name)
+	}
+	value := "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(name) + "'"
+	return strings.TrimSpace(dataSourceName + " application_name=" + value)
+}
+
+// SupportsLargeObjects returns true, the large objects are stored in
+// pg_largeobject.
+func (PostgreSQL) SupportsLargeObjects() bool {
+	return true
+}
+
+// quoteLiteral quotes a string literal, the transaction identifiers can't
+// be given as parameters.
+func quoteLiteral(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// FormatLiteral writes the booleans as TRUE and FALSE, and the bytes as an
+// hexadecimal bytea.
+func (PostgreSQL) FormatLiteral(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return "TRUE", true
+		}
+		return "FALSE", true
+	case []byte:
+		return "'\\x" + hex.EncodeToString(v) + "'::bytea", true
+	}
+	return "", false
+}
+
+// RecursiveWith uses WITH RECURSIVE.
+func (PostgreSQL) RecursiveWith() string {
+	return "WITH RECURSIVE"
+}
+
+// BuildRefreshMaterializedView uses REFRESH MATERIALIZED VIEW, the
+// concurrent refresh needs a unique index on the view.
+func (PostgreSQL) BuildRefreshMaterializedView(name string, concurrently bool) string {
+	if concurrently {
+		return "REFRESH MATERIALIZED VIEW CONCURRENTLY " + name
+	}
+	return "REFRESH MATERIALIZED VIEW " + name
+}
+
+// IgnoreConflictsClause uses ON CONFLICT DO NOTHING.
+func (PostgreSQL) IgnoreConflictsClause() string {
+	return "ON CONFLICT DO NOTHING"
+}
+
+// BuildCreateTempTable uses LIKE, the defaults of the columns are copied.
+func (PostgreSQL) BuildCreateTempTable(name string, source string) string {
+	return "CREATE TEMPORARY TABLE " + name + " (LIKE " + source + " INCLUDING DEFAULTS)"
+}
+
+// BuildDropTempTable uses DROP TABLE.
+func (PostgreSQL) BuildDropTempTable(name string) string {
+	return "DROP TABLE " + name
+}
+
+// BuildCreateSequence uses CREATE SEQUENCE IF NOT EXISTS.
+func (PostgreSQL) BuildCreateSequence(name string) string {
+	return "CREATE SEQUENCE IF NOT EXISTS " + name
+}
+
+// BuildNextSequenceValue uses the nextval function.
+func (PostgreSQL) BuildNextSequenceValue(name string) string {
+	return "SELECT nextval('" + strings.Replace(name, "'", "''", -1) + "')"
+}
+
+// BuildValuesList uses a VALUES list, its columns being named by the alias
+// of the list.
+func (PostgreSQL) BuildValuesList(rows []string, columns []string) string {
+	return "(SELECT * FROM (VALUES " + strings.Join(rows, ", ") + ") AS godb_values (" + strings.Join(columns, ", ") + "))"
+}
+
+// BuildTableSample uses TABLESAMPLE, with the SYSTEM and BERNOULLI methods.
+func (PostgreSQL) BuildTableSample(method string) string {
+	if method != "SYSTEM" && method != "BERNOULLI" {
+		return ""
+	}
+	return "TABLESAMPLE " + method + " (?)"
+}
+
+// BuildDateAdd multiplies an interval of one unit.
+func (PostgreSQL) BuildDateAdd(column string, amount string, unit string) string {
+	return "(" + column + " + " + amount + " * INTERVAL '1 " + unit + "')"
+}
+
+// BuildDateTrunc uses DATE_TRUNC.
+func (PostgreSQL) BuildDateTrunc(unit string, column string) string {
+	return "DATE_TRUNC('" + unit + "', " + column + ")"
+}
+
+// BuildStringAgg uses STRING_AGG, the values have to be texts.
+func (PostgreSQL) BuildStringAgg(column string, separator string, orderBy string) string {
+	return "STRING_AGG(" + column + ", " + separator + withinAggregateOrder(orderBy) + ")"
+}
+
+// BuildArrayAgg uses ARRAY_AGG.
+func (PostgreSQL) BuildArrayAgg(column string, orderBy string) string {
+	return "ARRAY_AGG(" + column + withinAggregateOrder(orderBy) + ")"
+}
+
+// BuildStatisticalAggregate uses the ordered-set aggregates for the
+// percentiles, STDDEV_SAMP and VAR_SAMP.
+func (PostgreSQL) BuildStatisticalAggregate(name string, column string, fraction string) string {
+	switch name {
+	case "PERCENTILE_CONT", "PERCENTILE_DISC":
+		return name + "(" + fraction + ") WITHIN GROUP (ORDER BY " + column + ")"
+	case "STDDEV":
+		return "STDDEV_SAMP(" + column + ")"
+	case "VARIANCE":
+		return "VAR_SAMP(" + column + ")"
+	}
+	return ""
+}
+
+// withinAggregateOrder returns the ORDER BY clause of an aggregate function,
+// if any.
+func withinAggregateOrder(orderBy string) string {
+	if orderBy == "" {
+		return ""
+	}
+	return " ORDER BY " + orderBy
+}
+
+// partitionBoundRegexp matches the bound of a range partition given by
+// pg_get_expr.
+var partitionBoundRegexp = regexp.MustCompile(`^FOR VALUES FROM \('([^']*)'\) TO \('([^']*)'\)$`)
+
+// partitionBoundLayouts are the layouts of the times of the bounds, for the
+// timestamp with or without time zone and date columns.
+var partitionBoundLayouts = []string{
+	"2006-01-02 15:04:05.999999-07",
+	"2006-01-02 15:04:05.999999-07:00",
+	"2006-01-02 15:04:05.999999",
+	"2006-01-02",
+}
+
+// BuildCreatePartition uses CREATE TABLE ... PARTITION OF, the parent table
+// is partitioned by range on a single time column.
+func (PostgreSQL) BuildCreatePartition(name string, parent string, from time.Time, to time.Time) string {
+	return "CREATE TABLE IF NOT EXISTS " + name + " PARTITION OF " + parent + " " + partitionBound(from, to)
+}
+
+// BuildAttachPartition uses ALTER TABLE ... ATTACH PARTITION.
+func (PostgreSQL) BuildAttachPartition(name string, parent string, from time.Time, to time.Time) string {
+	return "ALTER TABLE " + parent + " ATTACH PARTITION " + name + " " + partitionBound(from, to)
+}
+
+// BuildDetachPartition uses ALTER TABLE ... DETACH PARTITION.
+func (PostgreSQL) BuildDetachPartition(name string, parent string) string {
+	return "ALTER TABLE " + parent + " DETACH PARTITION " + name
+}
+
+// BuildListPartitions reads pg_inherits, the parent table is given as a
+// quoted name.
+func (PostgreSQL) BuildListPartitions() string {
+	return "SELECT c.relname AS name, pg_get_expr(c.relpartbound, c.oid) AS bound " +
+		"FROM pg_inherits i INNER JOIN pg_class c ON c.oid = i.inhrelid " +
+		"WHERE i.inhparent = CAST(? AS regclass) ORDER BY c.relname"
+}
+
+// ParsePartitionBound parses the bounds given by pg_get_expr, the default
+// partition and the MINVALUE and MAXVALUE bounds are not ranges of times.
+func (PostgreSQL) ParsePartitionBound(bound string) (time.Time, time.Time, bool) {
+	matches := partitionBoundRegexp.FindStringSubmatch(bound)
+	if matches == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	from, ok := parsePartitionTime(matches[1])
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	to, ok := parsePartitionTime(matches[2])
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
+
+// partitionBound returns the FOR VALUES clause of a range partition. The
+// times are literals, the DDL statements don't accept placeholders.
+func partitionBound(from time.Time, to time.Time) string {
+	const layout = "2006-01-02 15:04:05.999999-07:00"
+	return "FOR VALUES FROM ('" + from.Format(layout) + "') TO ('" + to.Format(layout) + "')"
+}
+
+// parsePartitionTime parses a time of a partition bound.
+func parsePartitionTime(value string) (time.Time, bool) {
+	for _, layout := range partitionBoundLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
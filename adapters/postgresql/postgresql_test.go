@@ -2,6 +2,7 @@ package postgresql
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -38,3 +39,132 @@ func TestFormatForNewValues(t *testing.T) {
 		})
 	})
 }
+
+func TestBuildProcedureCall(t *testing.T) {
+	Convey("Given a procedure name and arguments", t, func() {
+		Convey("BuildProcedureCall selects from the function", func() {
+			So(Adapter.BuildProcedureCall("\"foo\"", []interface{}{1, "bar"}), ShouldEqual, "SELECT * FROM \"foo\"(?, ?)")
+			So(Adapter.BuildProcedureCall("\"foo\"", nil), ShouldEqual, "SELECT * FROM \"foo\"()")
+		})
+	})
+}
+
+func TestBuildWithinDistance(t *testing.T) {
+	Convey("BuildWithinDistance uses PostGIS geography", t, func() {
+		sql := Adapter.BuildWithinDistance(`"location"`)
+		So(sql, ShouldEqual, `ST_DWithin("location"::geography, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography, ?)`)
+	})
+}
+
+func TestBuildJSONPath(t *testing.T) {
+	Convey("BuildJSONPath uses the #>> operator", t, func() {
+		sql := Adapter.BuildJSONPath(`"payload"`, "$.items[0].id")
+		So(sql, ShouldEqual, `"payload" #>> '{items,0,id}'`)
+	})
+}
+
+func TestBuildRefreshMaterializedView(t *testing.T) {
+	Convey("BuildRefreshMaterializedView uses REFRESH MATERIALIZED VIEW", t, func() {
+		So(Adapter.BuildRefreshMaterializedView(`"stats"`, false), ShouldEqual, `REFRESH MATERIALIZED VIEW "stats"`)
+		So(Adapter.BuildRefreshMaterializedView(`"stats"`, true), ShouldEqual, `REFRESH MATERIALIZED VIEW CONCURRENTLY "stats"`)
+	})
+}
+
+func TestBuildCreateTempTable(t *testing.T) {
+	Convey("BuildCreateTempTable copies the columns with LIKE", t, func() {
+		So(Adapter.BuildCreateTempTable(`"staging"`, `"books"`), ShouldEqual, `CREATE TEMPORARY TABLE "staging" (LIKE "books" INCLUDING DEFAULTS)`)
+		So(Adapter.BuildDropTempTable(`"staging"`), ShouldEqual, `DROP TABLE "staging"`)
+	})
+}
+
+func TestBuildPartitions(t *testing.T) {
+	Convey("Given the bounds of a partition", t, func() {
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 1, 0)
+
+		Convey("BuildCreatePartition uses PARTITION OF with literal bounds", func() {
+			sql := Adapter.BuildCreatePartition(`"events_2024_01"`, `"events"`, from, to)
+			So(sql, ShouldEqual, `CREATE TABLE IF NOT EXISTS "events_2024_01" PARTITION OF "events" FOR VALUES FROM ('2024-01-01 00:00:00+00:00') TO ('2024-02-01 00:00:00+00:00')`)
+		})
+
+		Convey("BuildAttachPartition and BuildDetachPartition alter the parent table", func() {
+			sql := Adapter.BuildAttachPartition(`"events_2024_01"`, `"events"`, from, to)
+			So(sql, ShouldStartWith, `ALTER TABLE "events" ATTACH PARTITION "events_2024_01" FOR VALUES FROM`)
+			sql = Adapter.BuildDetachPartition(`"events_2024_01"`, `"events"`)
+			So(sql, ShouldEqual, `ALTER TABLE "events" DETACH PARTITION "events_2024_01"`)
+		})
+
+		Convey("ParsePartitionBound parses the time ranges", func() {
+			parsedFrom, parsedTo, ok := Adapter.ParsePartitionBound("FOR VALUES FROM ('2024-01-01 00:00:00+00') TO ('2024-02-01 00:00:00+00')")
+			So(ok, ShouldBeTrue)
+			So(parsedFrom.Equal(from), ShouldBeTrue)
+			So(parsedTo.Equal(to), ShouldBeTrue)
+			parsedFrom, _, ok = Adapter.ParsePartitionBound("FOR VALUES FROM ('2024-01-01') TO ('2024-02-01')")
+			So(ok, ShouldBeTrue)
+			So(parsedFrom.Equal(from), ShouldBeTrue)
+			_, _, ok = Adapter.ParsePartitionBound("DEFAULT")
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
+
+func TestBuildSequence(t *testing.T) {
+	Convey("The sequences use the native sequences", t, func() {
+		So(Adapter.BuildCreateSequence(`"invoice_seq"`), ShouldEqual, `CREATE SEQUENCE IF NOT EXISTS "invoice_seq"`)
+		So(Adapter.BuildNextSequenceValue(`"invoice_seq"`), ShouldEqual, `SELECT nextval('"invoice_seq"')`)
+	})
+}
+
+func TestFormatLiteral(t *testing.T) {
+	Convey("FormatLiteral writes the booleans and bytes of PostgreSQL", t, func() {
+		literal, ok := Adapter.FormatLiteral(true)
+		So(ok, ShouldBeTrue)
+		So(literal, ShouldEqual, "TRUE")
+		literal, ok = Adapter.FormatLiteral([]byte{0x01, 0xab})
+		So(ok, ShouldBeTrue)
+		So(literal, ShouldEqual, `'\x01ab'::bytea`)
+		_, ok = Adapter.FormatLiteral("text")
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func TestBuildValuesList(t *testing.T) {
+	Convey("BuildValuesList uses a VALUES list named by its alias", t, func() {
+		sql := Adapter.BuildValuesList([]string{"(?, ?)", "(?, ?)"}, []string{`"id"`, `"label"`})
+		So(sql, ShouldEqual, `(SELECT * FROM (VALUES (?, ?), (?, ?)) AS godb_values ("id", "label"))`)
+	})
+}
+
+func TestBuildTableSample(t *testing.T) {
+	Convey("BuildTableSample supports the SYSTEM and BERNOULLI methods", t, func() {
+		So(Adapter.BuildTableSample("SYSTEM"), ShouldEqual, "TABLESAMPLE SYSTEM (?)")
+		So(Adapter.BuildTableSample("BERNOULLI"), ShouldEqual, "TABLESAMPLE BERNOULLI (?)")
+		So(Adapter.BuildTableSample("RANDOM"), ShouldEqual, "")
+	})
+}
+
+func TestBuildDateArithmetic(t *testing.T) {
+	Convey("The date arithmetic uses intervals and DATE_TRUNC", t, func() {
+		So(Adapter.BuildDateAdd(`"at"`, "?", "hour"), ShouldEqual, `("at" + ? * INTERVAL '1 hour')`)
+		So(Adapter.BuildDateTrunc("year", `"at"`), ShouldEqual, `DATE_TRUNC('year', "at")`)
+	})
+}
+
+func TestDataSourceWithApplicationName(t *testing.T) {
+	Convey("DataSourceWithApplicationName adds application_name", t, func() {
+		So(Adapter.DataSourceWithApplicationName("postgres://app@db/app?sslmode=require", "billing worker"), ShouldEqual, "postgres://app@db/app?sslmode=require&application_name=billing+worker")
+		So(Adapter.DataSourceWithApplicationName("postgres://app@db/app", "billing"), ShouldEqual, "postgres://app@db/app?application_name=billing")
+		So(Adapter.DataSourceWithApplicationName("host=db dbname=app", "bob's"), ShouldEqual, `host=db dbname=app application_name='bob\'s'`)
+	})
+}
+
+func TestParsePlanRows(t *testing.T) {
+	Convey("ParsePlanRows returns the rows of the top node of the plan", t, func() {
+		rows, err := Adapter.ParsePlanRows(`[{"Plan": {"Node Type": "Seq Scan", "Plan Rows": 1234, "Plans": [{"Plan Rows": 5}]}}]`)
+		So(err, ShouldBeNil)
+		So(rows, ShouldEqual, 1234)
+
+		_, err = Adapter.ParsePlanRows("[]")
+		So(err, ShouldNotBeNil)
+	})
+}
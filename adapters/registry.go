@@ -0,0 +1,54 @@
+package adapters
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// factories are the functions returning the registered adapters, by name.
+var factories = struct {
+	sync.RWMutex
+	byName map[string]func() Adapter
+}{byName: make(map[string]func() Adapter)}
+
+// Register makes an adapter available by the given name, allowing the
+// applications to select it from their configuration (see godb.OpenByName).
+// The adapters of this repository register themselves when their package is
+// imported, with the name of their package (sqlite, postgresql, mysql, ...).
+// Like sql.Register, it panics if the factory is nil or if the name is
+// already registered.
+func Register(name string, factory func() Adapter) {
+	factories.Lock()
+	defer factories.Unlock()
+	if factory == nil {
+		panic("adapters: Register factory is nil")
+	}
+	if _, dup := factories.byName[name]; dup {
+		panic("adapters: Register called twice for adapter " + name)
+	}
+	factories.byName[name] = factory
+}
+
+// Lookup returns a new adapter registered with the given name.
+func Lookup(name string) (Adapter, error) {
+	factories.RLock()
+	factory, ok := factories.byName[name]
+	factories.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown adapter %q (forgotten import?)", name)
+	}
+	return factory(), nil
+}
+
+// Names returns a sorted list of the names of the registered adapters.
+func Names() []string {
+	factories.RLock()
+	defer factories.RUnlock()
+	names := make([]string, 0, len(factories.byName))
+	for name := range factories.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,41 @@
+package adapters
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type registeredAdapter struct{}
+
+func (registeredAdapter) DriverName() string             { return "registered" }
+func (registeredAdapter) Quote(identifier string) string { return identifier }
+func (registeredAdapter) ParseError(err error) error     { return err }
+
+func TestRegister(t *testing.T) {
+	Convey("Given a registered adapter", t, func() {
+		Register("registered", func() Adapter { return registeredAdapter{} })
+		defer func() {
+			factories.Lock()
+			delete(factories.byName, "registered")
+			factories.Unlock()
+		}()
+
+		Convey("Lookup returns it by name", func() {
+			adapter, err := Lookup("registered")
+			So(err, ShouldBeNil)
+			So(adapter, ShouldResemble, registeredAdapter{})
+			So(Names(), ShouldContain, "registered")
+		})
+
+		Convey("Lookup fails with an unknown name", func() {
+			_, err := Lookup("unknown")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Register panics if the name is already used", func() {
+			So(func() { Register("registered", func() Adapter { return registeredAdapter{} }) }, ShouldPanic)
+			So(func() { Register("other", nil) }, ShouldPanic)
+		})
+	})
+}
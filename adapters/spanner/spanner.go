@@ -0,0 +1,129 @@
+package spanner
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/dberror"
+)
+
+// Spanner is the adapter of Google Cloud Spanner (GoogleSQL dialect). The
+// database/sql driver (github.com/googleapis/go-sql-spanner) has to be
+// imported by the application.
+//
+// The inserts, updates and deletes are DML statements, the auto fields being
+// read with a THEN RETURN clause : unlike the mutations, the changes are
+// seen by the following statements of the transaction, at the cost of a
+// round trip by statement. The bulk loads are better done with the mutations
+// of the driver (SpannerConn.Apply, through sql.Conn.Raw).
+//
+// The read only transactions are started with BeginTx and sql.TxOptions,
+// they read a consistent snapshot without taking locks.
+type Spanner struct{}
+
+var Adapter = Spanner{}
+
+func init() {
+	adapters.Register("spanner", func() adapters.Adapter { return Adapter })
+}
+
+func (Spanner) DriverName() string {
+	return "spanner"
+}
+
+func (Spanner) Quote(identifier string) string {
+	return "`" + identifier + "`"
+}
+
+// ReplacePlaceholders replaces the placeholders with named parameters (@p1,
+// @p2, ...).
+func (Spanner) ReplacePlaceholders(originalPlaceholder string, sql string) string {
+	sqlBuffer := bytes.NewBuffer(make([]byte, 0, len(sql)))
+	count := 1
+	for {
+		pp := strings.Index(sql, originalPlaceholder)
+		if pp == -1 {
+			break
+		}
+		sqlBuffer.WriteString(sql[:pp])
+		sqlBuffer.WriteString("@p")
+		sqlBuffer.WriteString(strconv.Itoa(count))
+		count++
+		sql = sql[pp+len(originalPlaceholder):]
+	}
+	sqlBuffer.WriteString(sql)
+	return sqlBuffer.String()
+}
+
+// ReturningBuild uses the THEN RETURN clause of the DML statements.
+func (Spanner) ReturningBuild(columns []string) string {
+	return "THEN RETURN " + strings.Join(columns, ", ")
+}
+
+func (s Spanner) FormatForNewValues(columns []string) []string {
+	formatedColumns := make([]string, 0, len(columns))
+	for _, column := range columns {
+		formatedColumns = append(formatedColumns, s.Quote(column))
+	}
+	return formatedColumns
+}
+
+// GetReturningPosition puts THEN RETURN at the end of the statements, like
+// the RETURNING clause of PostgreSQL.
+func (Spanner) GetReturningPosition() adapters.ReturningPosition {
+	return adapters.ReturningPostgreSQL
+}
+
+// SupportsLastInsertID is always false, the ids are read with THEN RETURN.
+func (Spanner) SupportsLastInsertID() bool {
+	return false
+}
+
+// ParseError recognizes the existing rows and the violated foreign keys from
+// the messages of the errors, the driver giving gRPC status errors.
+func (Spanner) ParseError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "AlreadyExists") && strings.Contains(message, "in table "):
+		return dberror.UniqueConstraint{Message: message, Field: dberror.ExtractStr(message, "in table ", " already exists"), Err: err}
+	case strings.Contains(message, "Foreign key constraint `"):
+		return dberror.ForeignKeyConstraint{Message: message, Field: dberror.ExtractStr(message, "constraint `", "`"), Err: err}
+	}
+	return err
+}
+
+// FormatLiteral escapes the quotes and backslashes of the strings with a
+// backslash, and writes the booleans, bytes and timestamps of Spanner.
+func (Spanner) FormatLiteral(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v)
+		return "'" + escaped + "'", true
+	case bool:
+		if v {
+			return "TRUE", true
+		}
+		return "FALSE", true
+	case []byte:
+		return "FROM_HEX('" + hex.EncodeToString(v) + "')", true
+	case time.Time:
+		return "TIMESTAMP '" + v.Format(time.RFC3339Nano) + "'", true
+	}
+	return "", false
+}
+
+// BuildFunction writes NOW as CURRENT_TIMESTAMP().
+func (Spanner) BuildFunction(name string, args []string) (string, bool) {
+	if name == "NOW" && len(args) == 0 {
+		return "CURRENT_TIMESTAMP()", true
+	}
+	return "", false
+}
@@ -0,0 +1,52 @@
+package spanner
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samonzeweb/godb/dberror"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReplacePlaceholders(t *testing.T) {
+	Convey("ReplacePlaceholders replaces the placeholders with named parameters", t, func() {
+		sql := "UPDATE `Singers` SET `Name` = ? WHERE `SingerId` = ?"
+		So(Adapter.ReplacePlaceholders("?", sql), ShouldEqual, "UPDATE `Singers` SET `Name` = @p1 WHERE `SingerId` = @p2")
+	})
+}
+
+func TestReturningBuild(t *testing.T) {
+	Convey("ReturningBuild uses THEN RETURN", t, func() {
+		columns := Adapter.FormatForNewValues([]string{"SingerId", "Version"})
+		So(Adapter.ReturningBuild(columns), ShouldEqual, "THEN RETURN `SingerId`, `Version`")
+	})
+}
+
+func TestParseError(t *testing.T) {
+	Convey("ParseError recognizes the errors of the constraints", t, func() {
+		err := Adapter.ParseError(errors.New(`spanner: code = "AlreadyExists", desc = "Row [1] in table Singers already exists"`))
+		uniqueConstraint, ok := err.(dberror.UniqueConstraint)
+		So(ok, ShouldBeTrue)
+		So(uniqueConstraint.Field, ShouldEqual, "Singers")
+
+		err = Adapter.ParseError(errors.New("spanner: code = \"FailedPrecondition\", desc = \"Foreign key constraint `FK_Albums_Singers` is violated on table `Albums`.\""))
+		foreignKeyConstraint, ok := err.(dberror.ForeignKeyConstraint)
+		So(ok, ShouldBeTrue)
+		So(foreignKeyConstraint.Field, ShouldEqual, "FK_Albums_Singers")
+
+		other := errors.New(`spanner: code = "Aborted"`)
+		So(Adapter.ParseError(other), ShouldEqual, other)
+	})
+}
+
+func TestFormatLiteral(t *testing.T) {
+	Convey("FormatLiteral writes the literals of Spanner", t, func() {
+		literal, ok := Adapter.FormatLiteral(`it's`)
+		So(ok, ShouldBeTrue)
+		So(literal, ShouldEqual, `'it\'s'`)
+		literal, ok = Adapter.FormatLiteral(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))
+		So(ok, ShouldBeTrue)
+		So(literal, ShouldEqual, "TIMESTAMP '2021-03-04T05:06:07Z'")
+	})
+}
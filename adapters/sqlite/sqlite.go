@@ -3,6 +3,7 @@ package sqlite
 import (
 	"strings"
 
+	"github.com/samonzeweb/godb/adapters"
 	"github.com/samonzeweb/godb/dberror"
 
 	sqlite3 "github.com/mattn/go-sqlite3"
@@ -12,6 +13,10 @@ type SQLite struct{}
 
 var Adapter = SQLite{}
 
+func init() {
+	adapters.Register("sqlite", func() adapters.Adapter { return Adapter })
+}
+
 func (SQLite) DriverName() string {
 	return "sqlite3"
 }
@@ -20,6 +25,11 @@ func (SQLite) Quote(identifier string) string {
 	return "\"" + identifier + "\""
 }
 
+// BuildJSONPath uses the json_extract function of the JSON1 extension.
+func (SQLite) BuildJSONPath(column string, path string) string {
+	return "json_extract(" + column + ", '" + path + "')"
+}
+
 func (SQLite) ParseError(err error) error {
 	if err == nil {
 		return nil
@@ -28,10 +38,89 @@ func (SQLite) ParseError(err error) error {
 	e, _ := err.(sqlite3.Error)
 	switch e.ExtendedCode {
 	case sqlite3.ErrConstraintUnique:
-		return dberror.UniqueConstraint{Message: e.Error(), Field: strings.Split(strings.Split(e.Error(), "failed: ")[1], ".")[1], Err: e}
+		return dberror.UniqueConstraint{Message: e.Error(), Field: constraintField(e.Error()), Err: e}
 	case sqlite3.ErrConstraintCheck:
-		return dberror.CheckConstraint{Message: e.Error(), Field: strings.Split(strings.Split(e.Error(), "failed: ")[1], ".")[1], Err: e}
+		return dberror.CheckConstraint{Message: e.Error(), Field: constraintField(e.Error()), Err: e}
 	default:
 		return err
 	}
 }
+
+// constraintField returns the column of a constraint error message, like
+// "UNIQUE constraint failed: table.column", or an empty string if the
+// constraint is not on a column (an index on an expression for example).
+func constraintField(message string) string {
+	parts := strings.SplitN(message, "failed: ", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+	names := strings.SplitN(parts[1], ".", 2)
+	if len(names) != 2 {
+		return ""
+	}
+	return names[1]
+}
+
+// RecursiveWith uses WITH RECURSIVE (SQLite 3.8.3 or later).
+func (SQLite) RecursiveWith() string {
+	return "WITH RECURSIVE"
+}
+
+// IgnoreConflictsClause uses ON CONFLICT DO NOTHING (SQLite 3.24 or
+// later).
+func (SQLite) IgnoreConflictsClause() string {
+	return "ON CONFLICT DO NOTHING"
+}
+
+// BuildCreateTempTable copies the columns of the source table, without
+// their constraints.
+func (SQLite) BuildCreateTempTable(name string, source string) string {
+	return "CREATE TEMPORARY TABLE " + name + " AS SELECT * FROM " + source + " WHERE 1 = 0"
+}
+
+// BuildDropTempTable uses DROP TABLE.
+func (SQLite) BuildDropTempTable(name string) string {
+	return "DROP TABLE " + name
+}
+
+// BuildFunction uses CURRENT_TIMESTAMP for NOW, and the || operator for
+// CONCAT.
+func (SQLite) BuildFunction(name string, args []string) (string, bool) {
+	switch name {
+	case "NOW":
+		return "CURRENT_TIMESTAMP", true
+	case "CONCAT":
+		return "(" + strings.Join(args, " || ") + ")", true
+	}
+	return "", false
+}
+
+// dateTruncFormats are the formats of strftime keeping a date up to a unit.
+var dateTruncFormats = map[string]string{
+	"second": "%Y-%m-%d %H:%M:%S",
+	"minute": "%Y-%m-%d %H:%M:00",
+	"hour":   "%Y-%m-%d %H:00:00",
+	"day":    "%Y-%m-%d 00:00:00",
+	"month":  "%Y-%m-01 00:00:00",
+	"year":   "%Y-01-01 00:00:00",
+}
+
+// BuildDateAdd uses a modifier of the datetime function, the date is
+// returned as text.
+func (SQLite) BuildDateAdd(column string, amount string, unit string) string {
+	return "datetime(" + column + ", " + amount + " || ' " + unit + "s')"
+}
+
+// BuildDateTrunc uses strftime, the date is returned as text.
+func (SQLite) BuildDateTrunc(unit string, column string) string {
+	return "strftime('" + dateTruncFormats[unit] + "', " + column + ")"
+}
+
+// BuildStringAgg uses GROUP_CONCAT, its ORDER BY clause needs SQLite 3.44
+// or later.
+func (SQLite) BuildStringAgg(column string, separator string, orderBy string) string {
+	if orderBy != "" {
+		separator += " ORDER BY " + orderBy
+	}
+	return "GROUP_CONCAT(" + column + ", " + separator + ")"
+}
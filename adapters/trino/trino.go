@@ -0,0 +1,110 @@
+package trino
+
+import (
+	"strings"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// Trino is the adapter of the Trino (and Presto) query engine, used to query
+// federated sources with the select builder and the struct tools. It's read
+// only : the statements changing data are rejected by godb. The driver
+// (github.com/trinodb/trino-go-client) has to be imported by the
+// application.
+type Trino struct{}
+
+var Adapter = Trino{}
+
+func init() {
+	adapters.Register("trino", func() adapters.Adapter { return Adapter })
+}
+
+func (Trino) DriverName() string {
+	return "trino"
+}
+
+func (Trino) Quote(identifier string) string {
+	return "\"" + identifier + "\""
+}
+
+// ParseError returns the error as is, the errors of the driver don't tell
+// which constraint failed.
+func (Trino) ParseError(err error) error {
+	return err
+}
+
+// IsReadOnly is always true, the writes are left to the federated sources.
+func (Trino) IsReadOnly() bool {
+	return true
+}
+
+// PaginationStyle uses OFFSET before LIMIT.
+func (Trino) PaginationStyle() adapters.PaginationStyle {
+	return adapters.OffsetLimit
+}
+
+// FormatLiteral writes the booleans as TRUE and FALSE, and the times as
+// timestamps with time zone.
+func (Trino) FormatLiteral(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return "TRUE", true
+		}
+		return "FALSE", true
+	case time.Time:
+		return "TIMESTAMP '" + v.Format("2006-01-02 15:04:05.999999999 -07:00") + "'", true
+	}
+	return "", false
+}
+
+// BuildValuesList uses a VALUES list, its columns being named by the alias
+// of the list.
+func (Trino) BuildValuesList(rows []string, columns []string) string {
+	return "(SELECT * FROM (VALUES " + strings.Join(rows, ", ") + ") AS godb_values (" + strings.Join(columns, ", ") + "))"
+}
+
+// BuildDateAdd uses the date_add function.
+func (Trino) BuildDateAdd(column string, amount string, unit string) string {
+	return "date_add('" + unit + "', " + amount + ", " + column + ")"
+}
+
+// BuildDateTrunc uses the date_trunc function.
+func (Trino) BuildDateTrunc(unit string, column string) string {
+	return "date_trunc('" + unit + "', " + column + ")"
+}
+
+// BuildStringAgg uses LISTAGG, which needs an order : the values are
+// ordered by themselves by default.
+func (Trino) BuildStringAgg(column string, separator string, orderBy string) string {
+	if orderBy == "" {
+		orderBy = column
+	}
+	return "LISTAGG(" + column + ", " + separator + ") WITHIN GROUP (ORDER BY " + orderBy + ")"
+}
+
+// BuildArrayAgg uses ARRAY_AGG.
+func (Trino) BuildArrayAgg(column string, orderBy string) string {
+	if orderBy != "" {
+		column += " ORDER BY " + orderBy
+	}
+	return "ARRAY_AGG(" + column + ")"
+}
+
+// BuildStatisticalAggregate uses STDDEV_SAMP and VAR_SAMP, the percentiles
+// being only approximated by Trino (approx_percentile).
+func (Trino) BuildStatisticalAggregate(name string, column string, fraction string) string {
+	switch name {
+	case "STDDEV":
+		return "STDDEV_SAMP(" + column + ")"
+	case "VARIANCE":
+		return "VAR_SAMP(" + column + ")"
+	}
+	return ""
+}
+
+// RecursiveWith uses WITH RECURSIVE.
+func (Trino) RecursiveWith() string {
+	return "WITH RECURSIVE"
+}
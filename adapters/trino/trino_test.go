@@ -0,0 +1,28 @@
+package trino
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFormatLiteral(t *testing.T) {
+	Convey("FormatLiteral writes the booleans and timestamps of Trino", t, func() {
+		literal, ok := Adapter.FormatLiteral(false)
+		So(ok, ShouldBeTrue)
+		So(literal, ShouldEqual, "FALSE")
+		literal, ok = Adapter.FormatLiteral(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))
+		So(ok, ShouldBeTrue)
+		So(literal, ShouldEqual, "TIMESTAMP '2021-03-04 05:06:07 +00:00'")
+		_, ok = Adapter.FormatLiteral("text")
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func TestBuildStringAgg(t *testing.T) {
+	Convey("BuildStringAgg uses LISTAGG, always ordered", t, func() {
+		So(Adapter.BuildStringAgg(`"title"`, "','", ""), ShouldEqual, `LISTAGG("title", ',') WITHIN GROUP (ORDER BY "title")`)
+		So(Adapter.BuildStringAgg(`"title"`, "','", "id"), ShouldEqual, `LISTAGG("title", ',') WITHIN GROUP (ORDER BY id)`)
+	})
+}
@@ -0,0 +1,122 @@
+package godb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// aggregateExpression is an aggregate function of a column, see StringAgg,
+// ArrayAgg and the statistical aggregates.
+type aggregateExpression struct {
+	name    string
+	column  string
+	orderBy string
+	// separator is the separator of the values, for StringAgg
+	separator string
+	// fraction is the percentile, for PercentileCont and PercentileDisc
+	fraction float64
+}
+
+// StringAgg returns an aggregate expression concatenating the values of the
+// given column, separated by the given separator, rendered according to the
+// adapter (STRING_AGG or GROUP_CONCAT, see adapters.StringAggBuilder). The
+// column is checked and quoted like Ident, the values are ordered by the
+// given expression (like OrderBy), or not ordered if it's empty. The
+// separator is written as a string literal.
+//
+// Example :
+// 	err := db.SelectFrom("books").
+// 		ColumnsExpr(godb.Ident("author_id"), godb.StringAgg("title", ", ", "title")).
+// 		GroupBy("author_id").
+// 		Do(&titlesByAuthor)
+func StringAgg(column string, separator string, orderBy string) Expression {
+	return &aggregateExpression{name: "STRING_AGG", column: column, orderBy: orderBy, separator: separator}
+}
+
+// ArrayAgg returns an aggregate expression collecting the values of the
+// given column into an array, ordered by the given expression (like OrderBy)
+// if not empty. It's available with PostgreSQL (see adapters.ArrayAggBuilder).
+func ArrayAgg(column string, orderBy string) Expression {
+	return &aggregateExpression{name: "ARRAY_AGG", column: column, orderBy: orderBy}
+}
+
+// PercentileCont returns an aggregate expression computing the given
+// percentile (a fraction between 0 and 1, 0.5 for the median) of the values
+// of the given column, interpolated between the values if needed. It's
+// available with PostgreSQL (see adapters.StatisticalAggregateBuilder), the
+// others adapters return an error.
+//
+// Example :
+// 	err := db.SelectFrom("requests").
+// 		ColumnsExpr(godb.Ident("route"), godb.PercentileCont(0.95, "duration")).
+// 		GroupBy("route").
+// 		Do(&latencies)
+func PercentileCont(fraction float64, column string) Expression {
+	return &aggregateExpression{name: "PERCENTILE_CONT", column: column, fraction: fraction}
+}
+
+// PercentileDisc is like PercentileCont, but returns the first value whose
+// position is at least the given percentile, without interpolation.
+func PercentileDisc(fraction float64, column string) Expression {
+	return &aggregateExpression{name: "PERCENTILE_DISC", column: column, fraction: fraction}
+}
+
+// StdDev returns an aggregate expression computing the standard deviation of
+// a sample, the values of the given column. It's available with PostgreSQL,
+// MySQL and SQL Server (see adapters.StatisticalAggregateBuilder), not with
+// SQLite.
+func StdDev(column string) Expression {
+	return &aggregateExpression{name: "STDDEV", column: column}
+}
+
+// Variance returns an aggregate expression computing the variance of a
+// sample, the values of the given column. It's available like StdDev.
+func Variance(column string) Expression {
+	return &aggregateExpression{name: "VARIANCE", column: column}
+}
+
+// BuildSQL renders the aggregate function for the given adapter.
+func (ae *aggregateExpression) BuildSQL(adapter adapters.Adapter) (string, []interface{}, error) {
+	column, _, err := Ident(ae.column).BuildSQL(adapter)
+	if err != nil {
+		return "", nil, err
+	}
+	orderBy := strings.TrimSpace(ae.orderBy)
+
+	switch ae.name {
+	case "STRING_AGG":
+		builder, ok := adapter.(adapters.StringAggBuilder)
+		if !ok {
+			return "", nil, fmt.Errorf("the adapter does not support string aggregation")
+		}
+		separator, err := formatLiteral(adapter, ae.separator)
+		if err != nil {
+			return "", nil, err
+		}
+		return builder.BuildStringAgg(column, separator, orderBy), nil, nil
+	case "ARRAY_AGG":
+		builder, ok := adapter.(adapters.ArrayAggBuilder)
+		if !ok {
+			return "", nil, fmt.Errorf("the adapter does not support array aggregation")
+		}
+		return builder.BuildArrayAgg(column, orderBy), nil, nil
+	}
+
+	var args []interface{}
+	if ae.name == "PERCENTILE_CONT" || ae.name == "PERCENTILE_DISC" {
+		if ae.fraction < 0 || ae.fraction > 1 {
+			return "", nil, fmt.Errorf("invalid percentile %v, it has to be between 0 and 1", ae.fraction)
+		}
+		args = []interface{}{ae.fraction}
+	}
+	sql := ""
+	if builder, ok := adapter.(adapters.StatisticalAggregateBuilder); ok {
+		sql = builder.BuildStatisticalAggregate(ae.name, column, Placeholder)
+	}
+	if sql == "" {
+		return "", nil, fmt.Errorf("the adapter does not support the %s aggregate", ae.name)
+	}
+	return sql, args, nil
+}
@@ -0,0 +1,108 @@
+package godb
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/mssql"
+	"github.com/samonzeweb/godb/adapters/mysql"
+	"github.com/samonzeweb/godb/adapters/postgresql"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAggregates(t *testing.T) {
+	Convey("StringAgg is rendered according to the adapter", t, func() {
+		sql, args, err := StringAgg("title", ", ", "title DESC").BuildSQL(postgresql.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, `STRING_AGG("title", ', ' ORDER BY title DESC)`)
+		So(args, ShouldBeEmpty)
+
+		sql, _, err = StringAgg("title", "'\\", "id").BuildSQL(mysql.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, "GROUP_CONCAT(`title` ORDER BY id SEPARATOR '''\\\\')")
+
+		sql, _, err = StringAgg("title", ",", "").BuildSQL(sqlite.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, `GROUP_CONCAT("title", ',')`)
+
+		sql, _, err = StringAgg("title", ",", "id").BuildSQL(mssql.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, "STRING_AGG([title], N',') WITHIN GROUP (ORDER BY id)")
+	})
+
+	Convey("ArrayAgg is available with PostgreSQL", t, func() {
+		sql, _, err := ArrayAgg("books.id", "books.id").BuildSQL(postgresql.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, `ARRAY_AGG("books"."id" ORDER BY books.id)`)
+
+		_, _, err = ArrayAgg("id", "").BuildSQL(sqlite.Adapter)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("The invalid columns are rejected", t, func() {
+		_, _, err := StringAgg("title, id", ",", "").BuildSQL(postgresql.Adapter)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("The values are aggregated by the database", func() {
+			var texts string
+			err := db.SelectFrom("dummies").
+				ColumnsExpr(StringAgg("a_text", "|", "")).
+				Scanx(&texts)
+			So(err, ShouldBeNil)
+			values := strings.Split(texts, "|")
+			sort.Strings(values)
+			So(values, ShouldResemble, []string{"First", "Second", "Third"})
+		})
+	})
+}
+
+func TestStatisticalAggregates(t *testing.T) {
+	Convey("The percentiles are ordered-set aggregates with PostgreSQL", t, func() {
+		sql, args, err := PercentileCont(0.95, "duration").BuildSQL(postgresql.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, `PERCENTILE_CONT(?) WITHIN GROUP (ORDER BY "duration")`)
+		So(args, ShouldResemble, []interface{}{0.95})
+
+		sql, _, err = PercentileDisc(0.5, "duration").BuildSQL(postgresql.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, `PERCENTILE_DISC(?) WITHIN GROUP (ORDER BY "duration")`)
+
+		_, _, err = PercentileCont(1.5, "duration").BuildSQL(postgresql.Adapter)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("The standard deviation and the variance are rendered according to the adapter", t, func() {
+		sql, args, err := StdDev("duration").BuildSQL(postgresql.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, `STDDEV_SAMP("duration")`)
+		So(args, ShouldBeEmpty)
+
+		sql, _, err = Variance("duration").BuildSQL(mysql.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, "VAR_SAMP(`duration`)")
+
+		sql, _, err = StdDev("duration").BuildSQL(mssql.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, "STDEV([duration])")
+	})
+
+	Convey("The unsupported aggregates return an error", t, func() {
+		_, _, err := PercentileCont(0.5, "duration").BuildSQL(mysql.Adapter)
+		So(err, ShouldNotBeNil)
+		_, _, err = PercentileDisc(0.5, "duration").BuildSQL(mssql.Adapter)
+		So(err, ShouldNotBeNil)
+		_, _, err = StdDev("duration").BuildSQL(sqlite.Adapter)
+		So(err, ShouldNotBeNil)
+
+		db := &DB{adapter: sqlite.Adapter}
+		_, _, err = db.SelectFrom("requests").ColumnsExpr(Variance("duration")).ToSQL()
+		So(err, ShouldNotBeNil)
+	})
+}
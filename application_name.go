@@ -0,0 +1,47 @@
+package godb
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// SetApplicationName names the application in the sessions shown by the
+// server (application_name with PostgreSQL, program_name with SQL Server),
+// so the sessions of a service are identifiable by the monitoring. The name
+// is added to the data source, the adapter has to implement
+// adapters.ApplicationNamer.
+//
+// The DB has to be created by Open. Its sql.DB is replaced, call
+// SetApplicationName before SetSessionSetup, before configuring the
+// connections pool, and before cloning the DB.
+//
+// Example :
+// 	err := db.SetApplicationName("billing-worker")
+func (db *DB) SetApplicationName(name string) error {
+	namer, ok := db.adapter.(adapters.ApplicationNamer)
+	if !ok {
+		return fmt.Errorf("the adapter does not support application names")
+	}
+	if db.sqlTx != nil {
+		return fmt.Errorf("SetApplicationName can't be called in a transaction")
+	}
+	if db.dataSourceName == "" || db.failover != nil || db.connector != nil {
+		return fmt.Errorf("SetApplicationName needs a DB created by Open")
+	}
+
+	dataSourceName := namer.DataSourceWithApplicationName(db.dataSourceName, name)
+	sqlDB, err := sql.Open(db.adapter.DriverName(), dataSourceName)
+	if err != nil {
+		return err
+	}
+	if err := db.stmtCacheDB.Clear(); err != nil {
+		sqlDB.Close()
+		return err
+	}
+	previous := db.sqlDB
+	db.sqlDB = sqlDB
+	db.dataSourceName = dataSourceName
+	return previous.Close()
+}
@@ -0,0 +1,47 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// namingAdapter adds the application name to the data source of SQLite as
+// an unused parameter.
+type namingAdapter struct {
+	adapters.Adapter
+}
+
+func (namingAdapter) DataSourceWithApplicationName(dataSourceName string, name string) string {
+	return dataSourceName + "&application=" + name
+}
+
+func TestSetApplicationName(t *testing.T) {
+	Convey("Given a DB whose adapter supports the application names", t, func() {
+		db, err := Open(namingAdapter{sqlite.Adapter}, "file:application_name?mode=memory")
+		So(err, ShouldBeNil)
+		defer db.Close()
+
+		Convey("SetApplicationName reopens the DB with the name in its data source", func() {
+			previous := db.CurrentDB()
+			So(db.SetApplicationName("worker"), ShouldBeNil)
+			So(db.dataSourceName, ShouldEqual, "file:application_name?mode=memory&application=worker")
+			So(db.CurrentDB(), ShouldNotEqual, previous)
+			So(db.CurrentDB().Ping(), ShouldBeNil)
+		})
+
+		Convey("SetApplicationName fails in a transaction", func() {
+			So(db.Begin(), ShouldBeNil)
+			defer db.Rollback()
+			So(db.SetApplicationName("worker"), ShouldNotBeNil)
+		})
+	})
+
+	Convey("SetApplicationName needs an adapter supporting it", t, func() {
+		db := createInMemoryConnection(t)
+		defer db.Close()
+		So(db.SetApplicationName("worker"), ShouldNotBeNil)
+	})
+}
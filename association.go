@@ -0,0 +1,537 @@
+package godb
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/samonzeweb/godb/tablenamer"
+)
+
+// associationTagName is the name of the struct tag declaring a relation.
+const associationTagName = "rel"
+
+// Kinds of relations.
+const (
+	hasManyRelation    = "hasmany"
+	manyToManyRelation = "manytomany"
+)
+
+// association describes a relation declared with the rel tag.
+type association struct {
+	foreignKey    string
+	deleteOrphans bool
+	joinTable     string
+	joinKey       string
+	targetKey     string
+}
+
+// Association manages the records related to an owner by a relation declared
+// on a slice field of its struct, with the rel tag. The first value of the
+// tag is the kind of relation :
+//   * hasmany : the related records have a column referencing the owner key,
+//     given by the foreignkey option (<owner struct in snake case>_id by
+//     default). The removed records get a NULL foreign key, or are deleted
+//     with the orphans=delete option.
+//   * manytomany : the records are related through the join table given by
+//     the join option. Its joinkey column references the owner key, and its
+//     targetkey column the related record key (by default the struct names
+//     in snake case, with the _id suffix). The others columns of the join
+//     table can be mapped to an edge struct, see Edge and Edges.
+//
+// The field has to be ignored by godb with db:"-". The owner and related
+// structs must have a single key, and the owner must already be inserted.
+// The new related records (having a zero auto key) are inserted.
+//
+// The changes are done in a transaction if there is none, and the field of
+// the owner is updated once they succeed. It's not loaded by godb (see
+// Load), the related records it already contains are not checked.
+//
+// Example :
+// 	type Book struct {
+// 		ID       int        `db:"id,key,auto"`
+// 		Chapters []Chapter  `db:"-" rel:"hasmany,foreignkey=book_id"`
+// 		Tags     []*Tag     `db:"-" rel:"manytomany,join=book_tags"`
+// 	}
+//
+// 	err := db.Association(&book, "Chapters").Append(&chapter)
+// 	err = db.Association(&book, "Tags").Replace(&tag1, &tag2)
+// 	err = db.Association(&book, "Tags").Clear()
+type Association struct {
+	db                *DB
+	error             error
+	ownerDescription  *recordDescription
+	ownerKey          interface{}
+	field             reflect.Value
+	fieldIndex        []int
+	association       association
+	targetType        reflect.Type
+	targetDescription *recordDescription
+	edge              interface{}
+}
+
+// Association returns the Association of the given owner (a struct pointer)
+// for the relation declared on the named field.
+func (db *DB) Association(owner interface{}, fieldName string) *Association {
+	a := &Association{db: db}
+	a.error = a.init(owner, fieldName)
+	return a
+}
+
+// init checks the owner and the relation, see Association.
+func (a *Association) init(owner interface{}, fieldName string) error {
+	var err error
+	a.ownerDescription, err = buildRecordDescription(owner)
+	if err != nil {
+		return err
+	}
+	if a.ownerDescription.isSlice {
+		return fmt.Errorf("Association accept only a single instance, got a slice")
+	}
+
+	structField, ok := a.ownerDescription.instanceType.FieldByName(fieldName)
+	if !ok {
+		return fmt.Errorf("the struct %s has no field %s", a.ownerDescription.instanceType.Name(), fieldName)
+	}
+	if structField.Type.Kind() != reflect.Slice {
+		return fmt.Errorf("the field %s is not a slice", fieldName)
+	}
+	a.targetType = structField.Type.Elem()
+	if a.targetType.Kind() == reflect.Ptr {
+		a.targetType = a.targetType.Elem()
+	}
+	if a.targetType.Kind() != reflect.Struct {
+		return fmt.Errorf("the field %s is not a slice of structs", fieldName)
+	}
+	a.fieldIndex = structField.Index
+	a.field = reflect.ValueOf(owner).Elem().FieldByIndex(structField.Index)
+	a.targetDescription, err = buildRecordDescription(reflect.New(a.targetType).Interface())
+	if err != nil {
+		return err
+	}
+
+	a.association, err = parseAssociationTag(structField, a.ownerDescription.instanceType, a.targetType)
+	if err != nil {
+		return err
+	}
+
+	ownerKeyValues := a.ownerDescription.structMapping.GetKeyFieldsValues(owner)
+	if len(ownerKeyValues) != 1 {
+		return fmt.Errorf("the struct %s must have a single key", a.ownerDescription.instanceType.Name())
+	}
+	if len(a.targetDescription.structMapping.GetKeyColumnsNames()) != 1 {
+		return fmt.Errorf("the struct %s must have a single key", a.targetType.Name())
+	}
+	a.ownerKey = ownerKeyValues[0]
+	if isZeroValue(a.ownerKey) {
+		return fmt.Errorf("the owner has no key value, insert it first")
+	}
+	return nil
+}
+
+// parseAssociationTag parses the rel tag of the given field, relating the
+// owner and target types.
+func parseAssociationTag(structField reflect.StructField, ownerType reflect.Type, targetType reflect.Type) (association, error) {
+	tag, ok := structField.Tag.Lookup(associationTagName)
+	if !ok {
+		return association{}, fmt.Errorf("the field %s has no %s tag", structField.Name, associationTagName)
+	}
+
+	tagContent := strings.Split(tag, ",")
+	kind := strings.TrimSpace(tagContent[0])
+	options := make(map[string]string)
+	for _, option := range tagContent[1:] {
+		parts := strings.SplitN(strings.TrimSpace(option), "=", 2)
+		if len(parts) != 2 {
+			return association{}, fmt.Errorf("invalid option %q in the %s tag of %s", option, associationTagName, structField.Name)
+		}
+		options[parts[0]] = parts[1]
+	}
+
+	var assoc association
+	var allowed []string
+	switch kind {
+	case hasManyRelation:
+		allowed = []string{"foreignkey", "orphans"}
+		assoc.foreignKey = optionOrDefault(options, "foreignkey", relationKeyName(ownerType))
+		if orphans, ok := options["orphans"]; ok {
+			if orphans != "delete" {
+				return association{}, fmt.Errorf("invalid orphans option %q of %s", orphans, structField.Name)
+			}
+			assoc.deleteOrphans = true
+		}
+	case manyToManyRelation:
+		allowed = []string{"join", "joinkey", "targetkey"}
+		assoc.joinTable = options["join"]
+		if assoc.joinTable == "" {
+			return association{}, fmt.Errorf("the relation %s needs a join table", structField.Name)
+		}
+		assoc.joinKey = optionOrDefault(options, "joinkey", relationKeyName(ownerType))
+		assoc.targetKey = optionOrDefault(options, "targetkey", relationKeyName(targetType))
+	default:
+		return association{}, fmt.Errorf("unknown relation kind %q of %s", kind, structField.Name)
+	}
+
+	for option := range options {
+		if indexOfString(allowed, option) < 0 {
+			return association{}, fmt.Errorf("unknown option %q in the %s tag of %s", option, associationTagName, structField.Name)
+		}
+	}
+	return assoc, nil
+}
+
+// optionOrDefault returns the given option, or the default value if it's
+// not set.
+func optionOrDefault(options map[string]string, name string, defaultValue string) string {
+	if value := options[name]; value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// relationKeyName returns the default name of a column referencing the
+// given struct.
+func relationKeyName(structType reflect.Type) string {
+	return tablenamer.ToSnakeCase(structType.Name()) + "_id"
+}
+
+// Edge sets the struct (a pointer) giving the values of the others columns
+// of the join table, for the rows inserted or already existing of the next
+// changes. Its columns relating the records are ignored, and it has to be
+// mapped only to columns of the join table.
+//
+// Example :
+// 	type BookTag struct {
+// 		TagID    int `db:"tag_id"`
+// 		Position int `db:"position"`
+// 	}
+//
+// 	err := db.Association(&book, "Tags").Edge(&BookTag{Position: 1}).Append(&tag)
+func (a *Association) Edge(edge interface{}) *Association {
+	if a.error != nil {
+		return a
+	}
+	if a.association.joinTable == "" {
+		a.error = fmt.Errorf("only the manytomany relations have edges")
+		return a
+	}
+	a.edge = edge
+	return a
+}
+
+// Edges fetches the rows of the join table relating the owner into the
+// given slice of edge structs, see Edge.
+func (a *Association) Edges(edges interface{}) error {
+	if a.error != nil {
+		return a.error
+	}
+	if a.association.joinTable == "" {
+		return fmt.Errorf("only the manytomany relations have edges")
+	}
+	db := a.db
+	return db.SelectFrom(db.quote(a.association.joinTable)).
+		ColumnsFromStruct(edges).
+		Where(db.quote(a.association.joinKey)+" = ?", a.ownerKey).
+		Do(edges)
+}
+
+// Load fetches the related records into the field of the owner, replacing
+// its content.
+func (a *Association) Load() error {
+	if a.error != nil {
+		return a.error
+	}
+
+	db := a.db
+	var condition *Condition
+	if a.association.joinTable == "" {
+		condition = Q(db.quoteFor(a.targetDescription, a.association.foreignKey)+" = ?", a.ownerKey)
+	} else {
+		keyColumn := a.targetDescription.structMapping.GetKeyColumnsNames()[0]
+		condition = Q(
+			db.quoteFor(a.targetDescription, keyColumn)+" IN (SELECT "+db.quote(a.association.targetKey)+
+				" FROM "+db.quote(a.association.joinTable)+" WHERE "+db.quote(a.association.joinKey)+" = ?)",
+			a.ownerKey,
+		)
+	}
+
+	records := reflect.New(a.field.Type())
+	records.Elem().Set(reflect.MakeSlice(a.field.Type(), 0, 0))
+	if err := db.Select(records.Interface()).WhereQ(condition).Do(); err != nil {
+		return err
+	}
+	a.field.Set(records.Elem())
+	a.cacheField(true)
+	return nil
+}
+
+// Append relates the given records (struct pointers) to the owner, and
+// appends them to its field.
+func (a *Association) Append(records ...interface{}) error {
+	if a.error != nil {
+		return a.error
+	}
+	err := a.inTransaction(func() error {
+		return a.append(records)
+	})
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		a.field.Set(reflect.Append(a.field, a.fieldElement(record)))
+	}
+	a.cacheField(false)
+	return nil
+}
+
+// Replace relates the given records (struct pointers) to the owner, and
+// unrelates the others. The field of the owner is set to the given records.
+func (a *Association) Replace(records ...interface{}) error {
+	if a.error != nil {
+		return a.error
+	}
+	err := a.inTransaction(func() error {
+		if err := a.append(records); err != nil {
+			return err
+		}
+		return a.removeOthers(records)
+	})
+	if err != nil {
+		return err
+	}
+	field := reflect.MakeSlice(a.field.Type(), 0, len(records))
+	for _, record := range records {
+		field = reflect.Append(field, a.fieldElement(record))
+	}
+	a.field.Set(field)
+	a.cacheField(true)
+	return nil
+}
+
+// Clear unrelates all the records of the owner, and empties its field.
+func (a *Association) Clear() error {
+	return a.Replace()
+}
+
+// inTransaction calls the given function in a transaction if there is none.
+func (a *Association) inTransaction(f func() error) error {
+	db := a.db
+	ownTx := db.sqlTx == nil
+	if ownTx {
+		if err := db.Begin(); err != nil {
+			return err
+		}
+	}
+
+	if err := f(); err != nil {
+		if ownTx {
+			db.Rollback()
+		}
+		return err
+	}
+	if ownTx {
+		return db.Commit()
+	}
+	return nil
+}
+
+// append relates the given records to the owner, inserting the new ones.
+func (a *Association) append(records []interface{}) error {
+	db := a.db
+	for _, record := range records {
+		if err := a.checkRecord(record); err != nil {
+			return err
+		}
+
+		if a.association.joinTable == "" {
+			if err := a.setForeignKey(record); err != nil {
+				return err
+			}
+			if a.isNew(record) {
+				if err := db.Insert(record).Do(); err != nil {
+					return err
+				}
+				continue
+			}
+			columns := []string{a.association.foreignKey}
+			if opLockColumn := a.targetDescription.structMapping.GetOpLockSQLFieldName(); opLockColumn != "" {
+				columns = append(columns, opLockColumn)
+			}
+			if err := db.Update(record).Whitelist(columns...).Do(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if a.isNew(record) {
+			if err := db.Insert(record).Do(); err != nil {
+				return err
+			}
+		}
+		if err := a.link(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// link inserts the row of the join table relating the owner and the given
+// record if it doesn't exist, or updates its edge columns.
+func (a *Association) link(record interface{}) error {
+	db := a.db
+	joinTable := db.quote(a.association.joinTable)
+	joinKey := db.quote(a.association.joinKey)
+	targetKey := db.quote(a.association.targetKey)
+	recordKey := a.targetDescription.structMapping.GetKeyFieldsValues(record)[0]
+	edgeColumns, edgeValues, err := a.edgeColumnsAndValues()
+	if err != nil {
+		return err
+	}
+
+	count, err := db.SelectFrom(joinTable).
+		Where(joinKey+" = ? AND "+targetKey+" = ?", a.ownerKey, recordKey).
+		Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		if len(edgeColumns) == 0 {
+			return nil
+		}
+		update := db.UpdateTable(joinTable)
+		for i, column := range edgeColumns {
+			update = update.Set(column, edgeValues[i])
+		}
+		_, err = update.Where(joinKey+" = ? AND "+targetKey+" = ?", a.ownerKey, recordKey).Do()
+		return err
+	}
+	_, err = db.InsertInto(joinTable).
+		Columns(append([]string{joinKey, targetKey}, edgeColumns...)...).
+		Values(append([]interface{}{a.ownerKey, recordKey}, edgeValues...)...).
+		Do()
+	return err
+}
+
+// edgeColumnsAndValues returns the quoted columns and the values of the
+// edge, without the columns relating the records.
+func (a *Association) edgeColumnsAndValues() ([]string, []interface{}, error) {
+	if a.edge == nil {
+		return nil, nil, nil
+	}
+	edgeDescription, err := buildRecordDescription(a.edge)
+	if err != nil {
+		return nil, nil, err
+	}
+	if edgeDescription.isSlice {
+		return nil, nil, fmt.Errorf("Edge accept only a single instance, got a slice")
+	}
+
+	allColumns := edgeDescription.structMapping.GetNonAutoColumnsNames()
+	allValues := edgeDescription.structMapping.GetNonAutoFieldsValues(a.edge)
+	columns := make([]string, 0, len(allColumns))
+	values := make([]interface{}, 0, len(allValues))
+	for i, column := range allColumns {
+		if column == a.association.joinKey || column == a.association.targetKey {
+			continue
+		}
+		columns = append(columns, a.db.quoteFor(edgeDescription, column))
+		values = append(values, allValues[i])
+	}
+	return columns, values, nil
+}
+
+// removeOthers unrelates the records of the owner other than the given ones.
+func (a *Association) removeOthers(records []interface{}) error {
+	db := a.db
+	keys := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		keys = append(keys, a.targetDescription.structMapping.GetKeyFieldsValues(record)[0])
+	}
+
+	if a.association.joinTable != "" {
+		condition := Q(db.quote(a.association.joinKey)+" = ?", a.ownerKey)
+		if len(keys) > 0 {
+			condition = And(condition, Q(db.quote(a.association.targetKey)+" NOT IN (?)", keys))
+		}
+		_, err := db.DeleteFrom(db.quote(a.association.joinTable)).WhereQ(condition).Do()
+		return err
+	}
+
+	tableName := db.quoteFor(a.targetDescription, db.defaultTableNamer(a.targetDescription.getTableName()))
+	foreignKey := db.quoteFor(a.targetDescription, a.association.foreignKey)
+	condition := Q(foreignKey+" = ?", a.ownerKey)
+	if len(keys) > 0 {
+		keyColumn := a.targetDescription.structMapping.GetKeyColumnsNames()[0]
+		condition = And(condition, Q(db.quoteFor(a.targetDescription, keyColumn)+" NOT IN (?)", keys))
+	}
+	var err error
+	if a.association.deleteOrphans {
+		_, err = db.DeleteFrom(tableName).WhereQ(condition).Do()
+	} else {
+		_, err = db.UpdateTable(tableName).SetRaw(foreignKey + " = NULL").WhereQ(condition).Do()
+	}
+	return err
+}
+
+// checkRecord checks that the given record is a pointer to the related
+// struct.
+func (a *Association) checkRecord(record interface{}) error {
+	recordType := reflect.TypeOf(record)
+	if recordType == nil || recordType.Kind() != reflect.Ptr || recordType.Elem() != a.targetType {
+		return fmt.Errorf("the related records must be of type *%s, got %T", a.targetType.Name(), record)
+	}
+	return nil
+}
+
+// isNew returns true if the given record has a zero auto key.
+func (a *Association) isNew(record interface{}) bool {
+	pointer, err := a.targetDescription.structMapping.GetAutoKeyPointer(record)
+	if err != nil || pointer == nil {
+		return false
+	}
+	return isZeroValue(reflect.ValueOf(pointer).Elem().Interface())
+}
+
+// setForeignKey sets the foreign key field of the given record to the owner
+// key.
+func (a *Association) setForeignKey(record interface{}) error {
+	pointers, err := a.targetDescription.structMapping.GetPointersForColumns(record, a.association.foreignKey)
+	if err != nil {
+		return err
+	}
+	if scanner, ok := pointers[0].(sql.Scanner); ok {
+		return scanner.Scan(a.ownerKey)
+	}
+
+	field := reflect.ValueOf(pointers[0]).Elem()
+	key := reflect.ValueOf(a.ownerKey)
+	switch {
+	case key.Type().ConvertibleTo(field.Type()):
+		field.Set(key.Convert(field.Type()))
+	case field.Kind() == reflect.Ptr && key.Type().ConvertibleTo(field.Type().Elem()):
+		value := reflect.New(field.Type().Elem())
+		value.Elem().Set(key.Convert(field.Type().Elem()))
+		field.Set(value)
+	default:
+		return fmt.Errorf("the key of type %T can't be set to the column %s", a.ownerKey, a.association.foreignKey)
+	}
+	return nil
+}
+
+// fieldElement returns the given record as an element of the owner field.
+func (a *Association) fieldElement(record interface{}) reflect.Value {
+	value := reflect.ValueOf(record)
+	if a.field.Type().Elem().Kind() != reflect.Ptr {
+		value = value.Elem()
+	}
+	return value
+}
+
+// isZeroValue returns true if the given value is the zero value of its type.
+func isZeroValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	return reflect.DeepEqual(value, reflect.Zero(reflect.TypeOf(value)).Interface())
+}
@@ -0,0 +1,202 @@
+package godb
+
+import (
+	"database/sql"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type AssociationAuthor struct {
+	ID      int                `db:"id,key,auto"`
+	Name    string             `db:"name"`
+	Books   []AssociationBook  `db:"-" rel:"hasmany,foreignkey=author_id"`
+	Drafts  []*AssociationBook `db:"-" rel:"hasmany,foreignkey=author_id,orphans=delete"`
+	Tags    []*AssociationTag  `db:"-" rel:"manytomany,join=authors_tags,joinkey=author_id,targetkey=tag_id"`
+	Invalid []AssociationBook  `db:"-"`
+}
+
+func (*AssociationAuthor) TableName() string {
+	return "authors"
+}
+
+type AssociationBook struct {
+	ID       int           `db:"id,key,auto"`
+	AuthorID sql.NullInt64 `db:"author_id"`
+	Title    string        `db:"title"`
+}
+
+func (*AssociationBook) TableName() string {
+	return "books"
+}
+
+type AssociationTag struct {
+	ID   int    `db:"id,key,auto"`
+	Name string `db:"name"`
+}
+
+func (*AssociationTag) TableName() string {
+	return "tags"
+}
+
+type AssociationAuthorTag struct {
+	TagID    int `db:"tag_id"`
+	Position int `db:"position"`
+}
+
+func associationFixturesSetup(t *testing.T) *DB {
+	db := createInMemoryConnection(t)
+	_, err := db.sqlDB.Exec(`
+		create table authors (
+			id integer not null primary key autoincrement,
+			name text not null);
+		create table books (
+			id integer not null primary key autoincrement,
+			author_id integer,
+			title text not null);
+		create table tags (
+			id integer not null primary key autoincrement,
+			name text not null);
+		create table authors_tags (
+			author_id integer not null,
+			tag_id integer not null,
+			position integer not null default 0);
+		insert into authors (name) values ("Author");
+		insert into books (author_id, title) values (NULL, "Existing");
+		insert into tags (name) values ("Existing");
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestAssociation(t *testing.T) {
+	Convey("Given a test database with an author", t, func() {
+		db := associationFixturesSetup(t)
+		defer db.Close()
+		author := &AssociationAuthor{}
+		So(db.Get(author, 1), ShouldBeNil)
+
+		countBooks := func(condition string, args ...interface{}) int64 {
+			count, err := db.SelectFrom("books").Where(condition, args...).Count()
+			So(err, ShouldBeNil)
+			return count
+		}
+		countLinks := func() int64 {
+			count, err := db.SelectFrom("authors_tags").Where("author_id = ?", 1).Count()
+			So(err, ShouldBeNil)
+			return count
+		}
+
+		Convey("Append sets the foreign key of new and existing records", func() {
+			existing := &AssociationBook{}
+			So(db.Get(existing, 1), ShouldBeNil)
+			book := &AssociationBook{Title: "New"}
+			So(db.Association(author, "Books").Append(existing, book), ShouldBeNil)
+
+			So(book.ID, ShouldEqual, 2)
+			So(book.AuthorID.Int64, ShouldEqual, 1)
+			So(countBooks("author_id = ?", 1), ShouldEqual, 2)
+			So(len(author.Books), ShouldEqual, 2)
+			So(author.Books[1].Title, ShouldEqual, "New")
+		})
+
+		Convey("Replace nullifies the foreign key of the others records", func() {
+			first := &AssociationBook{Title: "First"}
+			second := &AssociationBook{Title: "Second"}
+			So(db.Association(author, "Books").Append(first, second), ShouldBeNil)
+			So(db.Association(author, "Books").Replace(second), ShouldBeNil)
+
+			So(countBooks("author_id = ?", 1), ShouldEqual, 1)
+			So(countBooks("author_id is null"), ShouldEqual, 2)
+			So(len(author.Books), ShouldEqual, 1)
+			So(author.Books[0].ID, ShouldEqual, second.ID)
+		})
+
+		Convey("Clear deletes the orphans if asked", func() {
+			So(db.Association(author, "Drafts").Append(&AssociationBook{Title: "Draft"}), ShouldBeNil)
+			So(countBooks("1 = 1"), ShouldEqual, 2)
+			So(db.Association(author, "Drafts").Clear(), ShouldBeNil)
+
+			So(countBooks("1 = 1"), ShouldEqual, 1)
+			So(author.Drafts, ShouldBeEmpty)
+		})
+
+		Convey("Append inserts the join table rows once", func() {
+			existing := &AssociationTag{}
+			So(db.Get(existing, 1), ShouldBeNil)
+			tag := &AssociationTag{Name: "New"}
+			So(db.Association(author, "Tags").Append(existing, tag), ShouldBeNil)
+			So(db.Association(author, "Tags").Append(existing), ShouldBeNil)
+
+			So(tag.ID, ShouldEqual, 2)
+			So(countLinks(), ShouldEqual, 2)
+			So(len(author.Tags), ShouldEqual, 3)
+			So(author.Tags[1], ShouldEqual, tag)
+		})
+
+		Convey("Replace and Clear delete the join table rows", func() {
+			first := &AssociationTag{Name: "First"}
+			second := &AssociationTag{Name: "Second"}
+			So(db.Association(author, "Tags").Append(first, second), ShouldBeNil)
+			So(db.Association(author, "Tags").Replace(first), ShouldBeNil)
+			So(countLinks(), ShouldEqual, 1)
+
+			So(db.Association(author, "Tags").Clear(), ShouldBeNil)
+			So(countLinks(), ShouldEqual, 0)
+			So(author.Tags, ShouldBeEmpty)
+		})
+
+		Convey("Edge sets the others columns of the join table", func() {
+			first := &AssociationTag{Name: "First"}
+			second := &AssociationTag{Name: "Second"}
+			So(db.Association(author, "Tags").Edge(&AssociationAuthorTag{Position: 1}).Append(first), ShouldBeNil)
+			So(db.Association(author, "Tags").Edge(&AssociationAuthorTag{Position: 2}).Append(second), ShouldBeNil)
+			So(db.Association(author, "Tags").Edge(&AssociationAuthorTag{Position: 3}).Append(first), ShouldBeNil)
+
+			edges := make([]AssociationAuthorTag, 0)
+			So(db.Association(author, "Tags").Edges(&edges), ShouldBeNil)
+			So(len(edges), ShouldEqual, 2)
+			So(edges[0], ShouldResemble, AssociationAuthorTag{TagID: first.ID, Position: 3})
+			So(edges[1], ShouldResemble, AssociationAuthorTag{TagID: second.ID, Position: 2})
+			So(db.Association(author, "Books").Edges(&edges), ShouldNotBeNil)
+		})
+
+		Convey("Load fetches the related records", func() {
+			So(db.Association(author, "Books").Append(&AssociationBook{Title: "Book"}), ShouldBeNil)
+			So(db.Association(author, "Tags").Append(&AssociationTag{Name: "Tag"}), ShouldBeNil)
+			loaded := &AssociationAuthor{}
+			So(db.Get(loaded, 1), ShouldBeNil)
+
+			So(db.Association(loaded, "Books").Load(), ShouldBeNil)
+			So(len(loaded.Books), ShouldEqual, 1)
+			So(loaded.Books[0].Title, ShouldEqual, "Book")
+			So(db.Association(loaded, "Tags").Load(), ShouldBeNil)
+			So(len(loaded.Tags), ShouldEqual, 1)
+			So(loaded.Tags[0].Name, ShouldEqual, "Tag")
+			So(db.Association(loaded, "Drafts").Load(), ShouldBeNil)
+			So(len(loaded.Drafts), ShouldEqual, 1)
+			So(loaded.Drafts[0].Title, ShouldEqual, "Book")
+		})
+
+		Convey("The changes are rolled back on error", func() {
+			_, err := db.CurrentDB().Exec("drop table authors_tags")
+			So(err, ShouldBeNil)
+			err = db.Association(author, "Tags").Append(&AssociationTag{Name: "New"})
+			So(err, ShouldNotBeNil)
+
+			count, err := db.SelectFrom("tags").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+			So(author.Tags, ShouldBeEmpty)
+		})
+
+		Convey("Invalid relations and records are rejected", func() {
+			So(db.Association(author, "Invalid").Append(), ShouldNotBeNil)
+			So(db.Association(author, "Unknown").Append(), ShouldNotBeNil)
+			So(db.Association(&AssociationAuthor{}, "Books").Append(), ShouldNotBeNil)
+			So(db.Association(author, "Books").Append(&AssociationTag{}), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,50 @@
+package godb
+
+import (
+	"context"
+)
+
+// actorKey is the context key of the actor.
+type actorKey struct{}
+
+// WithActor returns a copy of the context carrying the given actor (an user
+// id, name, ...). When the context is given to a DB (see SetContext), the
+// struct tools fill the fields tagged with 'auditor_create' and
+// 'auditor_update' with the actor.
+//
+// Example :
+// 	type Book struct {
+// 		Id        int    `db:"id,key,auto"`
+// 		Title     string `db:"title"`
+// 		CreatedBy int    `db:"created_by,auditor_create"`
+// 		UpdatedBy int    `db:"updated_by,auditor_update"`
+// 	}
+// 	…
+// 	db.SetContext(godb.WithActor(ctx, userID))
+// 	err := db.Insert(&book).Do()
+func WithActor(ctx context.Context, actor interface{}) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor carried by the context, if any.
+func ActorFromContext(ctx context.Context) (interface{}, bool) {
+	actor := ctx.Value(actorKey{})
+	return actor, actor != nil
+}
+
+// setAuditors fills the auditor fields of all given records with the actor
+// of the DB context, if any.
+func (db *DB) setAuditors(recordDescription *recordDescription, isCreation bool) error {
+	actor, ok := ActorFromContext(db.Context())
+	if !ok {
+		return nil
+	}
+
+	for i := 0; i < recordDescription.len(); i++ {
+		record := recordDescription.index(i)
+		if err := recordDescription.structMapping.SetAuditorFieldsValues(record, actor, isCreation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
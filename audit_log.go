@@ -0,0 +1,222 @@
+package godb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Auditable is implemented by structs whose changes are written in the audit
+// log (see UseAuditLog). Audited could return false to skip an instance.
+type Auditable interface {
+	Audited() bool
+}
+
+// Audit log operations.
+const (
+	AuditInsert = "INSERT"
+	AuditUpdate = "UPDATE"
+	AuditDelete = "DELETE"
+)
+
+// UseAuditLog enables the audit log : the insert, update and delete of
+// Auditable structs done with the struct tools write an entry in the given
+// table, within the same transaction (a transaction is started if needed).
+//
+// The table needs these columns :
+// 	table_name  : the table of the changed record
+// 	record_key  : the key of the record (JSON)
+// 	operation   : INSERT, UPDATE or DELETE
+// 	before_data : JSON snapshot of changed columns before the change (or NULL)
+// 	after_data  : JSON snapshot of changed columns after the change (or NULL)
+// 	actor       : the actor of the context (see WithActor), or NULL
+// 	changed_at  : the time of the change
+func (db *DB) UseAuditLog(tableName string) {
+	db.auditLogTable = tableName
+}
+
+// audited runs the given function, and writes the audit log entries of the
+// records, if needed.
+func (db *DB) audited(recordDescription *recordDescription, operation string, do func() error) error {
+	if db.auditLogTable == "" || !isAuditable(recordDescription) {
+		return do()
+	}
+
+	ownTx := db.sqlTx == nil
+	if ownTx {
+		if err := db.Begin(); err != nil {
+			return err
+		}
+	}
+
+	err := db.doAudited(recordDescription, operation, do)
+	if ownTx {
+		if err != nil {
+			db.Rollback()
+			return err
+		}
+		return db.Commit()
+	}
+	return err
+}
+
+// isAuditable returns true if the records type implements Auditable.
+func isAuditable(recordDescription *recordDescription) bool {
+	_, ok := reflect.New(recordDescription.instanceType).Interface().(Auditable)
+	return ok
+}
+
+// doAudited takes the snapshots of records before and after the given
+// function, and writes the audit log entries.
+func (db *DB) doAudited(recordDescription *recordDescription, operation string, do func() error) error {
+	// Only single instances are updated or deleted
+	var before map[string]interface{}
+	if operation != AuditInsert && isRecordAudited(recordDescription.record) {
+		var err error
+		before, err = db.currentSnapshot(recordDescription)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := do(); err != nil {
+		return err
+	}
+
+	for i := 0; i < recordDescription.len(); i++ {
+		record := recordDescription.index(i)
+		if !isRecordAudited(record) {
+			continue
+		}
+
+		var after map[string]interface{}
+		if operation != AuditDelete {
+			after = snapshot(recordDescription, record)
+		}
+		if operation == AuditUpdate {
+			before, after = changedColumns(before, after)
+			if len(after) == 0 {
+				continue
+			}
+		}
+
+		if err := db.writeAuditLog(recordDescription, record, operation, before, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isRecordAudited returns true if the record has to be written in the
+// audit log.
+func isRecordAudited(record interface{}) bool {
+	auditable, ok := record.(Auditable)
+	return ok && auditable.Audited()
+}
+
+// currentSnapshot reads the current record in database and returns its
+// snapshot, or nil if the record does not exist.
+func (db *DB) currentSnapshot(recordDescription *recordDescription) (map[string]interface{}, error) {
+	keyValues := recordDescription.structMapping.GetKeyFieldsValues(recordDescription.record)
+	condition, err := db.keyCondition(recordDescription, keyValues)
+	if err != nil {
+		return nil, err
+	}
+
+	current := reflect.New(recordDescription.instanceType).Interface()
+	err = db.Select(current).WhereQ(condition).Do()
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return snapshot(recordDescription, current), nil
+}
+
+// snapshot returns all columns values of the record.
+func snapshot(recordDescription *recordDescription, record interface{}) map[string]interface{} {
+	columns := recordDescription.structMapping.GetAllColumnsNames()
+	fieldsValues := recordDescription.structMapping.GetAllFieldsValues(record)
+	values := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		value := fieldsValues[i]
+		if valuer, ok := value.(driver.Valuer); ok {
+			if v, err := valuer.Value(); err == nil {
+				value = v
+			}
+		}
+		values[column] = value
+	}
+	return values
+}
+
+// changedColumns returns the snapshots restricted to the changed columns.
+func changedColumns(before map[string]interface{}, after map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	if before == nil {
+		return nil, after
+	}
+
+	changedBefore := make(map[string]interface{})
+	changedAfter := make(map[string]interface{})
+	for column, afterValue := range after {
+		beforeJSON, _ := json.Marshal(before[column])
+		afterJSON, _ := json.Marshal(afterValue)
+		if string(beforeJSON) != string(afterJSON) {
+			changedBefore[column] = before[column]
+			changedAfter[column] = afterValue
+		}
+	}
+	return changedBefore, changedAfter
+}
+
+// writeAuditLog inserts an entry in the audit log table.
+func (db *DB) writeAuditLog(recordDescription *recordDescription, record interface{}, operation string, before map[string]interface{}, after map[string]interface{}) error {
+	keyColumns := recordDescription.structMapping.GetKeyColumnsNames()
+	keyValues := recordDescription.structMapping.GetKeyFieldsValues(record)
+	key := make(map[string]interface{}, len(keyColumns))
+	for i, column := range keyColumns {
+		key[column] = keyValues[i]
+	}
+
+	var actor interface{}
+	if a, ok := ActorFromContext(db.Context()); ok {
+		actor = fmt.Sprint(a)
+	}
+
+	keyJSON, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	beforeJSON, err := snapshotToJSON(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := snapshotToJSON(after)
+	if err != nil {
+		return err
+	}
+
+	tableName := db.defaultTableNamer(recordDescription.getTableName())
+	_, err = db.InsertInto(db.quote(db.auditLogTable)).
+		Columns(db.quoteAll([]string{"table_name", "record_key", "operation", "before_data", "after_data", "actor", "changed_at"})...).
+		Values(tableName, string(keyJSON), operation, beforeJSON, afterJSON, actor, time.Now()).
+		Do()
+	return err
+}
+
+// snapshotToJSON returns the JSON of a snapshot, or nil if there is none.
+func snapshotToJSON(snapshot map[string]interface{}) (interface{}, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
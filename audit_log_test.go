@@ -0,0 +1,121 @@
+package godb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type auditLoggedDummy struct {
+	ID    int    `db:"id,key,auto"`
+	AText string `db:"a_text"`
+	Count int    `db:"count"`
+}
+
+func (*auditLoggedDummy) TableName() string {
+	return "auditloggeddummies"
+}
+
+func (*auditLoggedDummy) Audited() bool {
+	return true
+}
+
+type auditLogEntry struct {
+	ID         int            `db:"id,key,auto"`
+	TableName  string         `db:"table_name"`
+	RecordKey  string         `db:"record_key"`
+	Operation  string         `db:"operation"`
+	BeforeData sql.NullString `db:"before_data"`
+	AfterData  sql.NullString `db:"after_data"`
+	Actor      sql.NullString `db:"actor"`
+}
+
+func TestAuditLog(t *testing.T) {
+	Convey("Given a test database with an audit log", t, func() {
+		db := createInMemoryConnection(t)
+		defer db.Close()
+		_, err := db.sqlDB.Exec(`
+			create table auditloggeddummies (
+			id         integer not null primary key autoincrement,
+			a_text     text not null,
+			count      integer not null);
+
+			create table audit_log (
+			id          integer not null primary key autoincrement,
+			table_name  text not null,
+			record_key  text not null,
+			operation   text not null,
+			before_data text,
+			after_data  text,
+			actor       text,
+			changed_at  timestamp not null);`)
+		So(err, ShouldBeNil)
+		db.UseAuditLog("audit_log")
+		db.SetContext(WithActor(context.Background(), 42))
+
+		readLog := func() []auditLogEntry {
+			entries := make([]auditLogEntry, 0)
+			err := db.SelectFrom("audit_log").
+				Columns("id", "table_name", "record_key", "operation", "before_data", "after_data", "actor").
+				OrderBy("id").
+				Do(&entries)
+			So(err, ShouldBeNil)
+			return entries
+		}
+		snapshot := func(data sql.NullString) map[string]interface{} {
+			So(data.Valid, ShouldBeTrue)
+			m := make(map[string]interface{})
+			So(json.Unmarshal([]byte(data.String), &m), ShouldBeNil)
+			return m
+		}
+
+		dummy := auditLoggedDummy{AText: "Foo", Count: 1}
+		So(db.Insert(&dummy).Do(), ShouldBeNil)
+
+		Convey("Insert writes the record snapshot", func() {
+			entries := readLog()
+			So(len(entries), ShouldEqual, 1)
+			So(entries[0].TableName, ShouldEqual, "auditloggeddummies")
+			So(entries[0].Operation, ShouldEqual, AuditInsert)
+			So(entries[0].BeforeData.Valid, ShouldBeFalse)
+			So(snapshot(entries[0].AfterData)["a_text"], ShouldEqual, "Foo")
+			So(entries[0].Actor.String, ShouldEqual, "42")
+		})
+
+		Convey("Update writes only changed columns", func() {
+			dummy.Count = 2
+			So(db.Update(&dummy).Do(), ShouldBeNil)
+			entries := readLog()
+			So(len(entries), ShouldEqual, 2)
+			So(entries[1].Operation, ShouldEqual, AuditUpdate)
+			So(snapshot(entries[1].BeforeData), ShouldResemble, map[string]interface{}{"count": float64(1)})
+			So(snapshot(entries[1].AfterData), ShouldResemble, map[string]interface{}{"count": float64(2)})
+
+			Convey("An update without change writes nothing", func() {
+				So(db.Update(&dummy).Do(), ShouldBeNil)
+				So(len(readLog()), ShouldEqual, 2)
+			})
+		})
+
+		Convey("Delete writes the snapshot before deletion", func() {
+			_, err := db.Delete(&dummy).Do()
+			So(err, ShouldBeNil)
+			entries := readLog()
+			So(len(entries), ShouldEqual, 2)
+			So(entries[1].Operation, ShouldEqual, AuditDelete)
+			So(snapshot(entries[1].BeforeData)["a_text"], ShouldEqual, "Foo")
+			So(entries[1].AfterData.Valid, ShouldBeFalse)
+		})
+
+		Convey("The audit log is written within the transaction", func() {
+			So(db.Begin(), ShouldBeNil)
+			dummy.Count = 3
+			So(db.Update(&dummy).Do(), ShouldBeNil)
+			So(db.Rollback(), ShouldBeNil)
+			So(len(readLog()), ShouldEqual, 1)
+		})
+	})
+}
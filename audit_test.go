@@ -0,0 +1,69 @@
+package godb
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type auditedDummy struct {
+	ID        int    `db:"id,key,auto"`
+	AText     string `db:"a_text"`
+	CreatedBy string `db:"created_by,auditor_create"`
+	UpdatedBy string `db:"updated_by,auditor_update"`
+}
+
+func (*auditedDummy) TableName() string {
+	return "auditeddummies"
+}
+
+func TestActorFromContext(t *testing.T) {
+	Convey("ActorFromContext returns the actor given with WithActor", t, func() {
+		_, ok := ActorFromContext(context.Background())
+		So(ok, ShouldBeFalse)
+
+		actor, ok := ActorFromContext(WithActor(context.Background(), "alice"))
+		So(ok, ShouldBeTrue)
+		So(actor, ShouldEqual, "alice")
+	})
+}
+
+func TestAuditorFields(t *testing.T) {
+	Convey("Given a test database and a context with an actor", t, func() {
+		db := createInMemoryConnection(t)
+		defer db.Close()
+		_, err := db.sqlDB.Exec(`create table auditeddummies (
+			id         integer not null primary key autoincrement,
+			a_text     text not null,
+			created_by text not null,
+			updated_by text not null);`)
+		So(err, ShouldBeNil)
+		db.SetContext(WithActor(context.Background(), "alice"))
+
+		Convey("Insert fills all auditor fields", func() {
+			dummy := auditedDummy{AText: "Foo"}
+			So(db.Insert(&dummy).Do(), ShouldBeNil)
+			So(dummy.CreatedBy, ShouldEqual, "alice")
+			So(dummy.UpdatedBy, ShouldEqual, "alice")
+
+			Convey("Update fills only the update auditor fields", func() {
+				db.SetContext(WithActor(context.Background(), "bob"))
+				dummy.AText = "Bar"
+				So(db.Update(&dummy).Do(), ShouldBeNil)
+
+				retrieved := auditedDummy{}
+				So(db.Select(&retrieved).Where("id = ?", dummy.ID).Do(), ShouldBeNil)
+				So(retrieved.CreatedBy, ShouldEqual, "alice")
+				So(retrieved.UpdatedBy, ShouldEqual, "bob")
+			})
+		})
+
+		Convey("Clones share the context", func() {
+			clone := db.Clone()
+			actor, ok := ActorFromContext(clone.Context())
+			So(ok, ShouldBeTrue)
+			So(actor, ShouldEqual, "alice")
+		})
+	})
+}
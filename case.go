@@ -0,0 +1,120 @@
+package godb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// CaseExpression is a CASE expression, see Case.
+type CaseExpression struct {
+	whens     []caseWhen
+	elseValue interface{}
+	hasElse   bool
+	alias     string
+}
+
+// caseWhen is a WHEN part of a CASE expression.
+type caseWhen struct {
+	condition *Condition
+	value     interface{}
+}
+
+// Case starts a CASE expression, usable in ColumnsExpr, GroupByExpr,
+// OrderByExpr and as a value of UpdateStatement.Set. Each value is an
+// expression (Ident, Raw, Fn, ...), nil for NULL, or a value given as an
+// argument of the statement. The arguments of the conditions and of the
+// values are merged in the order of the expression.
+//
+// Example :
+// 	err := db.SelectFrom("orders").
+// 		ColumnsExpr(godb.Ident("id"), godb.Case().
+// 			When(godb.Q("total >= ?", 1000), "large").
+// 			When(godb.Q("total >= ?", 100), "medium").
+// 			Else("small").
+// 			As("size")).
+// 		Do(&orders)
+func Case() *CaseExpression {
+	return &CaseExpression{}
+}
+
+// When adds a WHEN condition THEN value part to the expression.
+func (ce *CaseExpression) When(condition *Condition, value interface{}) *CaseExpression {
+	ce.whens = append(ce.whens, caseWhen{condition: condition, value: value})
+	return ce
+}
+
+// Else sets the value of the expression if no condition is met, NULL
+// otherwise.
+func (ce *CaseExpression) Else(value interface{}) *CaseExpression {
+	ce.elseValue = value
+	ce.hasElse = true
+	return ce
+}
+
+// As sets the alias of the expression, when it's used as a column. The alias
+// is checked and quoted like Ident.
+func (ce *CaseExpression) As(alias string) *CaseExpression {
+	ce.alias = alias
+	return ce
+}
+
+// BuildSQL returns the SQL of the expression, with its arguments.
+func (ce *CaseExpression) BuildSQL(adapter adapters.Adapter) (string, []interface{}, error) {
+	if len(ce.whens) == 0 {
+		return "", nil, fmt.Errorf("CASE expression without WHEN condition")
+	}
+
+	var sql strings.Builder
+	var args []interface{}
+	sql.WriteString("CASE")
+	for _, when := range ce.whens {
+		if when.condition == nil {
+			return "", nil, fmt.Errorf("nil condition in CASE expression")
+		}
+		if when.condition.err != nil {
+			return "", nil, when.condition.err
+		}
+		valueSQL, valueArgs, err := buildCaseValue(adapter, when.value)
+		if err != nil {
+			return "", nil, err
+		}
+		sql.WriteString(" WHEN " + when.condition.sql + " THEN " + valueSQL)
+		args = append(args, when.condition.args...)
+		args = append(args, valueArgs...)
+	}
+	if ce.hasElse {
+		valueSQL, valueArgs, err := buildCaseValue(adapter, ce.elseValue)
+		if err != nil {
+			return "", nil, err
+		}
+		sql.WriteString(" ELSE " + valueSQL)
+		args = append(args, valueArgs...)
+	}
+	sql.WriteString(" END")
+
+	if ce.alias != "" {
+		alias, _, err := Ident(ce.alias).BuildSQL(adapter)
+		if err != nil {
+			return "", nil, err
+		}
+		sql.WriteString(" AS " + alias)
+	}
+	return sql.String(), args, nil
+}
+
+// buildCaseValue returns the SQL of a value of a CASE expression : the SQL
+// of an expression, NULL, or a placeholder for the value.
+func buildCaseValue(adapter adapters.Adapter, value interface{}) (string, []interface{}, error) {
+	if value == nil {
+		return "NULL", nil, nil
+	}
+	if expression, ok := value.(Expression); ok {
+		if isNullArgument(expression) {
+			return "", nil, fmt.Errorf("nil expression")
+		}
+		return expression.BuildSQL(adapter)
+	}
+	return Placeholder, []interface{}{value}, nil
+}
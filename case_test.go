@@ -0,0 +1,75 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCase(t *testing.T) {
+	Convey("Given a CASE expression", t, func() {
+		size := Case().
+			When(Q("total >= ?", 1000), "large").
+			When(Q("total IN (?)", []int{0, 1}), Raw("NULL")).
+			When(Q("total >= ?", 100), Fn("UPPER", "medium")).
+			Else(nil).
+			As("size")
+
+		Convey("The conditions and values are written with their arguments in order", func() {
+			sql, args, err := size.BuildSQL(sqlite.Adapter)
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `CASE WHEN total >= ? THEN ? WHEN total IN (?,?) THEN NULL WHEN total >= ? THEN UPPER(?) ELSE NULL END AS "size"`)
+			So(args, ShouldResemble, []interface{}{1000, "large", 0, 1, 100, "medium"})
+		})
+
+		Convey("The arguments are merged with the others of the statement", func() {
+			db := &DB{adapter: sqlite.Adapter}
+			sql, args, err := db.SelectFrom("orders").
+				ColumnsExpr(Ident("id"), size).
+				Where("customer = ?", 7).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldStartWith, `SELECT "id", CASE WHEN total >= ? THEN ?`)
+			So(args, ShouldResemble, []interface{}{1000, "large", 0, 1, 100, "medium", 7})
+
+			sql, args, err = db.UpdateTable("orders").
+				Set("category", Case().When(Q("total > ?", 10), "big").Else("small")).
+				Where("customer = ?", 7).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "UPDATE orders SET category=CASE WHEN total > ? THEN ? ELSE ? END WHERE customer = ?")
+			So(args, ShouldResemble, []interface{}{10, "big", "small", 7})
+		})
+
+		Convey("The invalid expressions are rejected", func() {
+			_, _, err := Case().Else(1).BuildSQL(sqlite.Adapter)
+			So(err, ShouldNotBeNil)
+			_, _, err = Case().When(Q("a = ?"), 1).BuildSQL(sqlite.Adapter)
+			So(err, ShouldNotBeNil)
+			_, _, err = Case().When(nil, 1).BuildSQL(sqlite.Adapter)
+			So(err, ShouldNotBeNil)
+			_, _, err = Case().When(Q("a = 1"), 1).As("a b").BuildSQL(sqlite.Adapter)
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("The expression is computed by the database", func() {
+			type sizedDummy struct {
+				ID   int    `db:"id"`
+				Size string `db:"size"`
+			}
+			sized := make([]sizedDummy, 0)
+			err := db.SelectFrom("dummies").
+				ColumnsExpr(Ident("id"), Case().When(Q("an_integer > ?", 11), "big").Else("small").As("size")).
+				OrderBy("id").
+				Do(&sized)
+			So(err, ShouldBeNil)
+			So(sized, ShouldResemble, []sizedDummy{{1, "small"}, {2, "big"}, {3, "big"}})
+		})
+	})
+}
@@ -0,0 +1,135 @@
+package godb
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"math"
+	"time"
+)
+
+// Checksum executes the statement and returns a hash of its result : the
+// values of all the columns of all the rows, in order. Two executions give
+// the same checksum if the result is unchanged, it detects the concurrent
+// modifications between paginated requests, or validates a cache. The
+// statement needs an ORDER BY giving a stable order, and it should select
+// only the columns identifying the rows and their versions.
+//
+// Example :
+// 	checksum, err := db.SelectFrom("books").
+// 		Columns("id", "version").
+// 		OrderBy("id").
+// 		Offset(100).Limit(50).
+// 		Checksum()
+func (ss *SelectStatement) Checksum() (string, error) {
+	iterator, err := ss.DoWithIterator()
+	if err != nil {
+		return "", err
+	}
+	defer iterator.Close()
+
+	hasher := sha256.New()
+	// The dry run iterators have no row
+	if internals, ok := iterator.(*iteratorInternals); ok {
+		values := make([]interface{}, len(internals.columns))
+		pointers := make([]interface{}, len(values))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		for iterator.Next() {
+			if err := iterator.Scanx(pointers...); err != nil {
+				return "", err
+			}
+			for _, value := range values {
+				if err := writeChecksumValue(hasher, value); err != nil {
+					return "", err
+				}
+			}
+		}
+	}
+	if err := iterator.Err(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// Checksum executes the statement and returns a hash of the keys of the rows
+// and of their optimistic locking version if any, in the order of the keys
+// unless another order is given. See SelectStatement.Checksum.
+//
+// Example :
+// 	checksum, err := db.Select(&books).Where("author = ?", author).Checksum()
+// 	...
+// 	// the next page is consistent with the first one if the checksum is
+// 	// unchanged
+func (ss *StructSelect) Checksum() (string, error) {
+	if ss.error != nil {
+		return "", ss.error
+	}
+
+	structMapping := ss.recordDescription.structMapping
+	keyColumns := structMapping.GetKeyColumnsNames()
+	if len(keyColumns) == 0 {
+		return "", fmt.Errorf("the struct %s has no key", structMapping.Name)
+	}
+	columns := keyColumns
+	if opLockColumn := structMapping.GetOpLockSQLFieldName(); opLockColumn != "" {
+		columns = append(columns, opLockColumn)
+	}
+
+	// the statement is kept unchanged to be executed later
+	statement := ss.selectStatement.Clone()
+	db := statement.db
+	if len(statement.orderBy) == 0 {
+		for _, keyColumn := range keyColumns {
+			statement.OrderBy(db.quoteFor(ss.recordDescription, keyColumn))
+		}
+	}
+	statement.Columns(db.quoteAllFor(ss.recordDescription, columns)...)
+	return statement.Checksum()
+}
+
+// writeChecksumValue writes a value of a row in the checksum : its type and
+// its length prefix the data, then the values can't be confused.
+func writeChecksumValue(h hash.Hash, value interface{}) error {
+	var kind byte
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		kind = 'n'
+	case int64:
+		kind = 'i'
+		data = make([]byte, 8)
+		binary.BigEndian.PutUint64(data, uint64(v))
+	case float64:
+		kind = 'f'
+		data = make([]byte, 8)
+		binary.BigEndian.PutUint64(data, math.Float64bits(v))
+	case bool:
+		kind = 'b'
+		data = []byte{0}
+		if v {
+			data[0] = 1
+		}
+	case []byte:
+		kind = 's'
+		data = v
+	case string:
+		kind = 's'
+		data = []byte(v)
+	case time.Time:
+		kind = 't'
+		data = []byte(v.UTC().Format(time.RFC3339Nano))
+	default:
+		return fmt.Errorf("a value of type %T can't be added to a checksum", value)
+	}
+
+	prefix := make([]byte, 9)
+	prefix[0] = kind
+	binary.BigEndian.PutUint64(prefix[1:], uint64(len(data)))
+	h.Write(prefix)
+	h.Write(data)
+	return nil
+}
@@ -0,0 +1,73 @@
+package godb
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestChecksum(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("The checksum of a statement changes with its result", func() {
+			page := func() *SelectStatement {
+				return db.SelectFrom("dummies").Columns("id", "a_text").OrderBy("id").Limit(2)
+			}
+			checksum, err := page().Checksum()
+			So(err, ShouldBeNil)
+			So(len(checksum), ShouldEqual, 64)
+
+			same, err := page().Checksum()
+			So(err, ShouldBeNil)
+			So(same, ShouldEqual, checksum)
+
+			_, err = db.UpdateTable("dummies").Set("a_text", "Changed").Where("id = ?", 2).Do()
+			So(err, ShouldBeNil)
+			changed, err := page().Checksum()
+			So(err, ShouldBeNil)
+			So(changed, ShouldNotEqual, checksum)
+		})
+
+		Convey("The checksum of a struct select uses the keys and the version", func() {
+			dummies := make([]Dummy, 0)
+			checksum, err := db.Select(&dummies).Checksum()
+			So(err, ShouldBeNil)
+
+			_, err = db.UpdateTable("dummies").Set("a_text", "Changed").Where("id = ?", 2).Do()
+			So(err, ShouldBeNil)
+			same, err := db.Select(&dummies).Checksum()
+			So(err, ShouldBeNil)
+			So(same, ShouldEqual, checksum)
+
+			_, err = db.UpdateTable("dummies").SetRaw("version = version + 1").Where("id = ?", 2).Do()
+			So(err, ShouldBeNil)
+			changed, err := db.Select(&dummies).Checksum()
+			So(err, ShouldBeNil)
+			So(changed, ShouldNotEqual, checksum)
+		})
+
+		Convey("The checksum of a struct select doesn't change the statement", func() {
+			dummies := make([]Dummy, 0)
+			statement := db.Select(&dummies)
+			checksum, err := statement.Checksum()
+			So(err, ShouldBeNil)
+			same, err := statement.Checksum()
+			So(err, ShouldBeNil)
+			So(same, ShouldEqual, checksum)
+
+			err = statement.Do()
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+		})
+
+		Convey("The values can't be confused", func() {
+			first, err := db.SelectFrom("dummies").Columns("'ab'", "'c'").Limit(1).Checksum()
+			So(err, ShouldBeNil)
+			second, err := db.SelectFrom("dummies").Columns("'a'", "'bc'").Limit(1).Checksum()
+			So(err, ShouldBeNil)
+			So(first, ShouldNotEqual, second)
+		})
+	})
+}
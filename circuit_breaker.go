@@ -0,0 +1,300 @@
+package godb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/samonzeweb/godb/dberror"
+)
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+// States of a circuit breaker.
+const (
+	// CircuitClosed : the statements are executed
+	CircuitClosed CircuitState = iota
+	// CircuitOpen : the statements are rejected with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen : a few probe statements are executed to test the
+	// database, the others are rejected
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitBreakerConfig is the configuration of a circuit breaker, see
+// SetCircuitBreaker. The zero values are replaced by the defaults.
+type CircuitBreakerConfig struct {
+	// Window is the duration of the windows counting the executions, the
+	// counts are reset at the end of each window (default 10s).
+	Window time.Duration
+	// MinExecutions is the count of executions in a window before the
+	// breaker can trip (default 20).
+	MinExecutions int
+	// MaxErrorRate trips the breaker when the rate of failed executions of
+	// the window exceeds it, between 0 and 1 (default 0.5). Use a negative
+	// value to disable it.
+	MaxErrorRate float64
+	// SlowThreshold is the duration of a slow execution (default 0, disabling
+	// the slow executions detection).
+	SlowThreshold time.Duration
+	// MaxSlowRate trips the breaker when the rate of slow executions of the
+	// window exceeds it, between 0 and 1 (default 0.5).
+	MaxSlowRate float64
+	// OpenDuration is the time the breaker stays open before letting probes
+	// through (default 30s).
+	OpenDuration time.Duration
+	// HalfOpenProbes is the count of successful probes closing the breaker,
+	// a failed probe opens it again (default 1).
+	HalfOpenProbes int
+	// IsFailure tells if the error of an execution is a failure of the
+	// database. By default all errors are failures, except sql.ErrNoRows,
+	// the cancellations of contexts, and the constraint violations (the
+	// dberror types given by the adapter), which are errors of the
+	// application.
+	IsFailure func(err error) bool
+	// OnStateChange is called on each state change, to feed metrics or
+	// alerts. The changes are also logged. It's called once the breaker is
+	// unlocked, it can use the DB.
+	OnStateChange func(from CircuitState, to CircuitState)
+}
+
+// SetCircuitBreaker wraps the executions of the statements of the DB and its
+// clones (created after the call) with a circuit breaker : when too many
+// executions fail or are slow, the breaker opens and the statements are
+// rejected with ErrCircuitOpen without reaching the database, stopping
+// cascading failures at the data layer. After a while probes are let through,
+// and the breaker closes if they succeed. A nil configuration removes the
+// circuit breaker.
+//
+// Example :
+// 	db.SetCircuitBreaker(&godb.CircuitBreakerConfig{
+// 		MaxErrorRate:  0.3,
+// 		SlowThreshold: 2 * time.Second,
+// 	})
+func (db *DB) SetCircuitBreaker(config *CircuitBreakerConfig) {
+	if config == nil {
+		db.breaker = nil
+		return
+	}
+	parseError := func(err error) error {
+		return db.adapter.ParseError(err)
+	}
+	db.breaker = newCircuitBreaker(*config, parseError, db.logPrintln)
+}
+
+// CircuitState returns the state of the circuit breaker, CircuitClosed if
+// there is none.
+func (db *DB) CircuitState() CircuitState {
+	if db.breaker == nil {
+		return CircuitClosed
+	}
+	return db.breaker.currentState()
+}
+
+// circuitBreaker implements the circuit breaker of a DB.
+type circuitBreaker struct {
+	config CircuitBreakerConfig
+	log    func(v ...interface{})
+	now    func() time.Time
+
+	mutex sync.Mutex
+	state CircuitState
+	// counts of the current window (closed state)
+	windowStart time.Time
+	executions  int
+	failures    int
+	slows       int
+	// open state
+	openedAt time.Time
+	// half-open state
+	probes           int
+	successfulProbes int
+	// changes are the state changes to notify once unlocked
+	changes []stateChange
+}
+
+// stateChange is a state change of a circuit breaker.
+type stateChange struct {
+	from CircuitState
+	to   CircuitState
+}
+
+// newCircuitBreaker creates a closed circuit breaker with the given
+// configuration, completed with the defaults. The errors are given to
+// parseError before the default IsFailure.
+func newCircuitBreaker(config CircuitBreakerConfig, parseError func(err error) error, log func(v ...interface{})) *circuitBreaker {
+	if config.Window <= 0 {
+		config.Window = 10 * time.Second
+	}
+	if config.MinExecutions <= 0 {
+		config.MinExecutions = 20
+	}
+	if config.MaxErrorRate == 0 {
+		config.MaxErrorRate = 0.5
+	}
+	if config.MaxSlowRate <= 0 {
+		config.MaxSlowRate = 0.5
+	}
+	if config.OpenDuration <= 0 {
+		config.OpenDuration = 30 * time.Second
+	}
+	if config.HalfOpenProbes <= 0 {
+		config.HalfOpenProbes = 1
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = func(err error) bool {
+			return isDatabaseFailure(parseError(err))
+		}
+	}
+	return &circuitBreaker{config: config, log: log, now: time.Now}
+}
+
+// isDatabaseFailure is the default IsFailure function, for the errors
+// parsed by the adapter.
+func isDatabaseFailure(err error) bool {
+	var unique dberror.UniqueConstraint
+	var check dberror.CheckConstraint
+	var foreignKey dberror.ForeignKeyConstraint
+	return err != nil &&
+		!errors.Is(err, sql.ErrNoRows) &&
+		!errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded) &&
+		!errors.As(err, &unique) &&
+		!errors.As(err, &check) &&
+		!errors.As(err, &foreignKey)
+}
+
+// currentState returns the state, changing it to half-open if the open
+// duration is elapsed.
+func (cb *circuitBreaker) currentState() CircuitState {
+	cb.mutex.Lock()
+	defer cb.unlock()
+	cb.refreshState()
+	return cb.state
+}
+
+// allow returns ErrCircuitOpen if an execution is not allowed. Otherwise
+// the execution has to be recorded with record.
+func (cb *circuitBreaker) allow() error {
+	cb.mutex.Lock()
+	defer cb.unlock()
+	cb.refreshState()
+
+	switch cb.state {
+	case CircuitOpen:
+		return ErrCircuitOpen
+	case CircuitHalfOpen:
+		if cb.probes >= cb.config.HalfOpenProbes {
+			return ErrCircuitOpen
+		}
+		cb.probes++
+	}
+	return nil
+}
+
+// record records the result of an execution allowed by allow.
+func (cb *circuitBreaker) record(duration time.Duration, err error) {
+	cb.mutex.Lock()
+	defer cb.unlock()
+
+	failed := cb.config.IsFailure(err)
+	slow := cb.config.SlowThreshold > 0 && duration > cb.config.SlowThreshold
+
+	switch cb.state {
+	case CircuitHalfOpen:
+		if failed || slow {
+			cb.setState(CircuitOpen)
+			return
+		}
+		cb.successfulProbes++
+		if cb.successfulProbes >= cb.config.HalfOpenProbes {
+			cb.setState(CircuitClosed)
+		}
+	case CircuitClosed:
+		if cb.now().Sub(cb.windowStart) > cb.config.Window {
+			cb.resetWindow()
+		}
+		cb.executions++
+		if failed {
+			cb.failures++
+		}
+		if slow {
+			cb.slows++
+		}
+		if cb.executions < cb.config.MinExecutions {
+			return
+		}
+		errorRate := float64(cb.failures) / float64(cb.executions)
+		slowRate := float64(cb.slows) / float64(cb.executions)
+		if (cb.config.MaxErrorRate > 0 && errorRate > cb.config.MaxErrorRate) ||
+			(cb.config.SlowThreshold > 0 && slowRate > cb.config.MaxSlowRate) {
+			cb.setState(CircuitOpen)
+		}
+	}
+}
+
+// refreshState changes the state to half-open if the open duration is
+// elapsed, the mutex being locked.
+func (cb *circuitBreaker) refreshState() {
+	if cb.state == CircuitOpen && cb.now().Sub(cb.openedAt) >= cb.config.OpenDuration {
+		cb.setState(CircuitHalfOpen)
+	}
+}
+
+// unlock unlocks the mutex, then notifies the state changes made while it
+// was locked.
+func (cb *circuitBreaker) unlock() {
+	changes := cb.changes
+	cb.changes = nil
+	cb.mutex.Unlock()
+
+	for _, change := range changes {
+		cb.log("Circuit breaker state change :", change.from, "->", change.to)
+		if cb.config.OnStateChange != nil {
+			cb.config.OnStateChange(change.from, change.to)
+		}
+	}
+}
+
+// setState changes the state, the mutex being locked. The change is notified
+// by unlock.
+func (cb *circuitBreaker) setState(state CircuitState) {
+	previous := cb.state
+	cb.state = state
+	switch state {
+	case CircuitOpen:
+		cb.openedAt = cb.now()
+	case CircuitHalfOpen:
+		cb.probes = 0
+		cb.successfulProbes = 0
+	case CircuitClosed:
+		cb.resetWindow()
+	}
+
+	cb.changes = append(cb.changes, stateChange{from: previous, to: state})
+}
+
+// resetWindow starts a new counting window.
+func (cb *circuitBreaker) resetWindow() {
+	cb.windowStart = cb.now()
+	cb.executions = 0
+	cb.failures = 0
+	cb.slows = 0
+}
@@ -0,0 +1,132 @@
+package godb
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	Convey("Given a circuit breaker with a fake clock", t, func() {
+		now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		changes := make([]string, 0)
+		cb := newCircuitBreaker(CircuitBreakerConfig{
+			MinExecutions: 4,
+			SlowThreshold: time.Second,
+			OpenDuration:  time.Minute,
+			OnStateChange: func(from CircuitState, to CircuitState) {
+				changes = append(changes, from.String()+" -> "+to.String())
+			},
+		}, func(err error) error { return err }, func(v ...interface{}) {})
+		cb.now = func() time.Time { return now }
+		failure := errors.New("connection refused")
+
+		execute := func(duration time.Duration, err error) error {
+			if err := cb.allow(); err != nil {
+				return err
+			}
+			cb.record(duration, err)
+			return nil
+		}
+
+		Convey("It opens when the error rate is exceeded", func() {
+			So(execute(0, nil), ShouldBeNil)
+			So(execute(0, failure), ShouldBeNil)
+			So(execute(0, sql.ErrNoRows), ShouldBeNil)
+			So(cb.currentState(), ShouldEqual, CircuitClosed)
+			So(execute(0, failure), ShouldBeNil)
+			So(cb.currentState(), ShouldEqual, CircuitClosed)
+			So(execute(0, failure), ShouldBeNil)
+			So(cb.currentState(), ShouldEqual, CircuitOpen)
+			So(execute(0, nil), ShouldEqual, ErrCircuitOpen)
+
+			Convey("It lets a probe through after the open duration", func() {
+				now = now.Add(time.Minute)
+				So(cb.currentState(), ShouldEqual, CircuitHalfOpen)
+				So(cb.allow(), ShouldBeNil)
+				So(cb.allow(), ShouldEqual, ErrCircuitOpen)
+
+				Convey("A successful probe closes it", func() {
+					cb.record(0, nil)
+					So(cb.currentState(), ShouldEqual, CircuitClosed)
+					So(changes, ShouldResemble, []string{"closed -> open", "open -> half-open", "half-open -> closed"})
+				})
+
+				Convey("A failed probe opens it again", func() {
+					cb.record(0, failure)
+					So(cb.currentState(), ShouldEqual, CircuitOpen)
+				})
+			})
+		})
+
+		Convey("It opens when the slow rate is exceeded", func() {
+			for i := 0; i < 4; i++ {
+				So(execute(2*time.Second, nil), ShouldBeNil)
+			}
+			So(cb.currentState(), ShouldEqual, CircuitOpen)
+		})
+
+		Convey("The counts are reset with each window", func() {
+			So(execute(0, failure), ShouldBeNil)
+			So(execute(0, failure), ShouldBeNil)
+			So(execute(0, failure), ShouldBeNil)
+			now = now.Add(time.Minute)
+			So(execute(0, failure), ShouldBeNil)
+			So(cb.currentState(), ShouldEqual, CircuitClosed)
+		})
+	})
+}
+
+func TestSetCircuitBreaker(t *testing.T) {
+	Convey("Given a test database with a circuit breaker", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		db.SetCircuitBreaker(&CircuitBreakerConfig{MinExecutions: 2})
+
+		Convey("The failing executions open it", func() {
+			for i := 0; i < 2; i++ {
+				_, _, err := db.RawSQL("SELECT * FROM unknown_table").DoExec()
+				So(err, ShouldNotBeNil)
+			}
+			So(db.CircuitState(), ShouldEqual, CircuitOpen)
+			So(db.Clone().CircuitState(), ShouldEqual, CircuitOpen)
+
+			_, err := db.SelectFrom("dummies").Count()
+			So(errors.Is(err, ErrCircuitOpen), ShouldBeTrue)
+		})
+
+		Convey("The constraint violations are not failures", func() {
+			_, err := db.sqlDB.Exec("create unique index dummies_a_text on dummies(a_text)")
+			So(err, ShouldBeNil)
+			for i := 0; i < 2; i++ {
+				_, err := db.InsertInto("dummies").Columns("a_text", "another_text", "an_integer").Values("First", "Dup", 1).Do()
+				So(err, ShouldNotBeNil)
+			}
+			So(db.CircuitState(), ShouldEqual, CircuitClosed)
+		})
+
+		Convey("The state changes are notified without lock", func() {
+			states := make([]CircuitState, 0)
+			db.SetCircuitBreaker(&CircuitBreakerConfig{
+				MinExecutions: 2,
+				OnStateChange: func(from CircuitState, to CircuitState) {
+					states = append(states, db.CircuitState())
+				},
+			})
+			for i := 0; i < 2; i++ {
+				_, _, err := db.RawSQL("SELECT * FROM unknown_table").DoExec()
+				So(err, ShouldNotBeNil)
+			}
+			So(states, ShouldResemble, []CircuitState{CircuitOpen})
+		})
+
+		Convey("A nil configuration removes it", func() {
+			db.SetCircuitBreaker(nil)
+			So(db.CircuitState(), ShouldEqual, CircuitClosed)
+			So(db.breaker, ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,223 @@
+package godb
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultTimeLayouts are the layouts parsing the times given as strings, if
+// none are configured.
+var defaultTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"}
+
+// TolerantScanConfig is the configuration of the tolerant scan mode, see
+// SetTolerantScan. The zero values are replaced by the defaults.
+type TolerantScanConfig struct {
+	// TimeLayouts are the layouts tried in order to parse the times given as
+	// strings (default RFC 3339, "2006-01-02 15:04:05.999999999" and
+	// "2006-01-02").
+	TimeLayouts []string
+	// Location is the time zone of the times parsed without one (default
+	// UTC).
+	Location *time.Location
+}
+
+// coercePointers wraps the pointers given by the pointersGetter with
+// coercingScanner, if the DB is in tolerant scan mode.
+func (db *DB) coercePointers(getter pointersGetter) pointersGetter {
+	if db.tolerantScan == nil {
+		return getter
+	}
+	return func(record interface{}, columns []string) ([]interface{}, error) {
+		pointers, err := getter(record, columns)
+		if err != nil {
+			return nil, err
+		}
+		return db.coercingPointers(pointers), nil
+	}
+}
+
+// coercingPointers wraps the given pointers with coercingScanner.
+func (db *DB) coercingPointers(pointers []interface{}) []interface{} {
+	for i, pointer := range pointers {
+		if _, ok := pointer.(*coercingScanner); !ok {
+			pointers[i] = &coercingScanner{destination: pointer, config: db.tolerantScan}
+		}
+	}
+	return pointers
+}
+
+// timeLayouts returns the layouts parsing the times, a nil configuration
+// giving the default ones.
+func (config *TolerantScanConfig) timeLayouts() []string {
+	if config == nil || len(config.TimeLayouts) == 0 {
+		return defaultTimeLayouts
+	}
+	return config.TimeLayouts
+}
+
+// location returns the time zone of the times parsed without one.
+func (config *TolerantScanConfig) location() *time.Location {
+	if config == nil || config.Location == nil {
+		return time.UTC
+	}
+	return config.Location
+}
+
+// coercingScanner scans a value into a field, converting it if its type
+// doesn't match.
+type coercingScanner struct {
+	destination interface{}
+	config      *TolerantScanConfig
+}
+
+// Scan implements sql.Scanner.
+func (cs *coercingScanner) Scan(src interface{}) error {
+	if scanner, ok := cs.destination.(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+	return coerce(reflect.ValueOf(cs.destination).Elem(), src, cs.config)
+}
+
+// coerce sets the given value from the source.
+func coerce(value reflect.Value, src interface{}, config *TolerantScanConfig) error {
+	if src == nil {
+		value.Set(reflect.Zero(value.Type()))
+		return nil
+	}
+	if value.Kind() == reflect.Ptr {
+		element := reflect.New(value.Type().Elem())
+		if err := coerce(element.Elem(), src, config); err != nil {
+			return err
+		}
+		value.Set(element)
+		return nil
+	}
+	if scanner, ok := value.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+	if bytes, ok := src.([]byte); ok {
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+			value.SetBytes(append([]byte(nil), bytes...))
+			return nil
+		}
+		src = string(bytes)
+	}
+
+	srcValue := reflect.ValueOf(src)
+	if srcValue.Type().AssignableTo(value.Type()) {
+		value.Set(srcValue)
+		return nil
+	}
+
+	var err error
+	switch value.Kind() {
+	case reflect.String:
+		if t, ok := src.(time.Time); ok {
+			value.SetString(t.Format(time.RFC3339Nano))
+		} else {
+			value.SetString(fmt.Sprint(src))
+		}
+		return nil
+	case reflect.Bool:
+		var b bool
+		switch s := src.(type) {
+		case int64:
+			b = s != 0
+		case float64:
+			b = s != 0
+		case string:
+			b, err = strconv.ParseBool(strings.TrimSpace(s))
+		default:
+			err = errCoercion
+		}
+		if err == nil {
+			value.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		switch s := src.(type) {
+		case int64:
+			i = s
+		case float64:
+			i = int64(s)
+			if float64(i) != s {
+				err = errCoercion
+			}
+		case bool:
+			if s {
+				i = 1
+			}
+		case string:
+			i, err = strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		default:
+			err = errCoercion
+		}
+		if err == nil && value.OverflowInt(i) {
+			err = errCoercion
+		}
+		if err == nil {
+			value.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		switch s := src.(type) {
+		case int64:
+			u = uint64(s)
+			if s < 0 {
+				err = errCoercion
+			}
+		case string:
+			u, err = strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+		default:
+			err = errCoercion
+		}
+		if err == nil && value.OverflowUint(u) {
+			err = errCoercion
+		}
+		if err == nil {
+			value.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch s := src.(type) {
+		case int64:
+			f = float64(s)
+		case float64:
+			f = s
+		case string:
+			f, err = strconv.ParseFloat(strings.TrimSpace(s), 64)
+		default:
+			err = errCoercion
+		}
+		if err == nil {
+			value.SetFloat(f)
+		}
+	default:
+		if t, ok := src.(string); ok && value.Type() == reflect.TypeOf(time.Time{}) {
+			var parsed time.Time
+			for _, layout := range config.timeLayouts() {
+				if parsed, err = time.ParseInLocation(layout, strings.TrimSpace(t), config.location()); err == nil {
+					break
+				}
+			}
+			if err == nil {
+				value.Set(reflect.ValueOf(parsed))
+			}
+		} else if srcValue.Type().ConvertibleTo(value.Type()) {
+			value.Set(srcValue.Convert(value.Type()))
+		} else {
+			err = errCoercion
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("can't convert %v (%T) to %s", src, src, value.Type())
+	}
+	return nil
+}
+
+// errCoercion is the error of a value which can't be converted.
+var errCoercion = fmt.Errorf("conversion failure")
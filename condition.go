@@ -2,9 +2,12 @@ package godb
 
 import (
 	"bytes"
+	"database/sql/driver"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
+	"unicode"
 )
 
 // Condition is a struct allowing complex condition building, composing
@@ -23,7 +26,28 @@ func (c *Condition) Err() error {
 // Q builds a simple condition, managing slices in a particular way : it
 // replace the single placeholder with multiples ones according to the number
 // of arguments.
+//
+// NULL arguments (nil, nil pointers, invalid sql.Null* values, ...) are
+// managed too : 'col = ?' becomes 'col IS NULL', 'col <> ?' (or 'col != ?')
+// becomes 'col IS NOT NULL', in other cases NULL is given as a bind value.
 func Q(sql string, args ...interface{}) *Condition {
+	return q(sql, false, args)
+}
+
+// QAllowEmpty builds a condition like Q, but an empty slice used in a IN
+// clause does not produce an error. The whole 'column IN (?)' part is
+// replaced with an always false predicate (1=0), and 'column NOT IN (?)'
+// with an always true one (1=1).
+func QAllowEmpty(sql string, args ...interface{}) *Condition {
+	return q(sql, true, args)
+}
+
+// inClauseBeforePlaceholder matches the beginning of a IN clause, just before
+// its placeholder, without its left operand (see leftOperandStart).
+var inClauseBeforePlaceholder = regexp.MustCompile(`(?i)\s+(NOT\s+)?IN\s*\(\s*$`)
+
+// q builds a condition for Q and QAllowEmpty.
+func q(sql string, allowEmpty bool, args []interface{}) *Condition {
 	c := Condition{}
 
 	if strings.Count(sql, Placeholder) != len(args) {
@@ -36,23 +60,31 @@ func Q(sql string, args ...interface{}) *Condition {
 	remainingSQL := sql[:]
 	// Search slice args to manage case like "WHERE id IN (?)"
 	for _, arg := range args {
-		if arg == nil {
-			c.err = fmt.Errorf("using nil as argument in condition %s", sql)
-			return &c
-		}
-
 		placeholderPos = strings.Index(remainingSQL, Placeholder)
 		buffer.WriteString(remainingSQL[:placeholderPos])
 		remainingSQL = remainingSQL[placeholderPos+1:]
 		t := reflect.TypeOf(arg)
-		// t could be nil if arguments are not given (nil) to prepare a sql statement
-		if t != nil && t.Kind() == reflect.Slice {
+		if isNullArgument(arg) {
+			// NULL, 'col = ?' and 'col <> ?' become 'col IS [NOT] NULL'
+			if !replaceNullComparison(buffer) {
+				buffer.WriteString(Placeholder)
+				c.args = append(c.args, arg)
+			}
+		} else if t.Kind() == reflect.Slice {
 			// Slices. They can't be empty.
 			v := reflect.ValueOf(arg)
 			length := reflect.ValueOf(arg).Len()
 			if length == 0 {
-				c.err = fmt.Errorf("empty slice used as argument in condition %s", sql)
-				return &c
+				if !allowEmpty {
+					c.err = fmt.Errorf("empty slice used as argument in condition %s", sql)
+					return &c
+				}
+				remainingSQL, c.err = replaceEmptyInClause(buffer, remainingSQL)
+				if c.err != nil {
+					c.err = fmt.Errorf("%v in condition %s", c.err, sql)
+					return &c
+				}
+				continue
 			}
 			for i := 0; i < length; i++ {
 				c.args = append(c.args, v.Index(i).Interface())
@@ -70,6 +102,100 @@ func Q(sql string, args ...interface{}) *Condition {
 	return &c
 }
 
+// comparisonBeforePlaceholder matches an equality or inequality operator,
+// just before a placeholder.
+var comparisonBeforePlaceholder = regexp.MustCompile(`(?:^|[^<>!])(\s*)(<>|!=|=)\s*$`)
+
+// isNullArgument returns true if the given argument is nil, a nil pointer, or
+// a valuer returning nil (like a sql.NullString which is not valid).
+func isNullArgument(arg interface{}) bool {
+	if arg == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(arg)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return true
+		}
+	}
+
+	if valuer, ok := arg.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		return err == nil && value == nil
+	}
+	return false
+}
+
+// replaceNullComparison replaces an equality or inequality operator ending the
+// buffer with IS NULL or IS NOT NULL. It returns false if there is no such
+// operator.
+func replaceNullComparison(buffer *bytes.Buffer) bool {
+	match := comparisonBeforePlaceholder.FindStringSubmatchIndex(buffer.String())
+	if match == nil {
+		return false
+	}
+
+	operator := buffer.String()[match[4]:match[5]]
+	buffer.Truncate(match[2])
+	if operator == "=" {
+		buffer.WriteString(" IS NULL")
+	} else {
+		buffer.WriteString(" IS NOT NULL")
+	}
+	return true
+}
+
+// replaceEmptyInClause replaces the IN clause ending the buffer, and the
+// closing parenthesis starting the remaining sql, with a constant predicate.
+// It returns the remaining sql after the IN clause.
+func replaceEmptyInClause(buffer *bytes.Buffer, remainingSQL string) (string, error) {
+	written := buffer.String()
+	match := inClauseBeforePlaceholder.FindStringSubmatchIndex(written)
+	trimmedSQL := strings.TrimLeftFunc(remainingSQL, unicode.IsSpace)
+	if match == nil || !strings.HasPrefix(trimmedSQL, ")") {
+		return "", fmt.Errorf("empty slice used as argument outside of a IN clause")
+	}
+	operandStart := leftOperandStart(written[:match[0]])
+	if operandStart == -1 {
+		return "", fmt.Errorf("unable to find the left operand of the IN clause")
+	}
+
+	buffer.Truncate(operandStart)
+	if match[2] == -1 {
+		buffer.WriteString("1=0")
+	} else {
+		buffer.WriteString("1=1")
+	}
+	return trimmedSQL[1:], nil
+}
+
+// leftOperandStart returns the position of the operand ending the given sql,
+// a column or an expression like f(a, b), or -1 if there is none or if its
+// parentheses aren't balanced.
+func leftOperandStart(sql string) int {
+	depth := 0
+	start := len(sql)
+	for ; start > 0; start-- {
+		c := sql[start-1]
+		if c == ')' {
+			depth++
+		} else if c == '(' {
+			if depth == 0 {
+				break
+			}
+			depth--
+		} else if depth == 0 && unicode.IsSpace(rune(c)) {
+			break
+		}
+	}
+	if depth != 0 || start == len(sql) {
+		return -1
+	}
+	return start
+}
+
 // And combines two or more conditions inserting 'AND' between each
 // given conditions.
 func And(conditions ...*Condition) *Condition {
@@ -0,0 +1,232 @@
+package godb
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// ColumnCondition is the starting point of a fluent condition, built with Col.
+//
+// Example :
+// 	condition := godb.Col("age").Gte(18).And(godb.Col("status").In("a", "b"))
+type ColumnCondition struct {
+	column string
+	// expression replaces the column if not empty, it's already checked
+	expression string
+	// args are the arguments of the expression
+	args []interface{}
+	err  error
+}
+
+// Col starts a fluent condition on the given column. The column name is
+// checked like Ident, but not quoted (like in conditions built with Q).
+//
+// It isn't named C : the tests of godb dot-import goconvey, which declares C,
+// and a package level C would break all of them.
+func Col(column string) *ColumnCondition {
+	return &ColumnCondition{column: column}
+}
+
+// ColExpr starts a fluent condition on the given expression, like a function
+// call built with Fn, rendered according to the adapter.
+//
+// Example :
+// 	db.SelectFrom("users").WhereQ(db.ColExpr(godb.Fn("LENGTH", godb.Ident("name"))).Gt(20))
+func (db *DB) ColExpr(expression Expression) *ColumnCondition {
+	if isNullArgument(expression) {
+		return &ColumnCondition{err: fmt.Errorf("nil expression")}
+	}
+	sql, args, err := expression.BuildSQL(db.adapter)
+	if err != nil {
+		return &ColumnCondition{err: err}
+	}
+	return &ColumnCondition{expression: sql, args: args}
+}
+
+// Eq builds a 'column = ?' condition.
+func (cc *ColumnCondition) Eq(value interface{}) *Condition {
+	return cc.compare("=", value)
+}
+
+// Ne builds a 'column <> ?' condition.
+func (cc *ColumnCondition) Ne(value interface{}) *Condition {
+	return cc.compare("<>", value)
+}
+
+// Lt builds a 'column < ?' condition.
+func (cc *ColumnCondition) Lt(value interface{}) *Condition {
+	return cc.compare("<", value)
+}
+
+// Lte builds a 'column <= ?' condition.
+func (cc *ColumnCondition) Lte(value interface{}) *Condition {
+	return cc.compare("<=", value)
+}
+
+// Gt builds a 'column > ?' condition.
+func (cc *ColumnCondition) Gt(value interface{}) *Condition {
+	return cc.compare(">", value)
+}
+
+// Gte builds a 'column >= ?' condition.
+func (cc *ColumnCondition) Gte(value interface{}) *Condition {
+	return cc.compare(">=", value)
+}
+
+// Like builds a 'column LIKE ?' condition.
+func (cc *ColumnCondition) Like(pattern string) *Condition {
+	return cc.compare("LIKE", pattern)
+}
+
+// In builds a 'column IN (?, ...)' condition. At least one value is needed.
+func (cc *ColumnCondition) In(values ...interface{}) *Condition {
+	return cc.compare("IN", values)
+}
+
+// NotIn builds a 'column NOT IN (?, ...)' condition. At least one value is
+// needed.
+func (cc *ColumnCondition) NotIn(values ...interface{}) *Condition {
+	return cc.compare("NOT IN", values)
+}
+
+// Between builds a 'column BETWEEN ? AND ?' condition.
+func (cc *ColumnCondition) Between(low interface{}, high interface{}) *Condition {
+	left, err := cc.left()
+	if err != nil {
+		return &Condition{err: err}
+	}
+	args := append(append([]interface{}(nil), cc.args...), low, high)
+	return Q(left+" BETWEEN ? AND ?", args...)
+}
+
+// IsNull builds a 'column IS NULL' condition.
+func (cc *ColumnCondition) IsNull() *Condition {
+	left, err := cc.left()
+	if err != nil {
+		return &Condition{err: err}
+	}
+	return Q(left+" IS NULL", cc.args...)
+}
+
+// IsNotNull builds a 'column IS NOT NULL' condition.
+func (cc *ColumnCondition) IsNotNull() *Condition {
+	left, err := cc.left()
+	if err != nil {
+		return &Condition{err: err}
+	}
+	return Q(left+" IS NOT NULL", cc.args...)
+}
+
+// compare builds a condition comparing the column with a single placeholder.
+func (cc *ColumnCondition) compare(operator string, value interface{}) *Condition {
+	left, err := cc.left()
+	if err != nil {
+		return &Condition{err: err}
+	}
+	placeholder := Placeholder
+	if operator == "IN" || operator == "NOT IN" {
+		placeholder = "(" + Placeholder + ")"
+	}
+	args := append(append([]interface{}(nil), cc.args...), value)
+	return Q(left+" "+operator+" "+placeholder, args...)
+}
+
+// left returns the left part of the condition, the checked column or the
+// expression.
+func (cc *ColumnCondition) left() (string, error) {
+	if cc.err != nil {
+		return "", cc.err
+	}
+	if cc.expression != "" {
+		return cc.expression, nil
+	}
+	if err := checkIdentifier(cc.column); err != nil {
+		return "", err
+	}
+	return cc.column, nil
+}
+
+// BuildSQL allows the use of the column (quoted) or the expression in
+// ColumnsExpr, GroupByExpr and OrderByExpr.
+func (cc *ColumnCondition) BuildSQL(adapter adapters.Adapter) (string, []interface{}, error) {
+	if cc.expression != "" || cc.err != nil {
+		left, err := cc.left()
+		return left, cc.args, err
+	}
+	return Ident(cc.column).BuildSQL(adapter)
+}
+
+// And combines the condition with the given ones, see And.
+func (c *Condition) And(conditions ...*Condition) *Condition {
+	return And(append([]*Condition{c}, conditions...)...)
+}
+
+// Or combines the condition with the given ones, see Or.
+func (c *Condition) Or(conditions ...*Condition) *Condition {
+	return Or(append([]*Condition{c}, conditions...)...)
+}
+
+// jsonPathFormat is the accepted format of JSON paths : '$' followed by keys
+// ('.key') or array indexes ('[0]').
+var jsonPathFormat = regexp.MustCompile(`^\$(\.[A-Za-z_][A-Za-z0-9_]*|\[[0-9]+\])*$`)
+
+// JSONPath starts a fluent condition on the value at the given path of a JSON
+// column, rendered according to the adapter. The path is like
+// '$.customer.id' or '$.items[0].price'. With PostgreSQL the value is
+// compared as text.
+//
+// It's also usable as an expression in ColumnsExpr, GroupByExpr and
+// OrderByExpr.
+//
+// Example :
+// 	db.SelectFrom("orders").WhereQ(db.JSONPath("payload", "$.customer.id").Eq(42))
+func (db *DB) JSONPath(column string, path string) *ColumnCondition {
+	cc := &ColumnCondition{column: column}
+	builder, ok := db.adapter.(adapters.JSONPathBuilder)
+	if !ok {
+		cc.err = fmt.Errorf("the adapter does not support JSON paths")
+		return cc
+	}
+	if err := checkIdentifier(column); err != nil {
+		cc.err = err
+		return cc
+	}
+	if !jsonPathFormat.MatchString(path) {
+		cc.err = fmt.Errorf("invalid JSON path %s", path)
+		return cc
+	}
+
+	cc.expression = builder.BuildJSONPath(db.quote(column), path)
+	return cc
+}
+
+// ILike builds a case insensitive LIKE condition, rendered according to the
+// adapter : ILIKE with PostgreSQL, 'LOWER(column) LIKE LOWER(?)' otherwise.
+func (db *DB) ILike(column string, pattern string) *Condition {
+	if err := checkIdentifier(column); err != nil {
+		return &Condition{err: err}
+	}
+
+	if builder, ok := db.adapter.(adapters.ILikeBuilder); ok {
+		return Q(builder.BuildILike(db.quote(column)), pattern)
+	}
+	return Q("LOWER("+db.quote(column)+") LIKE LOWER(?)", pattern)
+}
+
+// UnaccentILike builds a case and accent insensitive LIKE condition,
+// rendered according to the adapter : the unaccent extension with PostgreSQL,
+// an accent insensitive collation with MySQL and SQL Server. It's not
+// available with SQLite.
+func (db *DB) UnaccentILike(column string, pattern string) *Condition {
+	builder, ok := db.adapter.(adapters.UnaccentILikeBuilder)
+	if !ok {
+		return &Condition{err: fmt.Errorf("the adapter does not support accent insensitive conditions")}
+	}
+	if err := checkIdentifier(column); err != nil {
+		return &Condition{err: err}
+	}
+
+	return Q(builder.BuildUnaccentILike(db.quote(column)), pattern)
+}
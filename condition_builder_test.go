@@ -0,0 +1,102 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/postgresql"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestColumnCondition(t *testing.T) {
+	Convey("Col builds conditions on a column", t, func() {
+		c := Col("age").Gte(18)
+		So(c.err, ShouldBeNil)
+		So(c.sql, ShouldEqual, "age >= ?")
+		So(c.args, ShouldResemble, []interface{}{18})
+
+		c = Col("status").In("a", "b")
+		So(c.err, ShouldBeNil)
+		So(c.sql, ShouldEqual, "status IN (?,?)")
+		So(c.args, ShouldResemble, []interface{}{"a", "b"})
+
+		c = Col("books.published").Between(1900, 2000)
+		So(c.sql, ShouldEqual, "books.published BETWEEN ? AND ?")
+		So(c.args, ShouldResemble, []interface{}{1900, 2000})
+
+		c = Col("deleted_at").Eq(nil)
+		So(c.sql, ShouldEqual, "deleted_at IS NULL")
+		So(c.args, ShouldBeEmpty)
+
+		c = Col("deleted_at").IsNull()
+		So(c.sql, ShouldEqual, "deleted_at IS NULL")
+		So(c.args, ShouldBeEmpty)
+	})
+
+	Convey("Conditions are chainable", t, func() {
+		c := Col("age").Gte(18).And(Col("status").In("a", "b").Or(Col("vip").Eq(true)))
+		So(c.err, ShouldBeNil)
+		So(c.sql, ShouldEqual, "age >= ? AND (status IN (?,?) OR vip = ?)")
+		So(c.args, ShouldResemble, []interface{}{18, "a", "b", true})
+	})
+
+	Convey("Errors are kept", t, func() {
+		So(Col("age; drop table users").Eq(1).Err(), ShouldNotBeNil)
+		So(Col("status").In().Err(), ShouldNotBeNil)
+		So(Col("age").Gte(18).And(Col("").Eq(1)).Err(), ShouldNotBeNil)
+	})
+}
+
+func TestJSONPath(t *testing.T) {
+	Convey("JSONPath builds conditions on JSON values", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+		c := db.JSONPath("payload", "$.customer.id").Eq(42)
+		So(c.Err(), ShouldBeNil)
+		So(c.sql, ShouldEqual, `json_extract("payload", '$.customer.id') = ?`)
+		So(c.args, ShouldResemble, []interface{}{42})
+
+		c = db.JSONPath("payload", "$.items[0]").IsNotNull()
+		So(c.sql, ShouldEqual, `json_extract("payload", '$.items[0]') IS NOT NULL`)
+	})
+
+	Convey("JSONPath is an expression", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+		sql, _, err := db.SelectFrom("orders").
+			Columns("id").
+			OrderByExpr(Desc(db.JSONPath("payload", "$.total"))).
+			ToSQL()
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, `SELECT id FROM orders ORDER BY json_extract("payload", '$.total') DESC`)
+	})
+
+	Convey("JSONPath checks the column and the path", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+		So(db.JSONPath("payload", "$.id'); --").Eq(1).Err(), ShouldNotBeNil)
+		So(db.JSONPath("payload)", "$.id").Eq(1).Err(), ShouldNotBeNil)
+	})
+}
+
+func TestILike(t *testing.T) {
+	Convey("ILike builds a case insensitive condition", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+		c := db.ILike("title", "%verne%")
+		So(c.Err(), ShouldBeNil)
+		So(c.sql, ShouldEqual, `LOWER("title") LIKE LOWER(?)`)
+		So(c.args, ShouldResemble, []interface{}{"%verne%"})
+
+		db = &DB{adapter: postgresql.Adapter}
+		c = db.ILike("books.title", "%verne%")
+		So(c.Err(), ShouldBeNil)
+		So(c.sql, ShouldEqual, `"books"."title" ILIKE ?`)
+	})
+
+	Convey("UnaccentILike builds a case and accent insensitive condition", t, func() {
+		db := &DB{adapter: postgresql.Adapter}
+		c := db.UnaccentILike("title", "%etoile%")
+		So(c.Err(), ShouldBeNil)
+		So(c.sql, ShouldEqual, `unaccent("title") ILIKE unaccent(?)`)
+
+		db = &DB{adapter: sqlite.Adapter}
+		So(db.UnaccentILike("title", "%etoile%").Err(), ShouldNotBeNil)
+	})
+}
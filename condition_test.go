@@ -1,6 +1,7 @@
 package godb
 
 import (
+	"database/sql"
 	"fmt"
 	"testing"
 
@@ -43,9 +44,77 @@ func TestQ(t *testing.T) {
 			q := Q("id IN (?)", []int{})
 			So(q.err, ShouldNotBeNil)
 		})
+	})
+}
+
+func TestQWithNull(t *testing.T) {
+	Convey("Q manages NULL arguments", t, func() {
+		Convey("Equality becomes IS NULL", func() {
+			q := Q("deleted_at = ? AND id = ?", nil, 123)
+			So(q.err, ShouldBeNil)
+			So(q.sql, ShouldEqual, "deleted_at IS NULL AND id = ?")
+			So(q.args, ShouldResemble, []interface{}{123})
+		})
+
+		Convey("Inequality becomes IS NOT NULL", func() {
+			var author *string
+			q := Q("author<>? OR title != ?", author, sql.NullString{})
+			So(q.err, ShouldBeNil)
+			So(q.sql, ShouldEqual, "author IS NOT NULL OR title IS NOT NULL")
+			So(q.args, ShouldBeEmpty)
+		})
+
+		Convey("Other cases use NULL as bind value", func() {
+			q := Q("published <= ? OR coalesce(?, 1) = 1", nil, sql.NullInt64{})
+			So(q.err, ShouldBeNil)
+			So(q.sql, ShouldEqual, "published <= ? OR coalesce(?, 1) = 1")
+			So(q.args, ShouldResemble, []interface{}{nil, sql.NullInt64{}})
+		})
+
+		Convey("Valid sql.Null* values are kept as is", func() {
+			q := Q("id = ?", sql.NullInt64{Int64: 123, Valid: true})
+			So(q.sql, ShouldEqual, "id = ?")
+			So(q.args, ShouldResemble, []interface{}{sql.NullInt64{Int64: 123, Valid: true}})
+		})
+	})
+}
+
+func TestQAllowEmpty(t *testing.T) {
+	Convey("QAllowEmpty build", t, func() {
+		Convey("A condition expanding non empty slice argument like Q", func() {
+			q := QAllowEmpty("id IN (?)", []int{123, 456})
+			So(q.err, ShouldBeNil)
+			So(q.sql, ShouldEqual, "id IN (?,?)")
+		})
+
+		Convey("An always false predicate for IN with an empty slice", func() {
+			q := QAllowEmpty("id IN (?) AND is_deleted = ?", []int{}, 0)
+			So(q.err, ShouldBeNil)
+			So(q.sql, ShouldEqual, "1=0 AND is_deleted = ?")
+			So(q.args, ShouldResemble, []interface{}{0})
+		})
+
+		Convey("An always true predicate for NOT IN with an empty slice", func() {
+			q := QAllowEmpty("is_deleted = ? AND books.id not in ( ? )", 0, []int{})
+			So(q.err, ShouldBeNil)
+			So(q.sql, ShouldEqual, "is_deleted = ? AND 1=1")
+			So(q.args, ShouldResemble, []interface{}{0})
+		})
+
+		Convey("An always false predicate for IN with an expression as operand", func() {
+			q := QAllowEmpty("(f(a, b) IN (?) OR id = ?)", []int{}, 1)
+			So(q.err, ShouldBeNil)
+			So(q.sql, ShouldEqual, "(1=0 OR id = ?)")
+			So(q.args, ShouldResemble, []interface{}{1})
+		})
+
+		Convey("An error if the operand of the IN clause isn't balanced", func() {
+			q := QAllowEmpty("a) IN (?)", []int{})
+			So(q.err, ShouldNotBeNil)
+		})
 
-		Convey("Error is set if a given slice is nil", func() {
-			q := Q("id IN (?)", nil)
+		Convey("An error if the empty slice is not used in a IN clause", func() {
+			q := QAllowEmpty("id = ?", []int{})
 			So(q.err, ShouldNotBeNil)
 		})
 	})
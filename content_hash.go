@@ -0,0 +1,74 @@
+package godb
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"sync"
+)
+
+// hashAlgorithms are the algorithms of the hash fields, by name (see
+// RegisterHashAlgorithm).
+var hashAlgorithms = struct {
+	sync.RWMutex
+	byName map[string]func() hash.Hash
+}{byName: map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}}
+
+// RegisterHashAlgorithm registers an algorithm of the hash fields, md5,
+// sha1, sha256 and sha512 are available by default.
+//
+// The hash fields are computed by the struct tools on insert and update, from
+// another column of the struct, for deduplication or integrity checks
+// without triggers. They are declared with the hash option of the db tag,
+// algorithm(column). The []byte fields get the raw digest, the others get
+// its hexadecimal form. A NULL source gives a NULL hash.
+//
+// When an update is restricted with Whitelist, the hash columns of the
+// whitelisted columns are updated too.
+//
+// Example :
+// 	type Document struct {
+// 		ID          int    `db:"id,key,auto"`
+// 		Content     string `db:"content"`
+// 		ContentHash string `db:"content_hash,hash=sha256(content)"`
+// 	}
+//
+// 	godb.RegisterHashAlgorithm("sha3-256", sha3.New256)
+func RegisterHashAlgorithm(name string, newHash func() hash.Hash) {
+	hashAlgorithms.Lock()
+	defer hashAlgorithms.Unlock()
+	hashAlgorithms.byName[name] = newHash
+}
+
+// computeHash returns the digest of the data with the given algorithm.
+func computeHash(algorithm string, data []byte) ([]byte, error) {
+	hashAlgorithms.RLock()
+	newHash := hashAlgorithms.byName[algorithm]
+	hashAlgorithms.RUnlock()
+	if newHash == nil {
+		return nil, fmt.Errorf("unknown hash algorithm %s", algorithm)
+	}
+
+	h := newHash()
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// setHashes computes the hash fields of all given records.
+func setHashes(recordDescription *recordDescription) error {
+	for i := 0; i < recordDescription.len(); i++ {
+		record := recordDescription.index(i)
+		if err := recordDescription.structMapping.SetHashFieldsValues(record, computeHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package godb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type HashedDummy struct {
+	ID          int    `db:"id,key,auto"`
+	AText       string `db:"a_text"`
+	AnotherText string `db:"another_text,hash=sha256(a_text)"`
+	AnInteger   int    `db:"an_integer"`
+}
+
+func (*HashedDummy) TableName() string {
+	return "dummies"
+}
+
+func sha256Hex(s string) string {
+	digest := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(digest[:])
+}
+
+func TestHashFields(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("The hash fields are computed on insert", func() {
+			dummy := &HashedDummy{AText: "Content"}
+			So(db.Insert(dummy).Do(), ShouldBeNil)
+			So(dummy.AnotherText, ShouldEqual, sha256Hex("Content"))
+
+			inserted := &Dummy{}
+			So(db.Get(inserted, dummy.ID), ShouldBeNil)
+			So(inserted.AnotherText, ShouldEqual, sha256Hex("Content"))
+		})
+
+		Convey("The hash fields are computed on update, even with a whitelist", func() {
+			dummy := &HashedDummy{AText: "Content"}
+			So(db.Insert(dummy).Do(), ShouldBeNil)
+			dummy.AText = "Changed"
+			So(db.Update(dummy).Whitelist("a_text").Do(), ShouldBeNil)
+
+			updated := &Dummy{}
+			So(db.Get(updated, dummy.ID), ShouldBeNil)
+			So(updated.AnotherText, ShouldEqual, sha256Hex("Changed"))
+		})
+
+		Convey("An unknown algorithm fails", func() {
+			type unknownAlgorithm struct {
+				ID          int    `db:"id,key,auto"`
+				AText       string `db:"a_text"`
+				AnotherText string `db:"another_text,hash=unknown(a_text)"`
+			}
+			err := db.Insert(&unknownAlgorithm{}).Table("dummies").Do()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
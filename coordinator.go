@@ -0,0 +1,120 @@
+package godb
+
+import "fmt"
+
+// Coordinator runs a unit of work writing to several databases, with a best
+// effort consistency : the transactions are begun together, and committed
+// in the order of the participants. If a commit fails, the next participants
+// are rolled back, and the compensations of the committed ones are called in
+// the reverse order. A *PartialCommitError reports the result.
+//
+// The participants order matters : put first the databases whose commit is
+// the most likely to fail, and last the ones without compensation.
+//
+// Example :
+// 	err := godb.NewCoordinator().
+// 		Add("orders", ordersDB, nil).
+// 		Add("billing", billingDB, func() error {
+// 			return cancelInvoice(billingDB, invoice)
+// 		}).
+// 		Run(func() error {
+// 			... (changes using ordersDB and billingDB)
+// 		})
+type Coordinator struct {
+	participants []coordinatorParticipant
+}
+
+// coordinatorParticipant is a database taking part in a Coordinator.
+type coordinatorParticipant struct {
+	name       string
+	db         *DB
+	compensate func() error
+}
+
+// NewCoordinator creates a Coordinator without participant.
+func NewCoordinator() *Coordinator {
+	return &Coordinator{}
+}
+
+// Add adds a participant with its name (used in errors), and a compensation
+// undoing the committed changes if a commit of a next participant fails
+// (nil if there is none). The DB must not be in a transaction, and must not
+// be shared with others goroutines during Run (use a clone).
+func (c *Coordinator) Add(name string, db *DB, compensate func() error) *Coordinator {
+	c.participants = append(c.participants, coordinatorParticipant{
+		name:       name,
+		db:         db,
+		compensate: compensate,
+	})
+	return c
+}
+
+// Run begins the transactions, calls the given function, and commits the
+// transactions if it succeeds. All the transactions are rolled back if the
+// function or a Begin fail, or if the commit of the first participant
+// fails. A *PartialCommitError is returned if a later commit fails.
+func (c *Coordinator) Run(f func() error) error {
+	for i, participant := range c.participants {
+		if err := participant.db.Begin(); err != nil {
+			c.rollback(c.participants[:i])
+			return fmt.Errorf("begin of %s failed : %w", participant.name, err)
+		}
+	}
+
+	if err := f(); err != nil {
+		c.rollback(c.participants)
+		return err
+	}
+
+	for i, participant := range c.participants {
+		err := participant.db.Commit()
+		if err == nil {
+			continue
+		}
+		next := c.participants[i+1:]
+		c.rollback(next)
+		if i == 0 {
+			return fmt.Errorf("commit of %s failed : %w", participant.name, err)
+		}
+		return c.compensate(c.participants[:i], participant.name, next, err)
+	}
+	return nil
+}
+
+// rollback rolls back the transactions of the given participants, the
+// errors are ignored as the changes are lost anyway.
+func (c *Coordinator) rollback(participants []coordinatorParticipant) {
+	for _, participant := range participants {
+		participant.db.Rollback()
+	}
+}
+
+// compensate calls the compensations of the committed participants in the
+// reverse order, and returns the *PartialCommitError.
+func (c *Coordinator) compensate(committed []coordinatorParticipant, failed string, rolledBack []coordinatorParticipant, err error) error {
+	partialCommitErr := &PartialCommitError{
+		Committed:          make([]string, 0, len(committed)),
+		Failed:             failed,
+		RolledBack:         make([]string, 0, len(rolledBack)),
+		Err:                err,
+		CompensationErrors: make(map[string]error),
+	}
+	for _, participant := range committed {
+		partialCommitErr.Committed = append(partialCommitErr.Committed, participant.name)
+	}
+	for _, participant := range rolledBack {
+		partialCommitErr.RolledBack = append(partialCommitErr.RolledBack, participant.name)
+	}
+
+	for i := len(committed) - 1; i >= 0; i-- {
+		participant := committed[i]
+		if participant.compensate == nil {
+			partialCommitErr.CompensationErrors[participant.name] = fmt.Errorf("no compensation")
+			continue
+		}
+		if err := participant.compensate(); err != nil {
+			partialCommitErr.CompensationErrors[participant.name] = err
+		}
+	}
+	return partialCommitErr
+}
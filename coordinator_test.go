@@ -0,0 +1,127 @@
+package godb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/sqlite"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCoordinator(t *testing.T) {
+	Convey("Given three databases", t, func() {
+		openDB := func() *DB {
+			db, err := Open(sqlite.Adapter, ":memory:")
+			So(err, ShouldBeNil)
+			db.CurrentDB().SetMaxOpenConns(1)
+			_, err = db.CurrentDB().Exec(`
+				PRAGMA foreign_keys = ON;
+				create table parents (id integer not null primary key);
+				create table children (
+					id        integer not null primary key,
+					parent_id integer not null references parents(id) deferrable initially deferred);`)
+			So(err, ShouldBeNil)
+			return db
+		}
+		first, second, third := openDB(), openDB(), openDB()
+		defer first.Close()
+		defer second.Close()
+		defer third.Close()
+
+		count := func(db *DB) int64 {
+			count, err := db.SelectFrom("children").Count()
+			So(err, ShouldBeNil)
+			return count
+		}
+		insertChild := func(db *DB, parentID int) error {
+			_, err := db.InsertInto("children").Columns("parent_id").Values(parentID).Do()
+			return err
+		}
+		compensated := make([]string, 0)
+		compensation := func(name string, db *DB) func() error {
+			return func() error {
+				compensated = append(compensated, name)
+				_, err := db.DeleteFrom("children").Do()
+				return err
+			}
+		}
+		coordinator := NewCoordinator().
+			Add("first", first, compensation("first", first)).
+			Add("second", second, compensation("second", second)).
+			Add("third", third, nil)
+		for _, db := range []*DB{first, second, third} {
+			_, err := db.InsertInto("parents").Columns("id").Values(1).Do()
+			So(err, ShouldBeNil)
+		}
+
+		Convey("Run commits all the participants", func() {
+			err := coordinator.Run(func() error {
+				So(insertChild(first, 1), ShouldBeNil)
+				So(insertChild(second, 1), ShouldBeNil)
+				So(insertChild(third, 1), ShouldBeNil)
+				return nil
+			})
+			So(err, ShouldBeNil)
+			So(count(first), ShouldEqual, 1)
+			So(count(second), ShouldEqual, 1)
+			So(count(third), ShouldEqual, 1)
+		})
+
+		Convey("Run rolls back all the participants if the function fails", func() {
+			failure := errors.New("failure")
+			err := coordinator.Run(func() error {
+				So(insertChild(first, 1), ShouldBeNil)
+				return failure
+			})
+			So(err, ShouldEqual, failure)
+			So(first.CurrentTx(), ShouldBeNil)
+			So(count(first), ShouldEqual, 0)
+		})
+
+		Convey("Run rolls back all the participants if the first commit fails", func() {
+			err := coordinator.Run(func() error {
+				So(insertChild(first, 2), ShouldBeNil)
+				So(insertChild(second, 1), ShouldBeNil)
+				return nil
+			})
+			So(err, ShouldNotBeNil)
+			So(errors.Is(err, ErrPartialCommit), ShouldBeFalse)
+			So(count(second), ShouldEqual, 0)
+		})
+
+		Convey("Run compensates the committed participants if a commit fails", func() {
+			err := coordinator.Run(func() error {
+				So(insertChild(first, 1), ShouldBeNil)
+				So(insertChild(second, 1), ShouldBeNil)
+				So(insertChild(third, 2), ShouldBeNil)
+				return nil
+			})
+			So(errors.Is(err, ErrPartialCommit), ShouldBeTrue)
+			partialCommitErr := err.(*PartialCommitError)
+			So(partialCommitErr.Committed, ShouldResemble, []string{"first", "second"})
+			So(partialCommitErr.Failed, ShouldEqual, "third")
+			So(partialCommitErr.RolledBack, ShouldBeEmpty)
+			So(partialCommitErr.CompensationErrors, ShouldBeEmpty)
+			So(compensated, ShouldResemble, []string{"second", "first"})
+			So(count(first), ShouldEqual, 0)
+			So(count(second), ShouldEqual, 0)
+		})
+
+		Convey("The participants without compensation are reported", func() {
+			coordinator := NewCoordinator().
+				Add("third", third, nil).
+				Add("first", first, nil).
+				Add("second", second, nil)
+			err := coordinator.Run(func() error {
+				So(insertChild(first, 2), ShouldBeNil)
+				return nil
+			})
+			partialCommitErr := err.(*PartialCommitError)
+			So(partialCommitErr.Committed, ShouldResemble, []string{"third"})
+			So(partialCommitErr.RolledBack, ShouldResemble, []string{"second"})
+			So(partialCommitErr.CompensationErrors["third"], ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "compensation of third failed")
+		})
+	})
+}
@@ -0,0 +1,94 @@
+package godb
+
+import (
+	"database/sql"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// CountEstimate returns an estimate of the count of rows of the statement,
+// from the statistics of the planner, for the very large tables where
+// COUNT(*) is too slow. The estimate is given by the statistics of the table
+// when the statement selects a whole table, otherwise by the plan of the
+// statement. It uses reltuples and EXPLAIN with PostgreSQL, and
+// information_schema.TABLES with MySQL (whole tables only).
+//
+// The second value is true if the count is exact : COUNT(*) is executed
+// when there is no estimate (others adapters, tables never analyzed, ...).
+//
+// Example :
+// 	count, exact, err := db.SelectFrom("events").CountEstimate()
+func (ss *SelectStatement) CountEstimate() (int64, bool, error) {
+	estimator, ok := ss.db.adapter.(adapters.CountEstimator)
+	if !ok || ss.error != nil {
+		count, err := ss.Count()
+		return count, true, err
+	}
+
+	if ss.selectsWholeTable() {
+		var estimate sql.NullFloat64
+		found, err := ss.db.queryValue(&estimate, estimator.BuildTableRowsEstimate(), guardedTableName(ss.fromTables[0]))
+		if err != nil {
+			return 0, false, err
+		}
+		if found && estimate.Valid && estimate.Float64 >= 0 {
+			return int64(estimate.Float64), false, nil
+		}
+	}
+
+	planned := ss.Clone()
+	if len(planned.columns) == 0 {
+		planned.Columns("*")
+	}
+	query, args, err := planned.ToSQL()
+	if err != nil {
+		return 0, false, err
+	}
+	if explain := estimator.BuildPlanRowsEstimate(query); explain != "" {
+		var plan string
+		found, err := ss.db.queryValue(&plan, explain, args...)
+		if err != nil {
+			return 0, false, err
+		}
+		if found {
+			estimate, err := estimator.ParsePlanRows(plan)
+			return estimate, false, err
+		}
+	}
+
+	count, err := ss.Count()
+	return count, true, err
+}
+
+// selectsWholeTable returns true if the statement selects all the rows of a
+// single table.
+func (ss *SelectStatement) selectsWholeTable() bool {
+	return len(ss.fromTables) == 1 && len(ss.fromArgs) == 0 && ss.tableSample == nil &&
+		len(ss.joins) == 0 && len(ss.where) == 0 && len(ss.groupBy) == 0 &&
+		len(ss.having) == 0 && len(ss.qualify) == 0 && !ss.distinct &&
+		ss.limit == nil && ss.offset == nil && len(ss.suffixes) == 0
+}
+
+// CountEstimate returns an estimate of the count of rows of the statement,
+// see SelectStatement.CountEstimate.
+func (ss *StructSelect) CountEstimate() (int64, bool, error) {
+	if ss.error != nil {
+		return 0, false, ss.error
+	}
+
+	return ss.selectStatement.CountEstimate()
+}
+
+// queryValue executes a query giving a single value, and scans it. It
+// returns false if the query gives no row.
+func (db *DB) queryValue(value interface{}, query string, arguments ...interface{}) (bool, error) {
+	iterator, err := db.RawSQL(query, arguments...).DoWithIterator()
+	if err != nil {
+		return false, err
+	}
+	defer iterator.Close()
+	if !iterator.Next() {
+		return false, iterator.Err()
+	}
+	return true, iterator.Scanx(value)
+}
@@ -0,0 +1,73 @@
+package godb
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// countEstimatorAdapter estimates the tables with a fixed count, and the
+// queries with their exact count.
+type countEstimatorAdapter struct {
+	adapters.Adapter
+	tableRows string
+}
+
+func (a countEstimatorAdapter) BuildTableRowsEstimate() string {
+	return "SELECT " + a.tableRows + " WHERE ? = 'dummies'"
+}
+
+func (countEstimatorAdapter) BuildPlanRowsEstimate(query string) string {
+	return "SELECT 'rows=' || COUNT(*) FROM (" + query + ")"
+}
+
+func (countEstimatorAdapter) ParsePlanRows(plan string) (int64, error) {
+	return strconv.ParseInt(strings.TrimPrefix(plan, "rows="), 10, 64)
+}
+
+func TestCountEstimate(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("CountEstimate returns the exact count if the adapter can't estimate", func() {
+			count, exact, err := db.SelectFrom("dummies").Where("an_integer > ?", 11).CountEstimate()
+			So(err, ShouldBeNil)
+			So(exact, ShouldBeTrue)
+			So(count, ShouldEqual, 2)
+		})
+
+		Convey("CountEstimate uses the statistics of a whole table", func() {
+			db.adapter = countEstimatorAdapter{Adapter: db.adapter, tableRows: "1000"}
+			count, exact, err := db.SelectFrom("dummies").CountEstimate()
+			So(err, ShouldBeNil)
+			So(exact, ShouldBeFalse)
+			So(count, ShouldEqual, 1000)
+
+			count, exact, err = db.Select(&Dummy{}).CountEstimate()
+			So(err, ShouldBeNil)
+			So(exact, ShouldBeFalse)
+			So(count, ShouldEqual, 1000)
+		})
+
+		Convey("CountEstimate uses the plan of the others statements", func() {
+			db.adapter = countEstimatorAdapter{Adapter: db.adapter, tableRows: "1000"}
+			count, exact, err := db.SelectFrom("dummies").Where("an_integer > ?", 11).CountEstimate()
+			So(err, ShouldBeNil)
+			So(exact, ShouldBeFalse)
+			So(count, ShouldEqual, 2)
+		})
+
+		Convey("CountEstimate uses the plan of a table without statistics", func() {
+			db.adapter = countEstimatorAdapter{Adapter: db.adapter, tableRows: "-1"}
+			count, exact, err := db.SelectFrom("dummies").CountEstimate()
+			So(err, ShouldBeNil)
+			So(exact, ShouldBeFalse)
+			So(count, ShouldEqual, 3)
+		})
+	})
+}
@@ -0,0 +1,187 @@
+package godb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// DataSourceProvider returns the data source used to open a new connection.
+// It allows the credentials having a short life, like the authentication
+// tokens of the managed databases (AWS RDS IAM authentication, Azure AD,
+// ...), to be refreshed on each connection.
+type DataSourceProvider func(ctx context.Context) (string, error)
+
+// OpenWithDataSourceProvider creates a new DB struct whose connections are
+// opened with the data source given by the provider, called each time a
+// connection is opened by the pool. The provider has to be safe for
+// concurrent use, and should cache the credentials while they are valid.
+//
+// Example :
+// 	db, err := godb.OpenWithDataSourceProvider(postgresql.Adapter, func(ctx context.Context) (string, error) {
+// 		token, err := auth.BuildAuthToken(ctx, endpoint, region, user, credentials)
+// 		if err != nil {
+// 			return "", err
+// 		}
+// 		return fmt.Sprintf("host=%s user=%s password=%s dbname=app sslmode=verify-full", host, user, url.QueryEscape(token)), nil
+// 	})
+func OpenWithDataSourceProvider(adapter adapters.Adapter, provider DataSourceProvider) (*DB, error) {
+	// sql.Open does not connect, it's only used to get the driver
+	dbInst, err := sql.Open(adapter.DriverName(), "")
+	if err != nil {
+		return nil, err
+	}
+	sqlDriver := dbInst.Driver()
+	dbInst.Close()
+
+	return OpenConnector(adapter, &providerConnector{driver: sqlDriver, provider: provider}), nil
+}
+
+// CredentialSource gives the current credentials of a database, for example
+// from a secrets manager rotating the passwords.
+type CredentialSource interface {
+	// Fetch returns the data source with the current credentials, and how
+	// long it could be used before being fetched again.
+	Fetch(ctx context.Context) (dataSourceName string, ttl time.Duration, err error)
+}
+
+// OpenWithCredentialSource creates a new DB struct whose connections are
+// opened with the data source given by the source. The data source is
+// fetched again once its TTL is over, when a connection is opened or taken
+// from the pool. When it changes (the password was rotated) the connections
+// opened with the previous one are closed instead of being reused, so the
+// rotation does not need a restart of the process.
+//
+// If the data source can't be fetched again, the previous one is still used
+// and the fetch is tried again with the next connection.
+//
+// Example :
+// 	db, err := godb.OpenWithCredentialSource(postgresql.Adapter, secretSource)
+func OpenWithCredentialSource(adapter adapters.Adapter, source CredentialSource) (*DB, error) {
+	dbInst, err := sql.Open(adapter.DriverName(), "")
+	if err != nil {
+		return nil, err
+	}
+	sqlDriver := dbInst.Driver()
+	dbInst.Close()
+
+	connector := &credentialConnector{driver: sqlDriver, source: source}
+	db := OpenConnector(adapter, connector)
+	connector.log = db.logPrintln
+	return db, nil
+}
+
+// OpenConnector creates a new DB struct whose connections are opened by the
+// given connector, for example the one of a driver getting tokens from a
+// credential provider (Azure AD with SQL Server), or of a cloud connector
+// (GCP Cloud SQL). Unlike Wrap, the DB allows SetSessionSetup.
+//
+// Example :
+// 	connector, err := mssql.NewConnectorWithAccessTokenProvider(dsn, tokenProvider)
+// 	db := godb.OpenConnector(mssql.Adapter, connector)
+func OpenConnector(adapter adapters.Adapter, connector driver.Connector) *DB {
+	db := initialize(adapter, sql.OpenDB(connector))
+	db.connector = connector
+	return db
+}
+
+// providerConnector is a driver.Connector opening the connections with the
+// data source given by a provider.
+type providerConnector struct {
+	driver   driver.Driver
+	provider DataSourceProvider
+}
+
+// Connect gets a data source and opens a connection. It implements
+// driver.Connector.
+func (c *providerConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	dataSourceName, err := c.provider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return openDataSource(ctx, c.driver, dataSourceName)
+}
+
+// Driver returns the underlying driver. It implements driver.Connector.
+func (c *providerConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// credentialConnector is a driver.Connector opening the connections with the
+// data source given by a CredentialSource, cached during its TTL.
+type credentialConnector struct {
+	driver         driver.Driver
+	source         CredentialSource
+	log            func(v ...interface{})
+	mutex          sync.Mutex
+	dataSourceName string
+	expiration     time.Time
+	// generation is incremented each time the data source changes
+	generation int64
+}
+
+// Connect opens a connection with the current data source. It implements
+// driver.Connector.
+func (c *credentialConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	dataSourceName, generation, err := c.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := openDataSource(ctx, c.driver, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	return &sessionDriverConn{Conn: conn, credentials: c, generation: generation}, nil
+}
+
+// Driver returns the underlying driver. It implements driver.Connector.
+func (c *credentialConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// current returns the current data source and its generation, fetching it
+// if its TTL is over.
+func (c *credentialConnector) current(ctx context.Context) (string, int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.dataSourceName != "" && time.Now().Before(c.expiration) {
+		return c.dataSourceName, c.generation, nil
+	}
+
+	dataSourceName, ttl, err := c.source.Fetch(ctx)
+	if err != nil {
+		if c.dataSourceName == "" {
+			return "", 0, err
+		}
+		c.log("Credentials fetch failed, the previous ones are used :", err)
+		return c.dataSourceName, c.generation, nil
+	}
+	if c.dataSourceName != "" && dataSourceName != c.dataSourceName {
+		c.log("Credentials rotated, the connections are recycled")
+	}
+	if dataSourceName != c.dataSourceName {
+		c.dataSourceName = dataSourceName
+		c.generation++
+	}
+	c.expiration = time.Now().Add(ttl)
+	return c.dataSourceName, c.generation, nil
+}
+
+// isCurrent tells if the given generation is the current one, fetching the
+// data source if its TTL is over.
+func (c *credentialConnector) isCurrent(ctx context.Context, generation int64) bool {
+	_, current, err := c.current(ctx)
+	return err != nil || current == generation
+}
+
+// currentGeneration returns the generation of the cached data source,
+// without fetching it.
+func (c *credentialConnector) currentGeneration() int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.generation
+}
@@ -0,0 +1,104 @@
+package godb
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOpenWithDataSourceProvider(t *testing.T) {
+	Convey("Given a DB opened with a data source provider", t, func() {
+		var calls int32
+		var failure atomic.Value
+		failure.Store(false)
+		db, err := OpenWithDataSourceProvider(sqlite.Adapter, func(ctx context.Context) (string, error) {
+			atomic.AddInt32(&calls, 1)
+			if failure.Load().(bool) {
+				return "", errors.New("token expired")
+			}
+			return ":memory:", nil
+		})
+		So(err, ShouldBeNil)
+		defer db.Close()
+
+		Convey("The provider is called for each new connection", func() {
+			So(db.CurrentDB().Ping(), ShouldBeNil)
+			So(atomic.LoadInt32(&calls), ShouldEqual, 1)
+
+			db.CurrentDB().SetMaxIdleConns(0)
+			So(db.CurrentDB().Ping(), ShouldBeNil)
+			So(atomic.LoadInt32(&calls), ShouldEqual, 2)
+		})
+
+		Convey("The errors of the provider are returned", func() {
+			failure.Store(true)
+			err := db.CurrentDB().Ping()
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "token expired")
+		})
+
+		Convey("The sessions can be set up", func() {
+			err := db.SetSessionSetup(SessionStatements("PRAGMA foreign_keys = ON"))
+			So(err, ShouldBeNil)
+			var enabled int
+			So(db.CurrentDB().QueryRow("PRAGMA foreign_keys").Scan(&enabled), ShouldBeNil)
+			So(enabled, ShouldEqual, 1)
+		})
+	})
+}
+
+// rotatingSource is a CredentialSource giving the data source stored in it.
+type rotatingSource struct {
+	dataSourceName atomic.Value
+	failure        atomic.Value
+}
+
+func (rs *rotatingSource) Fetch(ctx context.Context) (string, time.Duration, error) {
+	if failure, _ := rs.failure.Load().(bool); failure {
+		return "", 0, errors.New("source unavailable")
+	}
+	return rs.dataSourceName.Load().(string), 0, nil
+}
+
+func TestOpenWithCredentialSource(t *testing.T) {
+	Convey("Given a DB opened with a credential source", t, func() {
+		source := &rotatingSource{}
+		source.dataSourceName.Store("file:credentials_a?mode=memory&cache=shared")
+		source.failure.Store(true)
+		failing, err := OpenWithCredentialSource(sqlite.Adapter, source)
+		So(err, ShouldBeNil)
+		So(failing.CurrentDB().Ping(), ShouldNotBeNil)
+		failing.Close()
+		source.failure.Store(false)
+
+		db, err := OpenWithCredentialSource(sqlite.Adapter, source)
+		So(err, ShouldBeNil)
+		defer db.Close()
+		db.CurrentDB().SetMaxOpenConns(1)
+		_, err = db.CurrentDB().Exec("create table rotations (id integer)")
+		So(err, ShouldBeNil)
+
+		Convey("The connections are kept while the data source is the same", func() {
+			_, err := db.CurrentDB().Exec("insert into rotations values (1)")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("The previous data source is used if the fetch fails", func() {
+			source.failure.Store(true)
+			_, err := db.CurrentDB().Exec("insert into rotations values (1)")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("The connections are recycled once the data source is rotated", func() {
+			source.dataSourceName.Store("file:credentials_b?mode=memory&cache=shared")
+			_, err := db.CurrentDB().Exec("insert into rotations values (1)")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "no such table")
+		})
+	})
+}
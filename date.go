@@ -0,0 +1,79 @@
+package godb
+
+import (
+	"fmt"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// DateUnit is a unit of DateAdd and DateTrunc.
+type DateUnit string
+
+// Units of DateAdd and DateTrunc.
+const (
+	Seconds DateUnit = "second"
+	Minutes DateUnit = "minute"
+	Hours   DateUnit = "hour"
+	Days    DateUnit = "day"
+	Months  DateUnit = "month"
+	Years   DateUnit = "year"
+)
+
+// dateExpression is a date computed from a column, see DateAdd and
+// DateTrunc.
+type dateExpression struct {
+	column string
+	unit   DateUnit
+	// amount is the number of units added, for DateAdd
+	amount interface{}
+}
+
+// DateAdd returns an expression adding an amount (negative to subtract) of
+// units to the given date column, rendered according to the adapter (see
+// adapters.DateArithmeticBuilder). The column is checked and quoted like
+// Ident, the amount is given as an argument of the statement. With SQLite
+// the date is returned as text.
+//
+// Example :
+// 	count, err := db.SelectFrom("invoices").
+// 		WhereQ(db.ColExpr(godb.DateAdd("sent_at", 30, godb.Days)).Lt(time.Now())).
+// 		Count()
+func DateAdd(column string, amount int, unit DateUnit) Expression {
+	return &dateExpression{column: column, unit: unit, amount: amount}
+}
+
+// DateTrunc returns an expression truncating the given date column to the
+// given unit (the start of its month for example), rendered according to
+// the adapter (see adapters.DateArithmeticBuilder). The column is checked
+// and quoted like Ident. With SQLite the date is returned as text.
+//
+// Example :
+// 	err := db.SelectFrom("orders").
+// 		ColumnsExpr(godb.DateTrunc("month", "created_at"), godb.Raw("SUM(total)")).
+// 		GroupByExpr(godb.DateTrunc("month", "created_at")).
+// 		Do(&totals)
+func DateTrunc(unit DateUnit, column string) Expression {
+	return &dateExpression{column: column, unit: unit}
+}
+
+// BuildSQL renders the expression for the given adapter.
+func (de *dateExpression) BuildSQL(adapter adapters.Adapter) (string, []interface{}, error) {
+	builder, ok := adapter.(adapters.DateArithmeticBuilder)
+	if !ok {
+		return "", nil, fmt.Errorf("the adapter does not support date arithmetic")
+	}
+	switch de.unit {
+	case Seconds, Minutes, Hours, Days, Months, Years:
+	default:
+		return "", nil, fmt.Errorf("invalid date unit %q", de.unit)
+	}
+	column, _, err := Ident(de.column).BuildSQL(adapter)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if de.amount == nil {
+		return builder.BuildDateTrunc(string(de.unit), column), nil, nil
+	}
+	return builder.BuildDateAdd(column, Placeholder, string(de.unit)), []interface{}{de.amount}, nil
+}
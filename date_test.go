@@ -0,0 +1,70 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/mssql"
+	"github.com/samonzeweb/godb/adapters/mysql"
+	"github.com/samonzeweb/godb/adapters/postgresql"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDateExpressions(t *testing.T) {
+	Convey("DateAdd is rendered according to the adapter", t, func() {
+		sql, args, err := DateAdd("created_at", 7, Days).BuildSQL(mysql.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, "DATE_ADD(`created_at`, INTERVAL ? DAY)")
+		So(args, ShouldResemble, []interface{}{7})
+
+		sql, _, err = DateAdd("created_at", -1, Months).BuildSQL(sqlite.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, `datetime("created_at", ? || ' months')`)
+	})
+
+	Convey("DateTrunc is rendered according to the adapter", t, func() {
+		sql, args, err := DateTrunc("month", "orders.created_at").BuildSQL(mysql.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, "TIMESTAMP(DATE_FORMAT(`orders`.`created_at`, '%Y-%m-01 00:00:00'))")
+		So(args, ShouldBeEmpty)
+
+		sql, _, err = DateTrunc(Hours, "created_at").BuildSQL(sqlite.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, `strftime('%Y-%m-%d %H:00:00', "created_at")`)
+	})
+
+	Convey("The date expressions are merged in the statements", t, func() {
+		db := &DB{adapter: postgresql.Adapter}
+		sql, args, err := db.SelectFrom("orders").
+			ColumnsExpr(DateTrunc(Months, "created_at")).
+			WhereQ(db.ColExpr(DateAdd("created_at", 30, Days)).Lt("2020-01-01")).
+			ToSQL()
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, `SELECT DATE_TRUNC('month', "created_at") FROM orders WHERE ("created_at" + ? * INTERVAL '1 day') < ?`)
+		So(args, ShouldResemble, []interface{}{30, "2020-01-01"})
+	})
+
+	Convey("The invalid expressions are rejected", t, func() {
+		_, _, err := DateAdd("created_at", 1, "week").BuildSQL(mssql.Adapter)
+		So(err, ShouldNotBeNil)
+		_, _, err = DateTrunc(Days, "created at").BuildSQL(mssql.Adapter)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Given a table with dates", t, func() {
+		db := createInMemoryConnection(t)
+		defer db.Close()
+		_, err := db.CurrentDB().Exec("create table events (at datetime); insert into events values ('2020-01-31 10:20:30');")
+		So(err, ShouldBeNil)
+
+		Convey("The dates are computed by the database", func() {
+			var added, truncated string
+			err := db.SelectFrom("events").
+				ColumnsExpr(DateAdd("at", 7, Days), DateTrunc(Months, "at")).
+				Scanx(&added, &truncated)
+			So(err, ShouldBeNil)
+			So(added, ShouldEqual, "2020-02-07 10:20:30")
+			So(truncated, ShouldEqual, "2020-01-01 00:00:00")
+		})
+	})
+}
@@ -0,0 +1,51 @@
+package dbreflect
+
+import (
+	"sync"
+
+	"github.com/samonzeweb/godb/tablenamer"
+)
+
+var (
+	columnNamerLock     sync.RWMutex
+	columnNamer         = tablenamer.Snake()
+	columnNamesInferred bool
+)
+
+// InferColumnNames enables or disables the mapping of the fields without
+// 'db' tag (disabled by default). When enabled, the exported fields without
+// tag are mapped to columns named by the column namer (snake_case by default,
+// see SetColumnNamer), and a tag without column name (like `db:",key,auto"`)
+// uses the same name. Use the `db:"-"` tag to ignore a field. The nested
+// structs still need a tag.
+//
+// Call it before any use of the mappings, the cache is cleared.
+func InferColumnNames(enabled bool) {
+	columnNamerLock.Lock()
+	columnNamesInferred = enabled
+	columnNamerLock.Unlock()
+	Cache.clear()
+}
+
+// SetColumnNamer sets the function building the columns names of the fields
+// without 'db' tag, when the names are inferred (see InferColumnNames).
+// The function gets the field name.
+//
+// Call it before any use of the mappings, the cache is cleared.
+func SetColumnNamer(namer tablenamer.NamerFn) {
+	columnNamerLock.Lock()
+	columnNamer = namer
+	columnNamerLock.Unlock()
+	Cache.clear()
+}
+
+// inferredColumnName returns the column name of a field without explicit
+// name, or a blank string if the names are not inferred.
+func inferredColumnName(fieldName string) string {
+	columnNamerLock.RLock()
+	defer columnNamerLock.RUnlock()
+	if !columnNamesInferred {
+		return ""
+	}
+	return columnNamer(fieldName, false)
+}
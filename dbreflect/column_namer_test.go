@@ -0,0 +1,73 @@
+package dbreflect
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/samonzeweb/godb/tablenamer"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type StructWithoutTags struct {
+	ID          int       `db:"id,key,auto"`
+	AuthorName  string    // snake_case by default
+	Title       string    `db:"book_title"`
+	Ignored     string    `db:"-"`
+	Nested      SubStruct // nested structs need a tag
+	unexported  string
+	PublishedIn *int
+}
+
+type StructWithoutColumnName struct {
+	ID int `db:",key,auto"`
+}
+
+func TestInferColumnNames(t *testing.T) {
+	Convey("Given a struct without tags", t, func() {
+		Convey("Only the tagged fields are mapped by default", func() {
+			structMap, err := NewStructMapping(reflect.TypeOf(StructWithoutTags{}))
+			So(err, ShouldBeNil)
+			So(structMap.GetAllColumnsNames(), ShouldResemble, []string{"id", "book_title"})
+
+			_, err = NewStructMapping(reflect.TypeOf(StructWithoutColumnName{}))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("InferColumnNames maps the fields without tag with snake_case names", func() {
+			InferColumnNames(true)
+			defer InferColumnNames(false)
+
+			structMap, err := NewStructMapping(reflect.TypeOf(StructWithoutTags{}))
+			So(err, ShouldBeNil)
+			So(structMap.GetAllColumnsNames(), ShouldResemble, []string{"id", "author_name", "book_title", "published_in"})
+
+			structMap, err = NewStructMapping(reflect.TypeOf(StructWithoutColumnName{}))
+			So(err, ShouldBeNil)
+			So(structMap.GetKeyColumnsNames(), ShouldResemble, []string{"id"})
+
+			Convey("The column namer is pluggable", func() {
+				SetColumnNamer(func(name string, done bool) string {
+					return strings.ToUpper(name)
+				})
+				defer SetColumnNamer(tablenamer.Snake())
+
+				structMap, err := NewStructMapping(reflect.TypeOf(StructWithoutTags{}))
+				So(err, ShouldBeNil)
+				So(structMap.GetAllColumnsNames(), ShouldResemble, []string{"id", "AUTHORNAME", "book_title", "PUBLISHEDIN"})
+			})
+		})
+
+		Convey("Changing the inference clears the cache", func() {
+			structMap, err := Cache.GetOrCreateStructMapping(reflect.TypeOf(StructWithoutTags{}))
+			So(err, ShouldBeNil)
+			So(len(structMap.GetAllColumnsNames()), ShouldEqual, 2)
+
+			InferColumnNames(true)
+			defer InferColumnNames(false)
+			structMap, err = Cache.GetOrCreateStructMapping(reflect.TypeOf(StructWithoutTags{}))
+			So(err, ShouldBeNil)
+			So(len(structMap.GetAllColumnsNames()), ShouldEqual, 4)
+		})
+	})
+}
@@ -1,18 +1,30 @@
 package dbreflect
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
 const tagName = "db"
+const ignoredTag = "-"
 const contentSeparator = ","
 
 const optionKey = "key"
 const optionAuto = "auto"
 const optionOpLock = "oplock"
 const optionRelation = "rel"
+const optionAuditorCreate = "auditor_create"
+const optionAuditorUpdate = "auditor_update"
+const optionSequence = "sequence"
+const optionDefault = "default"
+const optionMask = "mask"
+const optionGenerated = "generated"
+const optionHash = "hash"
 
 // StructMapping contains the relation between a struct and database columns.
 type StructMapping struct {
@@ -28,6 +40,7 @@ type StructMapping struct {
 // and database columns.
 type structMappingDetails struct {
 	name             string
+	structType       reflect.Type
 	fieldsMapping    []fieldMapping
 	subStructMapping []subStructMapping
 }
@@ -40,6 +53,23 @@ type fieldMapping struct {
 	isKey    bool
 	isAuto   bool
 	isOpLock bool
+	// generated columns are computed by the database, they are auto columns
+	// never written, even if explicitly requested
+	isGenerated bool
+	// auditor fields are filled with the current actor (see godb.WithActor)
+	isAuditorCreate bool
+	isAuditorUpdate bool
+	// sequence filling the field on insert (see godb.NextSequenceValue)
+	sequence string
+	// default value of the field on insert, given as text
+	defaultValue string
+	hasDefault   bool
+	// masker replacing the value at scan time (see godb.UseMasking)
+	masker string
+	// hash of another column of the same struct, computed on insert and
+	// update (hash=algorithm(column))
+	hashAlgorithm string
+	hashSource    string
 }
 
 // subStructMapping contrains nested structs.
@@ -47,6 +77,7 @@ type subStructMapping struct {
 	name          string
 	prefix        string
 	relation      string
+	isPointer     bool
 	structMapping structMappingDetails
 }
 
@@ -68,6 +99,11 @@ func NewStructMapping(structInfo reflect.Type) (*StructMapping, error) {
 		return nil, err
 	}
 
+	err = sm.checkHashFields()
+	if err != nil {
+		return nil, err
+	}
+
 	return sm, nil
 }
 
@@ -84,22 +120,28 @@ func newStructMappingDetails(structInfo reflect.Type) (structMappingDetails, err
 	}
 
 	smd.name = structInfo.PkgPath() + "." + structInfo.Name()
+	smd.structType = structInfo
 	smd.fieldsMapping = make([]fieldMapping, 0, structInfo.NumField())
 
 	for i := 0; i < structInfo.NumField(); i++ {
 		fieldInfo := structInfo.Field(i)
-		// Only non pointers are mapped
-		if fieldInfo.Type.Kind() == reflect.Ptr {
+		// Pointers are nested structs, or nullable fields (nil is NULL)
+		fieldType := fieldInfo.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		// No tag, no mapping, unless the column name is inferred for an
+		// exported field
+		tag, hasTag := fieldInfo.Tag.Lookup(tagName)
+		if tag == ignoredTag {
 			continue
 		}
-		// No tag, no mapping
-		if _, ok := fieldInfo.Tag.Lookup(tagName); !ok {
+		if !hasTag && (fieldInfo.PkgPath != "" || isSubStruct(fieldType) || inferredColumnName(fieldInfo.Name) == "") {
 			continue
 		}
 
-		// Some structs are scannable, like time.Time, or other registered types.
-		// See RegisterScannableStruct.
-		if fieldInfo.Type.Kind() == reflect.Struct && !isStructScannable(fieldInfo.Type.Name()) {
+		if isSubStruct(fieldType) {
 			// Map a sub struct
 			subStructMapping, err := smd.newSubStructMapping(fieldInfo)
 			if err != nil {
@@ -119,6 +161,13 @@ func newStructMappingDetails(structInfo reflect.Type) (structMappingDetails, err
 	return smd, nil
 }
 
+// isSubStruct returns true if the type is a nested struct. Some structs are
+// scannable, like time.Time, or other registered types, they are mapped as
+// fields. See RegisterScannableStruct.
+func isSubStruct(fieldType reflect.Type) bool {
+	return fieldType.Kind() == reflect.Struct && !isStructScannable(fieldType.Name())
+}
+
 // newFieldMapping build a fieldMapping parsing tag content.
 func (smd *structMappingDetails) newFieldMapping(structField reflect.StructField) (*fieldMapping, error) {
 	fieldMapping := &fieldMapping{
@@ -135,17 +184,48 @@ func (smd *structMappingDetails) newFieldMapping(structField reflect.StructField
 	// First value is always the sql column name
 	var options map[string]string
 	fieldMapping.sqlName, options = smd.tagData(structField.Tag)
+	if len(fieldMapping.sqlName) < 1 {
+		fieldMapping.sqlName = inferredColumnName(fieldMapping.name)
+	}
 	if len(fieldMapping.sqlName) < 1 {
 		return nil, fmt.Errorf("empty tag name for %s.%s", smd.name, fieldMapping.name)
 	}
 
 	_, fieldMapping.isAuto = options[optionAuto]
+	_, fieldMapping.isGenerated = options[optionGenerated]
+	fieldMapping.isAuto = fieldMapping.isAuto || fieldMapping.isGenerated
 	_, fieldMapping.isKey = options[optionKey]
 	_, fieldMapping.isOpLock = options[optionOpLock]
+	_, fieldMapping.isAuditorCreate = options[optionAuditorCreate]
+	_, fieldMapping.isAuditorUpdate = options[optionAuditorUpdate]
+	fieldMapping.sequence = options[optionSequence]
+	fieldMapping.defaultValue, fieldMapping.hasDefault = options[optionDefault]
+	fieldMapping.masker = options[optionMask]
+	if hash, ok := options[optionHash]; ok {
+		fieldMapping.hashAlgorithm, fieldMapping.hashSource = parseHashOption(hash)
+		if fieldMapping.hashAlgorithm == "" {
+			return nil, fmt.Errorf("invalid hash option %s for %s.%s, expected algorithm(column)", hash, smd.name, fieldMapping.name)
+		}
+	}
 
 	return fieldMapping, nil
 }
 
+// parseHashOption splits the value of the hash option, algorithm(column). It
+// returns blank strings if the value is invalid.
+func parseHashOption(hash string) (string, string) {
+	open := strings.Index(hash, "(")
+	if open < 1 || !strings.HasSuffix(hash, ")") {
+		return "", ""
+	}
+	algorithm := strings.TrimSpace(hash[:open])
+	source := strings.TrimSpace(hash[open+1 : len(hash)-1])
+	if algorithm == "" || source == "" {
+		return "", ""
+	}
+	return algorithm, source
+}
+
 // newSubStructMapping build nested structs mapping.
 func (smd *structMappingDetails) newSubStructMapping(structField reflect.StructField) (*subStructMapping, error) {
 	structInfo := structField.Type
@@ -158,6 +238,7 @@ func (smd *structMappingDetails) newSubStructMapping(structField reflect.StructF
 
 	subStructMapping := &subStructMapping{
 		name:          structField.Name,
+		isPointer:     structInfo.Kind() == reflect.Ptr,
 		structMapping: structMapping,
 	}
 
@@ -245,6 +326,36 @@ func (sm *StructMapping) setOpLockField() error {
 	return err
 }
 
+// checkHashFields checks that the source of each hash field is another
+// column of the same struct.
+func (sm *StructMapping) checkHashFields() error {
+	columns := make(map[string]bool)
+	f := func(fullName string, _ *fieldMapping, _ *reflect.Value) (stop bool, err error) {
+		columns[fullName] = true
+		return false, nil
+	}
+	sm.structMapping.traverseTree("", "", nil, f)
+
+	f = func(fullName string, fieldMapping *fieldMapping, _ *reflect.Value) (stop bool, err error) {
+		if fieldMapping.hashSource == "" {
+			return false, nil
+		}
+		source := hashSourceName(fullName, fieldMapping)
+		if source == fullName || !columns[source] {
+			return true, fmt.Errorf("the hash field %s in the struct %s needs another column of the struct, got %s", fieldMapping.name, sm.Name, fieldMapping.hashSource)
+		}
+		return false, nil
+	}
+	_, err := sm.structMapping.traverseTree("", "", nil, f)
+	return err
+}
+
+// hashSourceName returns the full name of the source column of a hash field,
+// which is in the same struct.
+func hashSourceName(fullName string, fieldMapping *fieldMapping) string {
+	return strings.TrimSuffix(fullName, fieldMapping.sqlName) + fieldMapping.hashSource
+}
+
 // isValidNonAutoOpLockFieldType check if a field type (Kind) is valid for an
 // optimistic locking field, non automatic.
 func isValidNonAutoOpLockFieldType(fieldMapping *fieldMapping) bool {
@@ -277,6 +388,37 @@ func (sm *StructMapping) GetAllColumnsNames() []string {
 	return columns
 }
 
+// ColumnDescription describes the mapping of a column.
+type ColumnDescription struct {
+	Name        string
+	Field       string
+	IsKey       bool
+	IsAuto      bool
+	IsOpLock    bool
+	IsGenerated bool
+}
+
+// DescribeColumns returns the description of all columns, in the same order
+// as GetAllColumnsNames.
+func (sm *StructMapping) DescribeColumns() []ColumnDescription {
+	columns := make([]ColumnDescription, 0, sm.fieldCount)
+
+	f := func(fullName string, fieldMapping *fieldMapping, _ *reflect.Value) (stop bool, err error) {
+		columns = append(columns, ColumnDescription{
+			Name:        fullName,
+			Field:       fieldMapping.name,
+			IsKey:       fieldMapping.isKey,
+			IsAuto:      fieldMapping.isAuto,
+			IsOpLock:    fieldMapping.isOpLock,
+			IsGenerated: fieldMapping.isGenerated,
+		})
+		return false, nil
+	}
+	sm.structMapping.traverseTree("", "", nil, f)
+
+	return columns
+}
+
 // GetNonAutoColumnsNames returns the names of non auto columns.
 func (sm *StructMapping) GetNonAutoColumnsNames() []string {
 	columns := make([]string, 0, sm.fieldCount-sm.autoCount)
@@ -323,7 +465,9 @@ func (sm *StructMapping) GetKeyColumnsNames() []string {
 }
 
 // GetAllFieldsPointers returns pointers for all fields, in the same order
-// as GetAllColumnsNames.
+// as GetAllColumnsNames. The pointers are scan destinations, the nested
+// structs pointers are allocated, and set to nil if all their columns are
+// scanned as NULL (see nestedStruct).
 func (sm *StructMapping) GetAllFieldsPointers(s interface{}) []interface{} {
 	// TODO : check type
 	v := reflect.ValueOf(s)
@@ -331,13 +475,31 @@ func (sm *StructMapping) GetAllFieldsPointers(s interface{}) []interface{} {
 
 	pointers := make([]interface{}, 0, sm.fieldCount)
 
+	f := func(fullName string, fieldMapping *fieldMapping, nested *nestedStruct) (stop bool, err error) {
+		pointers = append(pointers, nested.destination(fieldMapping.name))
+		return false, nil
+	}
+	sm.structMapping.traverseScanTree("", "", &nestedStruct{value: v}, f)
+
+	return pointers
+}
+
+// GetAllFieldsValues returns values of all fields, in the same order as
+// GetAllColumnsNames. The fields of a nil nested struct are nil pointers.
+func (sm *StructMapping) GetAllFieldsValues(s interface{}) []interface{} {
+	// TODO : check type
+	v := reflect.ValueOf(s)
+	v = reflect.Indirect(v)
+
+	values := make([]interface{}, 0, sm.fieldCount)
+
 	f := func(fullName string, _ *fieldMapping, value *reflect.Value) (stop bool, err error) {
-		pointers = append(pointers, value.Addr().Interface())
+		values = append(values, value.Interface())
 		return false, nil
 	}
 	sm.structMapping.traverseTree("", "", &v, f)
 
-	return pointers
+	return values
 }
 
 // GetNonAutoFieldsValues returns values of non auto fields, in the same order
@@ -362,7 +524,7 @@ func (sm *StructMapping) GetNonAutoFieldsValues(s interface{}) []interface{} {
 
 // GetNonAutoFieldsValuesFiltered returns values of fields in filterColumns,
 // if filterColumns is empty than returns values of non auto fields like `GetNonAutoFieldsValues` but
-// as map
+// as map. The generated fields are never returned.
 func (sm *StructMapping) GetNonAutoFieldsValuesFiltered(s interface{}, filterColumns []string, isAlreadyOrdered bool) ([]string, []interface{}) {
 	// TODO : check type
 	v := reflect.ValueOf(s)
@@ -381,7 +543,11 @@ func (sm *StructMapping) GetNonAutoFieldsValuesFiltered(s interface{}, filterCol
 
 	values := make([]interface{}, 0, ln)
 	// Explicitly defined columns in filterColumns will be returned whether it is key column or not
-	flt := func(isAuto bool, colName string) bool {
+	flt := func(fieldMapping *fieldMapping) bool {
+		if fieldMapping.isGenerated {
+			return false
+		}
+		isAuto, colName := fieldMapping.isAuto, fieldMapping.sqlName
 		for _, c := range filterColumns {
 			if c == colName {
 				return true
@@ -393,7 +559,7 @@ func (sm *StructMapping) GetNonAutoFieldsValuesFiltered(s interface{}, filterCol
 		return !isAuto
 	}
 	f := func(fullName string, fieldMapping *fieldMapping, value *reflect.Value) (stop bool, err error) {
-		if flt(fieldMapping.isAuto, fieldMapping.sqlName) {
+		if flt(fieldMapping) {
 			// Build ordered columns list if not columns are already ordered and filtered
 			if !isAlreadyOrdered {
 				columns = append(columns, fieldMapping.sqlName)
@@ -436,17 +602,18 @@ func (sm *StructMapping) GetPointersForColumns(s interface{}, columns ...string)
 
 	pointersMap := make(map[string]interface{})
 
-	f := func(fullName string, _ *fieldMapping, value *reflect.Value) (stop bool, err error) {
+	f := func(fullName string, fieldMapping *fieldMapping, nested *nestedStruct) (stop bool, err error) {
 		for _, columnName := range columns {
 			if columnName == fullName {
-				pointersMap[columnName] = value.Addr().Interface()
+				pointersMap[columnName] = nested.destination(fieldMapping.name)
+				break
 			}
 		}
 		return false, nil
 	}
 
 	// Explore the struct tree
-	sm.structMapping.traverseTree("", "", &v, f)
+	sm.structMapping.traverseScanTree("", "", &nestedStruct{value: v}, f)
 
 	// Returns pointers in the same order than names
 	pointers := make([]interface{}, 0, len(columns))
@@ -472,7 +639,7 @@ func (sm *StructMapping) GetAutoKeyPointer(s interface{}) (interface{}, error) {
 	var autoKeyPointer interface{}
 
 	f := func(fullName string, fieldMapping *fieldMapping, value *reflect.Value) (stop bool, err error) {
-		if fieldMapping.isKey && fieldMapping.isAuto {
+		if fieldMapping.isKey && fieldMapping.isAuto && value.CanAddr() {
 			if autoKeyPointer != nil {
 				return true, fmt.Errorf("multiple auto+key fields for %s", sm.Name)
 			}
@@ -497,7 +664,7 @@ func (sm *StructMapping) GetAutoFieldsPointers(s interface{}) ([]interface{}, er
 	pointers := make([]interface{}, 0, sm.autoCount)
 
 	f := func(fullName string, fieldMapping *fieldMapping, value *reflect.Value) (stop bool, err error) {
-		if fieldMapping.isAuto {
+		if fieldMapping.isAuto && value.CanAddr() {
 			pointers = append(pointers, value.Addr().Interface())
 		}
 		return false, nil
@@ -532,7 +699,7 @@ func (sm *StructMapping) GetAndUpdateOpLockFieldValue(s interface{}) (interface{
 	f := func(fullName string, fieldMapping *fieldMapping, value *reflect.Value) (stop bool, err error) {
 		if fullName == sm.opLockSQLName {
 			currentFieldValue = value.Interface()
-			if !fieldMapping.isAuto {
+			if !fieldMapping.isAuto && value.CanSet() {
 				updateNonAutoOpLockField(value)
 			}
 			return true, nil
@@ -547,6 +714,374 @@ func (sm *StructMapping) GetAndUpdateOpLockFieldValue(s interface{}) (interface{
 	return currentFieldValue, nil
 }
 
+// SetAuditorFieldsValues sets the auditor fields with the given actor : the
+// auditor_update fields, and also the auditor_create ones for a creation.
+// The actor has to be assignable or convertible to the fields types, or the
+// fields have to be sql.Scanner (like sql.NullInt64).
+func (sm *StructMapping) SetAuditorFieldsValues(s interface{}, actor interface{}, isCreation bool) error {
+	v := reflect.ValueOf(s)
+	v = reflect.Indirect(v)
+	actorValue := reflect.ValueOf(actor)
+
+	f := func(fullName string, fieldMapping *fieldMapping, value *reflect.Value) (stop bool, err error) {
+		if !fieldMapping.isAuditorUpdate && !(isCreation && fieldMapping.isAuditorCreate) {
+			return false, nil
+		}
+		if !value.CanSet() {
+			// field of a nil nested struct
+			return false, nil
+		}
+
+		if value.Kind() == reflect.Ptr && !actorValue.Type().AssignableTo(value.Type()) {
+			// nullable field, the actor is set in a new value
+			pointer := reflect.New(value.Type().Elem())
+			value.Set(pointer)
+			elem := pointer.Elem()
+			value = &elem
+		}
+
+		switch {
+		case actorValue.Type().AssignableTo(value.Type()):
+			value.Set(actorValue)
+		case actorValue.Type().ConvertibleTo(value.Type()) && value.Kind() != reflect.String:
+			value.Set(actorValue.Convert(value.Type()))
+		default:
+			scanner, ok := value.Addr().Interface().(sql.Scanner)
+			if !ok {
+				return true, fmt.Errorf("the actor of type %T can't be set in the field %s of the struct %s", actor, fieldMapping.name, sm.Name)
+			}
+			if err := scanner.Scan(actor); err != nil {
+				return true, err
+			}
+		}
+		return false, nil
+	}
+
+	_, err := sm.structMapping.traverseTree("", "", &v, f)
+	return err
+}
+
+// SetSequenceFieldsValues sets the zero sequence fields with the values
+// given by the next function, called with the name of the sequence of each
+// field. The fields have an integer type, or are sql.Scanner (like
+// sql.NullInt64).
+func (sm *StructMapping) SetSequenceFieldsValues(s interface{}, next func(sequence string) (int64, error)) error {
+	v := reflect.ValueOf(s)
+	v = reflect.Indirect(v)
+
+	f := func(fullName string, fieldMapping *fieldMapping, value *reflect.Value) (stop bool, err error) {
+		if fieldMapping.sequence == "" || !value.CanSet() {
+			return false, nil
+		}
+		if !isZeroFieldValue(*value) {
+			return false, nil
+		}
+
+		nextValue, err := next(fieldMapping.sequence)
+		if err != nil {
+			return true, err
+		}
+		if value.Kind() == reflect.Ptr {
+			// nullable field, the value is set in a new value
+			pointer := reflect.New(value.Type().Elem())
+			value.Set(pointer)
+			elem := pointer.Elem()
+			value = &elem
+		}
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			value.SetInt(nextValue)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			value.SetUint(uint64(nextValue))
+		default:
+			scanner, ok := value.Addr().Interface().(sql.Scanner)
+			if !ok {
+				return true, fmt.Errorf("the sequence field %s of the struct %s must be an integer or a sql.Scanner", fieldMapping.name, sm.Name)
+			}
+			if err := scanner.Scan(nextValue); err != nil {
+				return true, err
+			}
+		}
+		return false, nil
+	}
+
+	_, err := sm.structMapping.traverseTree("", "", &v, f)
+	return err
+}
+
+// isZeroFieldValue returns true if the field has not been set.
+func isZeroFieldValue(value reflect.Value) bool {
+	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
+}
+
+// SetDefaultFieldsValues sets the zero fields having a default value : the
+// value of the default option of the tag, or the value given by the function
+// of the column, if any. The text of the default option is parsed according
+// to the type of the field (string, bool, numbers, or sql.Scanner). The
+// value given by a function has to be assignable or convertible to the type
+// of the field, or the field has to be a sql.Scanner.
+func (sm *StructMapping) SetDefaultFieldsValues(s interface{}, defaultFuncs map[string]func() interface{}) error {
+	v := reflect.ValueOf(s)
+	v = reflect.Indirect(v)
+
+	f := func(fullName string, fieldMapping *fieldMapping, value *reflect.Value) (stop bool, err error) {
+		defaultFunc := defaultFuncs[fullName]
+		if (!fieldMapping.hasDefault && defaultFunc == nil) || !value.CanSet() || !isZeroFieldValue(*value) {
+			return false, nil
+		}
+
+		if defaultFunc != nil {
+			err = setFieldValue(*value, defaultFunc())
+		} else {
+			err = setFieldText(*value, fieldMapping.defaultValue)
+		}
+		if err != nil {
+			return true, fmt.Errorf("invalid default value of the field %s of the struct %s : %v", fieldMapping.name, sm.Name, err)
+		}
+		return false, nil
+	}
+
+	_, err := sm.structMapping.traverseTree("", "", &v, f)
+	return err
+}
+
+// MaskFieldsValues replaces the values of the masked fields read from the
+// given columns with the values given by the mask function, called with the
+// name of the masker of each field. The masked fields are strings, string
+// pointers or sql.NullString, the empty and null values are kept.
+func (sm *StructMapping) MaskFieldsValues(s interface{}, columns []string, mask func(masker string, value string) (string, error)) error {
+	v := reflect.ValueOf(s)
+	v = reflect.Indirect(v)
+	scanned := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		scanned[column] = true
+	}
+
+	f := func(fullName string, fieldMapping *fieldMapping, value *reflect.Value) (stop bool, err error) {
+		if fieldMapping.masker == "" || !scanned[fullName] || !value.CanSet() {
+			return false, nil
+		}
+
+		if nullString, ok := value.Addr().Interface().(*sql.NullString); ok {
+			if !nullString.Valid || nullString.String == "" {
+				return false, nil
+			}
+			nullString.String, err = mask(fieldMapping.masker, nullString.String)
+			return err != nil, err
+		}
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return false, nil
+			}
+			elem := value.Elem()
+			value = &elem
+		}
+		if value.Kind() != reflect.String {
+			return true, fmt.Errorf("the masked field %s of the struct %s must be a string or a sql.NullString", fieldMapping.name, sm.Name)
+		}
+		if value.String() == "" {
+			return false, nil
+		}
+		masked, err := mask(fieldMapping.masker, value.String())
+		if err != nil {
+			return true, err
+		}
+		value.SetString(masked)
+		return false, nil
+	}
+
+	_, err := sm.structMapping.traverseTree("", "", &v, f)
+	return err
+}
+
+// GetHashColumnsNames returns the names of the hash columns computed from
+// the given columns.
+func (sm *StructMapping) GetHashColumnsNames(sourceColumns []string) []string {
+	sources := make(map[string]bool, len(sourceColumns))
+	for _, column := range sourceColumns {
+		sources[column] = true
+	}
+
+	columns := make([]string, 0)
+	f := func(fullName string, fieldMapping *fieldMapping, _ *reflect.Value) (stop bool, err error) {
+		if fieldMapping.hashSource != "" && sources[hashSourceName(fullName, fieldMapping)] {
+			columns = append(columns, fullName)
+		}
+		return false, nil
+	}
+	sm.structMapping.traverseTree("", "", nil, f)
+
+	return columns
+}
+
+// SetHashFieldsValues sets the hash fields with the digest of their source
+// column, given by the hash function called with the algorithm of each field.
+// The sources are strings, []byte, or driver.Valuer giving them (like
+// sql.NullString), a NULL source sets a NULL hash (the zero value of the
+// field). The []byte fields get the raw digest, the others get its
+// hexadecimal form : strings, string pointers or sql.Scanner (like
+// sql.NullString).
+func (sm *StructMapping) SetHashFieldsValues(s interface{}, hash func(algorithm string, data []byte) ([]byte, error)) error {
+	v := reflect.ValueOf(s)
+	v = reflect.Indirect(v)
+
+	values := make(map[string]reflect.Value)
+	f := func(fullName string, _ *fieldMapping, value *reflect.Value) (stop bool, err error) {
+		values[fullName] = *value
+		return false, nil
+	}
+	sm.structMapping.traverseTree("", "", &v, f)
+
+	f = func(fullName string, fieldMapping *fieldMapping, value *reflect.Value) (stop bool, err error) {
+		if fieldMapping.hashAlgorithm == "" || !value.CanSet() {
+			return false, nil
+		}
+
+		data, err := hashData(values[hashSourceName(fullName, fieldMapping)])
+		if err != nil {
+			return true, fmt.Errorf("invalid source of the hash field %s of the struct %s : %v", fieldMapping.name, sm.Name, err)
+		}
+		if data == nil {
+			value.Set(reflect.Zero(value.Type()))
+			return false, nil
+		}
+		digest, err := hash(fieldMapping.hashAlgorithm, data)
+		if err != nil {
+			return true, err
+		}
+
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+			value.SetBytes(digest)
+			return false, nil
+		}
+		if err := setFieldValue(*value, hex.EncodeToString(digest)); err != nil {
+			return true, fmt.Errorf("invalid hash field %s of the struct %s : %v", fieldMapping.name, sm.Name, err)
+		}
+		return false, nil
+	}
+
+	_, err := sm.structMapping.traverseTree("", "", &v, f)
+	return err
+}
+
+// hashData returns the data of the source of a hash, or nil for NULL.
+func hashData(value reflect.Value) ([]byte, error) {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, nil
+		}
+		value = value.Elem()
+	}
+
+	source := value.Interface()
+	if valuer, ok := source.(driver.Valuer); ok {
+		var err error
+		if source, err = valuer.Value(); err != nil {
+			return nil, err
+		}
+	}
+	switch s := source.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(s), nil
+	case []byte:
+		if s == nil {
+			return nil, nil
+		}
+		return s, nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return []byte(value.String()), nil
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			if value.IsNil() {
+				return nil, nil
+			}
+			return value.Bytes(), nil
+		}
+	}
+	return nil, fmt.Errorf("a value of type %T can't be hashed, it must be a string or []byte", source)
+}
+
+// setFieldValue sets a field with the given value, assigned, converted or
+// scanned.
+func setFieldValue(value reflect.Value, fieldValue interface{}) error {
+	newValue := reflect.ValueOf(fieldValue)
+	if !newValue.IsValid() {
+		return nil
+	}
+	if value.Kind() == reflect.Ptr && !newValue.Type().AssignableTo(value.Type()) {
+		// nullable field, the value is set in a new value
+		pointer := reflect.New(value.Type().Elem())
+		value.Set(pointer)
+		value = pointer.Elem()
+	}
+
+	switch {
+	case newValue.Type().AssignableTo(value.Type()):
+		value.Set(newValue)
+	case newValue.Type().ConvertibleTo(value.Type()) && value.Kind() != reflect.String:
+		value.Set(newValue.Convert(value.Type()))
+	default:
+		scanner, ok := value.Addr().Interface().(sql.Scanner)
+		if !ok {
+			return fmt.Errorf("a value of type %T can't be set in a field of type %s", fieldValue, value.Type())
+		}
+		return scanner.Scan(fieldValue)
+	}
+	return nil
+}
+
+// setFieldText sets a field with the value parsed from the given text.
+func setFieldText(value reflect.Value, text string) error {
+	if value.Kind() == reflect.Ptr {
+		pointer := reflect.New(value.Type().Elem())
+		if err := setFieldText(pointer.Elem(), text); err != nil {
+			return err
+		}
+		value.Set(pointer)
+		return nil
+	}
+	if scanner, ok := value.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(text)
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(text)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		value.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(text, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(text, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(text, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(parsed)
+	default:
+		return fmt.Errorf("the type %s has no text form", value.Type())
+	}
+	return nil
+}
+
 // updateNonAutoOpLockField updates the value of the optimistic locking field.
 // It manages only types accepted by isValidNonAutoOpLockFieldType, and of
 // course only non-auto oplock fields.
@@ -569,6 +1104,8 @@ type treeExplorer func(fullName string, fieldMapping *fieldMapping, value *refle
 // 	* fullName : the fill name of the SQL columns (using prefixes).
 //  * fieldMapping : the fieldMapping of the field.
 //  * value : the reflect.Value of the field (or nil if traverseTree got nil as startValue).
+//    The fields of a nil nested struct are given as nil pointers of their
+//    types (which are neither addressable nor settable).
 // The callback returns a boolean and an error. If the boolean is true, the walk is stopped.
 
 func (smd *structMappingDetails) traverseTree(relation string, prefix string, startValue *reflect.Value, f treeExplorer) (bool, error) {
@@ -602,13 +1139,47 @@ func (smd *structMappingDetails) traverseTree(relation string, prefix string, st
 			newRelation = sub.relation
 		}
 
-		if startValue != nil {
-			structValue := startValue.FieldByName(sub.name)
-			stopped, err = sub.structMapping.traverseTree(newRelation, prefix+sub.prefix, &structValue, f)
-		} else {
+		if startValue == nil {
 			stopped, err = sub.structMapping.traverseTree(newRelation, prefix+sub.prefix, nil, f)
+		} else if structValue := startValue.FieldByName(sub.name); sub.isPointer && structValue.IsNil() {
+			stopped, err = sub.structMapping.traverseNilTree(newRelation, prefix+sub.prefix, f)
+		} else {
+			structValue = reflect.Indirect(structValue)
+			stopped, err = sub.structMapping.traverseTree(newRelation, prefix+sub.prefix, &structValue, f)
+		}
+
+		if stopped || err != nil {
+			return stopped, err
+		}
+	}
+
+	return false, nil
+}
+
+// traverseNilTree traverses the structure tree of a nil nested struct like
+// traverseTree, giving the fields values as nil pointers.
+func (smd *structMappingDetails) traverseNilTree(relation string, prefix string, f treeExplorer) (bool, error) {
+	for _, fm := range smd.fieldsMapping {
+		fullName := prefix + fm.sqlName
+		if relation != "" {
+			fullName = relation + "." + fullName
+		}
+
+		structField, _ := smd.structType.FieldByName(fm.name)
+		fieldValue := reflect.Zero(reflect.PtrTo(structField.Type))
+		stopped, err := f(fullName, &fm, &fieldValue)
+		if stopped || err != nil {
+			return stopped, err
+		}
+	}
+
+	for _, sub := range smd.subStructMapping {
+		newRelation := relation
+		if sub.relation != "" {
+			newRelation = sub.relation
 		}
 
+		stopped, err := sub.structMapping.traverseNilTree(newRelation, prefix+sub.prefix, f)
 		if stopped || err != nil {
 			return stopped, err
 		}
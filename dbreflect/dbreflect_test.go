@@ -1,6 +1,7 @@
 package dbreflect
 
 import (
+	"database/sql"
 	"reflect"
 	"testing"
 	"time"
@@ -60,6 +61,36 @@ type StructWithInvalidOptimiticLocking struct {
 	BadVersion string `db:"version,oplock"`
 }
 
+type StructWithAuditors struct {
+	ID        int           `db:"id,key,auto"`
+	CreatedBy int64         `db:"created_by,auditor_create"`
+	UpdatedBy sql.NullInt64 `db:"updated_by,auditor_update"`
+}
+
+type StructWithSequences struct {
+	ID       int           `db:"id,key,auto"`
+	Number   int64         `db:"number,sequence=invoice_seq"`
+	Position sql.NullInt64 `db:"position,sequence=position_seq"`
+	Rank     *uint         `db:"rank,sequence=rank_seq"`
+}
+
+type StructWithHashes struct {
+	Content    string         `db:"content"`
+	Hash       string         `db:"hash,hash=sha256(content)"`
+	RawHash    []byte         `db:"raw_hash,hash=md5(content)"`
+	Comment    *string        `db:"comment"`
+	CommentSum sql.NullString `db:"comment_sum,hash=sha1(comment)"`
+}
+
+type StructWithDefaults struct {
+	Status   string         `db:"status,default=pending"`
+	Priority int8           `db:"priority,default=3"`
+	Ratio    float64        `db:"ratio,default=0.5"`
+	Active   *bool          `db:"active,default=true"`
+	Note     sql.NullString `db:"note,default=none"`
+	Created  int64          `db:"created"`
+}
+
 type ComplexStructsWithRelations struct {
 	// no prefix but a relation
 	SimpleStruct `db:",rel=firsttable"`
@@ -67,6 +98,18 @@ type ComplexStructsWithRelations struct {
 	Foobar SubStruct `db:"nested_,rel=secondtable"`
 }
 
+type StructWithNestedPointer struct {
+	ID     int        `db:"id,key,auto"`
+	Foobar *SubStruct `db:"nested_"`
+}
+
+type StructWithPointerFields struct {
+	ID      int        `db:"id,key,auto"`
+	Text    *string    `db:"my_text"`
+	Day     *time.Time `db:"a_day"`
+	Ignored *string
+}
+
 func TestStructMapping(t *testing.T) {
 	Convey("NewStructMapping with a struct type", t, func() {
 		structMap, _ := NewStructMapping(reflect.TypeOf(SimpleStruct{}))
@@ -223,6 +266,32 @@ func TestGetAutoColumnsNames(t *testing.T) {
 	})
 }
 
+type StructGenerated struct {
+	ID    int `db:"id,key,auto"`
+	Price int `db:"price"`
+	Total int `db:"total,generated"`
+}
+
+func TestGeneratedColumns(t *testing.T) {
+	Convey("Given a StructMapping with a generated column", t, func() {
+		structInstance := StructGenerated{ID: 1, Price: 2, Total: 3}
+		structMap, _ := NewStructMapping(reflect.TypeOf(&structInstance))
+
+		Convey("The generated column is an auto column", func() {
+			So(structMap.GetNonAutoColumnsNames(), ShouldResemble, []string{"price"})
+			So(structMap.GetAutoColumnsNames(), ShouldResemble, []string{"id", "total"})
+			So(structMap.DescribeColumns()[2].IsGenerated, ShouldBeTrue)
+			So(structMap.DescribeColumns()[2].IsAuto, ShouldBeTrue)
+		})
+
+		Convey("The generated column is never written", func() {
+			columns, values := structMap.GetNonAutoFieldsValuesFiltered(&structInstance, []string{"id", "total"}, false)
+			So(columns, ShouldResemble, []string{"id"})
+			So(values, ShouldResemble, []interface{}{1})
+		})
+	})
+}
+
 func TestGetKeyColumnsNames(t *testing.T) {
 	Convey("Given a StructMapping and a struct instance (nested)", t, func() {
 		structInstance := StructMultipleAuto{}
@@ -253,6 +322,73 @@ func TestGetAllFieldsPointers(t *testing.T) {
 	})
 }
 
+func TestNestedStructPointer(t *testing.T) {
+	Convey("Given a StructMapping of a struct with a nested struct pointer", t, func() {
+		structMap, err := NewStructMapping(reflect.TypeOf(StructWithNestedPointer{}))
+		So(err, ShouldBeNil)
+		So(structMap.GetAllColumnsNames(), ShouldResemble, []string{"id", "nested_foo", "nested_bar"})
+
+		Convey("The nested struct is allocated to get pointers", func() {
+			structInstance := StructWithNestedPointer{}
+			ptrs := structMap.GetAllFieldsPointers(&structInstance)
+			So(len(ptrs), ShouldEqual, 3)
+			So(ptrs[0], ShouldEqual, &(structInstance.ID))
+			So(structInstance.Foobar, ShouldNotBeNil)
+
+			Convey("It is set to nil if all its columns are NULL", func() {
+				So(ptrs[1].(sql.Scanner).Scan(nil), ShouldBeNil)
+				So(structInstance.Foobar, ShouldNotBeNil)
+				So(ptrs[2].(sql.Scanner).Scan(nil), ShouldBeNil)
+				So(structInstance.Foobar, ShouldBeNil)
+			})
+
+			Convey("It is kept if a column is not NULL", func() {
+				So(ptrs[1].(sql.Scanner).Scan([]byte("FOO")), ShouldBeNil)
+				So(ptrs[2].(sql.Scanner).Scan(nil), ShouldBeNil)
+				So(structInstance.Foobar, ShouldNotBeNil)
+				So(structInstance.Foobar.Foo, ShouldEqual, "FOO")
+				So(structInstance.Foobar.Bar, ShouldEqual, "")
+			})
+		})
+
+		Convey("The fields of a nil nested struct have nil values", func() {
+			structInstance := StructWithNestedPointer{ID: 1}
+			values := structMap.GetAllFieldsValues(&structInstance)
+			So(values[0], ShouldEqual, 1)
+			So(values[1], ShouldBeNil)
+			So(values[2], ShouldBeNil)
+		})
+	})
+}
+
+func TestPointerFields(t *testing.T) {
+	Convey("Given a StructMapping of a struct with pointer fields", t, func() {
+		structMap, err := NewStructMapping(reflect.TypeOf(StructWithPointerFields{}))
+		So(err, ShouldBeNil)
+
+		Convey("The pointer fields are mapped like others", func() {
+			So(structMap.GetAllColumnsNames(), ShouldResemble, []string{"id", "my_text", "a_day"})
+		})
+
+		Convey("GetAllFieldsPointers returns pointers to the pointer fields", func() {
+			structInstance := StructWithPointerFields{}
+			ptrs := structMap.GetAllFieldsPointers(&structInstance)
+			So(len(ptrs), ShouldEqual, 3)
+			So(ptrs[1], ShouldEqual, &(structInstance.Text))
+			So(ptrs[2], ShouldEqual, &(structInstance.Day))
+		})
+
+		Convey("GetNonAutoFieldsValues returns the pointers", func() {
+			text := "a text"
+			structInstance := StructWithPointerFields{Text: &text}
+			values := structMap.GetNonAutoFieldsValues(&structInstance)
+			So(len(values), ShouldEqual, 2)
+			So(values[0], ShouldEqual, &text)
+			So(values[1], ShouldBeNil)
+		})
+	})
+}
+
 func TestGetNonAutoFieldsValues(t *testing.T) {
 	Convey("Given a StructMapping and a struct instance (nested)", t, func() {
 		structInstance := ComplexStruct{
@@ -402,3 +538,223 @@ func TestInvalidOpLockFieldType(t *testing.T) {
 
 	})
 }
+
+func TestSetAuditorFieldsValues(t *testing.T) {
+	Convey("Given a struct with auditor fields", t, func() {
+		structMap, err := NewStructMapping(reflect.TypeOf(StructWithAuditors{}))
+		So(err, ShouldBeNil)
+
+		Convey("SetAuditorFieldsValues sets all auditor fields for a creation", func() {
+			s := StructWithAuditors{}
+			err := structMap.SetAuditorFieldsValues(&s, 42, true)
+			So(err, ShouldBeNil)
+			So(s.CreatedBy, ShouldEqual, 42)
+			So(s.UpdatedBy, ShouldResemble, sql.NullInt64{Int64: 42, Valid: true})
+		})
+
+		Convey("SetAuditorFieldsValues sets only update auditor fields for an update", func() {
+			s := StructWithAuditors{CreatedBy: 1}
+			err := structMap.SetAuditorFieldsValues(&s, int64(42), false)
+			So(err, ShouldBeNil)
+			So(s.CreatedBy, ShouldEqual, 1)
+			So(s.UpdatedBy.Int64, ShouldEqual, 42)
+		})
+
+		Convey("SetAuditorFieldsValues returns an error for incompatible actors", func() {
+			s := StructWithAuditors{}
+			err := structMap.SetAuditorFieldsValues(&s, struct{}{}, true)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSetSequenceFieldsValues(t *testing.T) {
+	Convey("Given a struct with sequence fields", t, func() {
+		structMap, err := NewStructMapping(reflect.TypeOf(StructWithSequences{}))
+		So(err, ShouldBeNil)
+		sequences := make([]string, 0)
+		next := func(sequence string) (int64, error) {
+			sequences = append(sequences, sequence)
+			return int64(len(sequences)), nil
+		}
+
+		Convey("SetSequenceFieldsValues sets the zero sequence fields", func() {
+			s := StructWithSequences{}
+			err := structMap.SetSequenceFieldsValues(&s, next)
+			So(err, ShouldBeNil)
+			So(sequences, ShouldResemble, []string{"invoice_seq", "position_seq", "rank_seq"})
+			So(s.Number, ShouldEqual, 1)
+			So(s.Position, ShouldResemble, sql.NullInt64{Int64: 2, Valid: true})
+			So(*s.Rank, ShouldEqual, 3)
+		})
+
+		Convey("SetSequenceFieldsValues keeps the fields already set", func() {
+			s := StructWithSequences{Number: 42, Position: sql.NullInt64{Int64: 0, Valid: true}}
+			err := structMap.SetSequenceFieldsValues(&s, next)
+			So(err, ShouldBeNil)
+			So(sequences, ShouldResemble, []string{"rank_seq"})
+			So(s.Number, ShouldEqual, 42)
+		})
+	})
+
+	Convey("SetSequenceFieldsValues returns an error for invalid fields", t, func() {
+		type invalidSequence struct {
+			Number string `db:"number,sequence=invoice_seq"`
+		}
+		structMap, err := NewStructMapping(reflect.TypeOf(invalidSequence{}))
+		So(err, ShouldBeNil)
+		err = structMap.SetSequenceFieldsValues(&invalidSequence{}, func(string) (int64, error) { return 1, nil })
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestSetHashFieldsValues(t *testing.T) {
+	Convey("Given a struct with hash fields", t, func() {
+		structMap, err := NewStructMapping(reflect.TypeOf(StructWithHashes{}))
+		So(err, ShouldBeNil)
+		algorithms := make([]string, 0)
+		hash := func(algorithm string, data []byte) ([]byte, error) {
+			algorithms = append(algorithms, algorithm)
+			return append([]byte{0xff}, data...), nil
+		}
+
+		Convey("SetHashFieldsValues sets the digests of the sources", func() {
+			comment := "ok"
+			s := StructWithHashes{Content: "abc", Comment: &comment}
+			err := structMap.SetHashFieldsValues(&s, hash)
+			So(err, ShouldBeNil)
+			So(algorithms, ShouldResemble, []string{"sha256", "md5", "sha1"})
+			So(s.Hash, ShouldEqual, "ff616263")
+			So(s.RawHash, ShouldResemble, []byte{0xff, 'a', 'b', 'c'})
+			So(s.CommentSum, ShouldResemble, sql.NullString{String: "ff6f6b", Valid: true})
+		})
+
+		Convey("SetHashFieldsValues sets a NULL hash for a NULL source", func() {
+			s := StructWithHashes{CommentSum: sql.NullString{String: "old", Valid: true}}
+			err := structMap.SetHashFieldsValues(&s, hash)
+			So(err, ShouldBeNil)
+			So(s.CommentSum.Valid, ShouldBeFalse)
+		})
+
+		Convey("GetHashColumnsNames returns the hash columns of the given sources", func() {
+			So(structMap.GetHashColumnsNames([]string{"content"}), ShouldResemble, []string{"hash", "raw_hash"})
+			So(structMap.GetHashColumnsNames([]string{"hash"}), ShouldBeEmpty)
+		})
+	})
+
+	Convey("NewStructMapping returns an error for invalid hash fields", t, func() {
+		type invalidOption struct {
+			Hash string `db:"hash,hash=sha256"`
+		}
+		_, err := NewStructMapping(reflect.TypeOf(invalidOption{}))
+		So(err, ShouldNotBeNil)
+
+		type unknownSource struct {
+			Hash string `db:"hash,hash=sha256(content)"`
+		}
+		_, err = NewStructMapping(reflect.TypeOf(unknownSource{}))
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("SetHashFieldsValues returns an error for invalid sources", t, func() {
+		type invalidSource struct {
+			Count int    `db:"count"`
+			Hash  string `db:"hash,hash=sha256(count)"`
+		}
+		structMap, err := NewStructMapping(reflect.TypeOf(invalidSource{}))
+		So(err, ShouldBeNil)
+		err = structMap.SetHashFieldsValues(&invalidSource{}, func(string, []byte) ([]byte, error) { return nil, nil })
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestSetDefaultFieldsValues(t *testing.T) {
+	Convey("Given a struct with default values", t, func() {
+		structMap, err := NewStructMapping(reflect.TypeOf(StructWithDefaults{}))
+		So(err, ShouldBeNil)
+
+		Convey("SetDefaultFieldsValues parses the default option of the zero fields", func() {
+			s := StructWithDefaults{Priority: 1}
+			err := structMap.SetDefaultFieldsValues(&s, nil)
+			So(err, ShouldBeNil)
+			So(s.Status, ShouldEqual, "pending")
+			So(s.Priority, ShouldEqual, 1)
+			So(s.Ratio, ShouldEqual, 0.5)
+			So(*s.Active, ShouldBeTrue)
+			So(s.Note, ShouldResemble, sql.NullString{String: "none", Valid: true})
+		})
+
+		Convey("SetDefaultFieldsValues calls the functions of the columns", func() {
+			s := StructWithDefaults{Status: "done"}
+			funcs := map[string]func() interface{}{
+				"status":  func() interface{} { return "ignored" },
+				"created": func() interface{} { return 42 },
+			}
+			err := structMap.SetDefaultFieldsValues(&s, funcs)
+			So(err, ShouldBeNil)
+			So(s.Status, ShouldEqual, "done")
+			So(s.Created, ShouldEqual, 42)
+		})
+
+		Convey("SetDefaultFieldsValues returns an error for invalid values", func() {
+			funcs := map[string]func() interface{}{
+				"created": func() interface{} { return "now" },
+			}
+			err := structMap.SetDefaultFieldsValues(&StructWithDefaults{}, funcs)
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("SetDefaultFieldsValues returns an error for unparsable options", t, func() {
+		type invalidDefault struct {
+			Count int `db:"count,default=many"`
+		}
+		structMap, err := NewStructMapping(reflect.TypeOf(invalidDefault{}))
+		So(err, ShouldBeNil)
+		So(structMap.SetDefaultFieldsValues(&invalidDefault{}, nil), ShouldNotBeNil)
+	})
+}
+
+func TestMaskFieldsValues(t *testing.T) {
+	type maskedStruct struct {
+		Email    string         `db:"email,mask=email"`
+		Phone    *string        `db:"phone,mask=last4"`
+		Note     sql.NullString `db:"note,mask=redact"`
+		Empty    string         `db:"empty,mask=redact"`
+		Other    string         `db:"other"`
+		Count    int            `db:"count,mask=redact"`
+		Unscaned string         `db:"unscanned,mask=redact"`
+	}
+	mask := func(masker string, value string) (string, error) {
+		return masker + ":" + value, nil
+	}
+
+	Convey("Given a struct with masked fields", t, func() {
+		structMap, err := NewStructMapping(reflect.TypeOf(maskedStruct{}))
+		So(err, ShouldBeNil)
+		phone := "0123456789"
+		s := maskedStruct{
+			Email:    "a@b.c",
+			Phone:    &phone,
+			Note:     sql.NullString{String: "note", Valid: true},
+			Other:    "other",
+			Unscaned: "unscanned",
+		}
+
+		Convey("MaskFieldsValues masks the fields of the given columns", func() {
+			err := structMap.MaskFieldsValues(&s, []string{"email", "phone", "note", "empty", "other"}, mask)
+			So(err, ShouldBeNil)
+			So(s.Email, ShouldEqual, "email:a@b.c")
+			So(*s.Phone, ShouldEqual, "last4:0123456789")
+			So(s.Note.String, ShouldEqual, "redact:note")
+			So(s.Empty, ShouldEqual, "")
+			So(s.Other, ShouldEqual, "other")
+			So(s.Unscaned, ShouldEqual, "unscanned")
+		})
+
+		Convey("MaskFieldsValues accepts only strings", func() {
+			err := structMap.MaskFieldsValues(&s, []string{"count"}, mask)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,204 @@
+package dbreflect
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// nestedStruct locates a struct of a record being scanned, the root struct or
+// a nested one. The nested structs pointers are allocated when one of their
+// fields is given to scan, and set back to nil when all their columns are
+// scanned as NULL (ie a LEFT JOIN without matching row).
+type nestedStruct struct {
+	parent    *nestedStruct
+	name      string
+	isPointer bool
+	// value is the root struct (only when parent is nil)
+	value reflect.Value
+	// count of fields given to scan, scanned, and scanned as NULL
+	fields  int
+	scanned int
+	nulls   int
+}
+
+// scanExplorer is a callback function for traverseScanTree, like treeExplorer
+// but giving the nestedStruct containing the field.
+type scanExplorer func(fullName string, fieldMapping *fieldMapping, nested *nestedStruct) (stop bool, err error)
+
+// traverseScanTree traverses the structure tree of the mapping like
+// traverseTree, giving the nestedStruct containing each field to the
+// callback. Use nestedStruct.destination to get the scan destination of a
+// field.
+func (smd *structMappingDetails) traverseScanTree(relation string, prefix string, nested *nestedStruct, f scanExplorer) (bool, error) {
+	for _, fm := range smd.fieldsMapping {
+		fullName := prefix + fm.sqlName
+		if relation != "" {
+			fullName = relation + "." + fullName
+		}
+
+		stopped, err := f(fullName, &fm, nested)
+		if stopped || err != nil {
+			return stopped, err
+		}
+	}
+
+	for _, sub := range smd.subStructMapping {
+		newRelation := relation
+		if sub.relation != "" {
+			newRelation = sub.relation
+		}
+
+		subNested := &nestedStruct{parent: nested, name: sub.name, isPointer: sub.isPointer}
+		stopped, err := sub.structMapping.traverseScanTree(newRelation, prefix+sub.prefix, subNested, f)
+		if stopped || err != nil {
+			return stopped, err
+		}
+	}
+
+	return false, nil
+}
+
+// structValue returns the value of the struct, allocating the pointers of
+// the nested structs if needed.
+func (ns *nestedStruct) structValue() reflect.Value {
+	if ns.parent == nil {
+		return ns.value
+	}
+
+	value := ns.parent.structValue().FieldByName(ns.name)
+	if !ns.isPointer {
+		return value
+	}
+	if value.IsNil() {
+		value.Set(reflect.New(value.Type().Elem()))
+	}
+	return value.Elem()
+}
+
+// isNullable returns true if the struct is inside a nested struct pointer.
+func (ns *nestedStruct) isNullable() bool {
+	for n := ns; n != nil; n = n.parent {
+		if n.isPointer {
+			return true
+		}
+	}
+	return false
+}
+
+// destination returns the scan destination of the given field : a pointer
+// to the field, or a *nullableField inside a nested struct pointer.
+func (ns *nestedStruct) destination(fieldName string) interface{} {
+	value := ns.structValue().FieldByName(fieldName)
+	if !ns.isNullable() {
+		return value.Addr().Interface()
+	}
+
+	for n := ns; n != nil; n = n.parent {
+		if n.isPointer {
+			n.fields++
+		}
+	}
+	return &nullableField{value: value, nested: ns}
+}
+
+// fieldScanned counts a scanned field, and sets the nested structs pointers
+// to nil when all their fields were NULL.
+func (ns *nestedStruct) fieldScanned(isNull bool) {
+	for n := ns; n != nil; n = n.parent {
+		if !n.isPointer {
+			continue
+		}
+		n.scanned++
+		if isNull {
+			n.nulls++
+		}
+		if n.scanned == n.fields && n.nulls == n.fields {
+			pointer := n.parent.structValue().FieldByName(n.name)
+			pointer.Set(reflect.Zero(pointer.Type()))
+		}
+	}
+}
+
+// nullableField is the scan destination of a field inside a nested struct
+// pointer. A NULL is scanned as the zero value of the field.
+type nullableField struct {
+	value  reflect.Value
+	nested *nestedStruct
+}
+
+// Scan implements the sql.Scanner interface.
+func (nf *nullableField) Scan(src interface{}) error {
+	if src == nil {
+		nf.value.Set(reflect.Zero(nf.value.Type()))
+	} else if err := assignValue(nf.value, src); err != nil {
+		return err
+	}
+	nf.nested.fieldScanned(src == nil)
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// assignValue sets a non NULL value given by a driver into the field. The
+// conversions are done with the sql.Null types, relying on database/sql ones.
+func assignValue(value reflect.Value, src interface{}) error {
+	if scanner, ok := value.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+
+	var converted interface{}
+	var err error
+	switch kind := value.Kind(); {
+	case kind == reflect.Ptr:
+		pointer := reflect.New(value.Type().Elem())
+		if err = assignValue(pointer.Elem(), src); err == nil {
+			value.Set(pointer)
+		}
+		return err
+	case value.Type() == timeType:
+		t, ok := src.(time.Time)
+		if !ok {
+			return fmt.Errorf("unsupported scan of %T into a %s", src, value.Type())
+		}
+		converted = t
+	case kind == reflect.String:
+		var ns sql.NullString
+		err = ns.Scan(src)
+		converted = ns.String
+	case kind >= reflect.Int && kind <= reflect.Int64:
+		var ni sql.NullInt64
+		err = ni.Scan(src)
+		converted = ni.Int64
+	case kind >= reflect.Uint && kind <= reflect.Uint64:
+		var ni sql.NullInt64
+		err = ni.Scan(src)
+		converted = ni.Int64
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		var nf sql.NullFloat64
+		err = nf.Scan(src)
+		converted = nf.Float64
+	case kind == reflect.Bool:
+		var nb sql.NullBool
+		err = nb.Scan(src)
+		converted = nb.Bool
+	case kind == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		switch s := src.(type) {
+		case []byte:
+			converted = append([]byte(nil), s...)
+		case string:
+			converted = []byte(s)
+		default:
+			return fmt.Errorf("unsupported scan of %T into a %s", src, value.Type())
+		}
+	default:
+		return fmt.Errorf("unsupported scan of %T into a %s", src, value.Type())
+	}
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.ValueOf(converted).Convert(value.Type()))
+	return nil
+}
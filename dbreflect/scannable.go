@@ -31,6 +31,10 @@ func init() {
 	RegisterScannableStruct(types.NullJSONStr{})
 	RegisterScannableStruct(types.CompactJSONStr{})
 	RegisterScannableStruct(types.NullCompactJSONStr{})
+	RegisterScannableStruct(types.Decimal{})
+	RegisterScannableStruct(types.NullDecimal{})
+	RegisterScannableStruct(types.Point{})
+	RegisterScannableStruct(types.NullPoint{})
 }
 
 // RegisterScannableStruct registers a struct (through an instance or pointer)
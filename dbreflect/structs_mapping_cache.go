@@ -69,3 +69,10 @@ func (smc *StructsMappingCache) createStructMapping(structType reflect.Type) (*S
 	smc.structsMapping[fmt.Sprintf("%s.%s", structType.PkgPath(), structType.Name())] = structMapping
 	return structMapping, nil
 }
+
+// clear removes all StructMapping from the cache.
+func (smc *StructsMappingCache) clear() {
+	smc.lock.Lock()
+	defer smc.lock.Unlock()
+	smc.structsMapping = make(map[string]*StructMapping)
+}
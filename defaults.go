@@ -0,0 +1,63 @@
+package godb
+
+import (
+	"reflect"
+	"sync"
+)
+
+// defaultFuncs are the functions giving the default values of the columns,
+// by struct type (see RegisterDefaultFunc).
+var defaultFuncs = struct {
+	sync.RWMutex
+	byType map[reflect.Type]map[string]func() interface{}
+}{byType: make(map[reflect.Type]map[string]func() interface{})}
+
+// RegisterDefaultFunc registers the function giving the default value of a
+// column of the given struct (used only for its type). Like the default
+// option of the db tag, the default value is set on insert by the struct
+// tools when the field is zero. The function is called for each insert, it
+// has to be safe for concurrent use.
+//
+// The default values are set by godb, not by the database : they complement
+// the database defaults, which are not given back without a RETURNING
+// clause.
+//
+// Example :
+// 	type Order struct {
+// 		ID        int       `db:"id,key,auto"`
+// 		Status    string    `db:"status,default=pending"`
+// 		CreatedAt time.Time `db:"created_at"`
+// 	}
+//
+// 	godb.RegisterDefaultFunc(&Order{}, "created_at", func() interface{} {
+// 		return time.Now()
+// 	})
+func RegisterDefaultFunc(record interface{}, column string, f func() interface{}) {
+	recordType := reflect.TypeOf(record)
+	for recordType.Kind() == reflect.Ptr {
+		recordType = recordType.Elem()
+	}
+
+	defaultFuncs.Lock()
+	defer defaultFuncs.Unlock()
+	if defaultFuncs.byType[recordType] == nil {
+		defaultFuncs.byType[recordType] = make(map[string]func() interface{})
+	}
+	defaultFuncs.byType[recordType][column] = f
+}
+
+// setDefaults sets the zero fields having a default value of all given
+// records.
+func setDefaults(recordDescription *recordDescription) error {
+	defaultFuncs.RLock()
+	funcs := defaultFuncs.byType[recordDescription.instanceType]
+	defaultFuncs.RUnlock()
+
+	for i := 0; i < recordDescription.len(); i++ {
+		record := recordDescription.index(i)
+		if err := recordDescription.structMapping.SetDefaultFieldsValues(record, funcs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package godb
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type DefaultedDummy struct {
+	ID          int    `db:"id,key,auto"`
+	AText       string `db:"a_text,default=Default"`
+	AnotherText string `db:"another_text"`
+	AnInteger   int    `db:"an_integer,default=42"`
+}
+
+func (*DefaultedDummy) TableName() string {
+	return "dummies"
+}
+
+func TestDefaultValues(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		RegisterDefaultFunc(&DefaultedDummy{}, "another_text", func() interface{} {
+			return "From func"
+		})
+
+		Convey("The zero fields are set with their default values on insert", func() {
+			dummy := &DefaultedDummy{AnInteger: 7}
+			So(db.Insert(dummy).Do(), ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "Default")
+			So(dummy.AnotherText, ShouldEqual, "From func")
+			So(dummy.AnInteger, ShouldEqual, 7)
+
+			inserted := &Dummy{}
+			So(db.Get(inserted, dummy.ID), ShouldBeNil)
+			So(inserted.AText, ShouldEqual, "Default")
+			So(inserted.AnotherText, ShouldEqual, "From func")
+		})
+
+		Convey("The default values are set for all the records of a bulk insert", func() {
+			dummies := []DefaultedDummy{{AText: "Given"}, {}}
+			So(db.BulkInsert(&dummies).Do(), ShouldBeNil)
+			So(dummies[0].AText, ShouldEqual, "Given")
+			So(dummies[1].AText, ShouldEqual, "Default")
+			So(dummies[1].AnInteger, ShouldEqual, 42)
+		})
+	})
+}
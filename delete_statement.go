@@ -1,6 +1,10 @@
 package godb
 
-import "github.com/samonzeweb/godb/adapters"
+import (
+	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+)
 
 // DeleteStatement is a DELETE sql statement builder.
 // Initialize it with the DeleteFrom method.
@@ -14,6 +18,7 @@ type DeleteStatement struct {
 	where            []*Condition
 	returningColumns []string
 	suffixes         []string
+	options          statementOptions
 }
 
 // DeleteFrom initializes a DELETE statement builder.
@@ -23,6 +28,16 @@ func (db *DB) DeleteFrom(tableName string) *DeleteStatement {
 	return ds
 }
 
+// Clone returns a copy of the statement, allowing to reuse a base query
+// and to branch it without altering the original one.
+func (ds *DeleteStatement) Clone() *DeleteStatement {
+	clone := *ds
+	clone.where = append([]*Condition(nil), ds.where...)
+	clone.returningColumns = append([]string(nil), ds.returningColumns...)
+	clone.suffixes = append([]string(nil), ds.suffixes...)
+	return &clone
+}
+
 // Where adds a condition using string and arguments.
 func (ds *DeleteStatement) Where(sql string, args ...interface{}) *DeleteStatement {
 	return ds.WhereQ(Q(sql, args...))
@@ -42,6 +57,23 @@ func (ds *DeleteStatement) Returning(columns ...string) *DeleteStatement {
 	return ds
 }
 
+// Priority sets the priority of the statement when the concurrent queries
+// are limited, see SetMaxConcurrentQueries.
+func (ds *DeleteStatement) Priority(priority Priority) *DeleteStatement {
+	ds.options.priority = priority
+	return ds
+}
+
+// Timeout sets the maximum execution time of the delete. It's cancelled when
+// the timeout expires, like when the context of the DB is done (see
+// SetContext), and PostgreSQL and SQL Server abort it. MySQL has no limit
+// for the deletes : the driver abandons the statement, but the rows could
+// still be deleted.
+func (ds *DeleteStatement) Timeout(timeout time.Duration) *DeleteStatement {
+	ds.options.timeout = timeout
+	return ds
+}
+
 // Suffix adds an expression to suffix the statement. Use it to add a
 // RETURNING clause with PostgreSQL (or whatever you need).
 func (ds *DeleteStatement) Suffix(suffix string) *DeleteStatement {
@@ -73,14 +105,28 @@ func (ds *DeleteStatement) ToSQL() (string, []interface{}, error) {
 	return sqlBuffer.SQL(), sqlBuffer.Arguments(), sqlBuffer.Err()
 }
 
+// ToInterpolatedSQL returns the SQL statement with the arguments written as
+// literals for the adapter, to be pasted into a SQL client. Debug only : the
+// statements are always executed with placeholders.
+func (ds *DeleteStatement) ToInterpolatedSQL() (string, error) {
+	query, args, err := ds.ToSQL()
+	if err != nil {
+		return "", err
+	}
+	return interpolateSQL(ds.db.adapter, query, args)
+}
+
 // Do executes the builded query, and return thr rows affected count.
 func (ds *DeleteStatement) Do() (int64, error) {
 	query, args, err := ds.ToSQL()
 	if err != nil {
 		return 0, err
 	}
+	if err := ds.checkGuards(query, args); err != nil {
+		return 0, err
+	}
 
-	result, err := ds.db.do(query, args)
+	result, err := ds.db.do(query, args, ds.options)
 	if err != nil {
 		return 0, err
 	}
@@ -113,6 +159,23 @@ func (ds *DeleteStatement) doWithReturning(recordDescription *recordDescription,
 	if err != nil {
 		return 0, err
 	}
+	if err := ds.checkGuards(query, args); err != nil {
+		return 0, err
+	}
+	if err := ds.db.checkWritable(query); err != nil {
+		return 0, err
+	}
+
+	return ds.db.doSelectOrWithReturning(query, args, recordDescription, pointersGetter, ds.options)
+}
 
-	return ds.db.doSelectOrWithReturning(query, args, recordDescription, pointersGetter)
+// checkGuards gives the built statement to the guards of the DB.
+func (ds *DeleteStatement) checkGuards(query string, args []interface{}) error {
+	return ds.db.checkGuards(&StatementInfo{
+		Kind:      "DELETE",
+		Tables:    guardedTableNames(ds.fromTable),
+		HasWhere:  len(ds.where) > 0,
+		Query:     query,
+		Arguments: args,
+	})
 }
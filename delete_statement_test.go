@@ -18,6 +18,18 @@ func TestDelete(t *testing.T) {
 	})
 }
 
+func TestDeleteClone(t *testing.T) {
+	Convey("Clone returns an independant copy of a delete statement", t, func() {
+		db := &DB{}
+		base := db.DeleteFrom("dummies").Where("foo = ?", 1)
+		clone := base.Clone().Where("bar = ?", 2).Returning("id")
+		So(len(clone.where), ShouldEqual, 2)
+		So(len(clone.returningColumns), ShouldEqual, 1)
+		So(len(base.where), ShouldEqual, 1)
+		So(len(base.returningColumns), ShouldEqual, 0)
+	})
+}
+
 func TestDeleteWhere(t *testing.T) {
 	Convey("Given a delete statement", t, func() {
 		db := &DB{}
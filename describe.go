@@ -0,0 +1,93 @@
+package godb
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/dbreflect"
+)
+
+// MappingDescription describes how a struct is mapped, and the statements the
+// struct tools build with it. See DescribeMapping.
+type MappingDescription struct {
+	Struct    string
+	Table     string
+	Columns   []dbreflect.ColumnDescription
+	SelectSQL string
+	InsertSQL string
+}
+
+// DescribeMapping returns the description of the mapping of the given struct
+// (through an instance or pointer) : the table name, the columns and their
+// options, and the SQL of Select and Insert for the adapter of the DB. Use it
+// to debug mapping surprises, its String method gives a printable version.
+//
+// Example :
+// 	description, err := db.DescribeMapping(&Book{})
+// 	fmt.Println(description)
+func (db *DB) DescribeMapping(record interface{}) (*MappingDescription, error) {
+	recordDescription, err := buildRecordDescription(record)
+	if err != nil {
+		return nil, err
+	}
+	structMapping := recordDescription.structMapping
+	instance := recordDescription.getOneInstancePointer()
+
+	description := &MappingDescription{
+		Struct:  structMapping.Name,
+		Table:   db.defaultTableNamer(recordDescription.getTableName()),
+		Columns: structMapping.DescribeColumns(),
+	}
+
+	description.SelectSQL, _, err = db.Select(instance).selectStatement.
+		Columns(db.quoteAllFor(recordDescription, structMapping.GetAllColumnsNames())...).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	insertStatement := db.InsertInto(db.quoteFor(recordDescription, description.Table)).
+		Columns(db.quoteAllFor(recordDescription, structMapping.GetNonAutoColumnsNames())...).
+		Values(structMapping.GetNonAutoFieldsValues(instance)...)
+	if returningBuilder, ok := db.adapter.(adapters.ReturningBuilder); ok {
+		insertStatement.Returning(returningBuilder.FormatForNewValues(structMapping.GetAutoColumnsNames())...)
+	}
+	description.InsertSQL, _, err = insertStatement.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	return description, nil
+}
+
+// String returns a printable description of the mapping.
+func (d *MappingDescription) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Struct : %s\n", d.Struct)
+	fmt.Fprintf(&builder, "Table  : %s\n", d.Table)
+	builder.WriteString("Columns :\n")
+
+	writer := tabwriter.NewWriter(&builder, 0, 4, 2, ' ', 0)
+	for _, column := range d.Columns {
+		options := make([]string, 0, 3)
+		if column.IsKey {
+			options = append(options, "key")
+		}
+		if column.IsGenerated {
+			options = append(options, "generated")
+		} else if column.IsAuto {
+			options = append(options, "auto")
+		}
+		if column.IsOpLock {
+			options = append(options, "oplock")
+		}
+		fmt.Fprintf(writer, "  %s\t%s\t%s\n", column.Name, column.Field, strings.Join(options, ","))
+	}
+	writer.Flush()
+
+	fmt.Fprintf(&builder, "Select : %s\n", d.SelectSQL)
+	fmt.Fprintf(&builder, "Insert : %s\n", d.InsertSQL)
+	return builder.String()
+}
@@ -0,0 +1,44 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDescribeMapping(t *testing.T) {
+	Convey("Given a DB", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+		db.defaultTableNamer = func(name string, done bool) string { return name }
+
+		Convey("DescribeMapping returns the table, columns and statements of a struct", func() {
+			description, err := db.DescribeMapping(&Dummy{})
+			So(err, ShouldBeNil)
+			So(description.Struct, ShouldEndWith, "Dummy")
+			So(description.Table, ShouldEqual, "dummies")
+			So(len(description.Columns), ShouldEqual, 6)
+			So(description.Columns[0].Name, ShouldEqual, "id")
+			So(description.Columns[0].Field, ShouldEqual, "ID")
+			So(description.Columns[0].IsKey, ShouldBeTrue)
+			So(description.Columns[0].IsAuto, ShouldBeTrue)
+			So(description.Columns[5].IsOpLock, ShouldBeTrue)
+			So(description.SelectSQL, ShouldEqual, `SELECT "id", "a_text", "another_text", "an_integer", "a_nullable_string", "version" FROM "dummies"`)
+			So(description.InsertSQL, ShouldEqual, `INSERT INTO "dummies" ("a_text", "another_text", "an_integer", "a_nullable_string", "version") VALUES (?, ?, ?, ?, ?)`)
+
+			Convey("String gives a printable version", func() {
+				printable := description.String()
+				So(printable, ShouldContainSubstring, "Table  : dummies\n")
+				So(printable, ShouldContainSubstring, "  id  ")
+				So(printable, ShouldContainSubstring, "key,auto\n")
+				So(printable, ShouldContainSubstring, "oplock\n")
+				So(printable, ShouldContainSubstring, "Select : SELECT")
+			})
+		})
+
+		Convey("DescribeMapping returns an error for a non struct", func() {
+			_, err := db.DescribeMapping(123)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
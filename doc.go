@@ -72,6 +72,14 @@ Example :
 		Do(&countByAuthor)
 	…
 
+	// Identifiers (checked and quoted) and raw expressions (used as is)
+	err = db.SelectFrom("books").
+		ColumnsExpr(godb.Ident("author"), godb.Raw("count(*) as count")).
+		GroupByExpr(godb.Ident("author")).
+		OrderByExpr(godb.Desc(godb.Raw("count(*)"))).
+		Do(&countByAuthor)
+	…
+
 	newId, err := db.InsertInto("dummies")
 		.Columns("foo", "bar", "baz")
 		.Values(1, 2, 3)
@@ -154,6 +162,12 @@ Examples :
 	err = db.Select(&multipleBooks).Do()
 	…
 
+	// Fetch by key, or check existence
+	err = db.Get(&singleBook, 123)
+	…
+	found, err := db.Exists(&Book{}, 123)
+	…
+
 
 Raw queries
 
@@ -174,9 +188,14 @@ Structs mapping
 Stucts contents are mapped to databases columns with tags, like in previous
 example with the Book struct. The tag is 'db' and its content is :
 
-	* The columns name (mandatory, there is no default rule).
+	* The columns name (mandatory, unless the names are inferred, see below).
 	* The 'key' keyword if the field/column is a part of the table key.
 	* The 'auto' keyword if the field/column value is set by the database.
+	* The 'generated' keyword if the column is computed by the database (a
+	  generated column). It's an 'auto' column never written, even if it's
+	  given to Whitelist. Its value is read back with the RETURNING (or
+	  OUTPUT) clause after an insert or an update with PostgreSQL and SQL
+	  Server, reload the record with the others databases.
 
 For autoincrement identifier simple use both 'key' and 'auto'.
 
@@ -189,6 +208,24 @@ Example :
 		Other string
 	}
 
+The columns names could be inferred from the fields names, then the fields
+without tag are mapped (snake_case names by default), and the tags are only
+needed for exceptions and options. The `db:"-"` tag ignores a field :
+
+	dbreflect.InferColumnNames(true)
+	// optional, tablenamer.Snake() is the default
+	dbreflect.SetColumnNamer(tablenamer.Same())
+
+	type SimpleStruct struct {
+		ID      int    `db:",key,auto"` // id
+		MyText  string // my_text
+		Other   string `db:"-"`
+	}
+
+Pointer fields (*string, *int64, *time.Time, ...) are nullable columns : a nil
+pointer is written as NULL, and NULL is read as nil. They could be used instead
+of the sql.Null* types.
+
 More than one field could have the 'key' keyword, but with most databases
 drivers none of them could have the 'auto' keyword, because executing an insert
 query only returns one value : the last inserted id : https://golang.org/pkg/database/sql/driver/#RowsAffected.LastInsertId .
@@ -199,6 +236,8 @@ Structs could be nested. A nested struct is mapped only if has the 'db' tag. The
 
 A nested struct could also have an optionnal `rel` attribute of the form `rel=relationname`. It's useful to build a select query using multiples relations (table, view, ...). See the example using the BooksWithInventories type.
 
+A nested struct could be a pointer. When scanning rows, the pointer is set to nil if all the columns of the nested struct are NULL (ie with a LEFT JOIN without matching row). When writing, the fields of a nil nested struct are NULL.
+
 Example
 
 	type KeyStruct struct {
@@ -238,6 +277,18 @@ this simply by simplementing a TableName method :
 		return "books"
 	}
 
+The table and columns names are quoted by the adapter (the parts already
+quoted and * are kept as is). For edge cases like reserved words or case
+sensitive legacy names, a struct could quote its identifiers itself with a
+QuoteIdentifier method, returning false to let the adapter quote the others :
+
+	func (*Book) QuoteIdentifier(identifier string) (string, bool) {
+		if identifier == "books" {
+			return `"Legacy"."BOOKS"`, true
+		}
+		return "", false
+	}
+
 
 Conditions
 
@@ -265,6 +316,20 @@ multiple ones. This allows code like :
 
 	count, err := db.SelectFrom("bar").Where("foo in (?)", fooSlice).Count()
 
+Empty slices produce an error, unless the condition is build with
+godb.QAllowEmpty : 'foo in (?)' then becomes '1=0', and 'foo not in (?)'
+becomes '1=1'.
+
+NULL arguments (nil, nil pointers, invalid sql.Null* values) are allowed :
+'foo = ?' becomes 'foo IS NULL', 'foo <> ?' becomes 'foo IS NOT NULL', and in
+other cases NULL is given as a bind value.
+
+Conditions could also be build with a fluent builder, starting with godb.Col,
+these calls are equivalents :
+
+	…WhereQ(godb.Col("age").Gte(18).And(godb.Col("status").In("a", "b")))…
+	…Where("age >= ? AND status IN (?)", 18, []string{"a", "b"})…
+
 
 SQLBuffer
 
@@ -0,0 +1,90 @@
+package godb
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// DryRunHandler is called for each statement not executed in dry run mode,
+// with the SQL (placeholders replaced for the adapter) and its arguments.
+type DryRunHandler func(query string, arguments []interface{})
+
+// DryRun enables or disables the dry run mode. In dry run mode the statements
+// are built, logged and given to the handler (see SetDryRunHandler) but never
+// executed. Use it to generate scripts, preview changes, ...
+//
+// As nothing is executed, the statements behave as if no row was affected
+// or returned : no auto field is filled, a select of a single instance
+// returns sql.ErrNoRows, and Scanx gives zero values (a count is 0). The
+// optimistic locking is not checked. Transactions
+// are still managed by the database.
+func (db *DB) DryRun(enabled bool) {
+	db.dryRun = enabled
+}
+
+// IsDryRun returns true if the dry run mode is enabled.
+func (db *DB) IsDryRun() bool {
+	return db.dryRun
+}
+
+// SetDryRunHandler sets the function receiving the statements not executed
+// in dry run mode.
+func (db *DB) SetDryRunHandler(handler DryRunHandler) {
+	db.dryRunHandler = handler
+}
+
+// skipStatement logs the statement and gives it to the dry run handler
+// (placeholders replaced) and to the SQL bundle being recorded.
+func (db *DB) skipStatement(query string, arguments []interface{}, options statementOptions) {
+	if db.sqlBundle != nil {
+		db.sqlBundle.add(query, arguments)
+	}
+	query = db.hintTimeout(db.replacePlaceholders(query), options)
+	db.logPrintln("DRY RUN", query, arguments)
+	if db.dryRunHandler != nil {
+		db.dryRunHandler(query, arguments)
+	}
+}
+
+// dryRunResult is the sql.Result of a statement not executed.
+type dryRunResult struct{}
+
+// LastInsertId returns 0 as nothing was inserted.
+func (dryRunResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+// RowsAffected returns 0 as nothing was changed.
+func (dryRunResult) RowsAffected() (int64, error) {
+	return 0, nil
+}
+
+var _ sql.Result = dryRunResult{}
+
+// dryRunIterator is an Iterator without rows.
+type dryRunIterator struct{}
+
+// Next returns always false, there are no rows.
+func (dryRunIterator) Next() bool {
+	return false
+}
+
+// Scan returns an error, there are no rows.
+func (dryRunIterator) Scan(interface{}) error {
+	return fmt.Errorf("no rows in dry run mode")
+}
+
+// Scanx returns an error, there are no rows.
+func (dryRunIterator) Scanx(...interface{}) error {
+	return fmt.Errorf("no rows in dry run mode")
+}
+
+// Close does nothing.
+func (dryRunIterator) Close() error {
+	return nil
+}
+
+// Err returns always nil.
+func (dryRunIterator) Err() error {
+	return nil
+}
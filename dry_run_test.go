@@ -0,0 +1,65 @@
+package godb
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDryRun(t *testing.T) {
+	Convey("Given a test database in dry run mode", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		var queries []string
+		db.DryRun(true)
+		db.SetDryRunHandler(func(query string, arguments []interface{}) {
+			queries = append(queries, query)
+		})
+		So(db.IsDryRun(), ShouldBeTrue)
+
+		Convey("Statements are given to the handler but not executed", func() {
+			dummy := Dummy{AText: "Foo", AnotherText: "Bar", AnInteger: 1}
+			So(db.Insert(&dummy).Do(), ShouldBeNil)
+			So(dummy.ID, ShouldEqual, 0)
+
+			count, err := db.DeleteFrom("dummies").Do()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 0)
+
+			iterator, err := db.SelectFrom("dummies").Columns("id").DoWithIterator()
+			So(err, ShouldBeNil)
+			So(iterator.Next(), ShouldBeFalse)
+
+			So(len(queries), ShouldEqual, 4)
+			So(queries[1], ShouldEqual, "DELETE FROM dummies")
+
+			db.DryRun(false)
+			count, err = db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("Scanx gives zero values without execution", func() {
+			count, err := db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+
+			text := "unchanged"
+			So(db.SelectFrom("dummies").Columns("a_text").Where("id = ?", 1).Scanx(&text), ShouldBeNil)
+			So(text, ShouldEqual, "")
+			So(len(queries), ShouldEqual, 2)
+			So(queries[0], ShouldEqual, "SELECT COUNT(*) FROM dummies")
+		})
+
+		Convey("Optimistic locking is not checked", func() {
+			dummy := Dummy{ID: 1, AText: "Foo", AnotherText: "Bar", AnInteger: 1}
+			So(db.Update(&dummy).Do(), ShouldBeNil)
+			So(len(queries), ShouldEqual, 1)
+		})
+	})
+}
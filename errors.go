@@ -0,0 +1,363 @@
+package godb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotFound is an error returned when a single record is requested but not
+// found. It wraps sql.ErrNoRows, then errors.Is(err, sql.ErrNoRows) is also
+// true.
+var ErrNotFound = fmt.Errorf("record not found: %w", sql.ErrNoRows)
+
+// NotFoundError is returned when a single record is requested but not found
+// (see UseNotFoundError). It gives the table name when it is known, and the
+// criteria used to search the record.
+//
+// It matches both ErrNotFound and sql.ErrNoRows with errors.Is.
+type NotFoundError struct {
+	Table     string
+	Criteria  string
+	Arguments []interface{}
+}
+
+// newNotFoundError builds a NotFoundError for the given table and WHERE
+// conditions.
+func newNotFoundError(table string, conditions []*Condition) *NotFoundError {
+	notFound := &NotFoundError{Table: table}
+	if len(conditions) > 0 {
+		condition := And(conditions...)
+		notFound.Criteria = condition.sql
+		notFound.Arguments = condition.args
+	}
+	return notFound
+}
+
+// Error returns the error message, with the table and criteria if known.
+func (e *NotFoundError) Error() string {
+	var message strings.Builder
+	message.WriteString("record not found")
+	if e.Table != "" {
+		message.WriteString(" in ")
+		message.WriteString(e.Table)
+	}
+	if e.Criteria != "" {
+		message.WriteString(" for ")
+		message.WriteString(e.Criteria)
+		if len(e.Arguments) > 0 {
+			fmt.Fprintf(&message, " %v", e.Arguments)
+		}
+	}
+	return message.String()
+}
+
+// Is allows errors.Is(err, ErrNotFound).
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// Unwrap returns sql.ErrNoRows.
+func (e *NotFoundError) Unwrap() error {
+	return sql.ErrNoRows
+}
+
+// ErrMultipleRecords is an error returned when a single record is expected
+// but several match (see SelectStatement.ExpectOne).
+var ErrMultipleRecords = errors.New("more than one record found")
+
+// MultipleRecordsError is returned when a single record is expected but
+// several match (see SelectStatement.ExpectOne). Like NotFoundError it gives
+// the table name when it is known, and the criteria used to search the record.
+//
+// It matches ErrMultipleRecords with errors.Is.
+type MultipleRecordsError struct {
+	Table     string
+	Criteria  string
+	Arguments []interface{}
+}
+
+// newMultipleRecordsError builds a MultipleRecordsError for the given table
+// and WHERE conditions.
+func newMultipleRecordsError(table string, conditions []*Condition) *MultipleRecordsError {
+	notFound := newNotFoundError(table, conditions)
+	return &MultipleRecordsError{
+		Table:     notFound.Table,
+		Criteria:  notFound.Criteria,
+		Arguments: notFound.Arguments,
+	}
+}
+
+// Error returns the error message, with the table and criteria if known.
+func (e *MultipleRecordsError) Error() string {
+	var message strings.Builder
+	message.WriteString(ErrMultipleRecords.Error())
+	if e.Table != "" {
+		message.WriteString(" in ")
+		message.WriteString(e.Table)
+	}
+	if e.Criteria != "" {
+		message.WriteString(" for ")
+		message.WriteString(e.Criteria)
+		if len(e.Arguments) > 0 {
+			fmt.Fprintf(&message, " %v", e.Arguments)
+		}
+	}
+	return message.String()
+}
+
+// Is allows errors.Is(err, ErrMultipleRecords).
+func (e *MultipleRecordsError) Is(target error) bool {
+	return target == ErrMultipleRecords
+}
+
+// ErrInvalidCursor is an error returned when a cursor token is malformed,
+// tampered, or used with another sort (see CursorEncoder).
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrReadOnly is an error returned when a statement changing data is
+// executed with a read only DB (see SetReadOnly), or a read only adapter.
+var ErrReadOnly = errors.New("the database is read only")
+
+// ReadOnlyError is returned when a statement changing data is executed with a
+// read only DB (see SetReadOnly). It gives the rejected statement.
+//
+// It matches ErrReadOnly with errors.Is.
+type ReadOnlyError struct {
+	Query string
+}
+
+// Error returns the error message with the rejected statement.
+func (e *ReadOnlyError) Error() string {
+	return ErrReadOnly.Error() + ", statement rejected : " + e.Query
+}
+
+// Is allows errors.Is(err, ErrReadOnly).
+func (e *ReadOnlyError) Is(target error) bool {
+	return target == ErrReadOnly
+}
+
+// ErrTooManyRows is an error returned when a result has more rows than
+// allowed (see SetMaxRows).
+var ErrTooManyRows = errors.New("too many rows")
+
+// TooManyRowsError is returned when a result has more rows than allowed (see
+// SetMaxRows). The scanning is aborted, the target contains the rows already
+// read.
+//
+// It matches ErrTooManyRows with errors.Is.
+type TooManyRowsError struct {
+	MaxRows int
+}
+
+// Error returns the error message with the maximum rows allowed.
+func (e *TooManyRowsError) Error() string {
+	return fmt.Sprintf("%v, the result exceeds %d rows", ErrTooManyRows, e.MaxRows)
+}
+
+// Is allows errors.Is(err, ErrTooManyRows).
+func (e *TooManyRowsError) Is(target error) bool {
+	return target == ErrTooManyRows
+}
+
+// ErrStatementDenied is an error returned when a statement is rejected by a
+// guard (see AddGuard).
+var ErrStatementDenied = errors.New("statement denied")
+
+// StatementDeniedError is returned by the guards given by godb when a
+// statement is rejected (see AddGuard). It gives the reason and the rejected
+// statement.
+//
+// It matches ErrStatementDenied with errors.Is.
+type StatementDeniedError struct {
+	Reason string
+	Query  string
+}
+
+// Error returns the error message with the reason and the rejected statement.
+func (e *StatementDeniedError) Error() string {
+	return ErrStatementDenied.Error() + " (" + e.Reason + ") : " + e.Query
+}
+
+// Is allows errors.Is(err, ErrStatementDenied).
+func (e *StatementDeniedError) Is(target error) bool {
+	return target == ErrStatementDenied
+}
+
+// ErrCircuitOpen is an error returned when a statement is rejected without
+// being executed because the circuit breaker is open (see
+// SetCircuitBreaker).
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// ErrPartialCommit is an error returned by Coordinator.Run when some
+// participants are committed and others are not.
+var ErrPartialCommit = errors.New("partial commit")
+
+// PartialCommitError is returned by Coordinator.Run when the commit of a
+// participant fails after the commit of the previous ones. It gives the
+// participants committed (and compensated if possible), the failed one, and
+// the ones rolled back.
+//
+// It matches ErrPartialCommit with errors.Is, and unwraps to the commit
+// error.
+type PartialCommitError struct {
+	Committed  []string
+	Failed     string
+	RolledBack []string
+	// Err is the commit error of the failed participant
+	Err error
+	// CompensationErrors contains the errors of the compensations, by
+	// participant. The committed participants missing here are compensated.
+	CompensationErrors map[string]error
+}
+
+// Error returns the error message with the participants states.
+func (e *PartialCommitError) Error() string {
+	message := fmt.Sprintf("%v, commit of %s failed (%v) after %s",
+		ErrPartialCommit, e.Failed, e.Err, strings.Join(e.Committed, ", "))
+	for _, name := range e.Committed {
+		if err, ok := e.CompensationErrors[name]; ok {
+			message += fmt.Sprintf(", compensation of %s failed (%v)", name, err)
+		}
+	}
+	return message
+}
+
+// Is allows errors.Is(err, ErrPartialCommit).
+func (e *PartialCommitError) Is(target error) bool {
+	return target == ErrPartialCommit
+}
+
+// Unwrap returns the commit error.
+func (e *PartialCommitError) Unwrap() error {
+	return e.Err
+}
+
+// ErrReadOnlyModel is an error returned when a struct marked as read only
+// is inserted, updated or deleted (see ReadOnlyModel).
+var ErrReadOnlyModel = errors.New("the model is read only")
+
+// ReadOnlyModelError is returned when a struct marked as read only is
+// inserted, updated or deleted (see ReadOnlyModel). It gives the struct and
+// its table.
+//
+// It matches ErrReadOnlyModel with errors.Is.
+type ReadOnlyModelError struct {
+	Struct string
+	Table  string
+}
+
+// Error returns the error message with the struct and the table.
+func (e *ReadOnlyModelError) Error() string {
+	return fmt.Sprintf("%v : %s (%s)", ErrReadOnlyModel, e.Struct, e.Table)
+}
+
+// Is allows errors.Is(err, ErrReadOnlyModel).
+func (e *ReadOnlyModelError) Is(target error) bool {
+	return target == ErrReadOnlyModel
+}
+
+// ErrValidation is an error returned when a struct is not valid on insert or
+// update (see Validatable and UseValidator).
+var ErrValidation = errors.New("validation failed")
+
+// ValidationError is returned when structs are not valid on insert or
+// update (see Validatable and UseValidator). It gives the errors of all the
+// fields, to be translated by the API layers (to a 422 response for example).
+//
+// It matches ErrValidation with errors.Is.
+type ValidationError struct {
+	Struct string
+	Fields []FieldError
+}
+
+// FieldError is an error of a field of a ValidationError. Record is the
+// index of the struct in the slice given to BulkInsert (0 otherwise). Field
+// is empty for an error of the whole struct.
+type FieldError struct {
+	Record  int
+	Field   string
+	Message string
+}
+
+// Error returns the error message with the struct and the errors of its
+// fields.
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		if field.Field == "" {
+			messages = append(messages, field.Message)
+		} else {
+			messages = append(messages, field.Field+": "+field.Message)
+		}
+	}
+	return fmt.Sprintf("%v : %s (%s)", ErrValidation, e.Struct, strings.Join(messages, ", "))
+}
+
+// Is allows errors.Is(err, ErrValidation).
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// ErrUnmappedColumns is an error returned by a strict scan when the columns
+// of a result don't match the fields of the struct (see SetStrictScan).
+var ErrUnmappedColumns = errors.New("unmapped columns")
+
+// UnmappedColumnsError is returned by a strict scan when columns of the
+// result have no field, or fields of the struct have no column (see
+// SetStrictScan). Nothing is scanned.
+//
+// It matches ErrUnmappedColumns with errors.Is.
+type UnmappedColumnsError struct {
+	Struct string
+	// UnknownColumns are the columns of the result having no field
+	UnknownColumns []string
+	// MissingColumns are the columns of the struct not in the result
+	MissingColumns []string
+}
+
+// Error returns the error message with the unmapped columns.
+func (e *UnmappedColumnsError) Error() string {
+	message := fmt.Sprintf("%v in struct %s", ErrUnmappedColumns, e.Struct)
+	if len(e.UnknownColumns) > 0 {
+		message += ", unknown columns : " + strings.Join(e.UnknownColumns, ", ")
+	}
+	if len(e.MissingColumns) > 0 {
+		message += ", missing columns : " + strings.Join(e.MissingColumns, ", ")
+	}
+	return message
+}
+
+// Is allows errors.Is(err, ErrUnmappedColumns).
+func (e *UnmappedColumnsError) Is(target error) bool {
+	return target == ErrUnmappedColumns
+}
+
+// ErrAlreadyExists is an error returned when a record can't be inserted
+// because a row with the same unique values exists (see InsertUnique).
+var ErrAlreadyExists = errors.New("the record already exists")
+
+// AlreadyExistsError is returned by InsertUnique when a row with the same
+// values for the unique columns exists. It gives the conflicting key and the
+// existing row.
+//
+// It matches ErrAlreadyExists with errors.Is.
+type AlreadyExistsError struct {
+	Struct string
+	// Columns and Values are the conflicting key
+	Columns []string
+	Values  []interface{}
+	// Existing is a pointer to a new struct filled with the existing row
+	Existing interface{}
+}
+
+// Error returns the error message with the conflicting key.
+func (e *AlreadyExistsError) Error() string {
+	return fmt.Sprintf("%v : %s with %s %v", ErrAlreadyExists, e.Struct, strings.Join(e.Columns, ", "), e.Values)
+}
+
+// Is allows errors.Is(err, ErrAlreadyExists).
+func (e *AlreadyExistsError) Is(target error) bool {
+	return target == ErrAlreadyExists
+}
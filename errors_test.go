@@ -0,0 +1,32 @@
+package godb
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNotFoundError(t *testing.T) {
+	Convey("Given a NotFoundError", t, func() {
+		err := newNotFoundError("dummies", []*Condition{Q("id = ?", 123), Q("a_text = ?", "foo")})
+
+		Convey("It contains the table and criteria", func() {
+			So(err.Table, ShouldEqual, "dummies")
+			So(err.Criteria, ShouldEqual, "id = ? AND a_text = ?")
+			So(err.Arguments, ShouldResemble, []interface{}{123, "foo"})
+			So(err.Error(), ShouldEqual, "record not found in dummies for id = ? AND a_text = ? [123 foo]")
+		})
+
+		Convey("It matches ErrNotFound and sql.ErrNoRows", func() {
+			So(errors.Is(err, ErrNotFound), ShouldBeTrue)
+			So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a NotFoundError without criteria", t, func() {
+		err := newNotFoundError("dummies", nil)
+		So(err.Error(), ShouldEqual, "record not found in dummies")
+	})
+}
@@ -0,0 +1,107 @@
+package godb
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// Expression is a SQL expression usable in place of a column name with
+// ColumnsExpr, GroupByExpr and OrderByExpr.
+//
+// The builder distinguishes identifiers (Ident), which are checked and quoted,
+// from deliberately raw SQL expressions (Raw) used as is.
+type Expression interface {
+	// BuildSQL returns the SQL of the expression for the given adapter,
+	// with its arguments.
+	BuildSQL(adapter adapters.Adapter) (string, []interface{}, error)
+}
+
+// Ident is an identifier (column, table, ...), optionally qualified with dots
+// like "books.title". Each part must start with a letter or an underscore,
+// followed by letters, digits or underscores. It is quoted by the adapter.
+type Ident string
+
+// BuildSQL checks and quotes the identifier.
+func (i Ident) BuildSQL(adapter adapters.Adapter) (string, []interface{}, error) {
+	if err := checkIdentifier(string(i)); err != nil {
+		return "", nil, err
+	}
+	return quoteIdentifier(adapter, string(i)), nil, nil
+}
+
+// Raw is a raw SQL expression used as is, like "count(*) as count".
+// Never build it with user inputs.
+type Raw string
+
+// BuildSQL returns the raw expression, it can't be blank.
+func (r Raw) BuildSQL(adapter adapters.Adapter) (string, []interface{}, error) {
+	if strings.TrimSpace(string(r)) == "" {
+		return "", nil, fmt.Errorf("empty raw expression")
+	}
+	return string(r), nil, nil
+}
+
+// sortedExpression is an expression followed by a sort direction.
+type sortedExpression struct {
+	expression Expression
+	direction  string
+}
+
+// Asc sorts the given expression in ascending order (see OrderByExpr).
+func Asc(expression Expression) Expression {
+	return &sortedExpression{expression: expression, direction: "ASC"}
+}
+
+// Desc sorts the given expression in descending order (see OrderByExpr).
+func Desc(expression Expression) Expression {
+	return &sortedExpression{expression: expression, direction: "DESC"}
+}
+
+// BuildSQL returns the expression SQL followed by the sort direction.
+func (se *sortedExpression) BuildSQL(adapter adapters.Adapter) (string, []interface{}, error) {
+	sql, args, err := se.expression.BuildSQL(adapter)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql + " " + se.direction, args, nil
+}
+
+// buildExpressions builds all given expressions, and returns their SQL and
+// all their arguments.
+func buildExpressions(adapter adapters.Adapter, expressions []Expression) ([]string, []interface{}, error) {
+	sqlParts := make([]string, 0, len(expressions))
+	var args []interface{}
+	for _, expression := range expressions {
+		if expression == nil {
+			return nil, nil, fmt.Errorf("nil expression")
+		}
+		sql, expressionArgs, err := expression.BuildSQL(adapter)
+		if err != nil {
+			return nil, nil, err
+		}
+		sqlParts = append(sqlParts, sql)
+		args = append(args, expressionArgs...)
+	}
+	return sqlParts, args, nil
+}
+
+// checkIdentifier returns an error if the given identifier could not be
+// safely quoted : all its parts (separated by dots) must start with a letter
+// or an underscore, followed by letters, digits or underscores.
+func checkIdentifier(identifier string) error {
+	for _, part := range strings.Split(identifier, ".") {
+		if part == "" {
+			return fmt.Errorf("invalid identifier %q", identifier)
+		}
+		for i, r := range part {
+			if r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)) {
+				continue
+			}
+			return fmt.Errorf("invalid identifier %q", identifier)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestExpressions(t *testing.T) {
+	Convey("Ident checks and quotes identifiers", t, func() {
+		sql, args, err := Ident("books.title").BuildSQL(sqlite.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, "\"books\".\"title\"")
+		So(args, ShouldBeEmpty)
+
+		_, _, err = Ident("title; drop table books").BuildSQL(sqlite.Adapter)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Raw is used as is", t, func() {
+		sql, _, err := Raw("count(*) as count").BuildSQL(sqlite.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, "count(*) as count")
+
+		_, _, err = Raw(" ").BuildSQL(sqlite.Adapter)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Asc and Desc add a direction", t, func() {
+		sql, _, err := Asc(Ident("title")).BuildSQL(sqlite.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, "\"title\" ASC")
+		sql, _, err = Desc(Raw("count(*)")).BuildSQL(sqlite.Adapter)
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, "count(*) DESC")
+	})
+}
+
+func TestCheckIdentifier(t *testing.T) {
+	Convey("checkIdentifier accepts valid identifiers", t, func() {
+		So(checkIdentifier("foo"), ShouldBeNil)
+		So(checkIdentifier("_foo_1"), ShouldBeNil)
+		So(checkIdentifier("schema.table.column"), ShouldBeNil)
+	})
+
+	Convey("checkIdentifier refuses invalid identifiers", t, func() {
+		So(checkIdentifier(""), ShouldNotBeNil)
+		So(checkIdentifier("1foo"), ShouldNotBeNil)
+		So(checkIdentifier("foo."), ShouldNotBeNil)
+		So(checkIdentifier("foo bar"), ShouldNotBeNil)
+		So(checkIdentifier("foo\""), ShouldNotBeNil)
+	})
+}
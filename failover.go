@@ -0,0 +1,214 @@
+package godb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// Backoff of an unreachable data source : it's not tried again before the
+// delay, which doubles with each failure up to the maximum.
+var (
+	failoverMinBackoff = time.Second
+	failoverMaxBackoff = time.Minute
+)
+
+// OpenWithFailover creates a new DB struct using several data sources of the
+// same logical database (the nodes of a cluster for example).
+//
+// The connections are opened with the current data source, starting with the
+// first one. When it becomes unreachable (the connection or its ping fails),
+// the next data sources are tried in order, and the first one reachable becomes
+// the current one. The connections already opened with the previous data
+// source are dropped by database/sql when the driver reports them as broken.
+// The unreachable data sources are not tried again before a backoff delay,
+// unless all of them are unreachable.
+//
+// There is no failback : the current data source stays the same as long as it
+// is reachable, as a previous primary could come back as a replica.
+//
+// Example :
+// 	db, err := godb.OpenWithFailover(postgresql.Adapter,
+// 		"host=node1 dbname=app connect_timeout=2",
+// 		"host=node2 dbname=app connect_timeout=2")
+func OpenWithFailover(adapter adapters.Adapter, dataSourceNames ...string) (*DB, error) {
+	if len(dataSourceNames) == 0 {
+		return nil, fmt.Errorf("OpenWithFailover needs at least one data source")
+	}
+
+	// sql.Open does not connect, it's only used to get the driver
+	dbInst, err := sql.Open(adapter.DriverName(), dataSourceNames[0])
+	if err != nil {
+		return nil, err
+	}
+	sqlDriver := dbInst.Driver()
+	dbInst.Close()
+
+	connector := newFailoverConnector(sqlDriver, dataSourceNames)
+	db := initialize(adapter, sql.OpenDB(connector))
+	db.failover = connector
+	connector.log = db.logPrintln
+	return db, nil
+}
+
+// CurrentDataSource returns the index of the data source given to
+// OpenWithFailover used to open the new connections, or -1 if the DB was
+// not created by OpenWithFailover.
+func (db *DB) CurrentDataSource() int {
+	if db.failover == nil {
+		return -1
+	}
+	return db.failover.currentIndex()
+}
+
+// failoverEndpoint is the health of a data source.
+type failoverEndpoint struct {
+	dataSourceName string
+	failures       int
+	retryAt        time.Time
+}
+
+// failoverConnector is a driver.Connector opening the connections with the
+// current data source, and failing over the next ones.
+type failoverConnector struct {
+	driver    driver.Driver
+	mutex     sync.Mutex
+	endpoints []failoverEndpoint
+	current   int
+	now       func() time.Time
+	log       func(v ...interface{})
+}
+
+// newFailoverConnector creates a failoverConnector for the given data sources.
+func newFailoverConnector(sqlDriver driver.Driver, dataSourceNames []string) *failoverConnector {
+	endpoints := make([]failoverEndpoint, 0, len(dataSourceNames))
+	for _, dataSourceName := range dataSourceNames {
+		endpoints = append(endpoints, failoverEndpoint{dataSourceName: dataSourceName})
+	}
+	return &failoverConnector{
+		driver:    sqlDriver,
+		endpoints: endpoints,
+		now:       time.Now,
+		log:       func(v ...interface{}) {},
+	}
+}
+
+// Connect opens a connection with the current data source, or the next
+// reachable one. It implements driver.Connector.
+func (fc *failoverConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	var lastErr error
+	for _, index := range fc.candidates() {
+		conn, err := fc.open(ctx, index)
+		if err == nil {
+			fc.markUp(index)
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		fc.markDown(index, err)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// Driver returns the underlying driver. It implements driver.Connector.
+func (fc *failoverConnector) Driver() driver.Driver {
+	return fc.driver
+}
+
+// candidates returns the indexes of the data sources to try : the current
+// one, then the next ones in a circular order, skipping the ones waiting for
+// their backoff delay. If all of them are waiting, they are all returned.
+func (fc *failoverConnector) candidates() []int {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	now := fc.now()
+	all := make([]int, 0, len(fc.endpoints))
+	available := make([]int, 0, len(fc.endpoints))
+	for i := range fc.endpoints {
+		index := (fc.current + i) % len(fc.endpoints)
+		all = append(all, index)
+		if !now.Before(fc.endpoints[index].retryAt) {
+			available = append(available, index)
+		}
+	}
+	if len(available) == 0 {
+		return all
+	}
+	return available
+}
+
+// open opens a connection with the given data source, and pings it if the
+// driver allows it.
+func (fc *failoverConnector) open(ctx context.Context, index int) (driver.Conn, error) {
+	conn, err := openDataSource(ctx, fc.driver, fc.endpoints[index].dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if pinger, ok := conn.(driver.Pinger); ok {
+		if err = pinger.Ping(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// openDataSource opens a connection with the given driver and data source,
+// like sql.Open does.
+func openDataSource(ctx context.Context, sqlDriver driver.Driver, dataSourceName string) (driver.Conn, error) {
+	if driverContext, ok := sqlDriver.(driver.DriverContext); ok {
+		connector, err := driverContext.OpenConnector(dataSourceName)
+		if err != nil {
+			return nil, err
+		}
+		return connector.Connect(ctx)
+	}
+	return sqlDriver.Open(dataSourceName)
+}
+
+// markUp resets the health of the data source, and makes it the current one.
+func (fc *failoverConnector) markUp(index int) {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	fc.endpoints[index].failures = 0
+	fc.endpoints[index].retryAt = time.Time{}
+	if fc.current != index {
+		fc.log("Failover to data source", index)
+		fc.current = index
+	}
+}
+
+// markDown records a failure of the data source, and delays its next try.
+func (fc *failoverConnector) markDown(index int, err error) {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	endpoint := &fc.endpoints[index]
+	endpoint.failures++
+	backoff := failoverMinBackoff
+	for i := 1; i < endpoint.failures && backoff < failoverMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > failoverMaxBackoff {
+		backoff = failoverMaxBackoff
+	}
+	endpoint.retryAt = fc.now().Add(backoff)
+	fc.log("Data source", index, "unreachable :", err)
+}
+
+// currentIndex returns the index of the current data source.
+func (fc *failoverConnector) currentIndex() int {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+	return fc.current
+}
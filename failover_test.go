@@ -0,0 +1,88 @@
+package godb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters/sqlite"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOpenWithFailover(t *testing.T) {
+	Convey("Given a DB with an unreachable first data source", t, func() {
+		db, err := OpenWithFailover(sqlite.Adapter, "/nonexistent/directory/godb.db", ":memory:")
+		So(err, ShouldBeNil)
+		defer db.Close()
+		So(db.CurrentDataSource(), ShouldEqual, 0)
+
+		Convey("The connections are opened with the next data source", func() {
+			So(db.CurrentDB().Ping(), ShouldBeNil)
+			So(db.CurrentDataSource(), ShouldEqual, 1)
+		})
+	})
+
+	Convey("Open does not use failover", t, func() {
+		db, err := Open(sqlite.Adapter, ":memory:")
+		So(err, ShouldBeNil)
+		defer db.Close()
+		So(db.CurrentDataSource(), ShouldEqual, -1)
+	})
+
+	Convey("OpenWithFailover needs a data source", t, func() {
+		_, err := OpenWithFailover(sqlite.Adapter)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestFailoverConnector(t *testing.T) {
+	Convey("Given a failover connector with a fake clock", t, func() {
+		db, err := Open(sqlite.Adapter, ":memory:")
+		So(err, ShouldBeNil)
+		defer db.Close()
+		unreachable := "/nonexistent/directory/godb.db"
+		fc := newFailoverConnector(db.CurrentDB().Driver(), []string{unreachable, ":memory:", unreachable})
+		now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		fc.now = func() time.Time { return now }
+		ctx := context.Background()
+
+		Convey("The unreachable data sources wait for their backoff delay", func() {
+			conn, err := fc.Connect(ctx)
+			So(err, ShouldBeNil)
+			conn.Close()
+			So(fc.currentIndex(), ShouldEqual, 1)
+			So(fc.endpoints[0].retryAt, ShouldEqual, now.Add(failoverMinBackoff))
+
+			// The current data source is tried first, and the first one waits
+			fc.endpoints[1].dataSourceName = unreachable
+			_, err = fc.Connect(ctx)
+			So(err, ShouldNotBeNil)
+			So(fc.endpoints[1].failures, ShouldEqual, 1)
+			So(fc.endpoints[2].failures, ShouldEqual, 1)
+			So(fc.endpoints[0].failures, ShouldEqual, 1)
+
+			// All are waiting, they are all tried, and the backoff grows
+			_, err = fc.Connect(ctx)
+			So(err, ShouldNotBeNil)
+			So(fc.endpoints[0].failures, ShouldEqual, 2)
+			So(fc.endpoints[0].retryAt, ShouldEqual, now.Add(2*failoverMinBackoff))
+
+			// The first one comes back
+			fc.endpoints[0].dataSourceName = ":memory:"
+			now = now.Add(failoverMaxBackoff)
+			conn, err = fc.Connect(ctx)
+			So(err, ShouldBeNil)
+			conn.Close()
+			So(fc.currentIndex(), ShouldEqual, 0)
+			So(fc.endpoints[0].failures, ShouldEqual, 0)
+		})
+
+		Convey("The backoff delay is limited", func() {
+			for i := 0; i < 20; i++ {
+				fc.markDown(0, context.DeadlineExceeded)
+			}
+			So(fc.endpoints[0].retryAt, ShouldEqual, now.Add(failoverMaxBackoff))
+		})
+	})
+}
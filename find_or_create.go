@@ -0,0 +1,205 @@
+package godb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/dberror"
+)
+
+// FindOrCreate looks up the row having the same values as the given struct
+// pointer for the given columns (a natural key, or the columns of a unique
+// constraint), and fills the struct with it. The struct is inserted if there
+// is no such row, with its values for the others columns. It returns true if
+// the struct was inserted.
+//
+// A concurrent insert of the same row is detected with the unique
+// constraint, then the row is looked up again : with an upsert if the
+// adapter supports it (see adapters.ConflictIgnorer), otherwise with the
+// constraint error.
+//
+// Example :
+// 	tag := Tag{Name: "golang"}
+// 	created, err := db.FindOrCreate(&tag, "name")
+func (db *DB) FindOrCreate(record interface{}, columns ...string) (bool, error) {
+	existing, err := db.findBy(record, columns)
+	if err != nil {
+		return false, err
+	}
+	if existing == nil {
+		created, err := db.createIfAbsent(record)
+		if err != nil || created {
+			return created, err
+		}
+		if existing, err = db.findConflicting(record, columns); err != nil {
+			return false, err
+		}
+	}
+	reflect.ValueOf(record).Elem().Set(reflect.ValueOf(existing).Elem())
+	return false, nil
+}
+
+// UpdateOrCreate looks up the row having the same values as the given
+// struct pointer for the given columns, and updates it with the struct. The
+// struct is inserted if there is no such row. It returns true if the struct
+// was inserted. The keys and the optimistic locking version of the struct
+// are the ones of the updated row. See FindOrCreate for the concurrent
+// inserts.
+//
+// Example :
+// 	setting := Setting{Name: "theme", Value: "dark"}
+// 	created, err := db.UpdateOrCreate(&setting, "name")
+func (db *DB) UpdateOrCreate(record interface{}, columns ...string) (bool, error) {
+	existing, err := db.findBy(record, columns)
+	if err != nil {
+		return false, err
+	}
+	if existing == nil {
+		created, err := db.createIfAbsent(record)
+		if err != nil || created {
+			return created, err
+		}
+		if existing, err = db.findConflicting(record, columns); err != nil {
+			return false, err
+		}
+	}
+
+	recordDescription, err := buildRecordDescription(record)
+	if err != nil {
+		return false, err
+	}
+	structMapping := recordDescription.structMapping
+	copyColumns := structMapping.GetKeyColumnsNames()
+	if opLockColumn := structMapping.GetOpLockSQLFieldName(); opLockColumn != "" {
+		copyColumns = append(copyColumns, opLockColumn)
+	}
+	for _, column := range copyColumns {
+		if err := copyColumnValue(structMapping, existing, record, column); err != nil {
+			return false, err
+		}
+	}
+	return false, db.Update(record).Do()
+}
+
+// InsertUnique inserts the given struct pointer, unless a row has the same
+// values for the given columns (a natural key, or the columns of a unique
+// constraint) : it then returns an *AlreadyExistsError giving the
+// conflicting key and the existing row, matching ErrAlreadyExists with
+// errors.Is.
+//
+// The existing rows are detected by the unique constraint, not by a prior
+// lookup, so the concurrent inserts of the same row are handled : with an
+// upsert doing nothing and a check of the affected rows if the adapter
+// supports it (see adapters.ConflictIgnorer), otherwise with the constraint
+// error. A conflict on another unique constraint returns an error too, but
+// not an *AlreadyExistsError.
+//
+// Example :
+// 	user := User{Email: "jdoe@example.com", Name: "John Doe"}
+// 	err := db.InsertUnique(&user, "email")
+// 	if errors.Is(err, godb.ErrAlreadyExists) {
+// 		...
+// 	}
+func (db *DB) InsertUnique(record interface{}, columns ...string) error {
+	recordDescription, err := buildRecordDescription(record)
+	if err != nil {
+		return err
+	}
+	if recordDescription.isSlice {
+		return fmt.Errorf("InsertUnique accept only a single instance, got a slice")
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("InsertUnique needs the columns of the unique key")
+	}
+	pointers, err := recordDescription.structMapping.GetPointersForColumns(record, columns...)
+	if err != nil {
+		return err
+	}
+
+	created, err := db.createIfAbsent(record)
+	if err != nil || created {
+		return err
+	}
+	existing, err := db.findConflicting(record, columns)
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, 0, len(pointers))
+	for _, pointer := range pointers {
+		values = append(values, reflect.ValueOf(pointer).Elem().Interface())
+	}
+	return &AlreadyExistsError{
+		Struct:   recordDescription.structMapping.Name,
+		Columns:  columns,
+		Values:   values,
+		Existing: existing,
+	}
+}
+
+// findBy returns a new struct pointer filled with the row having the same
+// values as the given struct pointer for the given columns, or nil if there
+// is no such row.
+func (db *DB) findBy(record interface{}, columns []string) (interface{}, error) {
+	recordDescription, err := buildRecordDescription(record)
+	if err != nil {
+		return nil, err
+	}
+	if recordDescription.isSlice {
+		return nil, fmt.Errorf("FindOrCreate and UpdateOrCreate accept only a single instance, got a slice")
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("FindOrCreate and UpdateOrCreate need the columns identifying the row")
+	}
+	pointers, err := recordDescription.structMapping.GetPointersForColumns(record, columns...)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := reflect.New(reflect.TypeOf(record).Elem()).Interface()
+	ss := db.Select(existing)
+	for i, column := range columns {
+		quotedColumn := db.quoteFor(recordDescription, column)
+		ss.WhereQ(Q(quotedColumn+" = ?", reflect.ValueOf(pointers[i]).Elem().Interface()))
+	}
+	err = ss.Do()
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// findConflicting returns the row inserted concurrently, conflicting with
+// the given struct pointer.
+func (db *DB) findConflicting(record interface{}, columns []string) (interface{}, error) {
+	existing, err := db.findBy(record, columns)
+	if err == nil && existing == nil {
+		err = fmt.Errorf("the record conflicts with a row having others values for the columns %v", columns)
+	}
+	return existing, err
+}
+
+// createIfAbsent inserts the given struct pointer, and returns false if a
+// row conflicting with a unique constraint already exists. The constraint
+// error is checked even with an upsert, some unique constraints aren't
+// handled by the upserts (like a SQLite index on an expression).
+func (db *DB) createIfAbsent(record interface{}) (bool, error) {
+	si := db.Insert(record)
+	_, si.ignoreConflicts = db.adapter.(adapters.ConflictIgnorer)
+	err := si.Do()
+	if err == nil {
+		return !si.conflicted, nil
+	}
+	if _, ok := err.(dberror.UniqueConstraint); ok {
+		return false, nil
+	}
+	if _, ok := db.adapter.ParseError(err).(dberror.UniqueConstraint); ok {
+		return false, nil
+	}
+	return false, err
+}
@@ -0,0 +1,141 @@
+package godb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type NaturalKeyTag struct {
+	ID      int    `db:"id,key,auto"`
+	Name    string `db:"name"`
+	Color   string `db:"color"`
+	Version int    `db:"version,oplock"`
+}
+
+func (*NaturalKeyTag) TableName() string {
+	return "tags"
+}
+
+func TestFindOrCreate(t *testing.T) {
+	for _, adapter := range []adapters.Adapter{sqlite.Adapter, levelByLevelAdapter{sqlite.Adapter}} {
+		Convey("Given a test database with a unique constraint", t, func() {
+			db, err := Open(adapter, ":memory:")
+			So(err, ShouldBeNil)
+			defer db.Close()
+			_, err = db.CurrentDB().Exec(`
+				create table tags (
+					id integer not null primary key autoincrement,
+					name text not null,
+					color text not null,
+					version integer not null default 0);
+				create unique index tags_name on tags (lower(name));
+				insert into tags (name, color) values ("go", "blue");`)
+			So(err, ShouldBeNil)
+
+			Convey("FindOrCreate fills the struct with the existing row", func() {
+				tag := &NaturalKeyTag{Name: "go", Color: "red"}
+				created, err := db.FindOrCreate(tag, "name")
+				So(err, ShouldBeNil)
+				So(created, ShouldBeFalse)
+				So(tag.ID, ShouldEqual, 1)
+				So(tag.Color, ShouldEqual, "blue")
+			})
+
+			Convey("FindOrCreate inserts the struct if there is no row", func() {
+				tag := &NaturalKeyTag{Name: "sql", Color: "red"}
+				created, err := db.FindOrCreate(tag, "name")
+				So(err, ShouldBeNil)
+				So(created, ShouldBeTrue)
+				So(tag.ID, ShouldEqual, 2)
+			})
+
+			Convey("FindOrCreate fails if the row conflicts with another one", func() {
+				_, err := db.FindOrCreate(&NaturalKeyTag{Name: "Go", Color: "red"}, "name")
+				So(err, ShouldNotBeNil)
+				count, err := db.SelectFrom("tags").Count()
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 1)
+			})
+
+			Convey("UpdateOrCreate updates the existing row", func() {
+				tag := &NaturalKeyTag{Name: "go", Color: "red"}
+				created, err := db.UpdateOrCreate(tag, "name")
+				So(err, ShouldBeNil)
+				So(created, ShouldBeFalse)
+				So(tag.ID, ShouldEqual, 1)
+				So(tag.Version, ShouldEqual, 1)
+
+				updated := &NaturalKeyTag{}
+				So(db.Get(updated, 1), ShouldBeNil)
+				So(updated.Color, ShouldEqual, "red")
+			})
+
+			Convey("UpdateOrCreate inserts the struct if there is no row", func() {
+				tag := &NaturalKeyTag{Name: "sql", Color: "red"}
+				created, err := db.UpdateOrCreate(tag, "name")
+				So(err, ShouldBeNil)
+				So(created, ShouldBeTrue)
+				So(tag.ID, ShouldEqual, 2)
+			})
+
+			Convey("The columns are mandatory", func() {
+				_, err := db.FindOrCreate(&NaturalKeyTag{Name: "go"})
+				So(err, ShouldNotBeNil)
+				_, err = db.UpdateOrCreate(&[]NaturalKeyTag{}, "name")
+				So(err, ShouldNotBeNil)
+			})
+		})
+	}
+}
+
+func TestInsertUnique(t *testing.T) {
+	for _, adapter := range []adapters.Adapter{sqlite.Adapter, levelByLevelAdapter{sqlite.Adapter}} {
+		Convey("Given a test database with a unique constraint", t, func() {
+			db, err := Open(adapter, ":memory:")
+			So(err, ShouldBeNil)
+			defer db.Close()
+			_, err = db.CurrentDB().Exec(`
+				create table tags (
+					id integer not null primary key autoincrement,
+					name text not null,
+					color text not null,
+					version integer not null default 0);
+				create unique index tags_name on tags (lower(name));
+				insert into tags (name, color) values ("go", "blue");`)
+			So(err, ShouldBeNil)
+
+			Convey("InsertUnique inserts the struct if there is no row", func() {
+				tag := &NaturalKeyTag{Name: "sql", Color: "red"}
+				So(db.InsertUnique(tag, "name"), ShouldBeNil)
+				So(tag.ID, ShouldEqual, 2)
+			})
+
+			Convey("InsertUnique returns an *AlreadyExistsError for an existing row", func() {
+				err := db.InsertUnique(&NaturalKeyTag{Name: "go", Color: "red"}, "name")
+				So(errors.Is(err, ErrAlreadyExists), ShouldBeTrue)
+				alreadyExists, ok := err.(*AlreadyExistsError)
+				So(ok, ShouldBeTrue)
+				So(alreadyExists.Columns, ShouldResemble, []string{"name"})
+				So(alreadyExists.Values, ShouldResemble, []interface{}{"go"})
+				So(alreadyExists.Existing.(*NaturalKeyTag).ID, ShouldEqual, 1)
+				So(alreadyExists.Existing.(*NaturalKeyTag).Color, ShouldEqual, "blue")
+			})
+
+			Convey("InsertUnique fails if the row conflicts with another one", func() {
+				err := db.InsertUnique(&NaturalKeyTag{Name: "Go", Color: "red"}, "name")
+				So(err, ShouldNotBeNil)
+				So(errors.Is(err, ErrAlreadyExists), ShouldBeFalse)
+			})
+
+			Convey("The columns are mandatory", func() {
+				So(db.InsertUnique(&NaturalKeyTag{Name: "go"}), ShouldNotBeNil)
+				So(db.InsertUnique(&NaturalKeyTag{Name: "go"}, "unknown"), ShouldNotBeNil)
+			})
+		})
+	}
+}
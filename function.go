@@ -0,0 +1,116 @@
+package godb
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// functions are the functions rendered by godb rather than by the adapters,
+// by name (see RegisterFunction).
+var functions = struct {
+	sync.RWMutex
+	byName map[string]func(adapter adapters.Adapter, args []string) (string, error)
+}{byName: map[string]func(adapter adapters.Adapter, args []string) (string, error){
+	"IFNULL": renderCoalesce,
+}}
+
+// Function is a call of a SQL function rendered according to the adapter,
+// see Fn.
+type Function struct {
+	name string
+	args []interface{}
+}
+
+// Fn returns a call of the named SQL function, usable as an expression in
+// ColumnsExpr, GroupByExpr, OrderByExpr, as a value of UpdateStatement.Set,
+// and in conditions with ColExpr. The arguments are expressions (Ident, Raw,
+// others calls, ...), or values given as arguments of the statement.
+//
+// The call is rendered, by order of priority, by the function registered
+// with RegisterFunction, by the adapter (see adapters.FunctionBuilder), or
+// as is : NAME(arg1, arg2, ...). It makes the common functions portable :
+// 	* NOW : the current date and time (CURRENT_TIMESTAMP with SQLite,
+// 	  SYSDATETIME() with SQL Server)
+// 	* CONCAT : the concatenation of strings (|| with SQLite)
+// 	* IFNULL and COALESCE : the first not null argument (COALESCE)
+// 	* LENGTH : the number of characters of a string (CHAR_LENGTH with MySQL,
+// 	  LEN with SQL Server)
+//
+// Example :
+// 	count, err := db.UpdateTable("users").
+// 		Set("updated_at", godb.Fn("NOW")).
+// 		Set("label", godb.Fn("CONCAT", godb.Ident("first_name"), " ", godb.Ident("last_name"))).
+// 		Do()
+func Fn(name string, args ...interface{}) *Function {
+	return &Function{name: name, args: args}
+}
+
+// BuildSQL renders the call for the given adapter, with the arguments of the
+// values.
+func (f *Function) BuildSQL(adapter adapters.Adapter) (string, []interface{}, error) {
+	if err := checkIdentifier(f.name); err != nil {
+		return "", nil, err
+	}
+
+	argsSQL := make([]string, 0, len(f.args))
+	var args []interface{}
+	for _, arg := range f.args {
+		expression, ok := arg.(Expression)
+		if !ok {
+			argsSQL = append(argsSQL, Placeholder)
+			args = append(args, arg)
+			continue
+		}
+		if isNullArgument(expression) {
+			return "", nil, fmt.Errorf("nil expression")
+		}
+		sql, expressionArgs, err := expression.BuildSQL(adapter)
+		if err != nil {
+			return "", nil, err
+		}
+		argsSQL = append(argsSQL, sql)
+		args = append(args, expressionArgs...)
+	}
+
+	name := strings.ToUpper(f.name)
+	functions.RLock()
+	render, ok := functions.byName[name]
+	functions.RUnlock()
+	if ok {
+		sql, err := render(adapter, argsSQL)
+		return sql, args, err
+	}
+	if builder, ok := adapter.(adapters.FunctionBuilder); ok {
+		if sql, ok := builder.BuildFunction(name, argsSQL); ok {
+			return sql, args, nil
+		}
+	}
+	return f.name + "(" + strings.Join(argsSQL, ", ") + ")", args, nil
+}
+
+// RegisterFunction registers the rendering of the named function (case
+// insensitive) called with Fn, replacing the rendering of the adapter and
+// any function of the same name. The render function gets the SQL of the
+// arguments, the placeholders of the values included, and has to keep their
+// order. It has to be safe for concurrent use.
+//
+// Example :
+// 	godb.RegisterFunction("RANDOM", func(adapter adapters.Adapter, args []string) (string, error) {
+// 		if adapter.DriverName() == "mysql" {
+// 			return "RAND()", nil
+// 		}
+// 		return "RANDOM()", nil
+// 	})
+func RegisterFunction(name string, render func(adapter adapters.Adapter, args []string) (string, error)) {
+	functions.Lock()
+	defer functions.Unlock()
+	functions.byName[strings.ToUpper(name)] = render
+}
+
+// renderCoalesce renders the call of COALESCE with the given arguments.
+func renderCoalesce(adapter adapters.Adapter, args []string) (string, error) {
+	return "COALESCE(" + strings.Join(args, ", ") + ")", nil
+}
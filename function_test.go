@@ -0,0 +1,114 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/adapters/mssql"
+	"github.com/samonzeweb/godb/adapters/mysql"
+	"github.com/samonzeweb/godb/adapters/postgresql"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFn(t *testing.T) {
+	Convey("Given function calls", t, func() {
+		render := func(adapter adapters.Adapter, f *Function) string {
+			sql, _, err := f.BuildSQL(adapter)
+			So(err, ShouldBeNil)
+			return sql
+		}
+
+		Convey("The common functions are rendered according to the adapter", func() {
+			now := Fn("now")
+			So(render(postgresql.Adapter, now), ShouldEqual, "now()")
+			So(render(mysql.Adapter, now), ShouldEqual, "now()")
+			So(render(sqlite.Adapter, now), ShouldEqual, "CURRENT_TIMESTAMP")
+			So(render(mssql.Adapter, now), ShouldEqual, "SYSDATETIME()")
+
+			concat := Fn("CONCAT", Ident("a"), Raw("b"))
+			So(render(postgresql.Adapter, concat), ShouldEqual, `CONCAT("a", b)`)
+			So(render(sqlite.Adapter, concat), ShouldEqual, `("a" || b)`)
+
+			length := Fn("LENGTH", Raw("name"))
+			So(render(mysql.Adapter, length), ShouldEqual, "CHAR_LENGTH(name)")
+			So(render(mssql.Adapter, length), ShouldEqual, "LEN(name)")
+			So(render(sqlite.Adapter, length), ShouldEqual, "LENGTH(name)")
+
+			So(render(mssql.Adapter, Fn("IFNULL", Raw("a"), Raw("b"))), ShouldEqual, "COALESCE(a, b)")
+		})
+
+		Convey("The values are given as arguments, nested calls included", func() {
+			sql, args, err := Fn("COALESCE", Raw("nickname"), Fn("CONCAT", Ident("first_name"), " ", 42)).BuildSQL(sqlite.Adapter)
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `COALESCE(nickname, ("first_name" || ? || ?))`)
+			So(args, ShouldResemble, []interface{}{" ", 42})
+		})
+
+		Convey("The registered functions replace the rendering of the adapter", func() {
+			RegisterFunction("godb_test_random", func(adapter adapters.Adapter, args []string) (string, error) {
+				if adapter.DriverName() == "mysql" {
+					return "RAND()", nil
+				}
+				return "RANDOM()", nil
+			})
+			So(render(mysql.Adapter, Fn("GODB_TEST_RANDOM")), ShouldEqual, "RAND()")
+			So(render(sqlite.Adapter, Fn("godb_test_random")), ShouldEqual, "RANDOM()")
+		})
+
+		Convey("The invalid calls are rejected", func() {
+			_, _, err := Fn("now()").BuildSQL(sqlite.Adapter)
+			So(err, ShouldNotBeNil)
+			var nilFunction *Function
+			_, _, err = Fn("UPPER", nilFunction).BuildSQL(sqlite.Adapter)
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given statements using function calls", t, func() {
+		db := &DB{adapter: mssql.Adapter}
+
+		Convey("The calls are written in the SET clauses", func() {
+			sql, args, err := db.UpdateTable("users").
+				Set("updated_at", Fn("NOW")).
+				Set("label", Fn("UPPER", "x")).
+				Where("id = ?", 1).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "UPDATE users SET updated_at=SYSDATETIME(), label=UPPER(?) WHERE id = ?")
+			So(args, ShouldResemble, []interface{}{"x", 1})
+		})
+
+		Convey("The calls are usable in conditions", func() {
+			sql, args, err := db.SelectFrom("users").
+				ColumnsExpr(Fn("LENGTH", Ident("name"))).
+				WhereQ(db.ColExpr(Fn("LENGTH", Fn("CONCAT", Ident("name"), "!"))).Between(2, 5)).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT LEN([name]) FROM users WHERE LEN(CONCAT([name], ?)) BETWEEN ? AND ?")
+			So(args, ShouldResemble, []interface{}{"!", 2, 5})
+		})
+	})
+
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("The calls are executed", func() {
+			count, err := db.UpdateTable("dummies").
+				Set("a_text", Fn("CONCAT", Ident("a_text"), "!")).
+				WhereQ(db.ColExpr(Fn("LENGTH", Ident("a_text"))).Eq(5)).
+				Do()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+
+			texts := make([]struct {
+				Text string `db:"a_text"`
+			}, 0)
+			err = db.SelectFrom("dummies").Columns("a_text").OrderBy("id").Do(&texts)
+			So(err, ShouldBeNil)
+			So(texts[0].Text, ShouldEqual, "First!")
+			So(texts[2].Text, ShouldEqual, "Third!")
+		})
+	})
+}
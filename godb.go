@@ -2,6 +2,7 @@ package godb
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"strings"
 	"time"
@@ -29,6 +30,59 @@ type DB struct {
 	// Optional error parsing by adapters (false by default = legacy mode)
 	// Will probably be the default behavior in new major release.
 	useErrorParser bool
+	// Optional typed error when a single record is not found (false by
+	// default = sql.ErrNoRows is returned).
+	useNotFoundError bool
+	// Context of the current work, carrying the actor (see WithActor)
+	ctx context.Context
+	// Optional audit log table (see UseAuditLog)
+	auditLogTable string
+	// Dry run mode, the statements are not executed (see DryRun)
+	dryRun        bool
+	dryRunHandler DryRunHandler
+	// Read only mode, the statements changing data are rejected
+	readOnly bool
+	// Default maximum rows of a select result (0 = no limit)
+	maxRows int
+	// strictScan checks that the columns of the results match the structs
+	strictScan bool
+	// Configuration of the tolerant scan mode (nil = disabled)
+	tolerantScan *TolerantScanConfig
+	// Guards checking the statements before their execution
+	guards []Guard
+	// Optional limit of concurrent executions, shared by the clones
+	limiter *limiter
+	// Optional circuit breaker, shared by the clones
+	breaker *circuitBreaker
+	// Data sources of OpenWithFailover, shared by the clones
+	failover *failoverConnector
+	// Read replicas, shared by the clones
+	replicas *replicaSet
+	// Data source given to Open, used by SetSessionSetup
+	dataSourceName string
+	// Connector given to OpenConnector or OpenWithDataSourceProvider, used by
+	// SetSessionSetup
+	connector driver.Connector
+	// Optional identity map (see UseIdentityMap), not shared by the clones
+	identityMap *identityMap
+	// Relations loaded by LoadRelation in the current transaction
+	relationCache map[string]interface{}
+	// Count of the savepoints of the current transaction (see WithSavepoint)
+	savepoints int
+	// Temporary tables created in the current transaction
+	tempTables []string
+	// Optional generator of the keys (see UseIDGenerator), shared by the clones
+	idGenerator IDGenerator
+	// Optional validator of the structs (see UseValidator), shared by the clones
+	validator Validator
+	// True if the masked fields are masked at scan time (see UseMasking)
+	masking bool
+	// Optional deduplication of the identical reads (see UseSingleflight),
+	// shared by the clones
+	singleflight *flightGroup
+	// SQL bundle recording the statements in dry run mode (see
+	// RecordSQLBundle)
+	sqlBundle *SQLBundle
 }
 
 // Placeholder is the placeholder string, use it to build queries.
@@ -44,7 +98,25 @@ func Open(adapter adapters.Adapter, dataSourceName string) (*DB, error) {
 	if err != nil {
 		return nil, err
 	}
-	return initialize(adapter, dbInst), nil
+	db := initialize(adapter, dbInst)
+	db.dataSourceName = dataSourceName
+	return db, nil
+}
+
+// OpenByName creates a new DB struct using the adapter registered with the
+// given name (see adapters.Register), and initialise a sql.DB connection. The
+// package of the adapter still has to be imported, it registers the adapter.
+//
+// Example :
+// 	import _ "github.com/samonzeweb/godb/adapters/postgresql"
+//
+// 	db, err := godb.OpenByName(config.Adapter, config.DSN)
+func OpenByName(adapterName string, dataSourceName string) (*DB, error) {
+	adapter, err := adapters.Lookup(adapterName)
+	if err != nil {
+		return nil, err
+	}
+	return Open(adapter, dataSourceName)
 }
 
 // Wrap creates a godb.DB by using provided and initialized sql.DB Helpful for
@@ -86,6 +158,30 @@ func (db *DB) Clone() *DB {
 		stmtCacheDB:       newStmtCache(),
 		stmtCacheTx:       newStmtCache(),
 		useErrorParser:    db.useErrorParser,
+		useNotFoundError:  db.useNotFoundError,
+		ctx:               db.ctx,
+		auditLogTable:     db.auditLogTable,
+		dryRun:            db.dryRun,
+		dryRunHandler:     db.dryRunHandler,
+		readOnly:          db.readOnly,
+		maxRows:           db.maxRows,
+		strictScan:        db.strictScan,
+		tolerantScan:      db.tolerantScan,
+		guards:            append([]Guard(nil), db.guards...),
+		limiter:           db.limiter,
+		breaker:           db.breaker,
+		failover:          db.failover,
+		replicas:          db.replicas,
+		dataSourceName:    db.dataSourceName,
+		connector:         db.connector,
+		idGenerator:       db.idGenerator,
+		validator:         db.validator,
+		masking:           db.masking,
+		singleflight:      db.singleflight,
+		sqlBundle:         db.sqlBundle,
+	}
+	if db.identityMap != nil {
+		clone.identityMap = newIdentityMap()
 	}
 
 	clone.stmtCacheDB.SetSize(db.stmtCacheDB.GetSize())
@@ -123,6 +219,12 @@ func (db *DB) Clear() error {
 func (db *DB) Close() error {
 	db.logPrintln("CLOSE DB")
 	db.Clear()
+	if db.replicas != nil {
+		if err := db.replicas.close(); err != nil {
+			db.sqlDB.Close()
+			return err
+		}
+	}
 	return db.sqlDB.Close()
 }
 
@@ -161,13 +263,77 @@ func timeElapsedSince(startTime time.Time) time.Duration {
 
 // quote quotes all part of the given string using the current adapter.
 func (db *DB) quote(identifier string) string {
-	parts := strings.Split(identifier, ".")
-	for i := range parts {
-		parts[i] = db.adapter.Quote(parts[i])
+	return quoteIdentifier(db.adapter, identifier)
+}
+
+// quoteFor quotes the given identifier for the record, using its
+// QuoteIdentifier method if it has one (see identifierQuoter).
+func (db *DB) quoteFor(recordDescription *recordDescription, identifier string) string {
+	if quoter, ok := recordDescription.getOneInstancePointer().(identifierQuoter); ok {
+		if quoted, ok := quoter.QuoteIdentifier(identifier); ok {
+			return quoted
+		}
+	}
+	return db.quote(identifier)
+}
+
+// quoteAllFor quotes all the given identifiers for the record, see quoteFor.
+func (db *DB) quoteAllFor(recordDescription *recordDescription, identifiers []string) []string {
+	quotedIdentifiers := make([]string, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		quotedIdentifiers = append(quotedIdentifiers, db.quoteFor(recordDescription, identifier))
+	}
+	return quotedIdentifiers
+}
+
+// quoteIdentifier quotes all part of the given string using the given
+// adapter. The parts already quoted and * are kept as is.
+func quoteIdentifier(adapter adapters.Adapter, identifier string) string {
+	parts := splitIdentifier(identifier)
+	for i, part := range parts {
+		if part != "*" && !isQuoted(part) {
+			parts[i] = adapter.Quote(part)
+		}
 	}
 	return strings.Join(parts, ".")
 }
 
+// identifierQuotes gives the closing quote of each opening quote used by the
+// adapters.
+var identifierQuotes = map[byte]byte{'"': '"', '`': '`', '[': ']'}
+
+// splitIdentifier splits the given identifier on dots, except the dots
+// inside quoted parts.
+func splitIdentifier(identifier string) []string {
+	parts := make([]string, 0, 2)
+	start := 0
+	var closingQuote byte
+	for i := 0; i < len(identifier); i++ {
+		c := identifier[i]
+		switch {
+		case closingQuote != 0:
+			if c == closingQuote {
+				closingQuote = 0
+			}
+		case c == '.':
+			parts = append(parts, identifier[start:i])
+			start = i + 1
+		default:
+			closingQuote = identifierQuotes[c]
+		}
+	}
+	return append(parts, identifier[start:])
+}
+
+// isQuoted returns true if the given identifier part is already quoted.
+func isQuoted(part string) bool {
+	if len(part) < 2 {
+		return false
+	}
+	closingQuote, ok := identifierQuotes[part[0]]
+	return ok && part[len(part)-1] == closingQuote
+}
+
 // quoteAll returns all strings given quoted by the adapter.
 func (db *DB) quoteAll(identifiers []string) []string {
 	quotedIdentifiers := make([]string, 0, len(identifiers))
@@ -199,6 +365,97 @@ func (db *DB) UseErrorParser() {
 	db.useErrorParser = true
 }
 
+// UseNotFoundError will return a *NotFoundError instead of sql.ErrNoRows when
+// a single record is requested but not found. The NotFoundError still
+// matches sql.ErrNoRows with errors.Is, but no longer with the == operator.
+func (db *DB) UseNotFoundError() {
+	db.useNotFoundError = true
+}
+
+// SetReadOnly enables or disables the read only mode. In read only mode the
+// statements changing data (Insert, Update, Delete and RawSQL DoExec) are
+// rejected with a *ReadOnlyError, without being executed. Use it with
+// replicas, or for a maintenance mode. The read only adapters (see
+// adapters.ReadOnlyChecker) are always in read only mode.
+func (db *DB) SetReadOnly(readOnly bool) {
+	db.readOnly = readOnly
+}
+
+// IsReadOnly returns true if the read only mode is enabled, or if the adapter
+// is read only.
+func (db *DB) IsReadOnly() bool {
+	if checker, ok := db.adapter.(adapters.ReadOnlyChecker); ok && checker.IsReadOnly() {
+		return true
+	}
+	return db.readOnly
+}
+
+// checkWritable returns a *ReadOnlyError for the given statement if the read
+// only mode is enabled.
+func (db *DB) checkWritable(query string) error {
+	if db.IsReadOnly() {
+		err := &ReadOnlyError{Query: query}
+		db.logExecutionErr(err, query)
+		return err
+	}
+	return nil
+}
+
+// SetStrictScan enables or disables the strict scan mode of the DB, shared
+// by the clones created after the call. In strict mode, scanning a result
+// into structs returns an *UnmappedColumnsError when a column of the result
+// has no field, or when a field of the struct has no column (a column
+// removed from the query or the schema would leave it silently to its zero
+// value). The columns are checked with the first row. The statements can
+// override it with their StrictScan method.
+func (db *DB) SetStrictScan(strict bool) {
+	db.strictScan = strict
+}
+
+// SetTolerantScan enables the tolerant scan mode of the DB, shared by the
+// clones created after the call. A nil configuration disables it. In tolerant
+// mode, the values whose type doesn't match the field of the struct are
+// converted instead of failing the scan. The drivers give surprising types,
+// MySQL ones in particular :
+// 	* []byte to string, numbers (the DECIMAL columns), booleans or times
+// 	* integers to booleans (zero is false)
+// 	* strings to times, with the layouts of the configuration
+// 	* numbers to strings
+// The fields implementing sql.Scanner (like types.Decimal) get the values
+// unchanged. A NULL value sets the field to its zero value. The values
+// scanned with Scanx aren't converted.
+//
+// Example :
+// 	db.SetTolerantScan(&godb.TolerantScanConfig{
+// 		TimeLayouts: []string{"2006-01-02 15:04:05", "02/01/2006"},
+// 		Location:    time.Local,
+// 	})
+func (db *DB) SetTolerantScan(config *TolerantScanConfig) {
+	db.tolerantScan = config
+}
+
+// SetMaxRows sets the default maximum count of rows scanned by select
+// statements and raw queries, 0 meaning no limit (the default). When a result
+// exceeds the limit, the scanning is aborted with a *TooManyRowsError.
+// The statements could override it with their MaxRows method.
+func (db *DB) SetMaxRows(maxRows int) {
+	db.maxRows = maxRows
+}
+
+// SetContext sets the context of the current work, the clones will share it.
+// The context carries the actor filling auditor fields (see WithActor).
+func (db *DB) SetContext(ctx context.Context) {
+	db.ctx = ctx
+}
+
+// Context returns the context of the current work, or context.Background()
+// if none was set.
+func (db *DB) Context() context.Context {
+	if db.ctx == nil {
+		return context.Background()
+	}
+	return db.ctx
+}
 
 // Tambahan FZL
 // Ping verifies a connection to the database is still alive,
@@ -3,6 +3,7 @@ package godb
 import (
 	"testing"
 
+	"github.com/samonzeweb/godb/adapters/sqlite"
 	"github.com/samonzeweb/godb/tablenamer"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -30,6 +31,19 @@ func TestClone(t *testing.T) {
 	})
 }
 
+func TestOpenByName(t *testing.T) {
+	Convey("OpenByName uses the adapter registered with the given name", t, func() {
+		db, err := OpenByName("sqlite", ":memory:")
+		So(err, ShouldBeNil)
+		defer db.Close()
+		So(db.Adapter(), ShouldResemble, sqlite.Adapter)
+		So(db.CurrentDB().Ping(), ShouldBeNil)
+
+		_, err = OpenByName("unknown", ":memory:")
+		So(err, ShouldNotBeNil)
+	})
+}
+
 func TestTableNamer(t *testing.T) {
 	db := createInMemoryConnection(t)
 	defer db.Close()
@@ -140,5 +154,43 @@ func TestQuote(t *testing.T) {
 
 			So(quotedIdentifier, ShouldEqual, expectedQuotedIdentified)
 		})
+
+		Convey("quote keeps the parts already quoted and *", func() {
+			So(db.quote(`"foo".bar`), ShouldEqual, `"foo"."bar"`)
+			So(db.quote(`foo."Bar.Baz"`), ShouldEqual, `"foo"."Bar.Baz"`)
+			So(db.quote("[foo].`bar`"), ShouldEqual, "[foo].`bar`")
+			So(db.quote("foo.*"), ShouldEqual, `"foo".*`)
+			So(db.quote("*"), ShouldEqual, "*")
+		})
+	})
+}
+
+type DummyWithQuoter struct {
+	ID    int    `db:"id,key,auto"`
+	Order string `db:"order"`
+}
+
+func (*DummyWithQuoter) TableName() string {
+	return "LegacyTable"
+}
+
+func (*DummyWithQuoter) QuoteIdentifier(identifier string) (string, bool) {
+	if identifier == "LegacyTable" {
+		return `"LEGACY"."LegacyTable"`, true
+	}
+	return "", false
+}
+
+func TestQuoteIdentifierOverride(t *testing.T) {
+	Convey("Given a DB and a struct with a QuoteIdentifier method", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+		db.defaultTableNamer = func(name string, done bool) string { return name }
+
+		Convey("The struct tools use it, with the adapter as fallback", func() {
+			description, err := db.DescribeMapping(&DummyWithQuoter{})
+			So(err, ShouldBeNil)
+			So(description.SelectSQL, ShouldEqual, `SELECT "id", "order" FROM "LEGACY"."LegacyTable"`)
+			So(description.InsertSQL, ShouldEqual, `INSERT INTO "LEGACY"."LegacyTable" ("order") VALUES (?)`)
+		})
 	})
 }
@@ -0,0 +1,131 @@
+package godb
+
+import "strings"
+
+// StatementInfo describes a statement built with a statement builder, given
+// to the guards before its execution.
+type StatementInfo struct {
+	// Kind is SELECT, INSERT, UPDATE or DELETE.
+	Kind string
+	// Tables contains the names of the tables used by the statement, without
+	// quotes nor aliases.
+	Tables []string
+	// Columns contains the selected columns of a SELECT, or the columns of an
+	// INSERT.
+	Columns []string
+	// HasWhere is true if the statement has at least one WHERE condition.
+	HasWhere  bool
+	Query     string
+	Arguments []interface{}
+}
+
+// Guard checks a statement before its execution. Returning an error rejects
+// the statement, which is not executed.
+type Guard func(statement *StatementInfo) error
+
+// AddGuard adds a guard checking the statements built with the statement
+// builders (and then the struct tools) before their execution, even in dry
+// run mode. Use it to catch dangerous mistakes at the library level, see
+// DenyDeleteWithoutWhere, DenyUpdateWithoutWhere and DenySelectStar.
+// Raw queries are not checked.
+//
+// Example :
+// 	db.AddGuard(godb.DenyDeleteWithoutWhere())
+// 	db.AddGuard(godb.DenySelectStar("accounts"))
+func (db *DB) AddGuard(guard Guard) {
+	db.guards = append(db.guards, guard)
+}
+
+// ClearGuards removes all the guards.
+func (db *DB) ClearGuards() {
+	db.guards = nil
+}
+
+// DenyDeleteWithoutWhere returns a guard rejecting the DELETE statements
+// without WHERE clause.
+func DenyDeleteWithoutWhere() Guard {
+	return func(statement *StatementInfo) error {
+		if statement.Kind == "DELETE" && !statement.HasWhere {
+			return &StatementDeniedError{Reason: "DELETE without WHERE", Query: statement.Query}
+		}
+		return nil
+	}
+}
+
+// DenyUpdateWithoutWhere returns a guard rejecting the UPDATE statements
+// without WHERE clause.
+func DenyUpdateWithoutWhere() Guard {
+	return func(statement *StatementInfo) error {
+		if statement.Kind == "UPDATE" && !statement.HasWhere {
+			return &StatementDeniedError{Reason: "UPDATE without WHERE", Query: statement.Query}
+		}
+		return nil
+	}
+}
+
+// DenySelectStar returns a guard rejecting the SELECT statements using * (or
+// table.*) on the given tables, or on any table if none is given. The table
+// names are compared without case.
+func DenySelectStar(tables ...string) Guard {
+	return func(statement *StatementInfo) error {
+		if statement.Kind != "SELECT" || !hasStarColumn(statement.Columns) {
+			return nil
+		}
+		if len(tables) == 0 {
+			return &StatementDeniedError{Reason: "SELECT *", Query: statement.Query}
+		}
+		for _, used := range statement.Tables {
+			for _, denied := range tables {
+				if strings.EqualFold(used, denied) {
+					return &StatementDeniedError{Reason: "SELECT * on " + used, Query: statement.Query}
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// hasStarColumn returns true if one of the columns is * or table.*.
+func hasStarColumn(columns []string) bool {
+	for _, column := range columns {
+		column = strings.TrimSpace(column)
+		if column == "*" || strings.HasSuffix(column, ".*") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkGuards runs the guards on the given statement, and returns the first
+// error.
+func (db *DB) checkGuards(statement *StatementInfo) error {
+	for _, guard := range db.guards {
+		if err := guard(statement); err != nil {
+			db.logExecutionErr(err, statement.Query, statement.Arguments)
+			return err
+		}
+	}
+	return nil
+}
+
+// guardedTableName returns the name of a table given to a statement builder,
+// without quotes nor alias.
+func guardedTableName(table string) string {
+	fields := strings.Fields(table)
+	if len(fields) == 0 {
+		return table
+	}
+	return identifierQuotesRemover.Replace(fields[0])
+}
+
+// identifierQuotesRemover removes the quotes of all adapters.
+var identifierQuotesRemover = strings.NewReplacer("\"", "", "`", "", "[", "", "]", "")
+
+// guardedTableNames applies guardedTableName to all the given tables.
+func guardedTableNames(tables ...string) []string {
+	names := make([]string, 0, len(tables))
+	for _, table := range tables {
+		names = append(names, guardedTableName(table))
+	}
+	return names
+}
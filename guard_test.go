@@ -0,0 +1,80 @@
+package godb
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGuards(t *testing.T) {
+	Convey("Given a test database with guards", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		db.AddGuard(DenyDeleteWithoutWhere())
+		db.AddGuard(DenyUpdateWithoutWhere())
+		db.AddGuard(DenySelectStar("Dummies"))
+
+		Convey("DELETE and UPDATE without WHERE are rejected", func() {
+			_, err := db.DeleteFrom("dummies").Do()
+			So(errors.Is(err, ErrStatementDenied), ShouldBeTrue)
+			deniedError := &StatementDeniedError{}
+			So(errors.As(err, &deniedError), ShouldBeTrue)
+			So(deniedError.Reason, ShouldEqual, "DELETE without WHERE")
+			So(deniedError.Query, ShouldEqual, "DELETE FROM dummies")
+
+			_, err = db.UpdateTable("dummies").Set("an_integer", 0).Do()
+			So(errors.Is(err, ErrStatementDenied), ShouldBeTrue)
+
+			count, err := db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("DELETE and UPDATE with WHERE are allowed", func() {
+			count, err := db.UpdateTable("dummies").Set("an_integer", 0).Where("a_text = ?", "First").Do()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+
+			count, err = db.DeleteFrom("dummies").Where("a_text = ?", "First").Do()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("SELECT * is rejected on the given tables only", func() {
+			var count int64
+			err := db.SelectFrom(db.quote("dummies")).Columns("*").Scanx(&count)
+			So(errors.Is(err, ErrStatementDenied), ShouldBeTrue)
+
+			_, err = db.SelectFrom("dummies d").Columns("d.*").DoWithIterator()
+			So(errors.Is(err, ErrStatementDenied), ShouldBeTrue)
+
+			iter, err := db.SelectFrom("relatedtodummies").Columns("*").DoWithIterator()
+			So(err, ShouldBeNil)
+			So(iter.Close(), ShouldBeNil)
+
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+		})
+
+		Convey("A custom guard receives the statement", func() {
+			var statement *StatementInfo
+			db.AddGuard(func(s *StatementInfo) error {
+				statement = s
+				return fmt.Errorf("rejected")
+			})
+			_, err := db.InsertInto("dummies").Columns("a_text").Values("Foo").Do()
+			So(err, ShouldNotBeNil)
+			So(statement.Kind, ShouldEqual, "INSERT")
+			So(statement.Tables, ShouldResemble, []string{"dummies"})
+			So(statement.Columns, ShouldResemble, []string{"a_text"})
+			So(statement.Arguments, ShouldResemble, []interface{}{"Foo"})
+
+			db.ClearGuards()
+			_, err = db.DeleteFrom("dummies").Do()
+			So(err, ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,225 @@
+// Package httpquery binds whitelisted HTTP query parameters onto godb
+// SELECT statements, for CRUD and admin endpoints.
+//
+// Example :
+// 	binder := httpquery.NewBinder().
+// 		Filter("status", "status").
+// 		Sort("created_at", "created_at").
+// 		Limits(20, 100)
+// 	// ?status=paid&sort=-created_at&limit=50
+// 	q := db.SelectFrom("invoices").Columns("id", "status", "created_at")
+// 	if err := binder.Bind(q, r.URL.Query()); err != nil {
+// 		// respond with 400 Bad Request
+// 	}
+// 	err = q.Do(&invoices)
+package httpquery
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/samonzeweb/godb"
+)
+
+// Reserved query parameters, they can't be used as filters.
+const (
+	SortParameter   = "sort"
+	LimitParameter  = "limit"
+	OffsetParameter = "offset"
+)
+
+// ParameterError is returned by Bind when a query parameter is invalid.
+type ParameterError struct {
+	Parameter string
+	Value     string
+	Reason    string
+}
+
+// Error returns the formatted error message.
+func (e *ParameterError) Error() string {
+	return fmt.Sprintf("invalid query parameter %s=%q : %s", e.Parameter, e.Value, e.Reason)
+}
+
+// Binder maps query parameters onto a SELECT statement. Only the declared
+// filters and sort fields are used, the others parameters are ignored, and
+// the parameters values are always given as placeholder arguments.
+type Binder struct {
+	// filters are kept in declaration order for a stable SQL
+	filterParameters []string
+	filters          map[string]string
+	sorts            map[string]string
+	defaultLimit     int
+	maxLimit         int
+	// err is the error of the declarations, returned by Bind
+	err error
+}
+
+// NewBinder creates a Binder without filter nor sort field, with a default
+// limit of 20 rows and a maximum of 100.
+func NewBinder() *Binder {
+	return &Binder{
+		filters:      map[string]string{},
+		sorts:        map[string]string{},
+		defaultLimit: 20,
+		maxLimit:     100,
+	}
+}
+
+// Filter allows the given parameter, filtering on the given column. Several
+// values of the parameter (?status=paid&status=sent) give an IN condition.
+// The reserved parameters (sort, limit and offset) can't be filters, Bind
+// then returns an error.
+func (b *Binder) Filter(parameter string, column string) *Binder {
+	switch parameter {
+	case SortParameter, LimitParameter, OffsetParameter:
+		if b.err == nil {
+			b.err = fmt.Errorf("the reserved parameter %s can't be a filter", parameter)
+		}
+		return b
+	}
+	if _, ok := b.filters[parameter]; !ok {
+		b.filterParameters = append(b.filterParameters, parameter)
+	}
+	b.filters[parameter] = column
+	return b
+}
+
+// Sort allows the given field in the sort parameter, sorting on the given
+// column. The sort parameter contains fields separated by commas, prefixed
+// with - for a descending order (?sort=-created_at,id).
+func (b *Binder) Sort(field string, column string) *Binder {
+	b.sorts[field] = column
+	return b
+}
+
+// Limits sets the limit used without limit parameter, and the maximum limit
+// allowed. A maximum of 0 removes the maximum.
+func (b *Binder) Limits(defaultLimit int, maxLimit int) *Binder {
+	b.defaultLimit = defaultLimit
+	b.maxLimit = maxLimit
+	return b
+}
+
+// query contains the clauses parsed from the query parameters.
+type query struct {
+	conditions []*godb.Condition
+	orderBy    []string
+	limit      int
+	offset     int
+}
+
+// statement receives the clauses of a query, it wraps a select statement or
+// a struct select.
+type statement struct {
+	whereQ  func(condition *godb.Condition)
+	orderBy func(orderBy string)
+	limit   func(limit int)
+	offset  func(offset int)
+}
+
+// Bind parses the query parameters and adds the filters, the order, the
+// limit and the offset to the statement. A *ParameterError is returned if a
+// parameter is invalid, the statement is then unchanged.
+func (b *Binder) Bind(ss *godb.SelectStatement, values url.Values) error {
+	return b.bind(values, statement{
+		whereQ:  func(condition *godb.Condition) { ss.WhereQ(condition) },
+		orderBy: func(orderBy string) { ss.OrderBy(orderBy) },
+		limit:   func(limit int) { ss.Limit(limit) },
+		offset:  func(offset int) { ss.Offset(offset) },
+	})
+}
+
+// BindStruct is like Bind, for the struct tools.
+func (b *Binder) BindStruct(ss *godb.StructSelect, values url.Values) error {
+	return b.bind(values, statement{
+		whereQ:  func(condition *godb.Condition) { ss.WhereQ(condition) },
+		orderBy: func(orderBy string) { ss.OrderBy(orderBy) },
+		limit:   func(limit int) { ss.Limit(limit) },
+		offset:  func(offset int) { ss.Offset(offset) },
+	})
+}
+
+// bind parses the query parameters, then adds the clauses to the statement.
+func (b *Binder) bind(values url.Values, s statement) error {
+	q, err := b.parse(values)
+	if err != nil {
+		return err
+	}
+	for _, condition := range q.conditions {
+		s.whereQ(condition)
+	}
+	for _, orderBy := range q.orderBy {
+		s.orderBy(orderBy)
+	}
+	if q.limit > 0 {
+		s.limit(q.limit)
+	}
+	if q.offset > 0 {
+		s.offset(q.offset)
+	}
+	return nil
+}
+
+// parse builds the clauses from the query parameters.
+func (b *Binder) parse(values url.Values) (*query, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	q := &query{limit: b.defaultLimit}
+
+	for _, parameter := range b.filterParameters {
+		column := b.filters[parameter]
+		parameterValues := values[parameter]
+		if len(parameterValues) == 0 {
+			continue
+		}
+		if len(parameterValues) == 1 {
+			q.conditions = append(q.conditions, godb.Col(column).Eq(parameterValues[0]))
+			continue
+		}
+		args := make([]interface{}, 0, len(parameterValues))
+		for _, value := range parameterValues {
+			args = append(args, value)
+		}
+		q.conditions = append(q.conditions, godb.Col(column).In(args...))
+	}
+
+	if sort := values.Get(SortParameter); sort != "" {
+		for _, field := range strings.Split(sort, ",") {
+			field = strings.TrimSpace(field)
+			direction := ""
+			if strings.HasPrefix(field, "-") {
+				field = field[1:]
+				direction = " DESC"
+			}
+			column, ok := b.sorts[field]
+			if !ok {
+				return nil, &ParameterError{Parameter: SortParameter, Value: sort, Reason: "unknown sort field " + field}
+			}
+			q.orderBy = append(q.orderBy, column+direction)
+		}
+	}
+
+	if limit := values.Get(LimitParameter); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return nil, &ParameterError{Parameter: LimitParameter, Value: limit, Reason: "a positive integer is expected"}
+		}
+		if b.maxLimit > 0 && n > b.maxLimit {
+			return nil, &ParameterError{Parameter: LimitParameter, Value: limit, Reason: fmt.Sprintf("the maximum is %d", b.maxLimit)}
+		}
+		q.limit = n
+	}
+
+	if offset := values.Get(OffsetParameter); offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil || n < 0 {
+			return nil, &ParameterError{Parameter: OffsetParameter, Value: offset, Reason: "a positive integer is expected"}
+		}
+		q.offset = n
+	}
+
+	return q, nil
+}
@@ -0,0 +1,63 @@
+package httpquery
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/samonzeweb/godb"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBind(t *testing.T) {
+	Convey("Given a binder", t, func() {
+		db, err := godb.Open(sqlite.Adapter, ":memory:")
+		So(err, ShouldBeNil)
+		defer db.Close()
+
+		binder := NewBinder().
+			Filter("status", "status").
+			Filter("customer", "customer_id").
+			Sort("created_at", "created_at").
+			Sort("id", "id").
+			Limits(20, 100)
+
+		bind := func(rawQuery string) (string, []interface{}, error) {
+			values, err := url.ParseQuery(rawQuery)
+			So(err, ShouldBeNil)
+			q := db.SelectFrom("invoices").Columns("id")
+			if err := binder.Bind(q, values); err != nil {
+				return "", nil, err
+			}
+			return q.ToSQL()
+		}
+
+		Convey("The declared parameters are bound", func() {
+			sql, args, err := bind("status=paid&customer=12&sort=-created_at,id&limit=50&offset=100&unknown=1")
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT id FROM invoices WHERE status = ? AND customer_id = ? ORDER BY created_at DESC, id LIMIT ? OFFSET ?")
+			So(args, ShouldResemble, []interface{}{"paid", "12", int64(50), int64(100)})
+		})
+
+		Convey("Several values of a filter give an IN condition", func() {
+			sql, args, err := bind("status=paid&status=sent")
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT id FROM invoices WHERE status IN (?,?) LIMIT ?")
+			So(args, ShouldResemble, []interface{}{"paid", "sent", int64(20)})
+		})
+
+		Convey("The invalid parameters are rejected", func() {
+			for _, rawQuery := range []string{"sort=password", "limit=0", "limit=abc", "limit=101", "offset=-1"} {
+				_, _, err := bind(rawQuery)
+				So(err, ShouldHaveSameTypeAs, &ParameterError{})
+			}
+		})
+
+		Convey("The reserved parameters can't be filters", func() {
+			binder.Filter("limit", "limit_column")
+			_, _, err := bind("limit=50")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,76 @@
+package godb
+
+import "reflect"
+
+// IDGenerator is the interface of the generators of keys, see
+// UseIDGenerator. The snowflake package gives a generator of time ordered
+// IDs.
+type IDGenerator interface {
+	NextID() (int64, error)
+}
+
+// UseIDGenerator sets the generator filling the keys on insert, or removes
+// it if nil. A key is filled if it's the single key of the struct, not an
+// auto column, with a zero value of type int, int64, uint or uint64. The
+// generator is shared by the clones.
+//
+// Example :
+// 	type Event struct {
+// 		ID   int64  `db:"id,key"`
+// 		Name string `db:"name"`
+// 	}
+//
+// 	generator, err := snowflake.NewGenerator(3, snowflake.DefaultNodeBits)
+// 	db.UseIDGenerator(generator)
+// 	err = db.Insert(&event).Do()
+func (db *DB) UseIDGenerator(generator IDGenerator) {
+	db.idGenerator = generator
+}
+
+// setGeneratedKeys fills the zero keys of all given records with the ID
+// generator, if any.
+func (db *DB) setGeneratedKeys(recordDescription *recordDescription) error {
+	if db.idGenerator == nil {
+		return nil
+	}
+	keyColumns := recordDescription.structMapping.GetKeyColumnsNames()
+	if len(keyColumns) != 1 {
+		return nil
+	}
+	for _, autoColumn := range recordDescription.structMapping.GetAutoColumnsNames() {
+		if autoColumn == keyColumns[0] {
+			return nil
+		}
+	}
+
+	for i := 0; i < recordDescription.len(); i++ {
+		pointers, err := recordDescription.structMapping.GetPointersForColumns(recordDescription.index(i), keyColumns[0])
+		if err != nil {
+			return err
+		}
+		key := reflect.ValueOf(pointers[0]).Elem()
+		switch key.Kind() {
+		case reflect.Int, reflect.Int64:
+			if key.Int() != 0 {
+				continue
+			}
+			id, err := db.idGenerator.NextID()
+			if err != nil {
+				return err
+			}
+			key.SetInt(id)
+		case reflect.Uint, reflect.Uint64:
+			if key.Uint() != 0 {
+				continue
+			}
+			id, err := db.idGenerator.NextID()
+			if err != nil {
+				return err
+			}
+			key.SetUint(uint64(id))
+		default:
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package godb
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type countingIDGenerator struct {
+	last int64
+	err  error
+}
+
+func (g *countingIDGenerator) NextID() (int64, error) {
+	g.last += 100
+	return g.last, g.err
+}
+
+type GeneratedEvent struct {
+	ID   int64  `db:"id,key"`
+	Name string `db:"name"`
+}
+
+func (*GeneratedEvent) TableName() string {
+	return "events"
+}
+
+func TestUseIDGenerator(t *testing.T) {
+	Convey("Given a test database with an ID generator", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		_, err := db.CurrentDB().Exec("create table events (id integer not null primary key, name text not null)")
+		So(err, ShouldBeNil)
+		generator := &countingIDGenerator{}
+		db.UseIDGenerator(generator)
+
+		Convey("The zero keys are filled on insert", func() {
+			event := &GeneratedEvent{Name: "First"}
+			So(db.Insert(event).Do(), ShouldBeNil)
+			So(event.ID, ShouldEqual, 100)
+
+			events := []GeneratedEvent{{Name: "Second"}, {ID: 42, Name: "Third"}}
+			So(db.BulkInsert(&events).Do(), ShouldBeNil)
+			So(events[0].ID, ShouldEqual, 200)
+			So(events[1].ID, ShouldEqual, 42)
+
+			count, err := db.SelectFrom("events").Where("id in (100, 200, 42)").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("The auto keys are not filled", func() {
+			dummy := &Dummy{AText: "Fourth"}
+			So(db.Insert(dummy).Do(), ShouldBeNil)
+			So(dummy.ID, ShouldEqual, 4)
+			So(generator.last, ShouldEqual, 0)
+		})
+
+		Convey("The errors of the generator are returned", func() {
+			generator.err = errors.New("no ID")
+			So(db.Insert(&GeneratedEvent{Name: "First"}).Do(), ShouldEqual, generator.err)
+		})
+	})
+}
@@ -0,0 +1,177 @@
+package godb
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// identityMap contains the instances loaded by a DB, by table and key
+// values. The values are pointers to structs.
+type identityMap struct {
+	instances map[string]reflect.Value
+}
+
+// UseIdentityMap enables or disables the identity map of the DB. Once
+// enabled, the records loaded by key (see Get and Load) are fetched only
+// once : the next loads return the same instance (Load) or a copy of it
+// (Get) without query. The records of a slice of pointers loaded by Select
+// are registered or replaced by the instances already loaded.
+//
+// The records changed with Insert, Update and Delete are forgotten, unless
+// the given instance is the one of the identity map. The changes done by
+// statements or others DB are not detected.
+//
+// The identity map is cleared when a transaction ends, it's not shared with
+// the clones (they get an empty one).
+//
+// Example :
+// 	db.UseIdentityMap(true)
+// 	var author1, author2 *Author
+// 	err := db.Load(&author1, 12)
+// 	err = db.Load(&author2, 12) // no query, author1 == author2
+func (db *DB) UseIdentityMap(enabled bool) {
+	if !enabled {
+		db.identityMap = nil
+		return
+	}
+	if db.identityMap == nil {
+		db.identityMap = newIdentityMap()
+	}
+}
+
+// ClearIdentityMap forgets all the instances of the identity map.
+func (db *DB) ClearIdentityMap() {
+	if db.identityMap != nil {
+		db.identityMap = newIdentityMap()
+	}
+}
+
+// newIdentityMap creates an empty identityMap.
+func newIdentityMap() *identityMap {
+	return &identityMap{instances: make(map[string]reflect.Value)}
+}
+
+// Load fetches the record having the given key values, like Get, into a new
+// struct instance, and sets the given pointer to it. With the identity map
+// (see UseIdentityMap) the instance already loaded is given without query.
+//
+// Example :
+// 	var book *Book
+// 	err := db.Load(&book, 123)
+func (db *DB) Load(record interface{}, keyValues ...interface{}) error {
+	recordValue := reflect.ValueOf(record)
+	if recordValue.Kind() != reflect.Ptr || recordValue.Elem().Kind() != reflect.Ptr ||
+		recordValue.Elem().Type().Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Load accepts only a pointer to a struct pointer, got %T", record)
+	}
+
+	instance := reflect.New(recordValue.Elem().Type().Elem())
+	if err := db.Get(instance.Interface(), keyValues...); err != nil {
+		return err
+	}
+
+	if db.identityMap != nil {
+		recordDescription, err := buildRecordDescription(instance.Interface())
+		if err != nil {
+			return err
+		}
+		if key, ok := db.identityKey(recordDescription, keyValues); ok {
+			if known, found := db.identityMap.instances[key]; found {
+				instance = known
+			}
+		}
+	}
+	recordValue.Elem().Set(instance)
+	return nil
+}
+
+// identityKey returns the key of the identity map for the given record
+// description and key values, or false if the struct has no key.
+func (db *DB) identityKey(recordDescription *recordDescription, keyValues []interface{}) (string, bool) {
+	if len(recordDescription.structMapping.GetKeyColumnsNames()) == 0 {
+		return "", false
+	}
+	tableName := db.defaultTableNamer(recordDescription.getTableName())
+	return fmt.Sprintf("%s\x00%v", tableName, keyValues), true
+}
+
+// identityKeyOf returns the key of the identity map for the given struct
+// pointer.
+func (db *DB) identityKeyOf(recordDescription *recordDescription, instance interface{}) (string, bool) {
+	keyValues := recordDescription.structMapping.GetKeyFieldsValues(instance)
+	return db.identityKey(recordDescription, keyValues)
+}
+
+// fetchIdentity copies the known instance having the given key values into
+// the given record (a single instance). It returns false if the instance is
+// unknown or the identity map disabled.
+func (db *DB) fetchIdentity(recordDescription *recordDescription, keyValues []interface{}) bool {
+	if db.identityMap == nil {
+		return false
+	}
+	key, ok := db.identityKey(recordDescription, keyValues)
+	if !ok {
+		return false
+	}
+	known, found := db.identityMap.instances[key]
+	if !found || known.Type().Elem() != recordDescription.instanceType {
+		return false
+	}
+	reflect.ValueOf(recordDescription.record).Elem().Set(known.Elem())
+	return true
+}
+
+// registerIdentities adds the loaded records to the identity map. The
+// records of a slice of pointers are replaced by the known instances, or
+// registered as is. The others records are registered as copies.
+func (db *DB) registerIdentities(recordDescription *recordDescription) {
+	if db.identityMap == nil {
+		return
+	}
+
+	var sliceValue reflect.Value
+	if recordDescription.isSlice {
+		sliceValue = reflect.ValueOf(recordDescription.record).Elem()
+	}
+	for i := 0; i < recordDescription.len(); i++ {
+		instance := recordDescription.index(i)
+		key, ok := db.identityKeyOf(recordDescription, instance)
+		if !ok {
+			return
+		}
+		known, found := db.identityMap.instances[key]
+		switch {
+		case found && recordDescription.isSliceOfPointers:
+			sliceValue.Index(i).Set(known)
+		case found:
+			reflect.ValueOf(instance).Elem().Set(known.Elem())
+		case recordDescription.isSliceOfPointers:
+			db.identityMap.instances[key] = reflect.ValueOf(instance)
+		default:
+			instanceCopy := reflect.New(recordDescription.instanceType)
+			instanceCopy.Elem().Set(reflect.ValueOf(instance).Elem())
+			db.identityMap.instances[key] = instanceCopy
+		}
+	}
+}
+
+// forgetIdentities removes the given records from the identity map, unless
+// keepKnown is true and the record is the known instance.
+func (db *DB) forgetIdentities(recordDescription *recordDescription, keepKnown bool) {
+	if db.identityMap == nil {
+		return
+	}
+
+	for i := 0; i < recordDescription.len(); i++ {
+		instance := recordDescription.index(i)
+		key, ok := db.identityKeyOf(recordDescription, instance)
+		if !ok {
+			return
+		}
+		known, found := db.identityMap.instances[key]
+		if found && keepKnown && known.Interface() == instance {
+			continue
+		}
+		delete(db.identityMap.instances, key)
+	}
+}
@@ -0,0 +1,102 @@
+package godb
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIdentityMap(t *testing.T) {
+	Convey("Given a test database with an identity map", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		db.UseIdentityMap(true)
+
+		// Changes not seen by the identity map
+		changeBehind := func() {
+			_, err := db.CurrentDB().Exec("update dummies set a_text = 'Changed'")
+			So(err, ShouldBeNil)
+		}
+
+		Convey("Load returns the same instance without query", func() {
+			var first, again *Dummy
+			So(db.Load(&first, 1), ShouldBeNil)
+			So(first.AText, ShouldEqual, "First")
+			changeBehind()
+			So(db.Load(&again, 1), ShouldBeNil)
+			So(again, ShouldEqual, first)
+			So(again.AText, ShouldEqual, "First")
+		})
+
+		Convey("Get returns a copy of the known instance", func() {
+			var first *Dummy
+			So(db.Load(&first, 1), ShouldBeNil)
+			first.AText = "Modified"
+			changeBehind()
+			dummy := Dummy{}
+			So(db.Get(&dummy, 1), ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "Modified")
+		})
+
+		Convey("Select registers and replaces the instances of a slice of pointers", func() {
+			var second *Dummy
+			So(db.Load(&second, 2), ShouldBeNil)
+			dummies := make([]*Dummy, 0)
+			So(db.Select(&dummies).OrderBy("id").Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+			So(dummies[1], ShouldEqual, second)
+
+			var third *Dummy
+			So(db.Load(&third, 3), ShouldBeNil)
+			So(third, ShouldEqual, dummies[2])
+		})
+
+		Convey("The updated instance stays known", func() {
+			var first *Dummy
+			So(db.Load(&first, 1), ShouldBeNil)
+			first.AText = "Updated"
+			So(db.Update(first).Do(), ShouldBeNil)
+			var again *Dummy
+			So(db.Load(&again, 1), ShouldBeNil)
+			So(again, ShouldEqual, first)
+		})
+
+		Convey("The records changed by others instances are forgotten", func() {
+			var first *Dummy
+			So(db.Load(&first, 1), ShouldBeNil)
+			other := *first
+			other.AText = "Other"
+			So(db.Update(&other).Do(), ShouldBeNil)
+			var again *Dummy
+			So(db.Load(&again, 1), ShouldBeNil)
+			So(again, ShouldNotEqual, first)
+			So(again.AText, ShouldEqual, "Other")
+
+			_, err := db.Delete(again).Do()
+			So(err, ShouldBeNil)
+			So(db.Load(&again, 1), ShouldNotBeNil)
+		})
+
+		Convey("The identity map is cleared when a transaction ends", func() {
+			So(db.Begin(), ShouldBeNil)
+			var first, again *Dummy
+			So(db.Load(&first, 1), ShouldBeNil)
+			So(db.Rollback(), ShouldBeNil)
+			So(db.Load(&again, 1), ShouldBeNil)
+			So(again, ShouldNotEqual, first)
+		})
+
+		Convey("Without identity map the instances are loaded again", func() {
+			db.UseIdentityMap(false)
+			var first, again *Dummy
+			So(db.Load(&first, 1), ShouldBeNil)
+			So(db.Load(&again, 1), ShouldBeNil)
+			So(again, ShouldNotEqual, first)
+		})
+
+		Convey("Load needs a pointer to a struct pointer", func() {
+			dummy := Dummy{}
+			So(db.Load(&dummy, 1), ShouldNotBeNil)
+		})
+	})
+}
@@ -1,6 +1,11 @@
 package godb
 
-import "github.com/samonzeweb/godb/adapters"
+import (
+	"database/sql"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+)
 
 // InsertStatement is an INSERT statement builder.
 // Initialize it with the InsertInto method.
@@ -17,8 +22,11 @@ type InsertStatement struct {
 	columns          []string
 	intoTable        string
 	values           [][]interface{}
+	fromSelect       *SelectStatement
+	ignoreConflicts  bool
 	returningColumns []string
 	suffixes         []string
+	options          statementOptions
 }
 
 // InsertInto initializes a INSERT statement builder
@@ -28,6 +36,17 @@ func (db *DB) InsertInto(tableName string) *InsertStatement {
 	return ip
 }
 
+// Clone returns a copy of the statement, allowing to reuse a base query
+// and to branch it without altering the original one.
+func (is *InsertStatement) Clone() *InsertStatement {
+	clone := *is
+	clone.columns = append([]string(nil), is.columns...)
+	clone.values = append([][]interface{}(nil), is.values...)
+	clone.returningColumns = append([]string(nil), is.returningColumns...)
+	clone.suffixes = append([]string(nil), is.suffixes...)
+	return &clone
+}
+
 // Columns adds columns to insert.
 func (is *InsertStatement) Columns(columns ...string) *InsertStatement {
 	is.columns = append(is.columns, columns...)
@@ -40,6 +59,29 @@ func (is *InsertStatement) Values(values ...interface{}) *InsertStatement {
 	return is
 }
 
+// FromSelect sets the SELECT statement giving the rows to insert, instead
+// of values. The columns it selects match the columns of the INSERT
+// statement.
+//
+// Example :
+// 	_, err := db.InsertInto("books").
+// 		Columns("title", "author").
+// 		FromSelect(db.SelectFrom("staging_books").Columns("title", "author")).
+// 		Do()
+func (is *InsertStatement) FromSelect(selectStatement *SelectStatement) *InsertStatement {
+	is.fromSelect = selectStatement
+	return is
+}
+
+// IgnoreConflicts skips the rows conflicting with a unique constraint,
+// instead of failing, with a clause given by the adapter (ON CONFLICT DO
+// NOTHING with PostgreSQL and SQLite). ToSQL returns an error if the adapter
+// does not support it.
+func (is *InsertStatement) IgnoreConflicts() *InsertStatement {
+	is.ignoreConflicts = true
+	return is
+}
+
 // Returning adds a RETURNING or OUTPUT clause to the statement. Use it with
 // PostgreSQL and SQL Server.
 func (is *InsertStatement) Returning(columns ...string) *InsertStatement {
@@ -47,6 +89,22 @@ func (is *InsertStatement) Returning(columns ...string) *InsertStatement {
 	return is
 }
 
+// Priority sets the priority of the statement when the concurrent queries
+// are limited, see SetMaxConcurrentQueries.
+func (is *InsertStatement) Priority(priority Priority) *InsertStatement {
+	is.options.priority = priority
+	return is
+}
+
+// Timeout sets the maximum execution time of the insert. It's cancelled when
+// the timeout expires, like when the context of the DB is done (see
+// SetContext), and PostgreSQL and SQL Server abort it. MySQL has no limit
+// for the inserts : the driver abandons it, but it could still be applied.
+func (is *InsertStatement) Timeout(timeout time.Duration) *InsertStatement {
+	is.options.timeout = timeout
+	return is
+}
+
 // Suffix adds an expression to suffix the statement.
 func (is *InsertStatement) Suffix(suffix string) *InsertStatement {
 	is.suffixes = append(is.suffixes, suffix)
@@ -65,30 +123,48 @@ func (is *InsertStatement) ToSQL() (string, []interface{}, error) {
 	sqlBuffer.writeColumns(is.columns)
 	sqlBuffer.Write(") ")
 	sqlBuffer.writeReturningForPosition(is.returningColumns, adapters.ReturningSQLServer)
-	sqlBuffer.Write("VALUES ")
-	sqlBuffer.writeInsertValues(is.values, len(is.columns))
+	if is.fromSelect != nil {
+		selectSQL, selectArgs, err := is.fromSelect.ToSQL()
+		if err != nil {
+			return "", nil, err
+		}
+		sqlBuffer.Write(selectSQL, selectArgs...)
+	} else {
+		sqlBuffer.Write("VALUES ")
+		sqlBuffer.writeInsertValues(is.values, len(is.columns))
+	}
+	if is.ignoreConflicts {
+		sqlBuffer.writeIgnoreConflicts()
+	}
 	sqlBuffer.writeReturningForPosition(is.returningColumns, adapters.ReturningPostgreSQL)
 	sqlBuffer.writeStringsWithSpaces(is.suffixes)
 
 	return sqlBuffer.SQL(), sqlBuffer.Arguments(), sqlBuffer.Err()
 }
 
-// Do executes the builded INSERT statement and returns the creadted 'id' if
-// the adapter does not implement InsertReturningSuffixer.
-func (is *InsertStatement) Do() (int64, error) {
+// ToInterpolatedSQL returns the SQL statement with the arguments written as
+// literals for the adapter, to be pasted into a SQL client. Debug only : the
+// statements are always executed with placeholders.
+func (is *InsertStatement) ToInterpolatedSQL() (string, error) {
 	query, args, err := is.ToSQL()
 	if err != nil {
-		return 0, err
+		return "", err
 	}
+	return interpolateSQL(is.db.adapter, query, args)
+}
 
-	result, err := is.db.do(query, args)
+// Do executes the builded INSERT statement and returns the creadted 'id' if
+// the adapter does not implement InsertReturningSuffixer, and if the driver
+// gives it.
+func (is *InsertStatement) Do() (int64, error) {
+	result, err := is.exec()
 	if err != nil {
 		return 0, err
 	}
 
 	// Return the created 'Id' (if available)
 	_, ok := is.db.adapter.(adapters.ReturningBuilder)
-	if ok {
+	if ok || !supportsLastInsertID(is.db.adapter) {
 		// adapters with ReturningSuffixer does not use LastInsertId()
 		return 0, nil
 	}
@@ -96,6 +172,18 @@ func (is *InsertStatement) Do() (int64, error) {
 	return lastInsertID, err
 }
 
+// exec executes the builded INSERT statement.
+func (is *InsertStatement) exec() (sql.Result, error) {
+	query, args, err := is.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	if err := is.checkGuards(query, args); err != nil {
+		return nil, err
+	}
+	return is.db.do(query, args, is.options)
+}
+
 // DoWithReturning executes the statement and fills the fields according to
 // the columns in RETURNING clause.
 func (is *InsertStatement) DoWithReturning(record interface{}) (int64, error) {
@@ -120,6 +208,30 @@ func (is *InsertStatement) doWithReturning(recordDescription *recordDescription,
 	if err != nil {
 		return 0, err
 	}
+	if err := is.checkGuards(query, args); err != nil {
+		return 0, err
+	}
+	if err := is.db.checkWritable(query); err != nil {
+		return 0, err
+	}
+
+	return is.db.doSelectOrWithReturning(query, args, recordDescription, pointersGetter, is.options)
+}
+
+// checkGuards gives the built statement to the guards of the DB.
+func (is *InsertStatement) checkGuards(query string, args []interface{}) error {
+	return is.db.checkGuards(&StatementInfo{
+		Kind:      "INSERT",
+		Tables:    guardedTableNames(is.intoTable),
+		Columns:   is.columns,
+		Query:     query,
+		Arguments: args,
+	})
+}
 
-	return is.db.doSelectOrWithReturning(query, args, recordDescription, pointersGetter)
+// supportsLastInsertID returns false if the adapter tells that its driver
+// does not give the id of an inserted row.
+func supportsLastInsertID(adapter adapters.Adapter) bool {
+	checker, ok := adapter.(adapters.LastInsertIDChecker)
+	return !ok || checker.SupportsLastInsertID()
 }
@@ -3,6 +3,8 @@ package godb
 import (
 	"testing"
 
+	"github.com/samonzeweb/godb/adapters/sqlite"
+
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -14,6 +16,18 @@ func TestInsertInto(t *testing.T) {
 	})
 }
 
+func TestInsertClone(t *testing.T) {
+	Convey("Clone returns an independant copy of an insert statement", t, func() {
+		db := &DB{}
+		base := db.InsertInto("dummies").Columns("foo", "bar").Values(1, 2)
+		clone := base.Clone().Values(3, 4).Suffix("RETURNING id")
+		So(len(clone.values), ShouldEqual, 2)
+		So(len(clone.suffixes), ShouldEqual, 1)
+		So(len(base.values), ShouldEqual, 1)
+		So(len(base.suffixes), ShouldEqual, 0)
+	})
+}
+
 func TestInsertColumns(t *testing.T) {
 	Convey("Given an insert statement", t, func() {
 		db := &DB{}
@@ -90,6 +104,36 @@ func TestInsertToSQL(t *testing.T) {
 		})
 
 	})
+
+	Convey("Given an insert statement ignoring the conflicts", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+		q := db.InsertInto("dummies").Columns("foo").Values(1).IgnoreConflicts()
+
+		Convey("ToSQL adds the clause of the adapter", func() {
+			sql, _, err := q.ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "INSERT INTO dummies (foo) VALUES (?) ON CONFLICT DO NOTHING")
+		})
+
+		Convey("ToSQL fails if the adapter does not support it", func() {
+			db.adapter = levelByLevelAdapter{sqlite.Adapter}
+			_, _, err := q.ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given an insert statement with a select statement", t, func() {
+		db := &DB{}
+		q := db.InsertInto("dummies").Columns("foo", "bar").
+			FromSelect(db.SelectFrom("staging").Columns("foo", "bar").Where("baz = ?", 1))
+
+		Convey("ToSQL create a SQL request with the select statement", func() {
+			sql, args, err := q.ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "INSERT INTO dummies (foo, bar) SELECT foo, bar FROM staging WHERE baz = ?")
+			So(args, ShouldResemble, []interface{}{1})
+		})
+	})
 }
 
 func TestInsertToSQLErrors(t *testing.T) {
@@ -0,0 +1,128 @@
+package godb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Queryer is the part of *sql.DB and *sql.Tx used by the libraries built on
+// database/sql, allowing them to run their statements with a DB (see
+// DB.Queryer).
+type Queryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Queryer returns a Queryer executing the statements of another library in
+// the current transaction of the DB if there is one (at the time of each
+// statement), and with the DB otherwise. The statements are used as is
+// (their placeholders aren't replaced), they are prepared and cached like
+// the ones built by godb if the prepared statements cache is enabled, and
+// logged.
+//
+// Example :
+// 	err := db.Begin()
+// 	// the audit library runs its statements in the transaction
+// 	err = audit.Record(db.Queryer(), event)
+// 	err = db.Commit()
+func (db *DB) Queryer() Queryer {
+	return dbQueryer{db: db}
+}
+
+// dbQueryer implements Queryer for a DB.
+type dbQueryer struct {
+	db *DB
+}
+
+// Exec implements Queryer.
+func (q dbQueryer) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return q.ExecContext(q.db.Context(), query, args...)
+}
+
+// Query implements Queryer.
+func (q dbQueryer) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return q.QueryContext(q.db.Context(), query, args...)
+}
+
+// QueryRow implements Queryer.
+func (q dbQueryer) QueryRow(query string, args ...interface{}) *sql.Row {
+	return q.QueryRowContext(q.db.Context(), query, args...)
+}
+
+// ExecContext implements Queryer.
+func (q dbQueryer) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	queryable, err := q.db.getQueryable(query)
+	if err != nil {
+		q.db.logExecutionErr(err, query, args)
+		return nil, err
+	}
+	startTime := time.Now()
+	result, err := queryable.ExecContext(ctx, args...)
+	q.logExecution(startTime, err, query, args)
+	return result, err
+}
+
+// QueryContext implements Queryer.
+func (q dbQueryer) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	queryable, err := q.db.getQueryable(query)
+	if err != nil {
+		q.db.logExecutionErr(err, query, args)
+		return nil, err
+	}
+	startTime := time.Now()
+	rows, err := queryable.QueryContext(ctx, args...)
+	q.logExecution(startTime, err, query, args)
+	return rows, err
+}
+
+// QueryRowContext implements Queryer. As with database/sql, the errors are
+// given by the Scan of the row.
+func (q dbQueryer) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	queryable, err := q.db.getQueryable(query)
+	if err != nil {
+		// the statement is executed without being prepared, the row gives
+		// the error
+		q.db.logExecutionErr(err, query, args)
+		queryable, _ = q.db.getQueryableWithOptions(query, false, true)
+	}
+	startTime := time.Now()
+	row := queryable.QueryRowContext(ctx, args...)
+	q.logExecution(startTime, row.Err(), query, args)
+	return row
+}
+
+// logExecution logs the execution of a statement started at the given
+// time, and adds its duration to the consumed time of the DB.
+func (q dbQueryer) logExecution(startTime time.Time, err error, query string, args []interface{}) {
+	consumedTime := timeElapsedSince(startTime)
+	q.db.addConsumedTime(consumedTime)
+	q.db.logExecution(consumedTime, query, args)
+	if err != nil {
+		q.db.logExecutionErr(err, query, args)
+	}
+}
+
+// ScanRows fills the given record, a pointer to a struct or to a slice of
+// structs or struct pointers, with the rows given by another library, using
+// the godb mapping of the struct. Like RawSQL the columns are matched by name
+// and a single struct must be given a single row (sql.ErrNoRows is returned
+// if there is none). The rows are read until their end and closed.
+//
+// Example :
+// 	rows, err := sqlDB.Query("SELECT * FROM books WHERE author_id = $1", authorID)
+// 	books := make([]Book, 0)
+// 	err = godb.ScanRows(rows, &books)
+func ScanRows(rows *sql.Rows, record interface{}) error {
+	return (&DB{}).ScanRows(rows, record)
+}
+
+// ScanRows works like the ScanRows function, the fields being masked if the
+// masking mode of the DB is enabled (see UseMasking).
+func (db *DB) ScanRows(rows *sql.Rows, record interface{}) error {
+	return db.ScanAll(rows, record, ScanOptions{})
+}
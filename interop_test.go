@@ -0,0 +1,75 @@
+package godb
+
+import (
+	"database/sql"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQueryer(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Queryer runs the statements with the DB", func() {
+			var count int
+			So(db.Queryer().QueryRow("select count(*) from dummies").Scan(&count), ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("Queryer runs the statements in the current transaction", func() {
+			queryer := db.Queryer()
+			So(db.Begin(), ShouldBeNil)
+			_, err := queryer.Exec("delete from dummies where id = ?", 1)
+			So(err, ShouldBeNil)
+			So(db.Rollback(), ShouldBeNil)
+
+			var count int
+			So(queryer.QueryRow("select count(*) from dummies").Scan(&count), ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("Queryer uses the prepared statements cache", func() {
+			db.StmtCacheDB().Enable()
+			rows, err := db.Queryer().Query("select id from dummies")
+			So(err, ShouldBeNil)
+			rows.Close()
+			So(db.StmtCacheDB().get("select id from dummies"), ShouldNotBeNil)
+		})
+
+		Convey("The errors of QueryRow are given by the row", func() {
+			var count int
+			err := db.Queryer().QueryRow("select count(*) from missing").Scan(&count)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestScanRows(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("ScanRows fills a slice with the rows", func() {
+			rows, err := db.CurrentDB().Query("select * from dummies order by id")
+			So(err, ShouldBeNil)
+			dummies := make([]Dummy, 0)
+			So(ScanRows(rows, &dummies), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+			So(dummies[1].AText, ShouldEqual, "Second")
+		})
+
+		Convey("ScanRows fills a struct with a single row", func() {
+			rows, err := db.CurrentDB().Query("select id, a_text from dummies where id = ?", 3)
+			So(err, ShouldBeNil)
+			dummy := Dummy{}
+			So(ScanRows(rows, &dummy), ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "Third")
+
+			rows, err = db.CurrentDB().Query("select id, a_text from dummies where id = ?", 4)
+			So(err, ShouldBeNil)
+			So(ScanRows(rows, &dummy), ShouldEqual, sql.ErrNoRows)
+		})
+	})
+}
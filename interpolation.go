@@ -0,0 +1,77 @@
+package godb
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// interpolateSQL replaces the placeholders of the given query (using '?' as
+// placeholder) with the literals of the arguments, written for the adapter.
+func interpolateSQL(adapter adapters.Adapter, query string, arguments []interface{}) (string, error) {
+	var statement strings.Builder
+	for i := 0; ; i++ {
+		position := strings.Index(query, Placeholder)
+		if position == -1 {
+			if i < len(arguments) {
+				return "", fmt.Errorf("the statement has fewer placeholders than arguments : %s", query)
+			}
+			break
+		}
+		if i >= len(arguments) {
+			return "", fmt.Errorf("the statement has more placeholders than arguments : %s", query)
+		}
+		literal, err := formatLiteral(adapter, arguments[i])
+		if err != nil {
+			return "", err
+		}
+		statement.WriteString(query[:position])
+		statement.WriteString(literal)
+		query = query[position+len(Placeholder):]
+	}
+	statement.WriteString(query)
+	return statement.String(), nil
+}
+
+// formatLiteral returns the given argument as a SQL literal for the adapter.
+func formatLiteral(adapter adapters.Adapter, argument interface{}) (string, error) {
+	value, err := driver.DefaultParameterConverter.ConvertValue(argument)
+	if err != nil {
+		return "", fmt.Errorf("the argument %v has no literal form : %v", argument, err)
+	}
+	if formatter, ok := adapter.(adapters.LiteralFormatter); ok {
+		if literal, ok := formatter.FormatLiteral(value); ok {
+			return literal, nil
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", fmt.Errorf("the argument %v has no literal form", v)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	case []byte:
+		return "X'" + hex.EncodeToString(v) + "'", nil
+	case string:
+		return "'" + strings.Replace(v, "'", "''", -1) + "'", nil
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05.999999999-07:00") + "'", nil
+	}
+	return "", fmt.Errorf("the argument %v has no literal form", argument)
+}
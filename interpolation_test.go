@@ -0,0 +1,51 @@
+package godb
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInterpolateSQL(t *testing.T) {
+	Convey("interpolateSQL replaces the placeholders with the literals", t, func() {
+		db := createInMemoryConnection(t)
+		defer db.Close()
+
+		query, err := interpolateSQL(db.adapter, "SELECT * FROM dummies WHERE a = ? AND b = ?", []interface{}{1, "x"})
+		So(err, ShouldBeNil)
+		So(query, ShouldEqual, "SELECT * FROM dummies WHERE a = 1 AND b = 'x'")
+
+		_, err = interpolateSQL(db.adapter, "SELECT * FROM dummies WHERE a = ?", nil)
+		So(err, ShouldNotBeNil)
+		_, err = interpolateSQL(db.adapter, "SELECT * FROM dummies", []interface{}{1})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestFormatLiteral(t *testing.T) {
+	Convey("formatLiteral writes the default literals", t, func() {
+		db := createInMemoryConnection(t)
+		defer db.Close()
+		when := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600))
+		literals := map[interface{}]string{
+			int8(-1):   "-1",
+			uint16(2):  "2",
+			true:       "1",
+			"a'b":      "'a''b'",
+			when:       "'2024-01-02 03:04:05+01:00'",
+			float32(2): "2",
+			nil:        "NULL",
+		}
+		for value, expected := range literals {
+			literal, err := formatLiteral(db.adapter, value)
+			So(err, ShouldBeNil)
+			So(literal, ShouldEqual, expected)
+		}
+		literal, err := formatLiteral(db.adapter, []byte("x"))
+		So(err, ShouldBeNil)
+		So(literal, ShouldEqual, "X'78'")
+		_, err = formatLiteral(db.adapter, struct{}{})
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -15,9 +15,12 @@ type Iterator interface {
 
 // iteratorInternals is the Iterator implementation (hidden)
 type iteratorInternals struct {
+	db         *DB
 	rows       *sql.Rows
 	recordInfo *recordDescription
 	columns    []string
+	// release is called when the iterator is closed
+	release func()
 }
 
 // Next prepares the next result row for reading with the Scan method.
@@ -45,18 +48,26 @@ func (i *iteratorInternals) Scan(record interface{}) error {
 	if err != nil {
 		return err
 	}
+	if i.db.tolerantScan != nil {
+		pointers = i.db.coercingPointers(pointers)
+	}
 
-	return i.rows.Scan(pointers...)
+	if err = i.rows.Scan(pointers...); err != nil {
+		return err
+	}
+	return i.db.mask(i.recordInfo, record, i.columns)
 }
 
-//Scanx scans record values to destination columns
+// Scanx scans record values to destination columns
 func (i *iteratorInternals) Scanx(dest ...interface{}) error {
 	return i.rows.Scan(dest...)
 }
 
 // Close frees ressources created by the request execution.
 func (i *iteratorInternals) Close() error {
-	return i.rows.Close()
+	err := i.rows.Close()
+	i.release()
+	return err
 }
 
 // Err returns the error that was encountered during iteration, or nil.
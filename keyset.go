@@ -0,0 +1,253 @@
+package godb
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cursor is the position in a keyset pagination : the sort specification and
+// the values of the sort columns of the last record read.
+type Cursor struct {
+	// Sort contains the sort columns, prefixed with - for a descending order
+	Sort   []string
+	Values []interface{}
+}
+
+// cursorValue is the serialized form of a cursor value, keeping its type.
+type cursorValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v"`
+}
+
+// cursorPayload is the serialized form of a cursor.
+type cursorPayload struct {
+	Sort   []string      `json:"s"`
+	Values []cursorValue `json:"v"`
+}
+
+// CursorEncoder encodes cursors into opaque tokens for APIs, and decodes
+// them. The tokens are signed with a secret key, a tampered token is rejected
+// with ErrInvalidCursor. They are not encrypted.
+type CursorEncoder struct {
+	secret []byte
+}
+
+// NewCursorEncoder creates a CursorEncoder signing the tokens with the given
+// secret key.
+func NewCursorEncoder(secret []byte) *CursorEncoder {
+	return &CursorEncoder{secret: secret}
+}
+
+// Encode returns the token of the given cursor. The values have to be
+// integers, floats, booleans, strings, bytes or times (or driver.Valuer
+// giving them).
+func (ce *CursorEncoder) Encode(cursor *Cursor) (string, error) {
+	payload := cursorPayload{Sort: cursor.Sort, Values: make([]cursorValue, 0, len(cursor.Values))}
+	for _, value := range cursor.Values {
+		encoded, err := encodeCursorValue(value)
+		if err != nil {
+			return "", err
+		}
+		payload.Values = append(payload.Values, encoded)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data) + "." +
+		base64.RawURLEncoding.EncodeToString(ce.sign(data)), nil
+}
+
+// Decode returns the cursor of the given token, or ErrInvalidCursor if the
+// token is malformed or its signature is wrong.
+func (ce *CursorEncoder) Decode(token string) (*Cursor, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, ce.sign(data)) {
+		return nil, ErrInvalidCursor
+	}
+
+	payload := cursorPayload{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	cursor := &Cursor{Sort: payload.Sort, Values: make([]interface{}, 0, len(payload.Values))}
+	for _, encoded := range payload.Values {
+		value, err := decodeCursorValue(encoded)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		cursor.Values = append(cursor.Values, value)
+	}
+	return cursor, nil
+}
+
+// sign returns the HMAC-SHA256 signature of the data.
+func (ce *CursorEncoder) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, ce.secret)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// encodeCursorValue converts a value with its type.
+func encodeCursorValue(value interface{}) (cursorValue, error) {
+	value, err := driver.DefaultParameterConverter.ConvertValue(value)
+	if err != nil {
+		return cursorValue{}, err
+	}
+
+	switch v := value.(type) {
+	case int64:
+		return cursorValue{Type: "i", Value: strconv.FormatInt(v, 10)}, nil
+	case float64:
+		return cursorValue{Type: "f", Value: strconv.FormatFloat(v, 'g', -1, 64)}, nil
+	case bool:
+		return cursorValue{Type: "b", Value: strconv.FormatBool(v)}, nil
+	case string:
+		return cursorValue{Type: "s", Value: v}, nil
+	case []byte:
+		return cursorValue{Type: "y", Value: base64.StdEncoding.EncodeToString(v)}, nil
+	case time.Time:
+		return cursorValue{Type: "t", Value: v.Format(time.RFC3339Nano)}, nil
+	}
+	return cursorValue{}, fmt.Errorf("unsupported cursor value %v (%T)", value, value)
+}
+
+// decodeCursorValue converts back a value encoded by encodeCursorValue.
+func decodeCursorValue(encoded cursorValue) (interface{}, error) {
+	switch encoded.Type {
+	case "i":
+		return strconv.ParseInt(encoded.Value, 10, 64)
+	case "f":
+		return strconv.ParseFloat(encoded.Value, 64)
+	case "b":
+		return strconv.ParseBool(encoded.Value)
+	case "s":
+		return encoded.Value, nil
+	case "y":
+		return base64.StdEncoding.DecodeString(encoded.Value)
+	case "t":
+		return time.Parse(time.RFC3339Nano, encoded.Value)
+	}
+	return nil, fmt.Errorf("unknown cursor value type %s", encoded.Type)
+}
+
+// KeysetPaginate fetches perPage records ordered by the given sort columns
+// (prefixed with - for a descending order), after the position given by the
+// token (use a blank token for the first page). It returns the token of the
+// next page, or a blank string for the last page.
+//
+// The record given to Select has to be a slice, the sort columns have to be
+// columns of the struct, not NULL, and unique together (add the key columns
+// at the end). Unlike Offset, the pages stay consistent when records are
+// added or removed, and the performance does not decrease with the pages.
+//
+// Example :
+// 	encoder := godb.NewCursorEncoder(secret)
+// 	books := make([]Book, 0)
+// 	next, err := db.Select(&books).KeysetPaginate(encoder, token, 20, "-published", "id")
+func (ss *StructSelect) KeysetPaginate(encoder *CursorEncoder, token string, perPage int, sort ...string) (string, error) {
+	if ss.error != nil {
+		return "", ss.error
+	}
+	if !ss.recordDescription.isSlice {
+		return "", fmt.Errorf("KeysetPaginate accepts only a slice")
+	}
+	if perPage < 1 || len(sort) == 0 {
+		return "", fmt.Errorf("KeysetPaginate needs at least one record per page and one sort column")
+	}
+
+	columns := make([]string, len(sort))
+	descending := make([]bool, len(sort))
+	allColumns := ss.recordDescription.structMapping.GetAllColumnsNames()
+	for i, sortColumn := range sort {
+		descending[i] = strings.HasPrefix(sortColumn, "-")
+		columns[i] = strings.TrimPrefix(sortColumn, "-")
+		if indexOfString(allColumns, columns[i]) < 0 {
+			return "", fmt.Errorf("unknown sort column %s in struct %s", columns[i], ss.recordDescription.structMapping.Name)
+		}
+	}
+
+	db := ss.selectStatement.db
+	if token != "" {
+		cursor, err := encoder.Decode(token)
+		if err != nil {
+			return "", err
+		}
+		if strings.Join(cursor.Sort, ",") != strings.Join(sort, ",") || len(cursor.Values) != len(sort) {
+			return "", ErrInvalidCursor
+		}
+		ss.WhereQ(ss.keysetCondition(columns, descending, cursor.Values))
+	}
+	for i, column := range columns {
+		direction := ""
+		if descending[i] {
+			direction = " DESC"
+		}
+		ss.OrderBy(db.quoteFor(ss.recordDescription, column) + direction)
+	}
+
+	// One more record tells if there is a next page
+	ss.Limit(perPage + 1)
+	if err := ss.Do(); err != nil {
+		return "", err
+	}
+	records := reflect.ValueOf(ss.recordDescription.record).Elem()
+	if records.Len() <= perPage {
+		return "", nil
+	}
+	records.SetLen(perPage)
+
+	lastValues := ss.recordDescription.structMapping.GetAllFieldsValues(ss.recordDescription.index(perPage - 1))
+	cursor := &Cursor{Sort: sort, Values: make([]interface{}, 0, len(columns))}
+	for _, column := range columns {
+		cursor.Values = append(cursor.Values, lastValues[indexOfString(allColumns, column)])
+	}
+	return encoder.Encode(cursor)
+}
+
+// keysetCondition builds the condition selecting the records after the given
+// values : (c1 > v1) OR (c1 = v1 AND c2 > v2) OR ...
+func (ss *StructSelect) keysetCondition(columns []string, descending []bool, values []interface{}) *Condition {
+	db := ss.selectStatement.db
+	alternatives := make([]*Condition, 0, len(columns))
+	for i := range columns {
+		conditions := make([]*Condition, 0, i+1)
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, Q(db.quoteFor(ss.recordDescription, columns[j])+" = ?", values[j]))
+		}
+		operator := " > ?"
+		if descending[i] {
+			operator = " < ?"
+		}
+		conditions = append(conditions, Q(db.quoteFor(ss.recordDescription, columns[i])+operator, values[i]))
+		alternatives = append(alternatives, And(conditions...))
+	}
+	return Or(alternatives...)
+}
+
+// indexOfString returns the index of the string in the slice, or -1.
+func indexOfString(slice []string, s string) int {
+	for i, element := range slice {
+		if element == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,81 @@
+package godb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCursorEncoder(t *testing.T) {
+	Convey("Given a cursor encoder", t, func() {
+		encoder := NewCursorEncoder([]byte("secret"))
+		day := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+		cursor := &Cursor{
+			Sort:   []string{"-published", "title", "id"},
+			Values: []interface{}{day, "Foo", 123, 1.5, true, []byte("bar")},
+		}
+
+		Convey("A token is decoded with the values types", func() {
+			token, err := encoder.Encode(cursor)
+			So(err, ShouldBeNil)
+
+			decoded, err := encoder.Decode(token)
+			So(err, ShouldBeNil)
+			So(decoded.Sort, ShouldResemble, cursor.Sort)
+			So(decoded.Values, ShouldResemble, []interface{}{day, "Foo", int64(123), 1.5, true, []byte("bar")})
+		})
+
+		Convey("A tampered token or a token signed with another key is rejected", func() {
+			token, err := encoder.Encode(cursor)
+			So(err, ShouldBeNil)
+
+			_, err = encoder.Decode("x" + token)
+			So(errors.Is(err, ErrInvalidCursor), ShouldBeTrue)
+
+			_, err = NewCursorEncoder([]byte("other")).Decode(token)
+			So(errors.Is(err, ErrInvalidCursor), ShouldBeTrue)
+
+			_, err = encoder.Decode("garbage")
+			So(errors.Is(err, ErrInvalidCursor), ShouldBeTrue)
+		})
+	})
+}
+
+func TestKeysetPaginate(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		encoder := NewCursorEncoder([]byte("secret"))
+
+		Convey("KeysetPaginate walks through the pages with the tokens", func() {
+			dummies := make([]Dummy, 0)
+			next, err := db.Select(&dummies).KeysetPaginate(encoder, "", 2, "-an_integer", "id")
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 2)
+			So(dummies[0].AText, ShouldEqual, "Third")
+			So(dummies[1].AText, ShouldEqual, "Second")
+			So(next, ShouldNotBeEmpty)
+
+			dummies = make([]Dummy, 0)
+			last, err := db.Select(&dummies).KeysetPaginate(encoder, next, 2, "-an_integer", "id")
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 1)
+			So(dummies[0].AText, ShouldEqual, "First")
+			So(last, ShouldBeEmpty)
+
+			Convey("A token is rejected with another sort", func() {
+				dummies = make([]Dummy, 0)
+				_, err := db.Select(&dummies).KeysetPaginate(encoder, next, 2, "an_integer", "id")
+				So(errors.Is(err, ErrInvalidCursor), ShouldBeTrue)
+			})
+		})
+
+		Convey("KeysetPaginate rejects unknown sort columns", func() {
+			dummies := make([]Dummy, 0)
+			_, err := db.Select(&dummies).KeysetPaginate(encoder, "", 2, "password")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
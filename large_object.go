@@ -0,0 +1,184 @@
+package godb
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// largeObjectChunkSize is the maximum count of bytes read or written by a
+// single statement.
+const largeObjectChunkSize = 1 << 20
+
+// LargeObjectMode is the access mode of an opened large object.
+type LargeObjectMode int32
+
+// Access modes of the large objects (INV_READ and INV_WRITE of PostgreSQL).
+const (
+	LargeObjectRead      LargeObjectMode = 0x40000
+	LargeObjectWrite     LargeObjectMode = 0x20000
+	LargeObjectReadWrite                 = LargeObjectRead | LargeObjectWrite
+)
+
+// LargeObjects manages the large objects of the current transaction, see
+// DB.LargeObjects.
+type LargeObjects struct {
+	db *DB
+}
+
+// LargeObject is an opened large object. It implements io.Reader, io.Writer,
+// io.Seeker, io.ReaderAt, io.WriterAt and io.Closer, and is usable until its
+// transaction ends. It's not safe for concurrent use.
+type LargeObject struct {
+	db *DB
+	fd int32
+}
+
+// LargeObjects returns the manager of the large objects, for the payloads too
+// big for a bytea column (up to 4 TB, read and written by chunks). It needs
+// an adapter supporting the large objects (PostgreSQL, see
+// adapters.LargeObjectChecker), and a transaction : the large objects are
+// opened until the end of the transaction, and their identifiers (oid) are
+// stored in the tables.
+//
+// The large objects aren't deleted with the rows referencing them, use
+// Unlink.
+//
+// Example :
+// 	db.Begin()
+// 	los, err := db.LargeObjects()
+// 	oid, err := los.Create()
+// 	lo, err := los.Open(oid, godb.LargeObjectWrite)
+// 	_, err = io.Copy(lo, file)
+// 	err = lo.Close()
+// 	_, err = db.InsertInto("documents").Columns("name", "content").Values(name, oid).Do()
+// 	err = db.Commit()
+func (db *DB) LargeObjects() (*LargeObjects, error) {
+	if checker, ok := db.adapter.(adapters.LargeObjectChecker); !ok || !checker.SupportsLargeObjects() {
+		return nil, fmt.Errorf("the adapter does not support the large objects")
+	}
+	if db.sqlTx == nil {
+		return nil, fmt.Errorf("LargeObjects was called without existing sql transaction")
+	}
+	return &LargeObjects{db: db}, nil
+}
+
+// Create creates an empty large object, and returns its identifier.
+func (los *LargeObjects) Create() (uint32, error) {
+	var oid uint32
+	err := los.db.queryLargeObject(true, &oid, "SELECT lo_create(0)")
+	return oid, err
+}
+
+// Open opens the large object having the given identifier.
+func (los *LargeObjects) Open(oid uint32, mode LargeObjectMode) (*LargeObject, error) {
+	lo := &LargeObject{db: los.db}
+	if err := los.db.queryLargeObject(false, &lo.fd, "SELECT lo_open(?, ?)", oid, int32(mode)); err != nil {
+		return nil, err
+	}
+	return lo, nil
+}
+
+// Unlink deletes the large object having the given identifier.
+func (los *LargeObjects) Unlink(oid uint32) error {
+	var result int32
+	return los.db.queryLargeObject(true, &result, "SELECT lo_unlink(?)", oid)
+}
+
+// Read reads up to len(p) bytes from the current position, it returns
+// io.EOF at the end of the large object.
+func (lo *LargeObject) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	size := len(p)
+	if size > largeObjectChunkSize {
+		size = largeObjectChunkSize
+	}
+	var data []byte
+	if err := lo.db.queryLargeObject(false, &data, "SELECT loread(?, ?)", lo.fd, size); err != nil {
+		return 0, err
+	}
+	if len(data) == 0 {
+		return 0, io.EOF
+	}
+	return copy(p, data), nil
+}
+
+// Write writes p at the current position.
+func (lo *LargeObject) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		end := written + largeObjectChunkSize
+		if end > len(p) {
+			end = len(p)
+		}
+		var count int32
+		if err := lo.db.queryLargeObject(true, &count, "SELECT lowrite(?, ?)", lo.fd, p[written:end]); err != nil {
+			return written, err
+		}
+		written += int(count)
+	}
+	return written, nil
+}
+
+// Seek sets the current position, like io.Seeker, and returns it.
+func (lo *LargeObject) Seek(offset int64, whence int) (int64, error) {
+	var position int64
+	err := lo.db.queryLargeObject(false, &position, "SELECT lo_lseek64(?, ?, ?)", lo.fd, offset, whence)
+	return position, err
+}
+
+// ReadAt reads len(p) bytes from the given offset, it moves the current
+// position.
+func (lo *LargeObject) ReadAt(p []byte, offset int64) (int, error) {
+	if _, err := lo.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.ReadFull(lo, p)
+}
+
+// WriteAt writes p at the given offset, it moves the current position.
+func (lo *LargeObject) WriteAt(p []byte, offset int64) (int, error) {
+	if _, err := lo.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return lo.Write(p)
+}
+
+// Tell returns the current position.
+func (lo *LargeObject) Tell() (int64, error) {
+	var position int64
+	err := lo.db.queryLargeObject(false, &position, "SELECT lo_tell64(?)", lo.fd)
+	return position, err
+}
+
+// Truncate truncates or extends the large object to the given size.
+func (lo *LargeObject) Truncate(size int64) error {
+	var result int32
+	return lo.db.queryLargeObject(true, &result, "SELECT lo_truncate64(?, ?)", lo.fd, size)
+}
+
+// Close closes the large object, it's closed anyway at the end of the
+// transaction.
+func (lo *LargeObject) Close() error {
+	var result int32
+	return lo.db.queryLargeObject(false, &result, "SELECT lo_close(?)", lo.fd)
+}
+
+// queryLargeObject executes a query calling a large object function, and
+// scans its single value. The queries changing data are rejected in read only
+// mode.
+func (db *DB) queryLargeObject(writing bool, value interface{}, query string, arguments ...interface{}) error {
+	if writing {
+		if err := db.checkWritable(query); err != nil {
+			return err
+		}
+	}
+	found, err := db.queryValue(value, query, arguments...)
+	if err == nil && !found {
+		err = fmt.Errorf("the query %s gave no value", query)
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package godb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// largeObjectAdapter tells that the database stores large objects.
+type largeObjectAdapter struct {
+	adapters.Adapter
+}
+
+func (largeObjectAdapter) SupportsLargeObjects() bool {
+	return true
+}
+
+func TestLargeObjects(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("LargeObjects fails if the adapter does not support them", func() {
+			So(db.Begin(), ShouldBeNil)
+			defer db.Rollback()
+			_, err := db.LargeObjects()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("LargeObjects fails without transaction", func() {
+			db.adapter = largeObjectAdapter{db.adapter}
+			_, err := db.LargeObjects()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("The large objects can't be changed in read only mode", func() {
+			db.adapter = largeObjectAdapter{db.adapter}
+			So(db.Begin(), ShouldBeNil)
+			defer db.Rollback()
+			los, err := db.LargeObjects()
+			So(err, ShouldBeNil)
+			db.SetReadOnly(true)
+
+			_, err = los.Create()
+			So(errors.Is(err, ErrReadOnly), ShouldBeTrue)
+			err = los.Unlink(42)
+			So(errors.Is(err, ErrReadOnly), ShouldBeTrue)
+			_, err = (&LargeObject{db: db}).Write([]byte("data"))
+			So(errors.Is(err, ErrReadOnly), ShouldBeTrue)
+		})
+	})
+}
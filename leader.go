@@ -0,0 +1,99 @@
+package godb
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// leaderCheckInterval is the time between two checks of the lock of a
+// Leadership.
+var leaderCheckInterval = time.Second
+
+// Leadership is the leadership of an election won with CampaignLeader.
+type Leadership struct {
+	mutex    *Mutex
+	lost     chan struct{}
+	stop     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+	// unlockErr is the error of the release of the lock
+	unlockErr error
+}
+
+// CampaignLeader waits until the current process becomes the leader of the
+// given election, or the context is done. The leadership is a lock (see
+// Mutex) checked and renewed in background, the channel given by Lost is
+// closed when it's lost : the database connection was lost, the context is
+// done, or Resign was called. It allows a single active worker among
+// replicas without an external coordinator.
+//
+// Example :
+// 	leadership, err := db.Clone().CampaignLeader(ctx, "scheduler")
+// 	if err != nil {
+// 		return err
+// 	}
+// 	defer leadership.Resign()
+// 	for {
+// 		select {
+// 		case <-leadership.Lost():
+// 			return errors.New("leadership lost")
+// 		case <-ticker.C:
+// 			// work as the leader
+// 		}
+// 	}
+func (db *DB) CampaignLeader(ctx context.Context, name string) (*Leadership, error) {
+	mutex := db.Mutex(name)
+	if err := mutex.Lock(ctx); err != nil {
+		return nil, err
+	}
+
+	leadership := &Leadership{
+		mutex:   mutex,
+		lost:    make(chan struct{}),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go leadership.watch(ctx)
+	return leadership, nil
+}
+
+// Lost returns a channel closed when the leadership is lost.
+func (l *Leadership) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Resign gives up the leadership, allowing another process to become the
+// leader. It returns the error of the release of the lock, if any.
+func (l *Leadership) Resign() error {
+	l.stopOnce.Do(func() {
+		close(l.stop)
+	})
+	<-l.stopped
+	return l.unlockErr
+}
+
+// watch checks the lock until the leadership is lost, then releases it.
+func (l *Leadership) watch(ctx context.Context) {
+	defer close(l.stopped)
+	defer func() {
+		l.unlockErr = l.mutex.Unlock()
+		close(l.lost)
+	}()
+
+	ticker := time.NewTicker(leaderCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			if held, err := l.mutex.check(ctx); err != nil || !held {
+				l.mutex.db.logPrintln("Leadership lost :", l.mutex.name, err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package godb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCampaignLeader(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		previousInterval := leaderCheckInterval
+		leaderCheckInterval = time.Millisecond
+		defer func() { leaderCheckInterval = previousInterval }()
+
+		Convey("CampaignLeader elects a single leader", func() {
+			leadership, err := db.CampaignLeader(context.Background(), "scheduler")
+			So(err, ShouldBeNil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			_, err = db.CampaignLeader(ctx, "scheduler")
+			So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+
+			Convey("Resign closes the lost channel and allows another leader", func() {
+				So(leadership.Resign(), ShouldBeNil)
+				_, open := <-leadership.Lost()
+				So(open, ShouldBeFalse)
+
+				other, err := db.CampaignLeader(context.Background(), "scheduler")
+				So(err, ShouldBeNil)
+				So(other.Resign(), ShouldBeNil)
+			})
+		})
+
+		Convey("The leadership is lost when the lock disappears", func() {
+			leadership, err := db.CampaignLeader(context.Background(), "scheduler")
+			So(err, ShouldBeNil)
+
+			_, err = db.CurrentDB().Exec("DELETE FROM godb_locks")
+			So(err, ShouldBeNil)
+			select {
+			case <-leadership.Lost():
+			case <-time.After(time.Second):
+				t.Error("the leadership is not lost")
+			}
+			So(leadership.Resign(), ShouldBeNil)
+		})
+
+		Convey("The leadership is lost when the context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			leadership, err := db.CampaignLeader(ctx, "scheduler")
+			So(err, ShouldBeNil)
+			cancel()
+			<-leadership.Lost()
+
+			other, err := db.CampaignLeader(context.Background(), "scheduler")
+			So(err, ShouldBeNil)
+			So(other.Resign(), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,151 @@
+package godb
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// Priority is the priority of a statement waiting for an execution slot,
+// see SetMaxConcurrentQueries.
+type Priority int
+
+// Priorities of the statements, the waiting statements with the highest
+// priority are executed first. Any other value could be used.
+const (
+	PriorityLow    Priority = -10
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 10
+)
+
+// statementOptions contains the options of a statement execution.
+type statementOptions struct {
+	priority Priority
+	// maxStaleness allows a read from a replica if it's not zero
+	maxStaleness time.Duration
+	// timeout of the execution if it's not zero
+	timeout time.Duration
+}
+
+// SetMaxConcurrentQueries limits the count of statements executed at the
+// same time by the DB and its clones (created after the call), independently
+// of the connection pool size. The others statements wait for a slot, the
+// ones with the highest priority first (see the Priority method of the
+// statements), or until the context of the DB is done. A slot is held until
+// the statement is done, its rows read and closed (an iterator holds it until
+// Close). A value of 0 removes the limit.
+//
+// It protects fragile databases from thundering herds during traffic spikes.
+func (db *DB) SetMaxConcurrentQueries(maxConcurrentQueries int) {
+	if maxConcurrentQueries <= 0 {
+		db.limiter = nil
+		return
+	}
+	db.limiter = newLimiter(maxConcurrentQueries)
+}
+
+// limiter is a semaphore giving the free slots to the waiters by priority,
+// then by order of arrival.
+type limiter struct {
+	mutex   sync.Mutex
+	max     int
+	running int
+	waiters waitersHeap
+	// sequence orders the waiters with the same priority
+	sequence uint64
+}
+
+// waiter is a statement waiting for a slot, its channel is closed when it
+// gets it.
+type waiter struct {
+	priority Priority
+	sequence uint64
+	ready    chan struct{}
+	index    int
+}
+
+// newLimiter creates a limiter with the given count of slots.
+func newLimiter(max int) *limiter {
+	return &limiter{max: max}
+}
+
+// acquire waits for a free slot, or until the context is done.
+func (l *limiter) acquire(ctx context.Context, priority Priority) error {
+	l.mutex.Lock()
+	if l.running < l.max && len(l.waiters) == 0 {
+		l.running++
+		l.mutex.Unlock()
+		return nil
+	}
+	l.sequence++
+	w := &waiter{priority: priority, sequence: l.sequence, ready: make(chan struct{})}
+	heap.Push(&l.waiters, w)
+	l.mutex.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		select {
+		case <-w.ready:
+			// The slot was given meanwhile, give it to the next waiter
+			l.releaseLocked()
+		default:
+			heap.Remove(&l.waiters, w.index)
+		}
+		return ctx.Err()
+	}
+}
+
+// release frees a slot, given to the first waiter if any.
+func (l *limiter) release() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.releaseLocked()
+}
+
+// releaseLocked frees a slot, the mutex being locked.
+func (l *limiter) releaseLocked() {
+	if len(l.waiters) == 0 {
+		l.running--
+		return
+	}
+	w := heap.Pop(&l.waiters).(*waiter)
+	close(w.ready)
+}
+
+// waitersHeap implements heap.Interface, the first waiter having the highest
+// priority and the lowest sequence.
+type waitersHeap []*waiter
+
+func (h waitersHeap) Len() int { return len(h) }
+
+func (h waitersHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].sequence < h[j].sequence
+}
+
+func (h waitersHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *waitersHeap) Push(x interface{}) {
+	w := x.(*waiter)
+	w.index = len(*h)
+	*h = append(*h, w)
+}
+
+func (h *waitersHeap) Pop() interface{} {
+	old := *h
+	w := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return w
+}
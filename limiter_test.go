@@ -0,0 +1,128 @@
+package godb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// waitForWaiters waits until the limiter has the given count of waiters.
+func waitForWaiters(l *limiter, count int) {
+	for {
+		l.mutex.Lock()
+		current := len(l.waiters)
+		l.mutex.Unlock()
+		if current == count {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	Convey("Given a limiter with a single slot in use", t, func() {
+		l := newLimiter(1)
+		So(l.acquire(context.Background(), PriorityNormal), ShouldBeNil)
+
+		Convey("The waiters get the slot by priority then by arrival", func() {
+			order := make(chan string, 4)
+			waiters := []struct {
+				name     string
+				priority Priority
+			}{
+				{"low", PriorityLow},
+				{"normal 1", PriorityNormal},
+				{"high", PriorityHigh},
+				{"normal 2", PriorityNormal},
+			}
+			for i, w := range waiters {
+				w := w
+				go func() {
+					l.acquire(context.Background(), w.priority)
+					order <- w.name
+					l.release()
+				}()
+				waitForWaiters(l, i+1)
+			}
+
+			l.release()
+			got := make([]string, 0, 4)
+			for range waiters {
+				got = append(got, <-order)
+			}
+			So(got, ShouldResemble, []string{"high", "normal 1", "normal 2", "low"})
+
+			// The last waiter may still be releasing its slot
+			for {
+				l.mutex.Lock()
+				running := l.running
+				l.mutex.Unlock()
+				if running == 0 {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+		})
+
+		Convey("A waiter gives up when its context is done", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+			defer cancel()
+			So(errors.Is(l.acquire(ctx, PriorityHigh), context.DeadlineExceeded), ShouldBeTrue)
+			So(len(l.waiters), ShouldEqual, 0)
+
+			l.release()
+			So(l.running, ShouldEqual, 0)
+		})
+	})
+}
+
+func TestSetMaxConcurrentQueries(t *testing.T) {
+	Convey("Given a test database with limited concurrent queries", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		db.SetMaxConcurrentQueries(1)
+
+		Convey("The clones share the limit", func() {
+			So(db.Clone().limiter, ShouldEqual, db.limiter)
+		})
+
+		Convey("The statements are executed and release their slot", func() {
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).Priority(PriorityHigh).Do(), ShouldBeNil)
+			count, err := db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+			_, err = db.UpdateTable("dummies").Set("an_integer", 0).Where("id = ?", 1).Priority(PriorityLow).Do()
+			So(err, ShouldBeNil)
+			So(db.limiter.running, ShouldEqual, 0)
+		})
+
+		Convey("The slot of a query is kept until its rows are closed", func() {
+			iter, err := db.SelectFrom("dummies").Columns("id").DoWithIterator()
+			So(err, ShouldBeNil)
+			So(iter.Next(), ShouldBeTrue)
+			So(db.limiter.running, ShouldEqual, 1)
+			So(iter.Close(), ShouldBeNil)
+			So(db.limiter.running, ShouldEqual, 0)
+		})
+
+		Convey("The statements wait for a slot until the context is done", func() {
+			So(db.limiter.acquire(context.Background(), PriorityNormal), ShouldBeNil)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+			defer cancel()
+			db.SetContext(ctx)
+
+			_, err := db.SelectFrom("dummies").Count()
+			So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+			db.limiter.release()
+		})
+
+		Convey("A value of 0 removes the limit", func() {
+			db.SetMaxConcurrentQueries(0)
+			So(db.limiter, ShouldBeNil)
+		})
+	})
+}
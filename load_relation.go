@@ -0,0 +1,143 @@
+package godb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// LoadRelation loads on demand the records related to the given parents (a
+// struct pointer, or a pointer to a slice of structs or struct pointers) by
+// a relation declared with a rel tag (see Association), into the relation
+// field. The path is the name of the field, or several names separated by
+// dots to load the relations of the related records ("Books.Tags").
+//
+// The hasmany relation of all the parents is loaded with a single query
+// (see Loader), the manytomany relations with a query by parent. Each
+// relation of a parent is loaded once by call, even if the path goes back to
+// it. In a transaction the loaded relations are cached until its end, and
+// given again without query. The cache is updated by the changes done with
+// Association, not by the others changes.
+//
+// Example :
+// 	authors := make([]Author, 0)
+// 	err := db.Select(&authors).Do()
+// 	err = db.LoadRelation(&authors, "Books.Tags")
+func (db *DB) LoadRelation(parents interface{}, path string) error {
+	recordDescription, err := buildRecordDescription(parents)
+	if err != nil {
+		return err
+	}
+	instances := make([]reflect.Value, 0, recordDescription.len())
+	for i := 0; i < recordDescription.len(); i++ {
+		instances = append(instances, reflect.ValueOf(recordDescription.index(i)))
+	}
+
+	cache := make(map[string]interface{})
+	if db.sqlTx != nil {
+		if db.relationCache == nil {
+			db.relationCache = cache
+		}
+		cache = db.relationCache
+	}
+
+	for _, fieldName := range strings.Split(path, ".") {
+		instances, err = db.loadRelation(instances, fieldName, cache)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadRelation loads the relation of the given parents (struct pointers)
+// declared on the named field, and returns the related records (struct
+// pointers).
+func (db *DB) loadRelation(parents []reflect.Value, fieldName string, cache map[string]interface{}) ([]reflect.Value, error) {
+	pending := make([]*Association, 0, len(parents))
+	loaded := make([]*Association, 0, len(parents))
+	for _, parent := range parents {
+		a := db.Association(parent.Interface(), fieldName)
+		if a.error != nil {
+			return nil, a.error
+		}
+		loaded = append(loaded, a)
+		if cached, ok := cache[a.cacheKey()]; ok {
+			a.field.Set(reflect.ValueOf(cached))
+			continue
+		}
+		pending = append(pending, a)
+	}
+
+	if len(pending) > 0 {
+		if pending[0].association.joinTable != "" {
+			for _, a := range pending {
+				if err := a.Load(); err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			loader := &Loader{db: db, recordType: pending[0].targetType, column: pending[0].association.foreignKey}
+			batch := &loaderBatch{done: make(chan struct{})}
+			for _, a := range pending {
+				batch.keys = append(batch.keys, a.ownerKey)
+			}
+			loader.fetch(batch)
+			if batch.err != nil {
+				return nil, batch.err
+			}
+			for _, a := range pending {
+				a.setField(batch.records[treeKey(a.ownerKey)])
+			}
+		}
+		for _, a := range pending {
+			cache[a.cacheKey()] = a.field.Interface()
+		}
+	}
+
+	children := make([]reflect.Value, 0)
+	known := make(map[interface{}]bool)
+	for _, a := range loaded {
+		for i := 0; i < a.field.Len(); i++ {
+			child := a.field.Index(i)
+			if child.Kind() != reflect.Ptr {
+				child = child.Addr()
+			}
+			if !known[child.Interface()] {
+				known[child.Interface()] = true
+				children = append(children, child)
+			}
+		}
+	}
+	return children, nil
+}
+
+// cacheKey returns the key of the relation of the owner in the relation
+// cache of the DB.
+func (a *Association) cacheKey() string {
+	tableName := a.db.defaultTableNamer(a.ownerDescription.getTableName())
+	fieldName := a.ownerDescription.instanceType.FieldByIndex(a.fieldIndex).Name
+	return fmt.Sprintf("%s\x00%s\x00%s", tableName, treeKey(a.ownerKey), fieldName)
+}
+
+// setField sets the field of the owner to the given records (struct
+// pointers).
+func (a *Association) setField(records []reflect.Value) {
+	field := reflect.MakeSlice(a.field.Type(), 0, len(records))
+	for _, record := range records {
+		field = reflect.Append(field, a.fieldElement(record.Interface()))
+	}
+	a.field.Set(field)
+}
+
+// cacheField updates the relation cache of the DB (see LoadRelation) with
+// the field of the owner if it's complete, or if it was already cached.
+func (a *Association) cacheField(complete bool) {
+	if a.db.relationCache == nil {
+		return
+	}
+	key := a.cacheKey()
+	if _, ok := a.db.relationCache[key]; ok || complete {
+		a.db.relationCache[key] = a.field.Interface()
+	}
+}
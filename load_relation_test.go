@@ -0,0 +1,88 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/sqlite"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLoadRelation(t *testing.T) {
+	Convey("Given a test database with authors, books and tags", t, func() {
+		db := associationFixturesSetup(t)
+		defer db.Close()
+		counter := &selectsCounter{}
+		db.SetLogger(counter)
+
+		_, err := db.CurrentDB().Exec(`
+			insert into authors (name) values ("Another");
+			insert into books (author_id, title) values (1, "First"), (2, "Second"), (1, "Third");
+			insert into authors_tags (author_id, tag_id) values (2, 1);
+		`)
+		So(err, ShouldBeNil)
+		authors := make([]*AssociationAuthor, 0)
+		So(db.Select(&authors).OrderBy("id").Do(), ShouldBeNil)
+		counter.count = 0
+
+		Convey("LoadRelation loads a hasmany relation with a single query", func() {
+			So(db.LoadRelation(&authors, "Books"), ShouldBeNil)
+			So(counter.selects(), ShouldEqual, 1)
+			So(len(authors[0].Books), ShouldEqual, 2)
+			So(authors[0].Books[1].Title, ShouldEqual, "Third")
+			So(len(authors[1].Books), ShouldEqual, 1)
+			So(authors[1].Books[0].Title, ShouldEqual, "Second")
+		})
+
+		Convey("LoadRelation loads a manytomany relation", func() {
+			So(db.LoadRelation(authors[1], "Tags"), ShouldBeNil)
+			So(len(authors[1].Tags), ShouldEqual, 1)
+			So(authors[1].Tags[0].Name, ShouldEqual, "Existing")
+		})
+
+		Convey("LoadRelation caches the relations in a transaction", func() {
+			So(db.Begin(), ShouldBeNil)
+			So(db.LoadRelation(&authors, "Books"), ShouldBeNil)
+			So(db.Association(authors[1], "Books").Append(&AssociationBook{Title: "New"}), ShouldBeNil)
+			counter.count = 0
+
+			reloaded := &AssociationAuthor{}
+			So(db.Get(reloaded, 2), ShouldBeNil)
+			So(db.LoadRelation(reloaded, "Books"), ShouldBeNil)
+			So(counter.selects(), ShouldEqual, 1)
+			So(len(reloaded.Books), ShouldEqual, 2)
+
+			So(db.Commit(), ShouldBeNil)
+			counter.count = 0
+			So(db.LoadRelation(reloaded, "Books"), ShouldBeNil)
+			So(counter.selects(), ShouldEqual, 1)
+		})
+
+		Convey("LoadRelation rejects unknown relations", func() {
+			So(db.LoadRelation(&authors, "Unknown"), ShouldNotBeNil)
+			So(db.LoadRelation(&authors, "Invalid"), ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a test database with a hierarchy", t, func() {
+		db := treeFixturesSetup(t, sqlite.Adapter)
+		defer db.Close()
+		root := TreeNode{}
+		So(db.Get(&root, 1), ShouldBeNil)
+
+		Convey("LoadRelation follows the path", func() {
+			So(db.LoadRelation(&root, "Children.Children"), ShouldBeNil)
+			So(len(root.Children), ShouldEqual, 2)
+			So(len(root.Children[0].Children), ShouldEqual, 1)
+			So(root.Children[0].Children[0].Name, ShouldEqual, "a.a")
+			So(root.Children[0].Children[0].Children, ShouldBeNil)
+		})
+
+		Convey("LoadRelation loads a relation once by call", func() {
+			_, err := db.CurrentDB().Exec("update nodes set parent_id = 4 where id = 1")
+			So(err, ShouldBeNil)
+			So(db.LoadRelation(&root, "Children.Children.Children.Children"), ShouldBeNil)
+			So(root.Children[0].Children[0].Children[0].Name, ShouldEqual, "root")
+		})
+	})
+}
@@ -0,0 +1,258 @@
+package godb
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Default settings of a Loader.
+const (
+	defaultLoaderWindow   = 2 * time.Millisecond
+	defaultLoaderMaxBatch = 100
+)
+
+// Loader coalesces the loads of records by key done concurrently into a
+// single query : the keys given during a short window (or until the batch is
+// full) are fetched with an IN clause, and the records are given back to
+// each caller. It avoids the N+1 queries problem when the records are
+// loaded one by one, for example by the resolvers of a GraphQL API.
+//
+// The queries are executed on a clone of the DB, outside its transaction.
+// There is no cache, each Load is part of a query.
+//
+// Example :
+// 	loader := db.NewLoader(&Author{})
+// 	// in several goroutines
+// 	author := Author{}
+// 	err := loader.Load(&author, book.AuthorID)
+type Loader struct {
+	db           *DB
+	recordType   reflect.Type
+	column       string
+	window       time.Duration
+	maxBatch     int
+	mutex        sync.Mutex
+	queryMutex   sync.Mutex
+	currentBatch *loaderBatch
+}
+
+// loaderBatch is a set of keys fetched by a single query.
+type loaderBatch struct {
+	keys    []interface{}
+	full    chan struct{}
+	done    chan struct{}
+	records map[string][]reflect.Value
+	err     error
+}
+
+// NewLoader creates a Loader of the given struct (used only for its type),
+// loading the records by key. The struct must have a single key, unless
+// another column is given with By.
+func (db *DB) NewLoader(record interface{}) *Loader {
+	recordType := reflect.TypeOf(record)
+	for recordType != nil && recordType.Kind() == reflect.Ptr {
+		recordType = recordType.Elem()
+	}
+	return &Loader{
+		db:         db.Clone(),
+		recordType: recordType,
+		window:     defaultLoaderWindow,
+		maxBatch:   defaultLoaderMaxBatch,
+	}
+}
+
+// By sets the column compared to the given keys, instead of the key of the
+// struct. Load all the records having a value with a slice, for example
+// the books of authors by their author_id column.
+func (l *Loader) By(column string) *Loader {
+	l.column = column
+	return l
+}
+
+// Window sets the time waited for others keys once the first key of a batch
+// is given, 2ms by default.
+func (l *Loader) Window(window time.Duration) *Loader {
+	l.window = window
+	return l
+}
+
+// MaxBatch sets the maximum number of keys fetched by a single query, 100 by
+// default.
+func (l *Loader) MaxBatch(maxBatch int) *Loader {
+	l.maxBatch = maxBatch
+	return l
+}
+
+// Load fetches the record having the given key into the given struct
+// pointer, or all the records having it into the given slice pointer (of
+// structs or struct pointers). Load waits for the query of the batch. Like
+// Get, it returns sql.ErrNoRows (or a *NotFoundError, see UseNotFoundError)
+// if a single record is not found.
+func (l *Loader) Load(record interface{}, key interface{}) error {
+	if l.recordType == nil || l.recordType.Kind() != reflect.Struct {
+		return fmt.Errorf("the loader needs a struct, got %v", l.recordType)
+	}
+	recordValue := reflect.ValueOf(record)
+	if recordValue.Kind() != reflect.Ptr || recordValue.IsNil() {
+		return fmt.Errorf("Load accepts only a struct or slice pointer, got %T", record)
+	}
+	target := recordValue.Elem()
+	isSlice := target.Kind() == reflect.Slice
+	elementType := target.Type()
+	if isSlice {
+		elementType = elementType.Elem()
+		if elementType.Kind() == reflect.Ptr {
+			elementType = elementType.Elem()
+		}
+	}
+	if elementType != l.recordType {
+		return fmt.Errorf("the loader fetches %s records, got %T", l.recordType.Name(), record)
+	}
+
+	batch, first := l.add(key)
+	if first {
+		l.wait(batch)
+		l.fetch(batch)
+	}
+	<-batch.done
+	if batch.err != nil {
+		return batch.err
+	}
+
+	found := batch.records[treeKey(key)]
+	if !isSlice {
+		if len(found) == 0 {
+			if l.db.useNotFoundError {
+				return &NotFoundError{Table: l.tableName(), Criteria: l.columnName() + " = ?", Arguments: []interface{}{key}}
+			}
+			return sql.ErrNoRows
+		}
+		target.Set(found[0].Elem())
+		return nil
+	}
+
+	slice := reflect.MakeSlice(target.Type(), 0, len(found))
+	for _, instance := range found {
+		if target.Type().Elem().Kind() == reflect.Ptr {
+			instanceCopy := reflect.New(l.recordType)
+			instanceCopy.Elem().Set(instance.Elem())
+			slice = reflect.Append(slice, instanceCopy)
+		} else {
+			slice = reflect.Append(slice, instance.Elem())
+		}
+	}
+	target.Set(slice)
+	return nil
+}
+
+// add adds the given key to the current batch, starting a new one if needed.
+// It returns true if the batch is new.
+func (l *Loader) add(key interface{}) (*loaderBatch, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	first := false
+	if l.currentBatch == nil {
+		l.currentBatch = &loaderBatch{
+			full: make(chan struct{}),
+			done: make(chan struct{}),
+		}
+		first = true
+	}
+	batch := l.currentBatch
+	batch.keys = append(batch.keys, key)
+	if l.maxBatch > 0 && len(batch.keys) >= l.maxBatch {
+		l.currentBatch = nil
+		close(batch.full)
+	}
+	return batch, first
+}
+
+// wait waits for the end of the window or for a full batch, then closes the
+// batch.
+func (l *Loader) wait(batch *loaderBatch) {
+	timer := time.NewTimer(l.window)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-batch.full:
+	}
+
+	l.mutex.Lock()
+	if l.currentBatch == batch {
+		l.currentBatch = nil
+	}
+	l.mutex.Unlock()
+}
+
+// fetch executes the query of the given batch, and groups the records by
+// key.
+func (l *Loader) fetch(batch *loaderBatch) {
+	defer close(batch.done)
+	l.queryMutex.Lock()
+	defer l.queryMutex.Unlock()
+
+	keys := make([]interface{}, 0, len(batch.keys))
+	known := make(map[string]bool)
+	for _, key := range batch.keys {
+		if !known[treeKey(key)] {
+			known[treeKey(key)] = true
+			keys = append(keys, key)
+		}
+	}
+
+	records := reflect.New(reflect.SliceOf(reflect.PtrTo(l.recordType)))
+	ss := l.db.Select(records.Interface())
+	if ss.error != nil {
+		batch.err = ss.error
+		return
+	}
+	column := l.columnName()
+	if column == "" {
+		batch.err = fmt.Errorf("the struct %s must have a single key, or the column has to be given with By", l.recordType.Name())
+		return
+	}
+	quotedColumn := l.db.quoteFor(ss.recordDescription, column)
+	if batch.err = ss.WhereQ(Q(quotedColumn+" IN (?)", keys)).Do(); batch.err != nil {
+		return
+	}
+
+	batch.records = make(map[string][]reflect.Value)
+	for _, instance := range sliceElements(records.Elem()) {
+		pointers, err := ss.recordDescription.structMapping.GetPointersForColumns(instance.Interface(), column)
+		if err != nil {
+			batch.err = err
+			return
+		}
+		key := treeKey(reflect.ValueOf(pointers[0]).Elem().Interface())
+		batch.records[key] = append(batch.records[key], instance)
+	}
+}
+
+// columnName returns the column compared to the keys.
+func (l *Loader) columnName() string {
+	if l.column != "" {
+		return l.column
+	}
+	recordDescription, err := buildRecordDescription(reflect.New(l.recordType).Interface())
+	if err != nil {
+		return ""
+	}
+	keyColumns := recordDescription.structMapping.GetKeyColumnsNames()
+	if len(keyColumns) != 1 {
+		return ""
+	}
+	return keyColumns[0]
+}
+
+// tableName returns the table of the loaded records.
+func (l *Loader) tableName() string {
+	recordDescription, err := buildRecordDescription(reflect.New(l.recordType).Interface())
+	if err != nil {
+		return ""
+	}
+	return l.db.defaultTableNamer(recordDescription.getTableName())
+}
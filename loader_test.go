@@ -0,0 +1,97 @@
+package godb
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// selectsCounter counts the logged SELECT statements.
+type selectsCounter struct {
+	mutex sync.Mutex
+	count int
+}
+
+func (c *selectsCounter) Println(v ...interface{}) {
+	if strings.Contains(fmt.Sprint(v...), "SELECT") {
+		c.mutex.Lock()
+		c.count++
+		c.mutex.Unlock()
+	}
+}
+
+func (c *selectsCounter) selects() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.count
+}
+
+func TestLoader(t *testing.T) {
+	Convey("Given a test database and a loader", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		db.sqlDB.SetMaxOpenConns(1)
+		counter := &selectsCounter{}
+		db.SetLogger(counter)
+		loader := db.NewLoader(&Dummy{}).Window(50 * time.Millisecond)
+
+		loadConcurrently := func(keys ...interface{}) ([]Dummy, []error) {
+			dummies := make([]Dummy, len(keys))
+			errs := make([]error, len(keys))
+			var wg sync.WaitGroup
+			for i := range keys {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					errs[i] = loader.Load(&dummies[i], keys[i])
+				}(i)
+			}
+			wg.Wait()
+			return dummies, errs
+		}
+
+		Convey("The concurrent loads are fetched by a single query", func() {
+			dummies, errs := loadConcurrently(1, 2, 3, 2)
+			So(errs, ShouldResemble, []error{nil, nil, nil, nil})
+			So(dummies[0].AText, ShouldEqual, "First")
+			So(dummies[1].AText, ShouldEqual, "Second")
+			So(dummies[2].AText, ShouldEqual, "Third")
+			So(dummies[3].AText, ShouldEqual, "Second")
+			So(counter.selects(), ShouldEqual, 1)
+		})
+
+		Convey("A full batch is fetched without waiting", func() {
+			loader.MaxBatch(2).Window(time.Hour)
+			_, errs := loadConcurrently(1, 2)
+			So(errs, ShouldResemble, []error{nil, nil})
+			So(counter.selects(), ShouldEqual, 1)
+		})
+
+		Convey("A missing record is not found", func() {
+			_, errs := loadConcurrently(1, 42)
+			So(errs[0], ShouldBeNil)
+			So(errs[1], ShouldEqual, sql.ErrNoRows)
+		})
+
+		Convey("All the records having a value are loaded in a slice", func() {
+			relatedLoader := db.NewLoader(&RelatedToDummy{}).By("dummies_id")
+			related := make([]*RelatedToDummy, 0)
+			So(relatedLoader.Load(&related, 2), ShouldBeNil)
+			So(len(related), ShouldEqual, 1)
+			So(related[0].AText, ShouldEqual, "REL_Second")
+
+			So(relatedLoader.Load(&related, 42), ShouldBeNil)
+			So(related, ShouldBeEmpty)
+		})
+
+		Convey("Load checks the type of the record", func() {
+			So(loader.Load(&RelatedToDummy{}, 1), ShouldNotBeNil)
+			So(loader.Load(Dummy{}, 1), ShouldNotBeNil)
+		})
+	})
+}
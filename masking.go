@@ -0,0 +1,101 @@
+package godb
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// maskers are the functions masking the values of the fields, by name (see
+// RegisterMasker).
+var maskers = struct {
+	sync.RWMutex
+	byName map[string]func(value string) string
+}{byName: map[string]func(value string) string{
+	"email":  maskEmail,
+	"redact": maskAll,
+	"last4":  maskAllButLast4,
+}}
+
+// UseMasking enables or disables the masking mode of the DB. Once enabled,
+// the values of the fields having a mask option in their db tag are replaced
+// at scan time by the struct tools (Select, Get, RawSQL, iterators, ...),
+// so that the real values are never seen, for example by a staging
+// environment or a support tool. The clones get the masking mode of the DB
+// when they are created, a later change doesn't affect them.
+//
+// The option gives the name of the masker :
+// 	* email : keeps the first letter and the domain (j*******@example.com)
+// 	* redact : replaces all the characters with *
+// 	* last4 : replaces all the characters with *, but the last four
+// Others maskers can be added with RegisterMasker.
+//
+// The masked fields are strings, string pointers or sql.NullString, the
+// empty and null values are kept. The values scanned with Scanx or into
+// anything else than a struct aren't masked.
+//
+// Example :
+// 	type User struct {
+// 		ID    int    `db:"id,key,auto"`
+// 		Email string `db:"email,mask=email"`
+// 	}
+//
+// 	db.UseMasking(os.Getenv("APP_ENV") != "production")
+func (db *DB) UseMasking(enabled bool) {
+	db.masking = enabled
+}
+
+// RegisterMasker registers a function masking the values of the fields
+// having the given name as mask option, replacing any masker of the same
+// name. The function is called for each masked value, it has to be safe for
+// concurrent use.
+func RegisterMasker(name string, masker func(value string) string) {
+	maskers.Lock()
+	defer maskers.Unlock()
+	maskers.byName[name] = masker
+}
+
+// mask masks the fields of the given record read from the given columns,
+// if the masking mode is enabled.
+func (db *DB) mask(recordDescription *recordDescription, record interface{}, columns []string) error {
+	if !db.masking {
+		return nil
+	}
+	return recordDescription.structMapping.MaskFieldsValues(record, columns, func(name string, value string) (string, error) {
+		maskers.RLock()
+		masker, ok := maskers.byName[name]
+		maskers.RUnlock()
+		if !ok {
+			return "", fmt.Errorf("unknown masker %s", name)
+		}
+		return masker(value), nil
+	})
+}
+
+// maskEmail keeps the first letter of the local part and the domain of the
+// given email address, the others letters are replaced with *. The value is
+// fully masked if it's not an email address.
+func maskEmail(value string) string {
+	at := strings.LastIndex(value, "@")
+	if at < 1 {
+		return maskAll(value)
+	}
+	first, size := utf8.DecodeRuneInString(value)
+	return string(first) + maskAll(value[size:at]) + value[at:]
+}
+
+// maskAll replaces all the characters of the given value with *.
+func maskAll(value string) string {
+	return strings.Repeat("*", utf8.RuneCountInString(value))
+}
+
+// maskAllButLast4 replaces all the characters of the given value with *, but
+// the last four.
+func maskAllButLast4(value string) string {
+	runes := []rune(value)
+	if len(runes) <= 4 {
+		return maskAll(value)
+	}
+	return maskAll(string(runes[:len(runes)-4])) + string(runes[len(runes)-4:])
+}
@@ -0,0 +1,89 @@
+package godb
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type MaskedDummy struct {
+	ID              int            `db:"id,key,auto"`
+	AText           string         `db:"a_text,mask=redact"`
+	AnotherText     *string        `db:"another_text,mask=upper"`
+	AnInteger       int            `db:"an_integer"`
+	ANullableString sql.NullString `db:"a_nullable_string,mask=last4"`
+	Version         int            `db:"version,oplock"`
+}
+
+func (*MaskedDummy) TableName() string {
+	return "dummies"
+}
+
+func TestMasking(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		RegisterMasker("upper", strings.ToUpper)
+
+		Convey("The fields aren't masked by default", func() {
+			dummy := MaskedDummy{}
+			So(db.Get(&dummy, 1), ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "First")
+		})
+
+		Convey("The masked fields are masked at scan time", func() {
+			db.UseMasking(true)
+			dummies := make([]MaskedDummy, 0)
+			So(db.Select(&dummies).OrderBy("id").Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+			So(dummies[0].AText, ShouldEqual, "*****")
+			So(*dummies[0].AnotherText, ShouldEqual, "PREMIER")
+			So(dummies[0].AnInteger, ShouldEqual, 11)
+			So(dummies[0].ANullableString.String, ShouldEqual, "*****mpty")
+
+			dummy := MaskedDummy{}
+			So(db.RawSQL("select id, a_text from dummies where id = ?", 1).Do(&dummy), ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "*****")
+		})
+
+		Convey("The masking mode applies to the iterators and the clones", func() {
+			db.UseMasking(true)
+			iter, err := db.Clone().Select(&MaskedDummy{}).DoWithIterator()
+			So(err, ShouldBeNil)
+			defer iter.Close()
+			So(iter.Next(), ShouldBeTrue)
+			dummy := MaskedDummy{}
+			So(iter.Scan(&dummy), ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "*****")
+		})
+
+		Convey("The inserted records aren't masked", func() {
+			db.UseMasking(true)
+			anotherText := "Quatrième"
+			dummy := MaskedDummy{AText: "Fourth", AnotherText: &anotherText}
+			So(db.Insert(&dummy).Do(), ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "Fourth")
+		})
+
+		Convey("An unknown masker gives an error", func() {
+			type unknownMasker struct {
+				ID    int    `db:"id,key,auto"`
+				AText string `db:"a_text,mask=unknown"`
+			}
+			db.UseMasking(true)
+			So(db.RawSQL("select id, a_text from dummies").Do(&[]unknownMasker{}), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestMaskers(t *testing.T) {
+	Convey("The maskers hide the values", t, func() {
+		So(maskEmail("john.doe@example.com"), ShouldEqual, "j*******@example.com")
+		So(maskEmail("not an email"), ShouldEqual, "************")
+		So(maskAll("été"), ShouldEqual, "***")
+		So(maskAllButLast4("4111111111111111"), ShouldEqual, "************1111")
+		So(maskAllButLast4("123"), ShouldEqual, "***")
+	})
+}
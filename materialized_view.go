@@ -0,0 +1,52 @@
+package godb
+
+import (
+	"fmt"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// MaterializedView marks a struct mapped to a materialized view. Embed it in
+// the struct : like a ReadOnlyModel it can't be written, and it's refreshed
+// with RefreshMaterializedViewOf.
+//
+// Example :
+// 	type AuthorStats struct {
+// 		godb.MaterializedView
+// 		Author string `db:"author"`
+// 		Books  int    `db:"books"`
+// 	}
+//
+// 	err := db.RefreshMaterializedViewOf(&AuthorStats{}, true)
+type MaterializedView struct {
+	ReadOnlyModel
+}
+
+// RefreshMaterializedView refreshes the materialized view having the given
+// name. A concurrent refresh doesn't lock the reads of the view, with
+// PostgreSQL it needs a unique index on the view.
+func (db *DB) RefreshMaterializedView(name string, concurrently bool) error {
+	return db.refreshMaterializedView(db.quote(name), concurrently)
+}
+
+// RefreshMaterializedViewOf refreshes the materialized view of the given
+// struct (used only for its table name), see RefreshMaterializedView.
+func (db *DB) RefreshMaterializedViewOf(record interface{}, concurrently bool) error {
+	recordDescription, err := buildRecordDescription(record)
+	if err != nil {
+		return err
+	}
+	quotedName := db.quoteFor(recordDescription, db.defaultTableNamer(recordDescription.getTableName()))
+	return db.refreshMaterializedView(quotedName, concurrently)
+}
+
+// refreshMaterializedView refreshes the materialized view having the given
+// quoted name.
+func (db *DB) refreshMaterializedView(quotedName string, concurrently bool) error {
+	refresher, ok := db.adapter.(adapters.MaterializedViewRefresher)
+	if !ok {
+		return fmt.Errorf("the adapter does not support materialized views")
+	}
+	_, _, err := db.RawSQL(refresher.BuildRefreshMaterializedView(quotedName, concurrently)).DoExec()
+	return err
+}
@@ -0,0 +1,34 @@
+package godb
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type DummyStats struct {
+	MaterializedView
+	Count int `db:"count"`
+}
+
+func (*DummyStats) TableName() string {
+	return "dummystats"
+}
+
+func TestMaterializedView(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("The refresh is not supported by the SQLite adapter", func() {
+			So(db.RefreshMaterializedView("dummystats", false), ShouldNotBeNil)
+			So(db.RefreshMaterializedViewOf(&DummyStats{}, false), ShouldNotBeNil)
+		})
+
+		Convey("A materialized view is read only", func() {
+			err := db.Insert(&DummyStats{Count: 1}).Do()
+			So(errors.Is(err, ErrReadOnlyModel), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,53 @@
+package godb
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMaxRows(t *testing.T) {
+	Convey("Given a test database with a maximum of rows", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		db.SetMaxRows(2)
+
+		Convey("A select exceeding the maximum returns an error", func() {
+			dummies := make([]Dummy, 0)
+			err := db.Select(&dummies).Do()
+			So(errors.Is(err, ErrTooManyRows), ShouldBeTrue)
+			tooManyRows := &TooManyRowsError{}
+			So(errors.As(err, &tooManyRows), ShouldBeTrue)
+			So(tooManyRows.MaxRows, ShouldEqual, 2)
+
+			dummies = make([]Dummy, 0)
+			err = db.RawSQL("select * from dummies").Do(&dummies)
+			So(errors.Is(err, ErrTooManyRows), ShouldBeTrue)
+		})
+
+		Convey("A select within the maximum succeeds", func() {
+			dummies := make([]Dummy, 0)
+			err := db.Select(&dummies).Where("id <= ?", 2).Do()
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 2)
+		})
+
+		Convey("Statements could override the maximum", func() {
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).MaxRows(-1).Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+
+			dummies = make([]Dummy, 0)
+			err := db.SelectFrom("dummies").
+				Columns("id", "a_text", "another_text", "an_integer", "a_nullable_string", "version").
+				MaxRows(1).
+				Do(&dummies)
+			So(errors.Is(err, ErrTooManyRows), ShouldBeTrue)
+
+			dummies = make([]Dummy, 0)
+			So(db.RawSQL("select * from dummies").MaxRows(3).Do(&dummies), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+		})
+	})
+}
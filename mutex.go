@@ -0,0 +1,273 @@
+package godb
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// lockRetryInterval is the time between two attempts of Mutex.Lock.
+const lockRetryInterval = 100 * time.Millisecond
+
+// lockTableName is the table of the locks for the adapters without named
+// locks (SQLite), created if needed.
+const lockTableName = "godb_locks"
+
+// lockTableTTL is the time after which a row of the locks table which wasn't
+// renewed is considered abandoned, and can be taken over by another process.
+// The rows are renewed every third of it while locked.
+var lockTableTTL = 30 * time.Second
+
+// Mutex is a named lock shared by all the processes using the same database,
+// allowing portable singletons (cron-style jobs, ...). Create it with
+// DB.Mutex.
+//
+// It uses advisory locks with PostgreSQL, GET_LOCK with MySQL, and
+// sp_getapplock with SQL Server. The lock is owned by a connection taken from
+// the pool until Unlock, and it's released by the database if the connection
+// is lost. With SQLite the locks are rows of the godb_locks table, renewed
+// in background while locked : the lock of a crashed process is released
+// after 30 seconds without renewal.
+type Mutex struct {
+	db    *DB
+	name  string
+	mutex sync.Mutex
+	// conn owns the named lock while locked
+	conn *sql.Conn
+	// owner identifies the row of the locks table while locked
+	owner string
+	// stopRenewal stops the renewal of the row of the locks table
+	stopRenewal chan struct{}
+}
+
+// Mutex returns a Mutex having the given name. The Mutex instances with the
+// same name exclude each others, in all processes.
+//
+// Example :
+// 	mutex := db.Mutex("daily-report")
+// 	locked, err := mutex.TryLock()
+// 	if err == nil && locked {
+// 		defer mutex.Unlock()
+// 		// ...
+// 	}
+func (db *DB) Mutex(name string) *Mutex {
+	return &Mutex{db: db, name: name}
+}
+
+// TryLock tries to acquire the lock without waiting, and returns true if it
+// is acquired.
+func (m *Mutex) TryLock() (bool, error) {
+	return m.tryLock(m.db.Context())
+}
+
+// Lock waits until the lock is acquired, or the context is done.
+func (m *Mutex) Lock(ctx context.Context) error {
+	for {
+		locked, err := m.tryLock(ctx)
+		if err != nil || locked {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+// Unlock releases the lock, it fails if the lock is not acquired.
+func (m *Mutex) Unlock() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	ctx := m.db.Context()
+	if m.conn != nil {
+		builder := m.db.adapter.(adapters.NamedLockBuilder)
+		_, err := m.exec(ctx, m.conn, builder.BuildUnlock(), m.name)
+		closeErr := m.conn.Close()
+		m.conn = nil
+		if err != nil {
+			return err
+		}
+		return closeErr
+	}
+
+	if m.owner != "" {
+		close(m.stopRenewal)
+		query := "DELETE FROM " + m.db.quote(lockTableName) + " WHERE name = ? AND owner = ?"
+		_, err := m.exec(ctx, m.db.sqlDB, query, m.name, m.owner)
+		m.owner = ""
+		m.stopRenewal = nil
+		return err
+	}
+
+	return fmt.Errorf("the mutex %s is not locked", m.name)
+}
+
+// check returns true if the lock is still acquired : the connection owning
+// the named lock is alive, or the row of the locks table is still there (its
+// time is then renewed).
+func (m *Mutex) check(ctx context.Context) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.conn != nil {
+		if err := m.conn.PingContext(ctx); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if m.owner != "" {
+		query := "UPDATE " + m.db.quote(lockTableName) + " SET locked_at = ? WHERE name = ? AND owner = ?"
+		result, err := m.exec(ctx, m.db.sqlDB, query, time.Now().UTC(), m.name, m.owner)
+		if err != nil {
+			return false, err
+		}
+		count, err := result.RowsAffected()
+		return count == 1, err
+	}
+
+	return false, nil
+}
+
+// tryLock executes TryLock with the given context.
+func (m *Mutex) tryLock(ctx context.Context) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.conn != nil || m.owner != "" {
+		return false, fmt.Errorf("the mutex %s is already locked by this instance", m.name)
+	}
+
+	if builder, ok := m.db.adapter.(adapters.NamedLockBuilder); ok {
+		return m.tryNamedLock(ctx, builder)
+	}
+	return m.tryTableLock(ctx)
+}
+
+// tryNamedLock tries to acquire a named lock of the database, on a dedicated
+// connection kept while the lock is acquired.
+func (m *Mutex) tryNamedLock(ctx context.Context, builder adapters.NamedLockBuilder) (bool, error) {
+	conn, err := m.db.sqlDB.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	query := m.db.replacePlaceholders(builder.BuildTryLock())
+	startTime := time.Now()
+	var result interface{}
+	err = conn.QueryRowContext(ctx, query, m.name).Scan(&result)
+	consumedTime := timeElapsedSince(startTime)
+	m.db.addConsumedTime(consumedTime)
+	m.db.logExecution(consumedTime, query, m.name)
+	if err != nil {
+		m.db.logExecutionErr(err, query, m.name)
+		conn.Close()
+		return false, err
+	}
+
+	if !isLockAcquired(result) {
+		return false, conn.Close()
+	}
+	m.conn = conn
+	return true, nil
+}
+
+// isLockAcquired returns true if the result of a lock statement is true or
+// 1.
+func isLockAcquired(result interface{}) bool {
+	switch r := result.(type) {
+	case bool:
+		return r
+	case int64:
+		return r == 1
+	case []byte:
+		return string(r) == "1" || string(r) == "t" || string(r) == "true"
+	}
+	return false
+}
+
+// tryTableLock tries to acquire the lock by adding a row in the locks table,
+// after the removal of its abandoned row if any (see lockTableTTL).
+func (m *Mutex) tryTableLock(ctx context.Context) (bool, error) {
+	table := m.db.quote(lockTableName)
+	create := "CREATE TABLE IF NOT EXISTS " + table + " (" +
+		"name VARCHAR(255) NOT NULL PRIMARY KEY, " +
+		"owner VARCHAR(32) NOT NULL, " +
+		"locked_at TIMESTAMP NOT NULL)"
+	if _, err := m.exec(ctx, m.db.sqlDB, create); err != nil {
+		return false, err
+	}
+
+	ownerBytes := make([]byte, 16)
+	if _, err := rand.Read(ownerBytes); err != nil {
+		return false, err
+	}
+	owner := hex.EncodeToString(ownerBytes)
+
+	now := time.Now().UTC()
+	expire := "DELETE FROM " + table + " WHERE name = ? AND locked_at < ?"
+	if _, err := m.exec(ctx, m.db.sqlDB, expire, m.name, now.Add(-lockTableTTL)); err != nil {
+		return false, err
+	}
+
+	insert := "INSERT INTO " + table + " (name, owner, locked_at) " +
+		"SELECT ?, ?, ? WHERE NOT EXISTS (SELECT 1 FROM " + table + " WHERE name = ?)"
+	result, err := m.exec(ctx, m.db.sqlDB, insert, m.name, owner, now, m.name)
+	if err != nil {
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil || count != 1 {
+		return false, err
+	}
+	m.owner = owner
+	m.stopRenewal = make(chan struct{})
+	go m.renewTableLock(lockTableTTL/3, m.stopRenewal)
+	return true, nil
+}
+
+// renewTableLock renews the row of the locks table at the given interval,
+// until the given channel is closed or the lock is lost.
+func (m *Mutex) renewTableLock(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if held, err := m.check(m.db.Context()); err != nil || !held {
+				m.db.logPrintln("Lock lost :", m.name, err)
+				return
+			}
+		}
+	}
+}
+
+// execerContext is a *sql.DB or a *sql.Conn.
+type execerContext interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// exec executes a statement of the mutex, outside of the current transaction.
+func (m *Mutex) exec(ctx context.Context, execer execerContext, query string, args ...interface{}) (sql.Result, error) {
+	query = m.db.replacePlaceholders(query)
+	startTime := time.Now()
+	result, err := execer.ExecContext(ctx, query, args...)
+	consumedTime := timeElapsedSince(startTime)
+	m.db.addConsumedTime(consumedTime)
+	m.db.logExecution(consumedTime, query, args)
+	if err != nil {
+		m.db.logExecutionErr(err, query, args)
+	}
+	return result, err
+}
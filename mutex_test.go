@@ -0,0 +1,108 @@
+package godb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMutex(t *testing.T) {
+	Convey("Given two mutexes with the same name", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		first := db.Mutex("job")
+		second := db.Mutex("job")
+
+		Convey("Only one can be locked at a time", func() {
+			locked, err := first.TryLock()
+			So(err, ShouldBeNil)
+			So(locked, ShouldBeTrue)
+
+			locked, err = second.TryLock()
+			So(err, ShouldBeNil)
+			So(locked, ShouldBeFalse)
+
+			locked, err = db.Mutex("other").TryLock()
+			So(err, ShouldBeNil)
+			So(locked, ShouldBeTrue)
+
+			So(first.Unlock(), ShouldBeNil)
+			locked, err = second.TryLock()
+			So(err, ShouldBeNil)
+			So(locked, ShouldBeTrue)
+			So(second.Unlock(), ShouldBeNil)
+		})
+
+		Convey("Lock waits until the context is done", func() {
+			So(first.Lock(context.Background()), ShouldBeNil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			So(errors.Is(second.Lock(ctx), context.DeadlineExceeded), ShouldBeTrue)
+
+			So(first.Unlock(), ShouldBeNil)
+			So(second.Lock(context.Background()), ShouldBeNil)
+			So(second.Unlock(), ShouldBeNil)
+		})
+
+		Convey("An abandoned lock of the locks table is taken over", func() {
+			locked, err := first.TryLock()
+			So(err, ShouldBeNil)
+			So(locked, ShouldBeTrue)
+
+			_, err = db.sqlDB.Exec("update godb_locks set locked_at = ?", time.Now().Add(-2*lockTableTTL).UTC())
+			So(err, ShouldBeNil)
+			locked, err = second.TryLock()
+			So(err, ShouldBeNil)
+			So(locked, ShouldBeTrue)
+
+			held, err := first.check(context.Background())
+			So(err, ShouldBeNil)
+			So(held, ShouldBeFalse)
+			So(first.Unlock(), ShouldBeNil)
+			So(second.Unlock(), ShouldBeNil)
+		})
+
+		Convey("The lock of the locks table is renewed while locked", func() {
+			previousTTL := lockTableTTL
+			lockTableTTL = 30 * time.Millisecond
+			defer func() { lockTableTTL = previousTTL }()
+
+			locked, err := first.TryLock()
+			So(err, ShouldBeNil)
+			So(locked, ShouldBeTrue)
+			time.Sleep(3 * lockTableTTL)
+			locked, err = second.TryLock()
+			So(err, ShouldBeNil)
+			So(locked, ShouldBeFalse)
+			So(first.Unlock(), ShouldBeNil)
+		})
+
+		Convey("Unlock fails if the mutex is not locked", func() {
+			So(first.Unlock(), ShouldNotBeNil)
+		})
+
+		Convey("TryLock fails if the mutex is already locked", func() {
+			_, err := first.TryLock()
+			So(err, ShouldBeNil)
+			_, err = first.TryLock()
+			So(err, ShouldNotBeNil)
+			So(first.Unlock(), ShouldBeNil)
+		})
+	})
+}
+
+func TestIsLockAcquired(t *testing.T) {
+	Convey("isLockAcquired understands the results of the adapters", t, func() {
+		So(isLockAcquired(true), ShouldBeTrue)
+		So(isLockAcquired(false), ShouldBeFalse)
+		So(isLockAcquired(int64(1)), ShouldBeTrue)
+		So(isLockAcquired(int64(0)), ShouldBeFalse)
+		So(isLockAcquired([]byte("1")), ShouldBeTrue)
+		So(isLockAcquired(nil), ShouldBeFalse)
+	})
+}
@@ -0,0 +1,165 @@
+// Package outbox implements the transactional outbox pattern : the events
+// are written in an outbox table in the same transaction as the business
+// changes, then a poller hands them to a callback (publishing them to a
+// broker, ...) and marks them dispatched. An event is then published if and
+// only if the changes are committed (at least once).
+//
+// The outbox table is not created by the package, it needs the columns :
+// 	id            : an auto incremented primary key
+// 	topic         : the event topic (VARCHAR)
+// 	payload       : the event payload (BLOB, BYTEA, ...)
+// 	created_at    : the time the event was written
+// 	dispatched_at : the time the event was dispatched, NULL before (indexed)
+//
+// Example :
+// 	events := outbox.New("outbox")
+// 	err := db.Begin()
+// 	err = db.Insert(&order).Do()
+// 	err = events.WriteJSON(db, "order.created", order)
+// 	err = db.Commit()
+//
+// 	// In the poller goroutine
+// 	err := events.Poll(ctx, db.Clone(), 100, time.Second, func(messages []outbox.Message) error {
+// 		return publish(messages)
+// 	})
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/samonzeweb/godb"
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// Message is an event read from the outbox table.
+type Message struct {
+	ID        int64     `db:"id"`
+	Topic     string    `db:"topic"`
+	Payload   []byte    `db:"payload"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Handler receives the batches of messages to dispatch. If it returns an
+// error the messages are not marked dispatched, and will be given again.
+type Handler func(messages []Message) error
+
+// Outbox writes and dispatches the events of an outbox table.
+type Outbox struct {
+	table string
+}
+
+// New creates an Outbox using the given table.
+func New(table string) *Outbox {
+	return &Outbox{table: table}
+}
+
+// Write writes an event in the outbox table. It has to be called inside the
+// transaction of the business changes, it fails without transaction.
+func (o *Outbox) Write(db *godb.DB, topic string, payload []byte) error {
+	if db.CurrentTx() == nil {
+		return fmt.Errorf("the outbox events have to be written inside a transaction")
+	}
+	_, err := db.InsertInto(o.table).
+		Columns("topic", "payload", "created_at").
+		Values(topic, payload, time.Now()).
+		Do()
+	return err
+}
+
+// WriteJSON writes an event with the JSON encoding of the given value as
+// payload, see Write.
+func (o *Outbox) WriteJSON(db *godb.DB, topic string, value interface{}) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return o.Write(db, topic, payload)
+}
+
+// Dispatch gives the oldest batch of messages not dispatched (at most
+// batchSize) to the handler, and marks them dispatched, in a transaction. It
+// returns the count of dispatched messages (0 when there is nothing to do).
+//
+// With PostgreSQL and MySQL the messages are locked with FOR UPDATE SKIP
+// LOCKED, several pollers can run concurrently, with distinct DB instances.
+// Otherwise run a single poller.
+func (o *Outbox) Dispatch(db *godb.DB, batchSize int, handler Handler) (int, error) {
+	if err := db.Begin(); err != nil {
+		return 0, err
+	}
+
+	count, err := o.dispatch(db, batchSize, handler)
+	if err != nil {
+		db.Rollback()
+		return 0, err
+	}
+	if err := db.Commit(); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// dispatch executes Dispatch inside a transaction.
+func (o *Outbox) dispatch(db *godb.DB, batchSize int, handler Handler) (int, error) {
+	q := db.SelectFrom(o.table).
+		Columns("id", "topic", "payload", "created_at").
+		Where("dispatched_at IS NULL").
+		OrderBy("id").
+		Limit(batchSize)
+	if _, ok := db.Adapter().(adapters.RowLockBuilder); ok {
+		q.ForUpdateSkipLocked()
+	}
+
+	messages := make([]Message, 0, batchSize)
+	if err := q.Do(&messages); err != nil {
+		return 0, err
+	}
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	if err := handler(messages); err != nil {
+		return 0, err
+	}
+
+	ids := make([]interface{}, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+	_, err := db.UpdateTable(o.table).
+		Set("dispatched_at", time.Now()).
+		WhereQ(godb.Col("id").In(ids...)).
+		Do()
+	if err != nil {
+		return 0, err
+	}
+	return len(messages), nil
+}
+
+// Poll calls Dispatch until the context is done, waiting the given interval
+// when there is no more message to dispatch. It returns the context error,
+// or the first error of Dispatch (the handler errors included).
+func (o *Outbox) Poll(ctx context.Context, db *godb.DB, batchSize int, interval time.Duration, handler Handler) error {
+	for {
+		count, err := o.Dispatch(db, batchSize, handler)
+		if err != nil {
+			return err
+		}
+		if count == batchSize {
+			// There is probably more to do
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
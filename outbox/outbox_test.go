@@ -0,0 +1,90 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samonzeweb/godb"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOutbox(t *testing.T) {
+	Convey("Given a test database with an outbox", t, func() {
+		db, err := godb.Open(sqlite.Adapter, ":memory:")
+		So(err, ShouldBeNil)
+		defer db.Close()
+		_, err = db.CurrentDB().Exec(`
+			create table outbox (
+				id            integer not null primary key autoincrement,
+				topic         text not null,
+				payload       blob not null,
+				created_at    timestamp not null,
+				dispatched_at timestamp);
+		`)
+		So(err, ShouldBeNil)
+
+		events := New("outbox")
+
+		Convey("Write needs a transaction", func() {
+			err := events.Write(db, "order.created", []byte("{}"))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Given written events", func() {
+			So(db.Begin(), ShouldBeNil)
+			So(events.Write(db, "order.created", []byte(`{"id":1}`)), ShouldBeNil)
+			So(events.WriteJSON(db, "order.paid", map[string]int{"id": 1}), ShouldBeNil)
+			So(events.WriteJSON(db, "order.shipped", map[string]int{"id": 1}), ShouldBeNil)
+			So(db.Commit(), ShouldBeNil)
+
+			Convey("Dispatch hands the batches in order and marks them dispatched", func() {
+				topics := make([]string, 0)
+				handler := func(messages []Message) error {
+					for _, message := range messages {
+						topics = append(topics, message.Topic)
+					}
+					return nil
+				}
+
+				count, err := events.Dispatch(db, 2, handler)
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 2)
+				count, err = events.Dispatch(db, 2, handler)
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 1)
+				count, err = events.Dispatch(db, 2, handler)
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 0)
+				So(topics, ShouldResemble, []string{"order.created", "order.paid", "order.shipped"})
+			})
+
+			Convey("Dispatch keeps the messages if the handler fails", func() {
+				failure := errors.New("broker unavailable")
+				_, err := events.Dispatch(db, 10, func([]Message) error { return failure })
+				So(err, ShouldEqual, failure)
+
+				count, err := db.SelectFrom("outbox").Where("dispatched_at IS NULL").Count()
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 3)
+			})
+
+			Convey("Poll dispatches until the context is done", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				dispatched := 0
+				err := events.Poll(ctx, db, 2, time.Millisecond, func(messages []Message) error {
+					dispatched += len(messages)
+					if dispatched == 3 {
+						cancel()
+					}
+					return nil
+				})
+				So(err, ShouldEqual, context.Canceled)
+				So(dispatched, ShouldEqual, 3)
+			})
+		})
+	})
+}
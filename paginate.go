@@ -0,0 +1,93 @@
+package godb
+
+import "fmt"
+
+// Page is a page of records returned by Paginate, ready to be serialized
+// for JSON APIs.
+type Page struct {
+	// Items is the slice given to Select (a pointer to the slice)
+	Items      interface{} `json:"items"`
+	Total      int64       `json:"total"`
+	Page       int         `json:"page"`
+	PerPage    int         `json:"per_page"`
+	TotalPages int         `json:"total_pages"`
+}
+
+// totalColumnAlias is the alias of the COUNT(*) OVER() column added by
+// PaginateWithWindowCount.
+const totalColumnAlias = "godb_total_count"
+
+// Paginate fetches the given page (starting at 1) of records, with perPage
+// records per page, and counts the total of matching records. It runs two
+// queries : the count and the data. The record given to Select has to be a
+// slice, use OrderBy to get consistent pages.
+//
+// Example :
+// 	books := make([]Book, 0)
+// 	page, err := db.Select(&books).OrderBy("title").Paginate(2, 20)
+func (ss *StructSelect) Paginate(page int, perPage int) (*Page, error) {
+	return ss.paginate(page, perPage, false)
+}
+
+// PaginateWithWindowCount is like Paginate but gets the total with the data in
+// a single query, using the COUNT(*) OVER() window function. The database
+// has to support window functions (PostgreSQL, SQL Server, MySQL 8,
+// SQLite 3.25, ...). A count query is still needed if the page is empty.
+func (ss *StructSelect) PaginateWithWindowCount(page int, perPage int) (*Page, error) {
+	return ss.paginate(page, perPage, true)
+}
+
+// paginate executes Paginate and PaginateWithWindowCount.
+func (ss *StructSelect) paginate(page int, perPage int, windowCount bool) (*Page, error) {
+	if ss.error != nil {
+		return nil, ss.error
+	}
+	if !ss.recordDescription.isSlice {
+		return nil, fmt.Errorf("Paginate accepts only a slice")
+	}
+	if page < 1 || perPage < 1 {
+		return nil, fmt.Errorf("invalid page %d or count per page %d, they start at 1", page, perPage)
+	}
+
+	result := &Page{
+		Items:   ss.recordDescription.record,
+		Page:    page,
+		PerPage: perPage,
+	}
+
+	// The count ignores the ordering, not allowed without grouping by some
+	// databases
+	countStatement := ss.selectStatement.Clone()
+	countStatement.orderBy = nil
+	countStatement.orderByArgs = nil
+
+	ss.selectStatement.Limit(perPage).Offset((page - 1) * perPage)
+	if windowCount {
+		db := ss.selectStatement.db
+		allColumns := ss.recordDescription.structMapping.GetAllColumnsNames()
+		ss.selectStatement = ss.selectStatement.
+			Columns(db.quoteAllFor(ss.recordDescription, allColumns)...).
+			Columns("COUNT(*) OVER() AS " + db.quote(totalColumnAlias))
+
+		f := func(record interface{}, columns []string) ([]interface{}, error) {
+			pointers := ss.recordDescription.structMapping.GetAllFieldsPointers(record)
+			return append(pointers, &result.Total), nil
+		}
+		if err := ss.selectStatement.do(ss.recordDescription, f); err != nil {
+			return nil, err
+		}
+	} else if err := ss.Do(); err != nil {
+		return nil, err
+	}
+
+	if !windowCount || ss.recordDescription.len() == 0 {
+		total, err := countStatement.Count()
+		if err != nil {
+			return nil, err
+		}
+		result.Total = total
+	}
+
+	result.TotalPages = int((result.Total + int64(perPage) - 1) / int64(perPage))
+	return result, nil
+}
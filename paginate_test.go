@@ -0,0 +1,61 @@
+package godb
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPaginate(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Paginate fetches a page and counts the records", func() {
+			dummies := make([]Dummy, 0)
+			page, err := db.Select(&dummies).OrderBy("an_integer").Paginate(2, 2)
+			So(err, ShouldBeNil)
+			So(page.Total, ShouldEqual, 3)
+			So(page.Page, ShouldEqual, 2)
+			So(page.PerPage, ShouldEqual, 2)
+			So(page.TotalPages, ShouldEqual, 2)
+			So(len(dummies), ShouldEqual, 1)
+			So(dummies[0].AText, ShouldEqual, "Third")
+			So(page.Items, ShouldEqual, &dummies)
+
+			Convey("The page is serializable", func() {
+				data, err := json.Marshal(page)
+				So(err, ShouldBeNil)
+				So(string(data), ShouldContainSubstring, `"total":3,"page":2,"per_page":2,"total_pages":2`)
+			})
+		})
+
+		Convey("PaginateWithWindowCount gets the total with the data", func() {
+			dummies := make([]Dummy, 0)
+			page, err := db.Select(&dummies).Where("an_integer > ?", 11).OrderBy("an_integer").PaginateWithWindowCount(1, 1)
+			So(err, ShouldBeNil)
+			So(page.Total, ShouldEqual, 2)
+			So(page.TotalPages, ShouldEqual, 2)
+			So(len(dummies), ShouldEqual, 1)
+			So(dummies[0].AText, ShouldEqual, "Second")
+		})
+
+		Convey("An empty page still gives the total", func() {
+			dummies := make([]Dummy, 0)
+			page, err := db.Select(&dummies).PaginateWithWindowCount(3, 2)
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 0)
+			So(page.Total, ShouldEqual, 3)
+		})
+
+		Convey("Paginate returns an error with a single instance or invalid page", func() {
+			_, err := db.Select(&Dummy{}).Paginate(1, 10)
+			So(err, ShouldNotBeNil)
+
+			dummies := make([]Dummy, 0)
+			_, err = db.Select(&dummies).Paginate(0, 10)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
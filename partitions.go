@@ -0,0 +1,143 @@
+package godb
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// Partitions manages the time range partitions of the table of a struct.
+// The table is partitioned by range on a single time column, and its
+// partitions are named after it, with a suffix for the period they hold
+// (events_2024_01 for a monthly partition of the events table).
+//
+// Only PostgreSQL is supported, see adapters.PartitionBuilder.
+//
+// Example :
+// 	// create table events (...) partition by range (created_at)
+// 	err := db.Partitions(&Event{}).EnsureMonthly(time.Now().AddDate(0, 1, 0))
+type Partitions struct {
+	db        *DB
+	error     error
+	builder   adapters.PartitionBuilder
+	tableName string
+}
+
+// Partition is a time range partition of a table. From is inclusive, To is
+// exclusive. They are zero if the partition is not a range of times (a
+// default partition for example).
+type Partition struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+// partitionRow is a row of the query listing the partitions.
+type partitionRow struct {
+	Name  string `db:"name"`
+	Bound string `db:"bound"`
+}
+
+// Partitions returns the manager of the partitions of the table of the given
+// struct (used only for its table name).
+func (db *DB) Partitions(record interface{}) *Partitions {
+	p := &Partitions{db: db}
+	builder, ok := db.adapter.(adapters.PartitionBuilder)
+	if !ok {
+		p.error = fmt.Errorf("the adapter does not support partitions")
+		return p
+	}
+	p.builder = builder
+
+	recordDescription, err := buildRecordDescription(record)
+	if err != nil {
+		p.error = err
+		return p
+	}
+	p.tableName = db.defaultTableNamer(recordDescription.getTableName())
+	return p
+}
+
+// EnsureMonthly creates the partition holding the month of the given time,
+// if it does not exist.
+func (p *Partitions) EnsureMonthly(t time.Time) error {
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return p.Create(p.tableName+from.Format("_2006_01"), from, from.AddDate(0, 1, 0))
+}
+
+// EnsureDaily creates the partition holding the day of the given time, if
+// it does not exist.
+func (p *Partitions) EnsureDaily(t time.Time) error {
+	from := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return p.Create(p.tableName+from.Format("_2006_01_02"), from, from.AddDate(0, 0, 1))
+}
+
+// Create creates the partition having the given name and holding the rows
+// from the given time (inclusive) to the other (exclusive), if it does not
+// exist.
+func (p *Partitions) Create(name string, from time.Time, to time.Time) error {
+	if p.error != nil {
+		return p.error
+	}
+	return p.exec(p.builder.BuildCreatePartition(p.db.quote(name), p.db.quote(p.tableName), from, to))
+}
+
+// Attach attaches the existing table having the given name as the partition
+// holding the rows from the given time (inclusive) to the other (exclusive).
+func (p *Partitions) Attach(name string, from time.Time, to time.Time) error {
+	if p.error != nil {
+		return p.error
+	}
+	return p.exec(p.builder.BuildAttachPartition(p.db.quote(name), p.db.quote(p.tableName), from, to))
+}
+
+// Detach detaches the partition having the given name, it remains as a
+// table.
+func (p *Partitions) Detach(name string) error {
+	if p.error != nil {
+		return p.error
+	}
+	return p.exec(p.builder.BuildDetachPartition(p.db.quote(name), p.db.quote(p.tableName)))
+}
+
+// Drop drops the partition having the given name, and its rows.
+func (p *Partitions) Drop(name string) error {
+	if p.error != nil {
+		return p.error
+	}
+	return p.exec("DROP TABLE " + p.db.quote(name))
+}
+
+// List returns the partitions of the table, ordered by range. The
+// partitions which are not a range of times are given first.
+func (p *Partitions) List() ([]Partition, error) {
+	if p.error != nil {
+		return nil, p.error
+	}
+
+	rows := make([]partitionRow, 0)
+	if err := p.db.RawSQL(p.builder.BuildListPartitions(), p.db.quote(p.tableName)).Do(&rows); err != nil {
+		return nil, err
+	}
+
+	partitions := make([]Partition, 0, len(rows))
+	for _, row := range rows {
+		partition := Partition{Name: row.Name}
+		if from, to, ok := p.builder.ParsePartitionBound(row.Bound); ok {
+			partition.From, partition.To = from, to
+		}
+		partitions = append(partitions, partition)
+	}
+	sort.SliceStable(partitions, func(i, j int) bool {
+		return partitions[i].From.Before(partitions[j].From)
+	})
+	return partitions, nil
+}
+
+// exec executes the given DDL statement.
+func (p *Partitions) exec(query string) error {
+	_, _, err := p.db.RawSQL(query).DoExec()
+	return err
+}
@@ -0,0 +1,23 @@
+package godb
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPartitions(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("The partitions are not supported by the SQLite adapter", func() {
+			partitions := db.Partitions(&Dummy{})
+			So(partitions.EnsureMonthly(time.Now()), ShouldNotBeNil)
+			So(partitions.Detach("dummies_2024_01"), ShouldNotBeNil)
+			_, err := partitions.List()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,58 @@
+package godb
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// PolicyFunc returns the condition restricting the records of a struct type
+// accessible in the given context, or nil if there is no restriction.
+type PolicyFunc func(ctx context.Context) *Condition
+
+var (
+	policiesLock sync.RWMutex
+	policies     = make(map[reflect.Type]PolicyFunc)
+)
+
+// Policy registers an access policy for the type of the given struct
+// instance (or pointer). The struct tools (Select, Update, Delete) add the
+// condition returned by the policy to their queries, using the context of
+// the DB (see SetContext). It allows row level security on databases without
+// native support.
+//
+// Registering a new policy for a type replaces the previous one, registering
+// nil removes it.
+//
+// Example :
+// 	godb.Policy(&Invoice{}, func(ctx context.Context) *godb.Condition {
+// 		actor, _ := godb.ActorFromContext(ctx)
+// 		return godb.Q("owner_id = ?", actor)
+// 	})
+func Policy(record interface{}, policy PolicyFunc) {
+	recordType := reflect.TypeOf(record)
+	for recordType.Kind() == reflect.Ptr || recordType.Kind() == reflect.Slice {
+		recordType = recordType.Elem()
+	}
+
+	policiesLock.Lock()
+	defer policiesLock.Unlock()
+	if policy == nil {
+		delete(policies, recordType)
+		return
+	}
+	policies[recordType] = policy
+}
+
+// policyCondition returns the condition of the policy registered for the
+// records, or nil if there is none.
+func (db *DB) policyCondition(recordDescription *recordDescription) *Condition {
+	policiesLock.RLock()
+	policy, ok := policies[recordDescription.instanceType]
+	policiesLock.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	return policy(db.Context())
+}
@@ -0,0 +1,55 @@
+package godb
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPolicy(t *testing.T) {
+	Convey("Given a test database and a policy on Dummy", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		Policy(&Dummy{}, func(ctx context.Context) *Condition {
+			actor, ok := ActorFromContext(ctx)
+			if !ok {
+				return nil
+			}
+			return Q("a_text = ?", actor)
+		})
+		defer Policy(&Dummy{}, nil)
+		db.SetContext(WithActor(context.Background(), "First"))
+
+		Convey("Select returns only the allowed records", func() {
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 1)
+			So(dummies[0].AText, ShouldEqual, "First")
+
+			count, err := db.Select(&Dummy{}).Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("Update and Delete ignore forbidden records", func() {
+			dummy := Dummy{}
+			db.SetContext(context.Background())
+			So(db.Select(&dummy).Where("a_text = ?", "Second").Do(), ShouldBeNil)
+			db.SetContext(WithActor(context.Background(), "First"))
+
+			dummy.AnInteger = 999
+			So(db.Update(&dummy).Do(), ShouldEqual, ErrOpLock)
+			count, err := db.Delete(&dummy).Do()
+			So(err, ShouldEqual, ErrOpLock)
+			So(count, ShouldEqual, 0)
+		})
+
+		Convey("The policy is not applied without restriction", func() {
+			db.SetContext(context.Background())
+			count, err := db.Select(&Dummy{}).Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+	})
+}
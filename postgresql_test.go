@@ -1,6 +1,8 @@
 package godb_test
 
 import (
+	"io"
+	"io/ioutil"
 	"os"
 	"testing"
 	"time"
@@ -169,3 +171,252 @@ func TestAutomaticOptimisticLockingPostgreSQL(t *testing.T) {
 
 	})
 }
+
+func TestTwoPhaseCommitPostgreSQL(t *testing.T) {
+	Convey("A DB for a PostgreSQL database", t, func() {
+		db, teardown := fixturesSetupPostgreSQL(t)
+		defer teardown()
+
+		Convey("A prepared transaction is committed later", func() {
+			So(db.Begin(), ShouldBeNil)
+			_, err := db.InsertInto("books").
+				Columns("title", "author", "published").
+				Values("The Hobbit", "Tolkien", time.Now()).
+				Do()
+			So(err, ShouldBeNil)
+			So(db.PrepareTransaction("godb-test-1"), ShouldBeNil)
+			So(db.CurrentTx(), ShouldBeNil)
+
+			ids, err := db.PreparedTransactions()
+			So(err, ShouldBeNil)
+			So(ids, ShouldContain, "godb-test-1")
+			count, err := db.SelectFrom("books").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+
+			So(db.CommitPrepared("godb-test-1"), ShouldBeNil)
+			count, err = db.SelectFrom("books").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("A prepared transaction is rolled back later", func() {
+			So(db.Begin(), ShouldBeNil)
+			_, err := db.InsertInto("books").
+				Columns("title", "author", "published").
+				Values("The Hobbit", "Tolkien", time.Now()).
+				Do()
+			So(err, ShouldBeNil)
+			So(db.PrepareTransaction("godb-test-2"), ShouldBeNil)
+			So(db.RollbackPrepared("godb-test-2"), ShouldBeNil)
+
+			count, err := db.SelectFrom("books").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+		})
+	})
+}
+
+type BookStats struct {
+	godb.MaterializedView
+	Author string `db:"author"`
+	Books  int    `db:"books"`
+}
+
+func (*BookStats) TableName() string {
+	return "book_stats"
+}
+
+func TestMaterializedViewPostgreSQL(t *testing.T) {
+	Convey("A DB for a PostgreSQL database", t, func() {
+		db, teardown := fixturesSetupPostgreSQL(t)
+		defer teardown()
+		_, err := db.CurrentDB().Exec(`
+			create materialized view book_stats as
+			select author, count(*) as books from books group by author;
+			create unique index book_stats_author on book_stats (author);`)
+		So(err, ShouldBeNil)
+		defer db.CurrentDB().Exec("drop materialized view book_stats")
+
+		Convey("A materialized view is refreshed", func() {
+			_, err := db.InsertInto("books").
+				Columns("title", "author", "published").
+				Values("The Hobbit", "Tolkien", time.Now()).
+				Do()
+			So(err, ShouldBeNil)
+			stats := make([]BookStats, 0)
+			So(db.Select(&stats).Do(), ShouldBeNil)
+			So(stats, ShouldBeEmpty)
+
+			So(db.RefreshMaterializedView("book_stats", false), ShouldBeNil)
+			So(db.Select(&stats).Do(), ShouldBeNil)
+			So(len(stats), ShouldEqual, 1)
+			So(stats[0].Books, ShouldEqual, 1)
+
+			So(db.RefreshMaterializedViewOf(&BookStats{}, true), ShouldBeNil)
+		})
+	})
+}
+
+type PartitionedEvent struct {
+	ID        int       `db:"id,key"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (*PartitionedEvent) TableName() string {
+	return "partitioned_events"
+}
+
+func TestPartitionsPostgreSQL(t *testing.T) {
+	Convey("A DB for a PostgreSQL database", t, func() {
+		db, teardown := fixturesSetupPostgreSQL(t)
+		defer teardown()
+		_, err := db.CurrentDB().Exec(`
+			create table partitioned_events (
+			id         int not null,
+			created_at timestamp with time zone not null)
+			partition by range (created_at);`)
+		So(err, ShouldBeNil)
+		defer db.CurrentDB().Exec("drop table partitioned_events; drop table if exists partitioned_events_2024_01")
+
+		Convey("The partitions are created, listed and detached", func() {
+			partitions := db.Partitions(&PartitionedEvent{})
+			day := time.Date(2024, 2, 10, 12, 0, 0, 0, time.UTC)
+			So(partitions.EnsureMonthly(day), ShouldBeNil)
+			So(partitions.EnsureMonthly(day), ShouldBeNil)
+			So(partitions.EnsureMonthly(day.AddDate(0, -1, 0)), ShouldBeNil)
+			So(db.Insert(&PartitionedEvent{ID: 1, CreatedAt: day}).Do(), ShouldBeNil)
+
+			list, err := partitions.List()
+			So(err, ShouldBeNil)
+			So(len(list), ShouldEqual, 2)
+			So(list[0].Name, ShouldEqual, "partitioned_events_2024_01")
+			So(list[1].From.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)), ShouldBeTrue)
+
+			So(partitions.Detach("partitioned_events_2024_01"), ShouldBeNil)
+			list, err = partitions.List()
+			So(err, ShouldBeNil)
+			So(len(list), ShouldEqual, 1)
+			from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			So(partitions.Attach("partitioned_events_2024_01", from, from.AddDate(0, 1, 0)), ShouldBeNil)
+		})
+
+		Convey("The old partitions are dropped by a pruner", func() {
+			partitions := db.Partitions(&PartitionedEvent{})
+			So(partitions.EnsureMonthly(time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)), ShouldBeNil)
+			So(partitions.EnsureMonthly(time.Now()), ShouldBeNil)
+			So(db.Insert(&PartitionedEvent{ID: 1, CreatedAt: time.Now()}).Do(), ShouldBeNil)
+
+			progress, err := db.NewPruner(&PartitionedEvent{}, "created_at", 24*time.Hour).DropPartitions().Run()
+			So(err, ShouldBeNil)
+			So(progress.DroppedPartitions, ShouldResemble, []string{"partitioned_events_2024_01"})
+			list, err := partitions.List()
+			So(err, ShouldBeNil)
+			So(len(list), ShouldEqual, 1)
+		})
+	})
+}
+
+func TestSequencePostgreSQL(t *testing.T) {
+	Convey("A DB for a PostgreSQL database", t, func() {
+		db, teardown := fixturesSetupPostgreSQL(t)
+		defer teardown()
+		So(db.CreateSequence("invoice_seq"), ShouldBeNil)
+		defer db.CurrentDB().Exec("drop sequence invoice_seq")
+
+		Convey("NextSequenceValue uses the native sequence", func() {
+			value, err := db.NextSequenceValue("invoice_seq")
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 1)
+			value, err = db.NextSequenceValue("invoice_seq")
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 2)
+		})
+	})
+}
+
+func TestLargeObjectsPostgreSQL(t *testing.T) {
+	Convey("A DB for a PostgreSQL database", t, func() {
+		db, teardown := fixturesSetupPostgreSQL(t)
+		defer teardown()
+
+		Convey("A large object is written, read by ranges and deleted", func() {
+			So(db.Begin(), ShouldBeNil)
+			defer db.Rollback()
+			los, err := db.LargeObjects()
+			So(err, ShouldBeNil)
+
+			oid, err := los.Create()
+			So(err, ShouldBeNil)
+			lo, err := los.Open(oid, godb.LargeObjectReadWrite)
+			So(err, ShouldBeNil)
+			count, err := lo.Write([]byte("Hello, large object"))
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 19)
+
+			data := make([]byte, 5)
+			count, err = lo.ReadAt(data, 7)
+			So(err, ShouldBeNil)
+			So(string(data[:count]), ShouldEqual, "large")
+			position, err := lo.Tell()
+			So(err, ShouldBeNil)
+			So(position, ShouldEqual, 12)
+
+			So(lo.Truncate(5), ShouldBeNil)
+			_, err = lo.Seek(0, io.SeekStart)
+			So(err, ShouldBeNil)
+			all, err := ioutil.ReadAll(lo)
+			So(err, ShouldBeNil)
+			So(string(all), ShouldEqual, "Hello")
+
+			So(lo.Close(), ShouldBeNil)
+			So(los.Unlink(oid), ShouldBeNil)
+			_, err = los.Open(oid, godb.LargeObjectRead)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestCountEstimatePostgreSQL(t *testing.T) {
+	Convey("A DB for a PostgreSQL database", t, func() {
+		db, teardown := fixturesSetupPostgreSQL(t)
+		defer teardown()
+
+		Convey("CountEstimate gives an estimate from the statistics", func() {
+			_, err := db.CurrentDB().Exec("analyze books")
+			So(err, ShouldBeNil)
+			_, exact, err := db.SelectFrom("books").CountEstimate()
+			So(err, ShouldBeNil)
+			So(exact, ShouldBeFalse)
+
+			_, exact, err = db.SelectFrom("books").Where("author = ?", "Tolkien").CountEstimate()
+			So(err, ShouldBeNil)
+			So(exact, ShouldBeFalse)
+		})
+	})
+}
+
+func TestWithSavepointPostgreSQL(t *testing.T) {
+	Convey("A DB for a PostgreSQL database", t, func() {
+		db, teardown := fixturesSetupPostgreSQL(t)
+		defer teardown()
+
+		Convey("A failed statement in a savepoint doesn't abort the transaction", func() {
+			So(db.Begin(), ShouldBeNil)
+			err := db.WithSavepoint(func(db *godb.DB) error {
+				_, _, err := db.RawSQL("select * from unknown_table").DoExec()
+				return err
+			})
+			So(err, ShouldNotBeNil)
+			_, err = db.InsertInto("books").
+				Columns("title", "author", "published").
+				Values("The Hobbit", "Tolkien", time.Now()).
+				Do()
+			So(err, ShouldBeNil)
+			So(db.Commit(), ShouldBeNil)
+			count, err := db.SelectFrom("books").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+	})
+}
@@ -1,14 +1,15 @@
 package godb
 
 import (
+	"context"
 	"database/sql"
 )
 
 // queryable represents either a Tx, a DB, or a Stmt.
 type queryable interface {
-	Exec(args ...interface{}) (sql.Result, error)
-	Query(args ...interface{}) (*sql.Rows, error)
-	QueryRow(args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, args ...interface{}) *sql.Row
 }
 
 // The queryWrapper type implements Queryable for sql.DB and sql.Tx
@@ -17,19 +18,19 @@ type queryWrapper struct {
 	sqlQuery string
 }
 
-// Exec wraps the Exec method for sql.DB or sql.Tx.
-func (q *queryWrapper) Exec(args ...interface{}) (sql.Result, error) {
-	return q.db.Exec(q.sqlQuery, args...)
+// ExecContext wraps the ExecContext method for sql.DB or sql.Tx.
+func (q *queryWrapper) ExecContext(ctx context.Context, args ...interface{}) (sql.Result, error) {
+	return q.db.ExecContext(ctx, q.sqlQuery, args...)
 }
 
-// Query wraps the Query method for sql.DB or sql.Tx.
-func (q *queryWrapper) Query(args ...interface{}) (*sql.Rows, error) {
-	return q.db.Query(q.sqlQuery, args...)
+// QueryContext wraps the QueryContext method for sql.DB or sql.Tx.
+func (q *queryWrapper) QueryContext(ctx context.Context, args ...interface{}) (*sql.Rows, error) {
+	return q.db.QueryContext(ctx, q.sqlQuery, args...)
 }
 
-// QueryRow wraps the QueryRow method for sql.DB or sql.Tx.
-func (q *queryWrapper) QueryRow(args ...interface{}) *sql.Row {
-	return q.db.QueryRow(q.sqlQuery, args...)
+// QueryRowContext wraps the QueryRowContext method for sql.DB or sql.Tx.
+func (q *queryWrapper) QueryRowContext(ctx context.Context, args ...interface{}) *sql.Row {
+	return q.db.QueryRowContext(ctx, q.sqlQuery, args...)
 }
 
 // getQueryable manages prepared statement, and its cache.
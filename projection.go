@@ -0,0 +1,97 @@
+package godb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ColumnsForFields returns the quoted columns of the given struct (through an
+// instance or pointer) matching the requested fields, ready to be given to
+// Columns. It allows APIs to fetch only the fields requested by the clients
+// (like GraphQL selections). A field matches a column name, or a struct field
+// name without case (createdAt matches CreatedAt). The key columns are
+// always included, and the columns are in the struct order. All the columns
+// are returned if no field is given, an unknown field returns an error.
+//
+// Example :
+// 	columns, err := db.ColumnsForFields(&Book{}, "title", "authorName")
+// 	err = db.SelectFrom("books").Columns(columns...).Do(&books)
+func (db *DB) ColumnsForFields(record interface{}, fields ...string) ([]string, error) {
+	recordDescription, err := buildRecordDescription(record)
+	if err != nil {
+		return nil, err
+	}
+	names, err := columnsForFields(recordDescription, fields)
+	if err != nil {
+		return nil, err
+	}
+	return db.quoteAllFor(recordDescription, names), nil
+}
+
+// columnsForFields returns the columns of the described struct matching the
+// given fields (see ColumnsForFields), unquoted.
+func columnsForFields(recordDescription *recordDescription, fields []string) ([]string, error) {
+	columns := recordDescription.structMapping.DescribeColumns()
+
+	selected := make([]bool, len(columns))
+	for _, field := range fields {
+		found := false
+		for i, column := range columns {
+			if column.Name == field || strings.EqualFold(column.Field, field) {
+				selected[i] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown field %s in struct %s", field, recordDescription.structMapping.Name)
+		}
+	}
+
+	names := make([]string, 0, len(columns))
+	for i, column := range columns {
+		if len(fields) == 0 || selected[i] || column.IsKey {
+			names = append(names, column.Name)
+		}
+	}
+	return names, nil
+}
+
+// projector wraps the Projections method, allowing a struct to define named
+// projections : the fields fetched by StructSelect.Projection, by name.
+type projector interface {
+	Projections() map[string][]string
+}
+
+// Projection fetches only the columns of the named projection of the struct,
+// instead of all its columns. The projections are given by the Projections
+// method of the struct, returning the fields of each projection (matched
+// like ColumnsForFields : column names or struct field names). The key
+// columns are always fetched, and a projection without field fetches all
+// the columns. The others fields of the records are left untouched.
+//
+// Example :
+// 	func (*Book) Projections() map[string][]string {
+// 		return map[string][]string{
+// 			"list":   {"id", "title"},
+// 			"detail": nil,
+// 		}
+// 	}
+//
+// 	err := db.Select(&books).Projection("list").Do()
+func (ss *StructSelect) Projection(name string) *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	projector, ok := ss.recordDescription.getOneInstancePointer().(projector)
+	if !ok {
+		ss.error = fmt.Errorf("the struct %s has no projections", ss.recordDescription.structMapping.Name)
+		return ss
+	}
+	fields, ok := projector.Projections()[name]
+	if !ok {
+		ss.error = fmt.Errorf("unknown projection %s of the struct %s", name, ss.recordDescription.structMapping.Name)
+		return ss
+	}
+	ss.columns, ss.error = columnsForFields(ss.recordDescription, fields)
+	return ss
+}
@@ -0,0 +1,115 @@
+package godb
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type ProjectedDummy struct {
+	Dummy `db:""`
+}
+
+func (*ProjectedDummy) TableName() string {
+	return "dummies"
+}
+
+func (*ProjectedDummy) Projections() map[string][]string {
+	return map[string][]string{
+		"list":   {"AText", "an_integer"},
+		"detail": nil,
+	}
+}
+
+func TestColumnsForFields(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("ColumnsForFields returns the requested columns with the keys", func() {
+			columns, err := db.ColumnsForFields(&Dummy{}, "anInteger", "a_text", "a_text")
+			So(err, ShouldBeNil)
+			So(columns, ShouldResemble, []string{`"id"`, `"a_text"`, `"an_integer"`})
+
+			dummies := make([]Dummy, 0)
+			err = db.SelectFrom("dummies").Columns(columns...).OrderBy("id").Do(&dummies)
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+			So(dummies[0].AText, ShouldEqual, "First")
+			So(dummies[0].AnInteger, ShouldEqual, 11)
+			So(dummies[0].AnotherText, ShouldBeEmpty)
+		})
+
+		Convey("ColumnsForFields returns all columns without field", func() {
+			columns, err := db.ColumnsForFields(&Dummy{})
+			So(err, ShouldBeNil)
+			So(len(columns), ShouldEqual, 6)
+		})
+
+		Convey("ColumnsForFields rejects unknown fields", func() {
+			_, err := db.ColumnsForFields(&Dummy{}, "a_text", "password")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestProjection(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Projection fetches only the columns of the projection", func() {
+			dummies := make([]ProjectedDummy, 0)
+			err := db.Select(&dummies).Projection("list").OrderBy("id").Do()
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+			So(dummies[0].ID, ShouldEqual, 1)
+			So(dummies[0].AText, ShouldEqual, "First")
+			So(dummies[0].AnInteger, ShouldEqual, 11)
+			So(dummies[0].AnotherText, ShouldBeEmpty)
+		})
+
+		Convey("Projection keeps the others fields untouched", func() {
+			dummy := ProjectedDummy{Dummy{AnotherText: "Kept"}}
+			err := db.Select(&dummy).Projection("list").Where("id = ?", 2).Do()
+			So(err, ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "Second")
+			So(dummy.AnotherText, ShouldEqual, "Kept")
+		})
+
+		Convey("A projection without fields fetches all the columns", func() {
+			dummy := ProjectedDummy{}
+			err := db.Select(&dummy).Projection("detail").Where("id = ?", 1).Do()
+			So(err, ShouldBeNil)
+			So(dummy.AnotherText, ShouldEqual, "Premier")
+		})
+
+		Convey("Projection applies to the iterators", func() {
+			iter, err := db.Select(&ProjectedDummy{}).Projection("list").OrderBy("id").DoWithIterator()
+			So(err, ShouldBeNil)
+			defer iter.Close()
+			So(iter.Next(), ShouldBeTrue)
+			dummy := ProjectedDummy{}
+			So(iter.Scan(&dummy), ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "First")
+			So(dummy.AnotherText, ShouldBeEmpty)
+		})
+
+		Convey("The records of a projection stay out of the identity map", func() {
+			db.UseIdentityMap(true)
+			dummies := make([]*ProjectedDummy, 0)
+			So(db.Select(&dummies).Projection("list").OrderBy("id").Do(), ShouldBeNil)
+			var loaded *ProjectedDummy
+			So(db.Load(&loaded, 1), ShouldBeNil)
+			So(loaded, ShouldNotEqual, dummies[0])
+			So(loaded.AnotherText, ShouldEqual, "Premier")
+		})
+
+		Convey("Projection rejects unknown projections", func() {
+			err := db.Select(&ProjectedDummy{}).Projection("unknown").Do()
+			So(err, ShouldNotBeNil)
+			err = db.Select(&Dummy{}).Projection("list").Do()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,184 @@
+package godb
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default settings of a Pruner.
+const (
+	defaultPrunerBatchSize = 1000
+	defaultPrunerPause     = 100 * time.Millisecond
+)
+
+// Pruner deletes the rows of a table older than a retention period, in
+// bounded batches separated by a pause, to avoid long locks and large
+// transaction logs. Each batch is a statement, execute the pruner outside a
+// transaction to commit them one by one.
+//
+// With DropPartitions the partitions holding only old rows are dropped
+// first (see Partitions), then the remaining old rows are deleted.
+//
+// Example :
+// 	progress, err := db.NewPruner(&Event{}, "created_at", 90*24*time.Hour).
+// 		BatchSize(5000).
+// 		OnProgress(func(progress godb.PruneProgress) error {
+// 			log.Printf("%d rows deleted", progress.Deleted)
+// 			return nil
+// 		}).
+// 		Run()
+type Pruner struct {
+	db             *DB
+	record         interface{}
+	column         string
+	retention      time.Duration
+	batchSize      int
+	pause          time.Duration
+	dropPartitions bool
+	onProgress     func(PruneProgress) error
+}
+
+// PruneProgress is the progress of a Pruner.
+type PruneProgress struct {
+	// Count of deleted rows
+	Deleted int64
+	// Count of executed delete batches
+	Batches int
+	// Names of the dropped partitions
+	DroppedPartitions []string
+}
+
+// NewPruner creates a Pruner of the table of the given struct (used only for
+// its table name and key), deleting the rows having a time column older
+// than the given retention period. The struct must have a single key.
+func (db *DB) NewPruner(record interface{}, column string, retention time.Duration) *Pruner {
+	return &Pruner{
+		db:        db,
+		record:    record,
+		column:    column,
+		retention: retention,
+		batchSize: defaultPrunerBatchSize,
+		pause:     defaultPrunerPause,
+	}
+}
+
+// BatchSize sets the maximum count of rows deleted by a statement, 1000 by
+// default.
+func (p *Pruner) BatchSize(batchSize int) *Pruner {
+	p.batchSize = batchSize
+	return p
+}
+
+// Pause sets the time waited between two batches, 100ms by default.
+func (p *Pruner) Pause(pause time.Duration) *Pruner {
+	p.pause = pause
+	return p
+}
+
+// DropPartitions drops the partitions holding only old rows, instead of
+// deleting their rows.
+func (p *Pruner) DropPartitions() *Pruner {
+	p.dropPartitions = true
+	return p
+}
+
+// OnProgress sets a function called after each batch or dropped partition.
+// The pruning stops if it returns an error, and Run returns it.
+func (p *Pruner) OnProgress(onProgress func(PruneProgress) error) *Pruner {
+	p.onProgress = onProgress
+	return p
+}
+
+// Run prunes the table, and returns the progress at the end (even if an
+// error occurred).
+func (p *Pruner) Run() (PruneProgress, error) {
+	progress := PruneProgress{}
+	if p.batchSize < 1 {
+		return progress, fmt.Errorf("the batch size of the pruner must be positive, got %d", p.batchSize)
+	}
+	recordDescription, err := buildRecordDescription(p.record)
+	if err != nil {
+		return progress, err
+	}
+	keyColumns := recordDescription.structMapping.GetKeyColumnsNames()
+	if len(keyColumns) != 1 {
+		return progress, fmt.Errorf("the struct %s must have a single key to be pruned", recordDescription.structMapping.Name)
+	}
+	cutoff := time.Now().Add(-p.retention)
+
+	if p.dropPartitions {
+		partitions := p.db.Partitions(p.record)
+		list, err := partitions.List()
+		if err != nil {
+			return progress, err
+		}
+		for _, partition := range list {
+			if partition.To.IsZero() || partition.To.After(cutoff) {
+				continue
+			}
+			if err := partitions.Drop(partition.Name); err != nil {
+				return progress, err
+			}
+			progress.DroppedPartitions = append(progress.DroppedPartitions, partition.Name)
+			if err := p.report(progress); err != nil {
+				return progress, err
+			}
+			time.Sleep(p.pause)
+		}
+	}
+
+	tableName := p.db.quoteFor(recordDescription, p.db.defaultTableNamer(recordDescription.getTableName()))
+	keyColumn := p.db.quoteFor(recordDescription, keyColumns[0])
+	column := p.db.quoteFor(recordDescription, p.column)
+	for {
+		keys, err := p.selectKeys(tableName, keyColumn, column, cutoff)
+		if err != nil || len(keys) == 0 {
+			return progress, err
+		}
+		deleted, err := p.db.DeleteFrom(tableName).WhereQ(Q(keyColumn+" IN (?)", keys)).Do()
+		if err != nil {
+			return progress, err
+		}
+		progress.Deleted += deleted
+		progress.Batches++
+		if err := p.report(progress); err != nil {
+			return progress, err
+		}
+		if len(keys) < p.batchSize {
+			return progress, nil
+		}
+		time.Sleep(p.pause)
+	}
+}
+
+// selectKeys returns the keys of the next batch of old rows.
+func (p *Pruner) selectKeys(tableName, keyColumn, column string, cutoff time.Time) ([]interface{}, error) {
+	iterator, err := p.db.SelectFrom(tableName).
+		Columns(keyColumn).
+		Where(column+" < ?", cutoff).
+		OrderBy(column).
+		Limit(p.batchSize).
+		DoWithIterator()
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	keys := make([]interface{}, 0, p.batchSize)
+	for iterator.Next() {
+		var key interface{}
+		if err := iterator.Scanx(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, iterator.Err()
+}
+
+// report gives the progress to the progress function, if any.
+func (p *Pruner) report(progress PruneProgress) error {
+	if p.onProgress == nil {
+		return nil
+	}
+	return p.onProgress(progress)
+}
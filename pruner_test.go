@@ -0,0 +1,83 @@
+package godb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type PrunedEvent struct {
+	ID        int       `db:"id,key,auto"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (*PrunedEvent) TableName() string {
+	return "events"
+}
+
+func TestPruner(t *testing.T) {
+	Convey("Given a test database with old and recent events", t, func() {
+		db := createInMemoryConnection(t)
+		defer db.Close()
+		_, err := db.CurrentDB().Exec(`
+			create table events (
+				id integer not null primary key autoincrement,
+				created_at timestamp not null);`)
+		So(err, ShouldBeNil)
+		now := time.Now().UTC()
+		for i := 0; i < 7; i++ {
+			createdAt := now.Add(-48 * time.Hour).Add(time.Duration(i) * time.Minute)
+			if i >= 5 {
+				createdAt = now
+			}
+			So(db.Insert(&PrunedEvent{CreatedAt: createdAt}).Do(), ShouldBeNil)
+		}
+
+		Convey("Run deletes the old rows in batches", func() {
+			reported := make([]PruneProgress, 0)
+			progress, err := db.NewPruner(&PrunedEvent{}, "created_at", 24*time.Hour).
+				BatchSize(2).
+				Pause(time.Millisecond).
+				OnProgress(func(progress PruneProgress) error {
+					reported = append(reported, progress)
+					return nil
+				}).
+				Run()
+			So(err, ShouldBeNil)
+			So(progress.Deleted, ShouldEqual, 5)
+			So(progress.Batches, ShouldEqual, 3)
+			So(len(reported), ShouldEqual, 3)
+			So(reported[0].Deleted, ShouldEqual, 2)
+
+			count, err := db.SelectFrom("events").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+		})
+
+		Convey("Run stops when the progress function fails", func() {
+			stop := errors.New("stop")
+			progress, err := db.NewPruner(&PrunedEvent{}, "created_at", 24*time.Hour).
+				BatchSize(2).
+				OnProgress(func(progress PruneProgress) error {
+					return stop
+				}).
+				Run()
+			So(err, ShouldEqual, stop)
+			So(progress.Deleted, ShouldEqual, 2)
+		})
+
+		Convey("Run needs a single key and a positive batch size", func() {
+			_, err := db.NewPruner(&PrunedEvent{}, "created_at", time.Hour).BatchSize(0).Run()
+			So(err, ShouldNotBeNil)
+			_, err = db.NewPruner(&AssociationAuthorTag{}, "created_at", time.Hour).Run()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("The partitions are not supported by the SQLite adapter", func() {
+			_, err := db.NewPruner(&PrunedEvent{}, "created_at", time.Hour).DropPartitions().Run()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,226 @@
+// Package queue implements a lightweight job queue stored in a table, for
+// applications which don't want a broker. The jobs are dequeued with
+// SELECT ... FOR UPDATE SKIP LOCKED with PostgreSQL and MySQL, several
+// workers can then dequeue concurrently (with distinct DB instances, see
+// godb.DB.Clone). Otherwise run a single worker.
+//
+// A dequeued job is invisible for the others workers during the visibility
+// timeout. It has to be acknowledged (Ack) when done, or released (Nack) to
+// be retried. A job not acknowledged in time (ie a crashed worker) is
+// dequeued again. After the maximum of attempts, a job is moved to the
+// dead-letter : it is kept in the table, but not dequeued anymore.
+//
+// The queue table is not created by the package, it needs the columns :
+// 	id         : an auto incremented primary key
+// 	queue      : the queue name (VARCHAR)
+// 	payload    : the job payload (BLOB, BYTEA, ...)
+// 	attempts   : the count of dequeues (INTEGER)
+// 	visible_at : the time the job can be dequeued (indexed with queue)
+// 	created_at : the time the job was enqueued
+// 	dead_at    : the time the job was moved to the dead-letter, or NULL
+//
+// Example :
+// 	emails := queue.New("jobs", "emails").VisibilityTimeout(time.Minute)
+// 	err := emails.Enqueue(db, payload)
+//
+// 	// In a worker
+// 	jobs, err := emails.Dequeue(db, 10)
+// 	for _, job := range jobs {
+// 		if err := send(job.Payload); err != nil {
+// 			emails.Nack(db, job, 10*time.Second)
+// 			continue
+// 		}
+// 		emails.Ack(db, job)
+// 	}
+package queue
+
+import (
+	"time"
+
+	"github.com/samonzeweb/godb"
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// Job is a job read from the queue table.
+type Job struct {
+	ID        int64     `db:"id"`
+	Queue     string    `db:"queue"`
+	Payload   []byte    `db:"payload"`
+	Attempts  int       `db:"attempts"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Queue gives access to a named queue of a queue table, several queues can
+// share the same table.
+type Queue struct {
+	table             string
+	name              string
+	maxAttempts       int
+	visibilityTimeout time.Duration
+}
+
+// New creates a Queue for the given table and queue name, with a maximum of
+// 5 attempts and a visibility timeout of 30 seconds.
+func New(table string, name string) *Queue {
+	return &Queue{
+		table:             table,
+		name:              name,
+		maxAttempts:       5,
+		visibilityTimeout: 30 * time.Second,
+	}
+}
+
+// MaxAttempts sets the count of attempts before a job is moved to the
+// dead-letter.
+func (q *Queue) MaxAttempts(maxAttempts int) *Queue {
+	q.maxAttempts = maxAttempts
+	return q
+}
+
+// VisibilityTimeout sets the time a dequeued job is invisible to others
+// workers, it has to be longer than the processing of a job.
+func (q *Queue) VisibilityTimeout(timeout time.Duration) *Queue {
+	q.visibilityTimeout = timeout
+	return q
+}
+
+// Enqueue adds a job to the queue. Inside a transaction, the job is visible
+// only after the commit.
+func (q *Queue) Enqueue(db *godb.DB, payload []byte) error {
+	return q.EnqueueAfter(db, payload, 0)
+}
+
+// EnqueueAfter adds a job to the queue, which can't be dequeued before the
+// given delay.
+func (q *Queue) EnqueueAfter(db *godb.DB, payload []byte, delay time.Duration) error {
+	now := now()
+	_, err := db.InsertInto(q.table).
+		Columns("queue", "payload", "attempts", "visible_at", "created_at").
+		Values(q.name, payload, 0, now.Add(delay), now).
+		Do()
+	return err
+}
+
+// Dequeue returns at most count visible jobs, the oldest first, and makes
+// them invisible during the visibility timeout. It returns an empty slice if
+// there is no job. The jobs having reached the maximum of attempts are moved
+// to the dead-letter.
+func (q *Queue) Dequeue(db *godb.DB, count int) ([]Job, error) {
+	ownTx := db.CurrentTx() == nil
+	if ownTx {
+		if err := db.Begin(); err != nil {
+			return nil, err
+		}
+	}
+
+	jobs, err := q.dequeue(db, count)
+	if ownTx {
+		if err != nil {
+			db.Rollback()
+			return nil, err
+		}
+		if err := db.Commit(); err != nil {
+			return nil, err
+		}
+	}
+	return jobs, err
+}
+
+// dequeue executes Dequeue inside a transaction.
+func (q *Queue) dequeue(db *godb.DB, count int) ([]Job, error) {
+	now := now()
+
+	// Jobs not acknowledged after their last attempt
+	_, err := db.UpdateTable(q.table).
+		Set("dead_at", now).
+		Where("queue = ? AND dead_at IS NULL AND visible_at <= ? AND attempts >= ?", q.name, now, q.maxAttempts).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+
+	selectStatement := db.SelectFrom(q.table).
+		Columns("id", "queue", "payload", "attempts", "created_at").
+		Where("queue = ? AND dead_at IS NULL AND visible_at <= ?", q.name, now).
+		OrderBy("visible_at, id").
+		Limit(count)
+	if _, ok := db.Adapter().(adapters.RowLockBuilder); ok {
+		selectStatement.ForUpdateSkipLocked()
+	}
+	jobs := make([]Job, 0, count)
+	if err := selectStatement.Do(&jobs); err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return jobs, nil
+	}
+
+	ids := make([]interface{}, 0, len(jobs))
+	for i := range jobs {
+		jobs[i].Attempts++
+		ids = append(ids, jobs[i].ID)
+	}
+	_, err = db.UpdateTable(q.table).
+		SetRaw("attempts = attempts + 1").
+		Set("visible_at", now.Add(q.visibilityTimeout)).
+		WhereQ(godb.Col("id").In(ids...)).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// Ack acknowledges a job processed successfully, and removes it from the
+// queue.
+func (q *Queue) Ack(db *godb.DB, job Job) error {
+	_, err := db.DeleteFrom(q.table).
+		Where("id = ?", job.ID).
+		Do()
+	return err
+}
+
+// Nack releases a job not processed, it can be dequeued again after the given
+// delay. If the job has reached the maximum of attempts it is moved to the
+// dead-letter.
+func (q *Queue) Nack(db *godb.DB, job Job, delay time.Duration) error {
+	now := now()
+	updateStatement := db.UpdateTable(q.table).Where("id = ?", job.ID)
+	if job.Attempts >= q.maxAttempts {
+		updateStatement.Set("dead_at", now)
+	} else {
+		updateStatement.Set("visible_at", now.Add(delay))
+	}
+	_, err := updateStatement.Do()
+	return err
+}
+
+// DeadJobs returns at most count jobs of the dead-letter, the oldest first.
+func (q *Queue) DeadJobs(db *godb.DB, count int) ([]Job, error) {
+	jobs := make([]Job, 0, count)
+	err := db.SelectFrom(q.table).
+		Columns("id", "queue", "payload", "attempts", "created_at").
+		Where("queue = ? AND dead_at IS NOT NULL", q.name).
+		OrderBy("dead_at, id").
+		Limit(count).
+		Do(&jobs)
+	return jobs, err
+}
+
+// Retry moves a job of the dead-letter back to the queue, with its attempts
+// reset.
+func (q *Queue) Retry(db *godb.DB, job Job) error {
+	_, err := db.UpdateTable(q.table).
+		SetRaw("dead_at = NULL").
+		Set("attempts", 0).
+		Set("visible_at", now()).
+		Where("id = ? AND dead_at IS NOT NULL", job.ID).
+		Do()
+	return err
+}
+
+// now returns the current time in UTC, the times of the table are compared
+// as strings by some drivers.
+func now() time.Time {
+	return time.Now().UTC()
+}
@@ -0,0 +1,107 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samonzeweb/godb"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQueue(t *testing.T) {
+	Convey("Given a test database with a queue", t, func() {
+		db, err := godb.Open(sqlite.Adapter, ":memory:")
+		So(err, ShouldBeNil)
+		defer db.Close()
+		_, err = db.CurrentDB().Exec(`
+			create table jobs (
+				id         integer not null primary key autoincrement,
+				queue      text not null,
+				payload    blob not null,
+				attempts   integer not null,
+				visible_at timestamp not null,
+				created_at timestamp not null,
+				dead_at    timestamp);
+		`)
+		So(err, ShouldBeNil)
+
+		emails := New("jobs", "emails").MaxAttempts(2).VisibilityTimeout(time.Hour)
+		So(emails.Enqueue(db, []byte("first")), ShouldBeNil)
+		So(emails.Enqueue(db, []byte("second")), ShouldBeNil)
+		So(emails.EnqueueAfter(db, []byte("later"), time.Hour), ShouldBeNil)
+		So(New("jobs", "others").Enqueue(db, []byte("other")), ShouldBeNil)
+
+		Convey("Dequeue returns the visible jobs of the queue and hides them", func() {
+			jobs, err := emails.Dequeue(db, 10)
+			So(err, ShouldBeNil)
+			So(len(jobs), ShouldEqual, 2)
+			So(string(jobs[0].Payload), ShouldEqual, "first")
+			So(string(jobs[1].Payload), ShouldEqual, "second")
+			So(jobs[0].Attempts, ShouldEqual, 1)
+
+			jobs, err = emails.Dequeue(db, 10)
+			So(err, ShouldBeNil)
+			So(jobs, ShouldBeEmpty)
+		})
+
+		Convey("Ack removes the job", func() {
+			jobs, err := emails.Dequeue(db, 1)
+			So(err, ShouldBeNil)
+			So(emails.Ack(db, jobs[0]), ShouldBeNil)
+
+			count, err := db.SelectFrom("jobs").Where("queue = ?", "emails").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+		})
+
+		Convey("Nack releases the job until the dead-letter", func() {
+			jobs, err := emails.Dequeue(db, 1)
+			So(err, ShouldBeNil)
+			So(emails.Nack(db, jobs[0], 0), ShouldBeNil)
+
+			// The released job is visible after the others
+			jobs, err = emails.Dequeue(db, 10)
+			So(err, ShouldBeNil)
+			So(len(jobs), ShouldEqual, 2)
+			So(string(jobs[0].Payload), ShouldEqual, "second")
+			So(string(jobs[1].Payload), ShouldEqual, "first")
+			So(jobs[1].Attempts, ShouldEqual, 2)
+			So(emails.Nack(db, jobs[1], 0), ShouldBeNil)
+
+			dead, err := emails.DeadJobs(db, 10)
+			So(err, ShouldBeNil)
+			So(len(dead), ShouldEqual, 1)
+			So(string(dead[0].Payload), ShouldEqual, "first")
+
+			jobs, err = emails.Dequeue(db, 10)
+			So(err, ShouldBeNil)
+			So(jobs, ShouldBeEmpty)
+
+			Convey("Retry moves the job back to the queue", func() {
+				So(emails.Retry(db, dead[0]), ShouldBeNil)
+				jobs, err := emails.Dequeue(db, 10)
+				So(err, ShouldBeNil)
+				So(len(jobs), ShouldEqual, 1)
+				So(string(jobs[0].Payload), ShouldEqual, "first")
+				So(jobs[0].Attempts, ShouldEqual, 1)
+			})
+		})
+
+		Convey("The jobs not acknowledged after the last attempt are moved to the dead-letter", func() {
+			shortTimeout := New("jobs", "emails").MaxAttempts(1).VisibilityTimeout(-time.Second)
+			jobs, err := shortTimeout.Dequeue(db, 1)
+			So(err, ShouldBeNil)
+			So(len(jobs), ShouldEqual, 1)
+
+			jobs, err = shortTimeout.Dequeue(db, 1)
+			So(err, ShouldBeNil)
+			So(string(jobs[0].Payload), ShouldEqual, "second")
+
+			dead, err := shortTimeout.DeadJobs(db, 10)
+			So(err, ShouldBeNil)
+			So(len(dead), ShouldEqual, 1)
+		})
+	})
+}
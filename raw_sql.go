@@ -1,20 +1,36 @@
 package godb
 
-import "database/sql"
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+)
 
 // RawSQL allows the execution of a custom SQL query.
 // Initialize it with the RawSQL method.
 //
 // WARNING : the arguments will be used 'as is' by the Go sql package, then it
-// will not duplicate the placeholders if you use a slice.
+// will not duplicate the placeholders if you use a slice, unless you call
+// ExpandSlices.
 //
 // Note : the API could have been build without an intermediaite struct.
 // But this produce a mode homogeneous API, and allows
 // later evolutions without breaking the it.
 type RawSQL struct {
-	db        *DB
-	sql       string
-	arguments []interface{}
+	db           *DB
+	error        error
+	sql          string
+	arguments    []interface{}
+	expandSlices bool
+	maxRows      int
+	strictScan   *bool
+	options      statementOptions
 }
 
 // RawSQL create a RawSQL structure, allowing the executing of a custom sql
@@ -27,11 +43,113 @@ func (db *DB) RawSQL(sql string, args ...interface{}) *RawSQL {
 	}
 }
 
+// CallProc creates a RawSQL calling the given stored procedure with the
+// given arguments, using the syntax of the adapter (CALL by default, EXEC for
+// SQL Server, and a set returning function call for PostgreSQL).
+//
+// Use the RawSQL methods to execute it, for example Do to scan the returned
+// rows into structs, or DoExec if it returns nothing.
+//
+// OUT parameters are supported if the driver allows them, by giving sql.Out
+// arguments (with SQL Server they must be named with sql.Named).
+func (db *DB) CallProc(name string, args ...interface{}) *RawSQL {
+	quotedName := db.quote(name)
+	var query string
+	if procedureCallBuilder, ok := db.adapter.(adapters.ProcedureCallBuilder); ok {
+		query = procedureCallBuilder.BuildProcedureCall(quotedName, args)
+	} else {
+		placeholders := strings.TrimSuffix(strings.Repeat(Placeholder+", ", len(args)), ", ")
+		query = "CALL " + quotedName + "(" + placeholders + ")"
+	}
+	return db.RawSQL(query, args...)
+}
+
+// ExpandSlices makes slices arguments replaced by multiple placeholders, like
+// Q does. This allows queries like "... WHERE id IN (?)" with a slice.
+// []byte and types implementing driver.Valuer are not expanded.
+//
+// When it is used the placeholders count must match the arguments count, then
+// don't use it if the query contains placeholder characters elsewhere (in a
+// string literal for example).
+func (raw *RawSQL) ExpandSlices() *RawSQL {
+	raw.expandSlices = true
+	return raw
+}
+
+// Priority sets the priority of the statement when the concurrent queries
+// are limited, see SetMaxConcurrentQueries.
+func (raw *RawSQL) Priority(priority Priority) *RawSQL {
+	raw.options.priority = priority
+	return raw
+}
+
+// Timeout sets the maximum execution time of the raw query, the reading of
+// its rows included. It's cancelled when the timeout expires, like when the
+// context of the DB is done (see SetContext). The PostgreSQL and SQL Server
+// drivers stop it on the server. With MySQL only a query starting with
+// SELECT gets a MAX_EXECUTION_TIME hint, the others keep running on the
+// server once abandoned.
+func (raw *RawSQL) Timeout(timeout time.Duration) *RawSQL {
+	raw.options.timeout = timeout
+	return raw
+}
+
+// MaxStaleness allows the statement to read from a replica lagging at most
+// the given duration, see AddReplica. It's ignored by Exec and in a
+// transaction.
+func (raw *RawSQL) MaxStaleness(maxStaleness time.Duration) *RawSQL {
+	raw.options.maxStaleness = maxStaleness
+	return raw
+}
+
+// MaxRows sets the maximum count of rows scanned by Do, overriding the
+// default one of the DB (see SetMaxRows). A negative value means no limit.
+func (raw *RawSQL) MaxRows(maxRows int) *RawSQL {
+	raw.maxRows = maxRows
+	return raw
+}
+
+// StrictScan enables or disables the strict scan mode for Do, overriding the
+// one of the DB (see SetStrictScan).
+func (raw *RawSQL) StrictScan(strict bool) *RawSQL {
+	raw.strictScan = &strict
+	return raw
+}
+
+// ToSQL returns the SQL query and its arguments as they will be executed
+// (with slices expanded if ExpandSlices was called).
+func (raw *RawSQL) ToSQL() (string, []interface{}, error) {
+	if raw.error != nil {
+		return "", nil, raw.error
+	}
+	if !raw.expandSlices {
+		return raw.sql, raw.arguments, nil
+	}
+	return expandSliceArguments(raw.sql, raw.arguments)
+}
+
+// ToInterpolatedSQL returns the SQL query with the arguments written as
+// literals for the adapter, to be pasted into a SQL client. Debug only : the
+// statements are always executed with placeholders.
+func (raw *RawSQL) ToInterpolatedSQL() (string, error) {
+	query, args, err := raw.ToSQL()
+	if err != nil {
+		return "", err
+	}
+	return interpolateSQL(raw.db.adapter, query, args)
+}
+
 // Do executes the raw query.
 // The record argument has to be a pointer to a struct or a slice.
 // If the argument is not a slice, a row is expected, and Do returns
-// sql.ErrNoRows is none where found.
+// sql.ErrNoRows is none where found (or a *NotFoundError, see
+// UseNotFoundError).
 func (raw *RawSQL) Do(record interface{}) error {
+	query, arguments, err := raw.ToSQL()
+	if err != nil {
+		return err
+	}
+
 	recordInfo, err := buildRecordDescription(record)
 	if err != nil {
 		return err
@@ -44,7 +162,7 @@ func (raw *RawSQL) Do(record interface{}) error {
 		return pointers, err
 	}
 
-	rowsCount, err := raw.db.doSelectOrWithReturning(raw.sql, raw.arguments, recordInfo, pointersGetter)
+	rowsCount, err := raw.db.doSelectOrWithReturning(query, arguments, recordInfo, raw.db.limitRows(raw.maxRows, raw.db.checkColumns(raw.strictScan, recordInfo, pointersGetter)), raw.options)
 	if err != nil {
 		return err
 	}
@@ -53,31 +171,144 @@ func (raw *RawSQL) Do(record interface{}) error {
 	// returned like QueryRow in database/sql package.
 	if !recordInfo.isSlice && rowsCount == 0 {
 		err = sql.ErrNoRows
+		if raw.db.useNotFoundError {
+			err = &NotFoundError{Criteria: query, Arguments: arguments}
+		}
 	}
 
 	return err
 }
 
+// DoMulti executes a raw query returning multiple result sets (batches,
+// stored procedures, ...), and fills each given record with a result set, in
+// the same order. Like Do the records have to be pointers to a struct or a
+// slice, but unlike Do no error is returned when a single instance has no
+// corresponding row.
+//
+// Example :
+//
+// 	books := make([]Book, 0)
+// 	authors := make([]Author, 0)
+// 	err := db.RawSQL("EXEC books_and_authors ?", 123).DoMulti(&books, &authors)
+//
+// Not all drivers support multiple result sets (SQLite does not).
+func (raw *RawSQL) DoMulti(records ...interface{}) error {
+	query, arguments, err := raw.ToSQL()
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return fmt.Errorf("DoMulti needs at least one record")
+	}
+
+	recordDescriptions := make([]*recordDescription, 0, len(records))
+	pointersGetters := make([]pointersGetter, 0, len(records))
+	for _, record := range records {
+		recordInfo, err := buildRecordDescription(record)
+		if err != nil {
+			return err
+		}
+		recordDescriptions = append(recordDescriptions, recordInfo)
+		pointersGetters = append(pointersGetters, func(record interface{}, columns []string) ([]interface{}, error) {
+			return recordInfo.structMapping.GetPointersForColumns(record, columns...)
+		})
+	}
+
+	return raw.db.doMultiSelect(query, arguments, recordDescriptions, pointersGetters, raw.options)
+}
+
 // DoWithIterator executes the select query and returns an Iterator allowing
 // the caller to fetch rows one at a time.
 // Warning : it does not use an existing transation to avoid some pitfalls with
 // drivers, nor the prepared statement.
 func (raw *RawSQL) DoWithIterator() (Iterator, error) {
-	return raw.db.doWithIterator(raw.sql, raw.arguments)
+	query, arguments, err := raw.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	return raw.db.doWithIterator(query, arguments, raw.options)
+}
+
+// DoExec executes a raw statement which does not return rows (DDL, bulk
+// DELETE, stored procedure calls, ...), and returns the count of affected rows
+// and the last inserted id.
+// The statement is executed in the current transaction if there is one.
+//
+// The last inserted id is always zero with adapters implementing
+// ReturningBuilder (PostgreSQL, SQL Server) as their drivers do not support it,
+// and with the adapters telling it (BigQuery, see
+// adapters.LastInsertIDChecker).
+func (raw *RawSQL) DoExec() (int64, int64, error) {
+	query, arguments, err := raw.ToSQL()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	result, err := raw.db.do(query, arguments, raw.options)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if _, ok := raw.db.adapter.(adapters.ReturningBuilder); ok || !supportsLastInsertID(raw.db.adapter) {
+		return rowsAffected, 0, nil
+	}
+	lastInsertID, err := result.LastInsertId()
+	return rowsAffected, lastInsertID, err
 }
 
-// Tambahan FZL
-// function khusus exec store procedure dengan transaction
-// tambahkan SELECT 1 AS ADA, karena godb butuh return jika ingin menggunakan transaction, dan tidak bsa transaction menggunakan DoWithIterator
-func (raw *RawSQL) DoExec() error {
+// expandSliceArguments replaces the placeholder of each slice argument with
+// as many placeholders as the slice length, and flattens the arguments.
+func expandSliceArguments(sql string, args []interface{}) (string, []interface{}, error) {
+	if strings.Count(sql, Placeholder) != len(args) {
+		return "", nil, fmt.Errorf("wrong number of arguments in raw query %s", sql)
+	}
 
-	type StoreProcedure struct {
-		Sp string `db:"ADA"`
+	buffer := bytes.NewBuffer(make([]byte, 0, len(sql)))
+	expandedArgs := make([]interface{}, 0, len(args))
+	remainingSQL := sql
+	for _, arg := range args {
+		placeholderPos := strings.Index(remainingSQL, Placeholder)
+		buffer.WriteString(remainingSQL[:placeholderPos])
+		remainingSQL = remainingSQL[placeholderPos+len(Placeholder):]
+
+		if !isExpandableSlice(arg) {
+			buffer.WriteString(Placeholder)
+			expandedArgs = append(expandedArgs, arg)
+			continue
+		}
+
+		v := reflect.ValueOf(arg)
+		length := v.Len()
+		if length == 0 {
+			return "", nil, fmt.Errorf("empty slice used as argument in raw query %s", sql)
+		}
+		for i := 0; i < length; i++ {
+			expandedArgs = append(expandedArgs, v.Index(i).Interface())
+		}
+		buffer.WriteString(Placeholder + strings.Repeat(","+Placeholder, length-1))
 	}
-	
-	Select := make([]StoreProcedure, 0)
+	buffer.WriteString(remainingSQL)
 
-	err := raw.db.RawSQL(raw.sql+" SELECT 1 AS ADA").Do(&Select)
+	return buffer.String(), expandedArgs, nil
+}
 
-	return err
-}
\ No newline at end of file
+// isExpandableSlice returns true if the argument is a slice which is not
+// already a value for drivers ([]byte or driver.Valuer).
+func isExpandableSlice(arg interface{}) bool {
+	if arg == nil {
+		return false
+	}
+	if _, ok := arg.(driver.Valuer); ok {
+		return false
+	}
+	if _, ok := arg.([]byte); ok {
+		return false
+	}
+	return reflect.TypeOf(arg).Kind() == reflect.Slice
+}
@@ -0,0 +1,129 @@
+package godb
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// templateFuncs are the only functions allowed to produce output in a SQL
+// template.
+var templateFuncs = []string{"ident", "arg"}
+
+// RawSQLTemplate creates a RawSQL from a text/template and its data. It eases
+// the build of queries with dynamic tables or columns names without using
+// fmt.Sprintf and the risk of SQL injection.
+//
+// Two functions are available into the template :
+//
+// 	* ident : quotes an identifier with the adapter (the identifier must be
+// 	  made of letters, digits, underscores and dots).
+// 	* arg : adds a placeholder and its argument. Slices are managed like with
+// 	  Q, a placeholder is added for each value.
+//
+// Any other output (like {{.Value}}) is refused, but the template language is
+// available (if, range, ...).
+//
+// Example :
+//
+// 	data := map[string]interface{}{"Column": "author", "Authors": authors}
+// 	err := db.RawSQLTemplate(
+// 		"SELECT {{ident .Column}}, count(*) as count FROM books "+
+// 			"WHERE {{ident .Column}} IN ({{arg .Authors}}) GROUP BY {{ident .Column}}",
+// 		data).Do(&countByAuthor)
+func (db *DB) RawSQLTemplate(tmpl string, data interface{}) *RawSQL {
+	var arguments []interface{}
+	funcs := template.FuncMap{
+		"ident": func(identifier string) (string, error) {
+			if err := checkIdentifier(identifier); err != nil {
+				return "", err
+			}
+			return db.quote(identifier), nil
+		},
+		"arg": func(value interface{}) (string, error) {
+			if !isExpandableSlice(value) {
+				arguments = append(arguments, value)
+				return Placeholder, nil
+			}
+			v := reflect.ValueOf(value)
+			if v.Len() == 0 {
+				return "", fmt.Errorf("empty slice used as argument in template")
+			}
+			for i := 0; i < v.Len(); i++ {
+				arguments = append(arguments, v.Index(i).Interface())
+			}
+			return Placeholder + strings.Repeat(","+Placeholder, v.Len()-1), nil
+		},
+	}
+
+	raw := &RawSQL{db: db}
+	t, err := template.New("godb").Funcs(funcs).Parse(tmpl)
+	if err != nil {
+		raw.error = err
+		return raw
+	}
+	if err := checkTemplateOutputs(t.Tree.Root); err != nil {
+		raw.error = err
+		return raw
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, len(tmpl)))
+	if err := t.Execute(buffer, data); err != nil {
+		raw.error = err
+		return raw
+	}
+
+	raw.sql = buffer.String()
+	raw.arguments = arguments
+	return raw
+}
+
+// checkTemplateOutputs checks that all actions writing something into the
+// template result use one of the templateFuncs.
+func checkTemplateOutputs(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkTemplateOutputs(child); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		// Variable declarations do not write anything
+		if len(n.Pipe.Decl) > 0 {
+			return nil
+		}
+		lastCommand := n.Pipe.Cmds[len(n.Pipe.Cmds)-1]
+		if identifier, ok := lastCommand.Args[0].(*parse.IdentifierNode); ok {
+			for _, name := range templateFuncs {
+				if identifier.Ident == name {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("unsafe template output %s, use ident or arg", n)
+	case *parse.IfNode:
+		return checkTemplateBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		return checkTemplateBranch(&n.BranchNode)
+	case *parse.WithNode:
+		return checkTemplateBranch(&n.BranchNode)
+	case *parse.TemplateNode:
+		return fmt.Errorf("template calls are not allowed in SQL templates : %s", n)
+	}
+	return nil
+}
+
+// checkTemplateBranch checks both parts of a branch node (if, range, with).
+func checkTemplateBranch(branch *parse.BranchNode) error {
+	if err := checkTemplateOutputs(branch.List); err != nil {
+		return err
+	}
+	return checkTemplateOutputs(branch.ElseList)
+}
@@ -0,0 +1,64 @@
+package godb
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRawSQLTemplate(t *testing.T) {
+	Convey("Given a DB", t, func() {
+		db := createInMemoryConnection(t)
+		defer db.Close()
+
+		Convey("ident quotes identifiers and arg adds placeholders", func() {
+			data := map[string]interface{}{"Table": "dummies", "Column": "a_text", "Value": "foo"}
+			query, args, err := db.RawSQLTemplate("SELECT * FROM {{ident .Table}} WHERE {{ident .Column}} = {{arg .Value}}", data).ToSQL()
+			So(err, ShouldBeNil)
+			So(query, ShouldEqual, "SELECT * FROM \"dummies\" WHERE \"a_text\" = ?")
+			So(args, ShouldResemble, []interface{}{"foo"})
+		})
+
+		Convey("arg expands slices", func() {
+			data := map[string]interface{}{"IDs": []int{1, 2, 3}}
+			query, args, err := db.RawSQLTemplate("SELECT * FROM dummies WHERE id IN ({{arg .IDs}})", data).ToSQL()
+			So(err, ShouldBeNil)
+			So(query, ShouldEqual, "SELECT * FROM dummies WHERE id IN (?,?,?)")
+			So(args, ShouldResemble, []interface{}{1, 2, 3})
+		})
+
+		Convey("The template language is available", func() {
+			data := map[string]interface{}{"Columns": []string{"id", "a_text"}, "Filter": true}
+			query, _, err := db.RawSQLTemplate("SELECT {{range $i, $c := .Columns}}{{if $i}}, {{end}}{{ident $c}}{{end}} FROM dummies{{if .Filter}} WHERE id > {{arg 1}}{{end}}", data).ToSQL()
+			So(err, ShouldBeNil)
+			So(query, ShouldEqual, "SELECT \"id\", \"a_text\" FROM dummies WHERE id > ?")
+		})
+
+		Convey("Outputs without ident or arg are refused", func() {
+			data := map[string]interface{}{"Value": "1; DROP TABLE dummies"}
+			_, _, err := db.RawSQLTemplate("SELECT * FROM dummies WHERE id = {{.Value}}", data).ToSQL()
+			So(err, ShouldNotBeNil)
+			_, _, err = db.RawSQLTemplate("SELECT * FROM dummies WHERE id = {{ident .Value | printf \"%s\"}}", data).ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Invalid identifiers are refused", func() {
+			data := map[string]interface{}{"Column": "a_text\" = '' OR \"1"}
+			_, _, err := db.RawSQLTemplate("SELECT {{ident .Column}} FROM dummies", data).ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Do executes the query built by the template", func() {
+			dummies := make([]Dummy, 0)
+			data := map[string]interface{}{"Column": "an_integer", "Values": []int{11, 12}}
+			err := db.RawSQLTemplate("SELECT * FROM dummies WHERE {{ident .Column}} IN ({{arg .Values}})", data).Do(&dummies)
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 2)
+		})
+	})
+}
@@ -2,6 +2,7 @@ package godb
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -28,6 +29,16 @@ func TestRawSQLDo(t *testing.T) {
 			So(err, ShouldEqual, sql.ErrNoRows)
 		})
 
+		Convey("Do returns a *NotFoundError if a single instance is not found and NotFoundError is used", func() {
+			db.UseNotFoundError()
+			dummy := Dummy{}
+			err := db.RawSQL("select * from dummies where an_integer = ?", 123).Do(&dummy)
+			notFound, ok := err.(*NotFoundError)
+			So(ok, ShouldBeTrue)
+			So(notFound.Criteria, ShouldEqual, "select * from dummies where an_integer = ?")
+			So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+		})
+
 		Convey("Do execute the query and fills a slice", func() {
 			dummiesSlice := make([]Dummy, 0)
 			err := db.RawSQL("select * from dummies").Do(&dummiesSlice)
@@ -73,3 +84,140 @@ func TestRawSQLDoWithIterator(t *testing.T) {
 		})
 	})
 }
+
+func TestRawSQLExpandSlices(t *testing.T) {
+	Convey("Given a raw query with slices arguments", t, func() {
+		db := createInMemoryConnection(t)
+		defer db.Close()
+
+		Convey("ToSQL does not expand slices by default", func() {
+			query, args, err := db.RawSQL("select * from dummies where id in (?)", []int{1, 2}).ToSQL()
+			So(err, ShouldBeNil)
+			So(query, ShouldEqual, "select * from dummies where id in (?)")
+			So(len(args), ShouldEqual, 1)
+		})
+
+		Convey("ToSQL expands slices with ExpandSlices", func() {
+			query, args, err := db.RawSQL("select * from dummies where id in (?) and a_text = ? and a_blob = ?", []int{1, 2}, "foo", []byte("bar")).
+				ExpandSlices().
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(query, ShouldEqual, "select * from dummies where id in (?,?) and a_text = ? and a_blob = ?")
+			So(args, ShouldResemble, []interface{}{1, 2, "foo", []byte("bar")})
+		})
+
+		Convey("ToSQL keeps nil arguments with ExpandSlices", func() {
+			_, args, err := db.RawSQL("update dummies set a_nullable_string = ?", nil).ExpandSlices().ToSQL()
+			So(err, ShouldBeNil)
+			So(args, ShouldResemble, []interface{}{nil})
+		})
+
+		Convey("ToSQL returns an error with an empty slice", func() {
+			_, _, err := db.RawSQL("select * from dummies where id in (?)", []int{}).ExpandSlices().ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("ToSQL returns an error if the arguments count is wrong", func() {
+			_, _, err := db.RawSQL("select * from dummies where id in (?)", 1, 2).ExpandSlices().ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Do executes the query with expanded slices", func() {
+			dummies := make([]Dummy, 0)
+			err := db.RawSQL("select * from dummies where an_integer in (?)", []int{11, 13}).
+				ExpandSlices().
+				Do(&dummies)
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 2)
+		})
+	})
+}
+
+func TestRawSQLDoExec(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("DoExec executes the statement and returns the affected rows count", func() {
+			rowsAffected, _, err := db.RawSQL("update dummies set a_text = ? where an_integer > ?", "Updated", 11).DoExec()
+			So(err, ShouldBeNil)
+			So(rowsAffected, ShouldEqual, 2)
+		})
+
+		Convey("DoExec returns the last inserted id", func() {
+			rowsAffected, lastInsertID, err := db.RawSQL("insert into dummies (a_text, another_text, an_integer) values (?, ?, ?)", "Fourth", "Quatrième", 14).DoExec()
+			So(err, ShouldBeNil)
+			So(rowsAffected, ShouldEqual, 1)
+			So(lastInsertID, ShouldEqual, 4)
+		})
+
+		Convey("DoExec executes statements without rows like DDL", func() {
+			_, _, err := db.RawSQL("create table others (id integer not null primary key)").DoExec()
+			So(err, ShouldBeNil)
+		})
+
+		Convey("DoExec uses the current transaction", func() {
+			err := db.Begin()
+			So(err, ShouldBeNil)
+			_, _, err = db.RawSQL("delete from dummies").DoExec()
+			So(err, ShouldBeNil)
+			err = db.Rollback()
+			So(err, ShouldBeNil)
+
+			count, err := db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+	})
+}
+
+func TestRawSQLDoMulti(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("DoMulti fills a record with the result set", func() {
+			dummies := make([]Dummy, 0)
+			err := db.RawSQL("select * from dummies where an_integer > ?", 11).DoMulti(&dummies)
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 2)
+		})
+
+		Convey("DoMulti returns an error if there are fewer result sets than records", func() {
+			dummies := make([]Dummy, 0)
+			related := make([]RelatedToDummy, 0)
+			err := db.RawSQL("select * from dummies").DoMulti(&dummies, &related)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("DoMulti returns an error without records", func() {
+			err := db.RawSQL("select * from dummies").DoMulti()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestCallProc(t *testing.T) {
+	Convey("Given a DB", t, func() {
+		db := createInMemoryConnection(t)
+		defer db.Close()
+
+		Convey("CallProc builds a CALL statement by default", func() {
+			query, args, err := db.CallProc("my_proc", 1, "foo").ToSQL()
+			So(err, ShouldBeNil)
+			So(query, ShouldEqual, "CALL \"my_proc\"(?, ?)")
+			So(args, ShouldResemble, []interface{}{1, "foo"})
+		})
+
+		Convey("CallProc quotes all parts of the procedure name", func() {
+			query, _, err := db.CallProc("my_schema.my_proc").ToSQL()
+			So(err, ShouldBeNil)
+			So(query, ShouldEqual, "CALL \"my_schema\".\"my_proc\"()")
+		})
+	})
+}
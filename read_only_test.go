@@ -0,0 +1,69 @@
+package godb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/trino"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReadOnly(t *testing.T) {
+	Convey("Given a test database in read only mode", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		db.SetReadOnly(true)
+		So(db.IsReadOnly(), ShouldBeTrue)
+
+		Convey("Statements changing data are rejected", func() {
+			err := db.Insert(&Dummy{AText: "Foo", AnotherText: "Bar"}).Do()
+			So(errors.Is(err, ErrReadOnly), ShouldBeTrue)
+
+			_, err = db.UpdateTable("dummies").Set("an_integer", 0).Do()
+			So(errors.Is(err, ErrReadOnly), ShouldBeTrue)
+
+			_, err = db.DeleteFrom("dummies").Do()
+			So(errors.Is(err, ErrReadOnly), ShouldBeTrue)
+			readOnlyError := &ReadOnlyError{}
+			So(errors.As(err, &readOnlyError), ShouldBeTrue)
+			So(readOnlyError.Query, ShouldEqual, "DELETE FROM dummies")
+
+			_, _, err = db.RawSQL("delete from dummies").DoExec()
+			So(errors.Is(err, ErrReadOnly), ShouldBeTrue)
+
+			db.SetReadOnly(false)
+			count, err := db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("Selects are allowed", func() {
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+		})
+	})
+}
+
+func TestReadOnlyAdapter(t *testing.T) {
+	Convey("Given a DB using a read only adapter", t, func() {
+		db := &DB{adapter: trino.Adapter}
+		So(db.IsReadOnly(), ShouldBeTrue)
+
+		Convey("Statements changing data are rejected without being executed", func() {
+			_, err := db.UpdateTable("events").Set("seen", true).Do()
+			So(errors.Is(err, ErrReadOnly), ShouldBeTrue)
+
+			db.SetReadOnly(false)
+			_, err = db.DeleteFrom("events").Do()
+			So(errors.Is(err, ErrReadOnly), ShouldBeTrue)
+		})
+
+		Convey("Selects are built for the adapter", func() {
+			sql, args, err := db.SelectFrom("events").Columns("id").OrderBy("id").Limit(10).Offset(20).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT id FROM events ORDER BY id OFFSET ? LIMIT ?")
+			So(args, ShouldResemble, []interface{}{int64(20), int64(10)})
+		})
+	})
+}
@@ -26,6 +26,14 @@ type tableNamer interface {
 	TableName() string
 }
 
+// identifierQuoter wraps the QuoteIdentifier method, allowing a struct to
+// quote its table and columns names itself, for edge cases like reserved
+// words or case sensitive legacy columns. If the method returns false, the
+// identifier is quoted by the adapter.
+type identifierQuoter interface {
+	QuoteIdentifier(identifier string) (string, bool)
+}
+
 // buildRecordDescription builds a recordDescription for the given object.
 // Always use a pointer as argument.
 func buildRecordDescription(record interface{}) (*recordDescription, error) {
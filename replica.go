@@ -0,0 +1,152 @@
+package godb
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// replicaLagRefreshInterval is the minimum delay between two measures of the
+// replication lag of a replica.
+var replicaLagRefreshInterval = time.Second
+
+// AddReplica opens a connection to a read replica of the database. The reads
+// declaring a maximum staleness (see SelectStatement.MaxStaleness) are
+// routed to the replicas lagging less than it, and to the primary if all
+// replicas are too stale. The others statements always use the primary.
+//
+// The replication lag is measured with an adapter specific query, at most
+// once per second and per replica. A replica whose lag can't be measured is
+// considered as too stale. The replicas are closed with the DB.
+//
+// Example :
+// 	err := db.AddReplica("host=replica1 dbname=app")
+// 	...
+// 	err = db.Select(&books).MaxStaleness(5 * time.Second).Do()
+func (db *DB) AddReplica(dataSourceName string) error {
+	querier, ok := db.adapter.(adapters.ReplicationLagQuerier)
+	if !ok {
+		return fmt.Errorf("the adapter does not support the replication lag")
+	}
+	dbInst, err := sql.Open(db.adapter.DriverName(), dataSourceName)
+	if err != nil {
+		return err
+	}
+
+	query := db.replacePlaceholders(querier.ReplicationLagQuery())
+	db.addReplica(dbInst, func(replicaDB *sql.DB) (time.Duration, error) {
+		var seconds float64
+		if err := replicaDB.QueryRow(query).Scan(&seconds); err != nil {
+			return 0, err
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	})
+	return nil
+}
+
+// addReplica adds a replica with the given function measuring its lag.
+func (db *DB) addReplica(dbInst *sql.DB, measure func(*sql.DB) (time.Duration, error)) {
+	if db.replicas == nil {
+		db.replicas = &replicaSet{now: time.Now}
+	}
+	db.replicas.add(&replica{sqlDB: dbInst, measure: measure})
+}
+
+// replica is a read replica and its last lag measure.
+type replica struct {
+	sqlDB      *sql.DB
+	measure    func(*sql.DB) (time.Duration, error)
+	lag        time.Duration
+	lagErr     error
+	measuredAt time.Time
+	measuring  bool
+}
+
+// replicaSet contains the read replicas, shared by the clones.
+type replicaSet struct {
+	mutex    sync.Mutex
+	replicas []*replica
+	// next is used to spread the reads among the fresh replicas
+	next int
+	now  func() time.Time
+}
+
+// add adds a replica to the set.
+func (rs *replicaSet) add(r *replica) {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	rs.replicas = append(rs.replicas, r)
+}
+
+// pick returns a replica whose lag is at most maxStaleness, or nil if there
+// is none. The outdated lags are measured first.
+func (rs *replicaSet) pick(maxStaleness time.Duration) *sql.DB {
+	rs.refresh()
+
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	for i := range rs.replicas {
+		r := rs.replicas[(rs.next+i)%len(rs.replicas)]
+		if !r.measuredAt.IsZero() && r.lagErr == nil && r.lag <= maxStaleness {
+			rs.next = (rs.next + i + 1) % len(rs.replicas)
+			return r.sqlDB
+		}
+	}
+	return nil
+}
+
+// refresh measures the lags older than replicaLagRefreshInterval. The
+// measures are done without holding the lock, and a replica is measured by a
+// single goroutine at a time.
+func (rs *replicaSet) refresh() {
+	rs.mutex.Lock()
+	now := rs.now()
+	outdated := make([]*replica, 0, len(rs.replicas))
+	for _, r := range rs.replicas {
+		if !r.measuring && now.Sub(r.measuredAt) >= replicaLagRefreshInterval {
+			r.measuring = true
+			outdated = append(outdated, r)
+		}
+	}
+	rs.mutex.Unlock()
+
+	for _, r := range outdated {
+		lag, err := r.measure(r.sqlDB)
+		rs.mutex.Lock()
+		r.lag = lag
+		r.lagErr = err
+		r.measuredAt = rs.now()
+		r.measuring = false
+		rs.mutex.Unlock()
+	}
+}
+
+// close closes all the replicas.
+func (rs *replicaSet) close() error {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	var firstErr error
+	for _, r := range rs.replicas {
+		if err := r.sqlDB.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// getReadQueryable returns a queryable for a read statement, using a replica
+// if the statement declares a maximum staleness, there is no current
+// transaction, and a replica is fresh enough. It uses getQueryableWithOptions
+// otherwise.
+func (db *DB) getReadQueryable(query string, noTx, noStmtCache bool, options statementOptions) (queryable, error) {
+	if options.maxStaleness > 0 && db.replicas != nil && db.CurrentTx() == nil {
+		if replicaDB := db.replicas.pick(options.maxStaleness); replicaDB != nil {
+			return &queryWrapper{db: replicaDB, sqlQuery: query}, nil
+		}
+		db.logPrintln("No replica fresh enough, read from the primary")
+	}
+	return db.getQueryableWithOptions(query, noTx, noStmtCache)
+}
@@ -0,0 +1,108 @@
+package godb
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters/sqlite"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReplicas(t *testing.T) {
+	Convey("Given a DB with two replicas", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		lags := []time.Duration{10 * time.Second, 2 * time.Second}
+		var lagErr error
+		for i := range lags {
+			index := i
+			replicaDB, err := sql.Open("sqlite3", ":memory:")
+			So(err, ShouldBeNil)
+			// A single connection to keep the in-memory database
+			replicaDB.SetMaxOpenConns(1)
+			_, err = replicaDB.Exec(`create table dummies (
+				id integer not null primary key autoincrement,
+				a_text text not null, another_text text not null,
+				an_integer integer not null, a_nullable_string text,
+				version integer not null default(0));`)
+			So(err, ShouldBeNil)
+			_, err = replicaDB.Exec("insert into dummies (a_text, another_text, an_integer) values (?, 'Replica', ?)", "Replica", index)
+			So(err, ShouldBeNil)
+			db.addReplica(replicaDB, func(*sql.DB) (time.Duration, error) {
+				return lags[index], lagErr
+			})
+		}
+		now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		db.replicas.now = func() time.Time { return now }
+
+		Convey("The reads without maximum staleness use the primary", func() {
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+		})
+
+		Convey("The reads with a maximum staleness use a fresh enough replica", func() {
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).MaxStaleness(5*time.Second).Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 1)
+			So(dummies[0].AnInteger, ShouldEqual, 1)
+
+			var count int
+			So(db.SelectFrom("dummies").Columns("count(*)").MaxStaleness(time.Minute).Scanx(&count), ShouldBeNil)
+			So(count, ShouldEqual, 1)
+
+			dummies = make([]Dummy, 0)
+			So(db.RawSQL("select * from dummies").MaxStaleness(5*time.Second).Do(&dummies), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 1)
+		})
+
+		Convey("The reads use the primary when the replicas are too stale", func() {
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).MaxStaleness(time.Second).Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+		})
+
+		Convey("The lags are measured again after the refresh interval", func() {
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).MaxStaleness(time.Second).Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+
+			lags[0] = time.Second
+			lags[1] = time.Second
+			dummies = make([]Dummy, 0)
+			So(db.Select(&dummies).MaxStaleness(time.Second).Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+
+			now = now.Add(replicaLagRefreshInterval)
+			dummies = make([]Dummy, 0)
+			So(db.Select(&dummies).MaxStaleness(time.Second).Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 1)
+		})
+
+		Convey("The replicas whose lag can't be measured are not used", func() {
+			lagErr = errors.New("connection refused")
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).MaxStaleness(time.Hour).Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+		})
+
+		Convey("The reads in a transaction use the primary", func() {
+			So(db.Begin(), ShouldBeNil)
+			defer db.Rollback()
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).MaxStaleness(time.Hour).Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+		})
+	})
+
+	Convey("AddReplica needs an adapter measuring the lag", t, func() {
+		db, err := Open(sqlite.Adapter, ":memory:")
+		So(err, ShouldBeNil)
+		defer db.Close()
+		So(db.AddReplica(":memory:"), ShouldNotBeNil)
+	})
+}
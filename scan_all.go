@@ -0,0 +1,153 @@
+package godb
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ColumnMatching is the strategy matching the columns of the rows with the
+// columns of a struct, see ScanOptions.
+type ColumnMatching int
+
+const (
+	// MatchExact matches the columns having the same name (the default).
+	MatchExact ColumnMatching = iota
+	// MatchCaseInsensitive matches the columns whose names differ only by
+	// their case.
+	MatchCaseInsensitive
+	// MatchNormalized matches the columns whose names differ only by their
+	// case and their underscores (first_name, FirstName and FIRSTNAME).
+	MatchNormalized
+)
+
+// ScanOptions are the options of ScanAll.
+type ScanOptions struct {
+	// IgnoreUnknownColumns discards the columns not matching a column of
+	// the struct, instead of returning an error.
+	IgnoreUnknownColumns bool
+	// Matching is the strategy matching the columns names.
+	Matching ColumnMatching
+	// Coerce converts the values whose type doesn't match the field, like
+	// the numbers given as strings by some drivers, or the integers used as
+	// booleans (see SetTolerantScan). Without it the values are converted by
+	// database/sql, unless the DB is in tolerant scan mode.
+	Coerce bool
+	// Strict returns an *UnmappedColumnsError if the columns don't match the
+	// fields of the struct, even if the unknown columns are ignored. The
+	// strict scan mode of the DB (see SetStrictScan) also enables it.
+	Strict bool
+}
+
+// ScanAll works like ScanRows with options, for the rows whose columns don't
+// exactly match the struct (results of stored procedures, external query
+// sources, ...).
+//
+// Example :
+// 	rows, err := sqlDB.Query("EXEC dbo.ListBooks")
+// 	books := make([]Book, 0)
+// 	err = godb.ScanAll(rows, &books, godb.ScanOptions{
+// 		IgnoreUnknownColumns: true,
+// 		Matching:             godb.MatchNormalized,
+// 		Coerce:               true,
+// 	})
+func ScanAll(rows *sql.Rows, record interface{}, options ScanOptions) error {
+	return (&DB{}).ScanAll(rows, record, options)
+}
+
+// ScanAll works like the ScanAll function, the fields being masked if the
+// masking mode of the DB is enabled (see UseMasking).
+func (db *DB) ScanAll(rows *sql.Rows, record interface{}, options ScanOptions) error {
+	defer rows.Close()
+	recordInfo, err := buildRecordDescription(record)
+	if err != nil {
+		return err
+	}
+	rowsColumns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	columns, err := matchColumns(rowsColumns, recordInfo.structMapping.GetAllColumnsNames(), options)
+	if err != nil {
+		return fmt.Errorf("%v in struct %s", err, recordInfo.structMapping.Name)
+	}
+	if options.Strict || db.strictScan {
+		// the unknown columns are reported with their names
+		checkedColumns := make([]string, 0, len(columns))
+		for i, column := range columns {
+			if column == "" {
+				column = rowsColumns[i]
+			}
+			checkedColumns = append(checkedColumns, column)
+		}
+		if err := checkUnmappedColumns(recordInfo, checkedColumns); err != nil {
+			return err
+		}
+	}
+
+	pointersGetter := func(record interface{}, columns []string) ([]interface{}, error) {
+		known := make([]string, 0, len(columns))
+		for _, column := range columns {
+			if column != "" {
+				known = append(known, column)
+			}
+		}
+		knownPointers, err := recordInfo.structMapping.GetPointersForColumns(record, known...)
+		if err != nil {
+			return nil, err
+		}
+		pointers := make([]interface{}, 0, len(columns))
+		for _, column := range columns {
+			if column == "" {
+				pointers = append(pointers, new(interface{}))
+				continue
+			}
+			pointer := knownPointers[0]
+			knownPointers = knownPointers[1:]
+			if options.Coerce {
+				pointer = &coercingScanner{destination: pointer, config: db.tolerantScan}
+			}
+			pointers = append(pointers, pointer)
+		}
+		return pointers, nil
+	}
+	rowsCount, err := db.fillRecord(recordInfo, pointersGetter, columns, rows)
+	if err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if !recordInfo.isSlice && rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+	return rows.Close()
+}
+
+// matchColumns returns the columns of the struct matching the given columns
+// of the rows, in the same order, the unknown columns being blank.
+func matchColumns(rowsColumns []string, structColumns []string, options ScanOptions) ([]string, error) {
+	normalize := func(name string) string {
+		switch options.Matching {
+		case MatchCaseInsensitive:
+			return strings.ToLower(name)
+		case MatchNormalized:
+			return strings.ToLower(strings.Replace(name, "_", "", -1))
+		}
+		return name
+	}
+	byName := make(map[string]string, len(structColumns))
+	for _, column := range structColumns {
+		byName[normalize(column)] = column
+	}
+
+	columns := make([]string, 0, len(rowsColumns))
+	for _, rowsColumn := range rowsColumns {
+		column, ok := byName[normalize(rowsColumn)]
+		if !ok && !options.IgnoreUnknownColumns {
+			return nil, fmt.Errorf("unknown column name %s", rowsColumn)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
@@ -0,0 +1,140 @@
+package godb
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestScanAll(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("ScanAll fails with an unknown column by default", func() {
+			rows, err := db.CurrentDB().Query("select id, 'x' as extra from dummies")
+			So(err, ShouldBeNil)
+			dummies := make([]Dummy, 0)
+			err = ScanAll(rows, &dummies, ScanOptions{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, "unknown column name extra in struct")
+		})
+
+		Convey("ScanAll ignores the unknown columns if asked", func() {
+			rows, err := db.CurrentDB().Query("select id, 'x' as extra, a_text from dummies order by id")
+			So(err, ShouldBeNil)
+			dummies := make([]Dummy, 0)
+			So(ScanAll(rows, &dummies, ScanOptions{IgnoreUnknownColumns: true}), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+			So(dummies[0].ID, ShouldEqual, 1)
+			So(dummies[0].AText, ShouldEqual, "First")
+		})
+
+		Convey("ScanAll matches the names with the given strategy", func() {
+			rows, err := db.CurrentDB().Query("select id as ID, a_text as A_TEXT from dummies where id = 2")
+			So(err, ShouldBeNil)
+			dummy := Dummy{}
+			So(ScanAll(rows, &dummy, ScanOptions{Matching: MatchCaseInsensitive}), ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "Second")
+
+			rows, err = db.CurrentDB().Query("select id as Id, a_text as AText, an_integer as AnInteger from dummies where id = 3")
+			So(err, ShouldBeNil)
+			So(ScanAll(rows, &dummy, ScanOptions{Matching: MatchNormalized}), ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "Third")
+			So(dummy.AnInteger, ShouldEqual, 13)
+		})
+
+		Convey("ScanAll converts the values if asked", func() {
+			query := "select cast(id as text) as id, an_integer as a_text, ' 42 ' as an_integer from dummies where id = 1"
+			rows, err := db.CurrentDB().Query(query)
+			So(err, ShouldBeNil)
+			dummy := Dummy{}
+			So(ScanAll(rows, &dummy, ScanOptions{Coerce: true}), ShouldBeNil)
+			So(dummy.ID, ShouldEqual, 1)
+			So(dummy.AText, ShouldEqual, "11")
+			So(dummy.AnInteger, ShouldEqual, 42)
+
+			rows, err = db.CurrentDB().Query("select 'abc' as an_integer")
+			So(err, ShouldBeNil)
+			So(ScanAll(rows, &dummy, ScanOptions{Coerce: true}), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestCoercingScanner(t *testing.T) {
+	Convey("coercingScanner converts the values to the type of the field", t, func() {
+		var b bool
+		So((&coercingScanner{destination: &b}).Scan(int64(1)), ShouldBeNil)
+		So(b, ShouldBeTrue)
+
+		var f *float64
+		So((&coercingScanner{destination: &f}).Scan([]byte("1.5")), ShouldBeNil)
+		So(*f, ShouldEqual, 1.5)
+		So((&coercingScanner{destination: &f}).Scan(nil), ShouldBeNil)
+		So(f, ShouldBeNil)
+
+		var d time.Time
+		So((&coercingScanner{destination: &d}).Scan("2021-03-04 05:06:07"), ShouldBeNil)
+		So(d, ShouldResemble, time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))
+
+		var i8 int8
+		So((&coercingScanner{destination: &i8}).Scan(int64(300)), ShouldNotBeNil)
+		So((&coercingScanner{destination: &i8}).Scan(2.5), ShouldNotBeNil)
+	})
+}
+
+func TestSetTolerantScan(t *testing.T) {
+	Convey("Given a DB in tolerant scan mode", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		db.SetTolerantScan(&TolerantScanConfig{TimeLayouts: []string{"02/01/2006"}})
+
+		Convey("The mismatching values are converted by the statements", func() {
+			type textual struct {
+				ID      string `db:"id"`
+				AText   string `db:"a_text"`
+				Integer bool   `db:"an_integer"`
+			}
+			var records []textual
+			err := db.SelectFrom("dummies").OrderBy("id").Do(&records)
+			So(err, ShouldBeNil)
+			So(len(records), ShouldEqual, 3)
+			So(records[0].ID, ShouldEqual, "1")
+			So(records[0].Integer, ShouldBeTrue)
+		})
+
+		Convey("The times are parsed with the configured layouts", func() {
+			type dated struct {
+				Day time.Time `db:"day"`
+			}
+			var record dated
+			err := db.RawSQL("select '04/03/2021' as day").Do(&record)
+			So(err, ShouldBeNil)
+			So(record.Day, ShouldResemble, time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC))
+		})
+
+		Convey("The iterators convert the values too", func() {
+			type numeric struct {
+				Value float64 `db:"value"`
+			}
+			iter, err := db.RawSQL("select '12.50' as value").DoWithIterator()
+			So(err, ShouldBeNil)
+			defer iter.Close()
+			So(iter.Next(), ShouldBeTrue)
+			var record numeric
+			So(iter.Scan(&record), ShouldBeNil)
+			So(record.Value, ShouldEqual, 12.5)
+		})
+
+		Convey("A nil configuration disables it", func() {
+			db.SetTolerantScan(nil)
+			type dated struct {
+				Day time.Time `db:"day"`
+			}
+			var record dated
+			err := db.RawSQL("select '04/03/2021' as day").Do(&record)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
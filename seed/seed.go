@@ -0,0 +1,222 @@
+// Package seed applies declarative seed data (Go structs, or YAML or JSON
+// files) to a database, to bootstrap environments. The records are upserted
+// on natural keys, and the applied seeds are tracked in a table, so applying
+// the same seeds again does nothing.
+//
+// The seeds table is not created by the package, it needs the columns :
+// 	name       : the seed name, primary key (VARCHAR)
+// 	checksum   : the checksum of the applied records (VARCHAR(64))
+// 	applied_at : the time the seed was applied
+//
+// Example :
+// 	countries := []Country{{Code: "FR", Name: "France"}, {Code: "DE", Name: "Germany"}}
+// 	seeder := seed.NewSeeder(db)
+// 	err := seeder.Apply(seed.Seed{Name: "countries", Records: &countries, NaturalKey: []string{"code"}})
+package seed
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/samonzeweb/godb"
+	"github.com/samonzeweb/godb/dbreflect"
+)
+
+// DefaultTable is the default name of the seeds table.
+const DefaultTable = "godb_seeds"
+
+// Seed is a named set of records to upsert.
+type Seed struct {
+	// Name identifies the seed in the seeds table
+	Name string
+	// Records is a pointer to a slice of structs (or pointers to structs)
+	Records interface{}
+	// NaturalKey contains the columns identifying a record, the struct keys
+	// are used if empty
+	NaturalKey []string
+}
+
+// Unmarshaler decodes the data of a seed file, like json.Unmarshal or the
+// Unmarshal functions of YAML packages.
+type Unmarshaler func(data []byte, v interface{}) error
+
+// Decode builds a Seed from data decoded with the given function (or
+// json.Unmarshal if nil) into the records, a pointer to a slice of structs.
+// The struct needs the tags of the decoding package.
+//
+// Example with YAML :
+// 	var countries []Country
+// 	data, err := ioutil.ReadFile("seeds/countries.yml")
+// 	countriesSeed, err := seed.Decode("countries", data, &countries, []string{"code"}, yaml.Unmarshal)
+func Decode(name string, data []byte, records interface{}, naturalKey []string, unmarshal Unmarshaler) (*Seed, error) {
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+	if err := unmarshal(data, records); err != nil {
+		return nil, fmt.Errorf("unable to decode the seed %s : %v", name, err)
+	}
+	return &Seed{Name: name, Records: records, NaturalKey: naturalKey}, nil
+}
+
+// Seeder applies seeds to a database.
+type Seeder struct {
+	db    *godb.DB
+	table string
+}
+
+// NewSeeder creates a Seeder for the given database, using the default seeds
+// table.
+func NewSeeder(db *godb.DB) *Seeder {
+	return &Seeder{db: db, table: DefaultTable}
+}
+
+// Table changes the name of the seeds table.
+func (s *Seeder) Table(table string) *Seeder {
+	s.table = table
+	return s
+}
+
+// Apply applies the given seeds in order, in one transaction (or in the
+// current one). A seed already applied with the same records is skipped,
+// otherwise its records are inserted, or updated if a row has the same
+// natural key. The rows not in the seed are kept.
+func (s *Seeder) Apply(seeds ...Seed) error {
+	ownTx := s.db.CurrentTx() == nil
+	if ownTx {
+		if err := s.db.Begin(); err != nil {
+			return err
+		}
+	}
+
+	for _, seed := range seeds {
+		if err := s.apply(seed); err != nil {
+			if ownTx {
+				s.db.Rollback()
+			}
+			return fmt.Errorf("unable to apply the seed %s : %v", seed.Name, err)
+		}
+	}
+
+	if ownTx {
+		return s.db.Commit()
+	}
+	return nil
+}
+
+// apply applies a single seed.
+func (s *Seeder) apply(seed Seed) error {
+	checksum, err := recordsChecksum(seed.Records)
+	if err != nil {
+		return err
+	}
+
+	var appliedChecksum string
+	err = s.db.SelectFrom(s.table).
+		Columns("checksum").
+		Where("name = ?", seed.Name).
+		Scanx(&appliedChecksum)
+	applied := true
+	if errors.Is(err, sql.ErrNoRows) {
+		applied = false
+	} else if err != nil {
+		return err
+	}
+	if applied && appliedChecksum == checksum {
+		return nil
+	}
+
+	records := reflect.ValueOf(seed.Records)
+	if records.Kind() != reflect.Ptr || records.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("the records have to be a pointer to a slice, got a %T", seed.Records)
+	}
+	for i := 0; i < records.Elem().Len(); i++ {
+		record := records.Elem().Index(i)
+		if record.Kind() != reflect.Ptr {
+			record = record.Addr()
+		}
+		if err := s.upsert(record.Interface(), seed.NaturalKey); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	if applied {
+		_, err = s.db.UpdateTable(s.table).
+			Set("checksum", checksum).
+			Set("applied_at", now).
+			Where("name = ?", seed.Name).
+			Do()
+	} else {
+		_, err = s.db.InsertInto(s.table).
+			Columns("name", "checksum", "applied_at").
+			Values(seed.Name, checksum, now).
+			Do()
+	}
+	return err
+}
+
+// upsert inserts the record, or updates the row having the same natural key.
+func (s *Seeder) upsert(record interface{}, naturalKey []string) error {
+	structMapping, err := dbreflect.Cache.GetOrCreateStructMapping(reflect.TypeOf(record).Elem())
+	if err != nil {
+		return err
+	}
+	if len(naturalKey) == 0 {
+		naturalKey = structMapping.GetKeyColumnsNames()
+	}
+	if len(naturalKey) == 0 {
+		return fmt.Errorf("the struct %s has no key nor natural key", structMapping.Name)
+	}
+
+	keyPointers, err := structMapping.GetPointersForColumns(record, naturalKey...)
+	if err != nil {
+		return err
+	}
+	existing := reflect.New(reflect.TypeOf(record).Elem()).Interface()
+	selectStatement := s.db.Select(existing)
+	for i, column := range naturalKey {
+		selectStatement.WhereQ(godb.Col(column).Eq(reflect.ValueOf(keyPointers[i]).Elem().Interface()))
+	}
+	err = selectStatement.Do()
+	if errors.Is(err, sql.ErrNoRows) {
+		return s.db.Insert(record).Do()
+	}
+	if err != nil {
+		return err
+	}
+
+	// The row keeps its keys (ie auto keys) and optimistic locking version
+	copyColumns := structMapping.GetKeyColumnsNames()
+	if opLockColumn := structMapping.GetOpLockSQLFieldName(); opLockColumn != "" {
+		copyColumns = append(copyColumns, opLockColumn)
+	}
+	fromPointers, err := structMapping.GetPointersForColumns(existing, copyColumns...)
+	if err != nil {
+		return err
+	}
+	toPointers, err := structMapping.GetPointersForColumns(record, copyColumns...)
+	if err != nil {
+		return err
+	}
+	for i := range fromPointers {
+		reflect.ValueOf(toPointers[i]).Elem().Set(reflect.ValueOf(fromPointers[i]).Elem())
+	}
+	return s.db.Update(record).Do()
+}
+
+// recordsChecksum returns the SHA-256 checksum of the JSON form of the
+// records.
+func recordsChecksum(records interface{}) (string, error) {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,95 @@
+package seed
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type country struct {
+	ID   int    `db:"id,key,auto" json:"-"`
+	Code string `db:"code" json:"code"`
+	Name string `db:"name" json:"name"`
+}
+
+func (*country) TableName() string {
+	return "countries"
+}
+
+func TestApply(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db, err := godb.Open(sqlite.Adapter, ":memory:")
+		So(err, ShouldBeNil)
+		defer db.Close()
+		_, err = db.CurrentDB().Exec(`
+			create table countries (
+				id   integer not null primary key autoincrement,
+				code text not null,
+				name text not null);
+			create table godb_seeds (
+				name       text not null primary key,
+				checksum   text not null,
+				applied_at timestamp not null);
+			insert into countries (code, name) values ('FR', 'La France');
+		`)
+		So(err, ShouldBeNil)
+
+		seeder := NewSeeder(db)
+		countries := []country{{Code: "FR", Name: "France"}, {Code: "DE", Name: "Germany"}}
+
+		Convey("Apply upserts the records on the natural key", func() {
+			err := seeder.Apply(Seed{Name: "countries", Records: &countries, NaturalKey: []string{"code"}})
+			So(err, ShouldBeNil)
+
+			stored := make([]country, 0)
+			So(db.Select(&stored).OrderBy("id").Do(), ShouldBeNil)
+			So(stored, ShouldResemble, []country{{1, "FR", "France"}, {2, "DE", "Germany"}})
+
+			Convey("Applying the same seed again does nothing", func() {
+				_, err := db.CurrentDB().Exec("update countries set name = 'Changed'")
+				So(err, ShouldBeNil)
+				same := []country{{Code: "FR", Name: "France"}, {Code: "DE", Name: "Germany"}}
+				So(seeder.Apply(Seed{Name: "countries", Records: &same, NaturalKey: []string{"code"}}), ShouldBeNil)
+
+				count, err := db.SelectFrom("countries").Where("name = ?", "Changed").Count()
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 2)
+			})
+
+			Convey("A changed seed is applied again", func() {
+				changed := []country{{Code: "FR", Name: "République française"}}
+				So(seeder.Apply(Seed{Name: "countries", Records: &changed, NaturalKey: []string{"code"}}), ShouldBeNil)
+
+				stored := make([]country, 0)
+				So(db.Select(&stored).OrderBy("id").Do(), ShouldBeNil)
+				So(stored, ShouldResemble, []country{{1, "FR", "République française"}, {2, "DE", "Germany"}})
+			})
+		})
+
+		Convey("Decode builds a seed from data", func() {
+			decoded := make([]country, 0)
+			s, err := Decode("countries", []byte(`[{"code": "IT", "name": "Italy"}]`), &decoded, []string{"code"}, nil)
+			So(err, ShouldBeNil)
+			So(seeder.Apply(*s), ShouldBeNil)
+
+			count, err := db.SelectFrom("countries").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+		})
+
+		Convey("A failing seed is rolled back", func() {
+			err := seeder.Apply(
+				Seed{Name: "countries", Records: &countries, NaturalKey: []string{"code"}},
+				Seed{Name: "invalid", Records: &countries, NaturalKey: []string{"unknown"}},
+			)
+			So(err, ShouldNotBeNil)
+
+			count, err := db.SelectFrom("countries").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+	})
+}
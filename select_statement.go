@@ -3,6 +3,8 @@ package godb
 import (
 	"database/sql"
 	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/samonzeweb/godb/adapters"
@@ -22,17 +24,33 @@ type SelectStatement struct {
 
 	distinct             bool
 	columns              []string
+	columnsArgs          []interface{}
 	areColumnsFromStruct bool
 	columnAliases        map[string]string
 	fromTables           []string
+	fromArgs             []interface{}
+	tableSample          *tableSample
 	joins                []*joinPart
 	where                []*Condition
 	groupBy              []string
+	groupByArgs          []interface{}
 	having               []*Condition
+	qualify              []*Condition
 	orderBy              []string
-	limit                *int
-	offset               *int
+	orderByArgs          []interface{}
+	limit                *int64
+	offset               *int64
 	suffixes             []string
+	// unordered prevents the automatic ORDER BY on keys for single instances
+	unordered bool
+	maxRows   int
+	// strictScan overrides the strict scan mode of the DB if it isn't nil
+	strictScan *bool
+	expectOne  bool
+	// forUpdate and skipLocked add a row lock clause, see ForUpdate
+	forUpdate  bool
+	skipLocked bool
+	options    statementOptions
 }
 
 // joinPart describes a sql JOIN clause.
@@ -41,6 +59,13 @@ type joinPart struct {
 	tableName string
 	as        string
 	on        *Condition
+	args      []interface{}
+}
+
+// tableSample is a sampling of the table, see TableSample.
+type tableSample struct {
+	method  string
+	percent float64
 }
 
 // SelectFrom initializes a SELECT statement builder.
@@ -49,12 +74,73 @@ func (db *DB) SelectFrom(tableNames ...string) *SelectStatement {
 	return ss.From(tableNames...)
 }
 
+// Clone returns a copy of the statement, allowing to reuse a base query
+// and to branch it without altering the original one.
+//
+// Example :
+// 	base := db.SelectFrom("books").Where("author = ?", "Jules Verne")
+// 	count, err := base.Clone().Count()
+// 	err = base.Clone().OrderBy("title").Limit(10).Do(&books)
+func (ss *SelectStatement) Clone() *SelectStatement {
+	clone := *ss
+	clone.columns = append([]string(nil), ss.columns...)
+	clone.columnsArgs = append([]interface{}(nil), ss.columnsArgs...)
+	clone.columnAliases = make(map[string]string, len(ss.columnAliases))
+	for k, v := range ss.columnAliases {
+		clone.columnAliases[k] = v
+	}
+	clone.fromTables = append([]string(nil), ss.fromTables...)
+	clone.fromArgs = append([]interface{}(nil), ss.fromArgs...)
+	clone.joins = append([]*joinPart(nil), ss.joins...)
+	clone.where = append([]*Condition(nil), ss.where...)
+	clone.groupBy = append([]string(nil), ss.groupBy...)
+	clone.groupByArgs = append([]interface{}(nil), ss.groupByArgs...)
+	clone.having = append([]*Condition(nil), ss.having...)
+	clone.qualify = append([]*Condition(nil), ss.qualify...)
+	clone.orderBy = append([]string(nil), ss.orderBy...)
+	clone.orderByArgs = append([]interface{}(nil), ss.orderByArgs...)
+	clone.suffixes = append([]string(nil), ss.suffixes...)
+	return &clone
+}
+
 // From adds table to the select statement. It can be called multiple times.
 func (ss *SelectStatement) From(tableNames ...string) *SelectStatement {
 	ss.fromTables = append(ss.fromTables, tableNames...)
 	return ss
 }
 
+// FromExpr adds a data source given as an expression, like a list of rows
+// built with Values, with the given alias.
+func (ss *SelectStatement) FromExpr(source Expression, as string) *SelectStatement {
+	sql, args, err := buildSource(ss.db.adapter, source, as)
+	if err != nil {
+		ss.error = err
+		return ss
+	}
+	ss.fromTables = append(ss.fromTables, sql)
+	ss.fromArgs = append(ss.fromArgs, args...)
+	return ss
+}
+
+// FromFunction adds a call of a set-returning function (PostgreSQL), or of a
+// table-valued function (SQL Server), to the FROM clause, with its
+// arguments. An alias could follow the call.
+//
+// Example :
+// 	err := db.SelectFrom().
+// 		Columns("day").
+// 		FromFunction("generate_series(?::date, ?::date, '1 day') AS day", from, to).
+// 		Do(&days)
+func (ss *SelectStatement) FromFunction(call string, args ...interface{}) *SelectStatement {
+	if strings.TrimSpace(call) == "" {
+		ss.error = fmt.Errorf("empty function call")
+		return ss
+	}
+	ss.fromTables = append(ss.fromTables, call)
+	ss.fromArgs = append(ss.fromArgs, args...)
+	return ss
+}
+
 // Columns adds columns to select. Multple calls of columns are allowed.
 func (ss *SelectStatement) Columns(columns ...string) *SelectStatement {
 	if ss.areColumnsFromStruct {
@@ -66,6 +152,25 @@ func (ss *SelectStatement) Columns(columns ...string) *SelectStatement {
 	return ss
 }
 
+// ColumnsExpr adds columns to select, given as expressions : identifiers
+// checked and quoted (Ident), raw SQL expressions (Raw), ...
+// It could be mixed with Columns, but not with ColumnsFromStruct.
+func (ss *SelectStatement) ColumnsExpr(expressions ...Expression) *SelectStatement {
+	if ss.areColumnsFromStruct {
+		ss.error = fmt.Errorf("you can't mix Columns and ColumnsFromStruct to build a select query")
+		return ss
+	}
+
+	columns, args, err := buildExpressions(ss.db.adapter, expressions)
+	if err != nil {
+		ss.error = err
+		return ss
+	}
+	ss.columns = append(ss.columns, columns...)
+	ss.columnsArgs = append(ss.columnsArgs, args...)
+	return ss
+}
+
 // ColumnsFromStruct adds columns to select, extrating them from the
 // given struct (or slice of struct). Always use a pointer as argument.
 // You can't mix the use of ColumnsFromStruct and Columns methods.
@@ -80,7 +185,7 @@ func (ss *SelectStatement) ColumnsFromStruct(record interface{}) *SelectStatemen
 	if err != nil {
 		ss.error = err
 	} else {
-		columns := ss.db.quoteAll(recordInfo.structMapping.GetAllColumnsNames())
+		columns := ss.db.quoteAllFor(recordInfo, recordInfo.structMapping.GetAllColumnsNames())
 		ss.columns = append(ss.columns, columns...)
 	}
 
@@ -112,6 +217,30 @@ func (ss *SelectStatement) LeftJoin(tableName string, as string, on *Condition)
 	return ss.addJoin("LEFT JOIN", tableName, as, on)
 }
 
+// InnerJoinExpr adds an INNER JOIN clause on a data source given as an
+// expression, like a list of rows built with Values (see FromExpr).
+func (ss *SelectStatement) InnerJoinExpr(source Expression, as string, on *Condition) *SelectStatement {
+	return ss.addJoinExpr("INNER JOIN", source, as, on)
+}
+
+// LeftJoinExpr adds a LEFT JOIN clause on a data source given as an
+// expression, like a list of rows built with Values (see FromExpr).
+func (ss *SelectStatement) LeftJoinExpr(source Expression, as string, on *Condition) *SelectStatement {
+	return ss.addJoinExpr("LEFT JOIN", source, as, on)
+}
+
+// addJoinExpr adds a join clause on the given expression.
+func (ss *SelectStatement) addJoinExpr(joinType string, source Expression, as string, on *Condition) *SelectStatement {
+	sql, args, err := buildSource(ss.db.adapter, source, "")
+	if err != nil {
+		ss.error = err
+		return ss
+	}
+	ss.addJoin(joinType, sql, as, on)
+	ss.joins[len(ss.joins)-1].args = args
+	return ss
+}
+
 // addJoin adds a join clause.
 func (ss *SelectStatement) addJoin(joinType string, tableName string, as string, on *Condition) *SelectStatement {
 	join := &joinPart{
@@ -142,6 +271,18 @@ func (ss *SelectStatement) GroupBy(groupBy string) *SelectStatement {
 	return ss
 }
 
+// GroupByExpr adds GROUP BY expressions, see ColumnsExpr.
+func (ss *SelectStatement) GroupByExpr(expressions ...Expression) *SelectStatement {
+	groupBy, args, err := buildExpressions(ss.db.adapter, expressions)
+	if err != nil {
+		ss.error = err
+		return ss
+	}
+	ss.groupBy = append(ss.groupBy, groupBy...)
+	ss.groupByArgs = append(ss.groupByArgs, args...)
+	return ss
+}
+
 // Having adds a HAVING clause with a condition build with a sql string and
 // its arguments (like Where).
 func (ss *SelectStatement) Having(sql string, args ...interface{}) *SelectStatement {
@@ -155,6 +296,33 @@ func (ss *SelectStatement) HavingQ(condition *Condition) *SelectStatement {
 	return ss
 }
 
+// Qualify adds a QUALIFY clause with a condition build with a sql string and
+// its arguments (like Where). The QUALIFY clause filters the rows once the
+// window functions are computed, for example to keep the last row of each
+// group.
+//
+// If the adapter does not support it (see adapters.Qualifier), the statement
+// is wrapped by a subquery filtered with a WHERE clause, the DISTINCT, ORDER
+// BY and limit clauses being applied to the subquery. To be portable, the
+// condition, the order and the requested columns have to use the names of
+// the columns of the statement (aliases of the window functions).
+//
+// Example :
+// 	err := db.SelectFrom("events").
+// 		Columns("*", "ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY at DESC) AS rank").
+// 		Qualify("rank = ?", 1).
+// 		Do(&lastEvents)
+func (ss *SelectStatement) Qualify(sql string, args ...interface{}) *SelectStatement {
+	return ss.QualifyQ(Q(sql, args...))
+}
+
+// QualifyQ adds a simple or complex predicate generated with Q and
+// conjunctions (like WhereQ), see Qualify.
+func (ss *SelectStatement) QualifyQ(condition *Condition) *SelectStatement {
+	ss.qualify = append(ss.qualify, condition)
+	return ss
+}
+
 // OrderBy adds an expression for the ORDER BY clause.
 // You can call GroupBy multiple times.
 func (ss *SelectStatement) OrderBy(orderBy string) *SelectStatement {
@@ -162,17 +330,148 @@ func (ss *SelectStatement) OrderBy(orderBy string) *SelectStatement {
 	return ss
 }
 
-// Offset specifies the value for the OFFSET clause.
+// OrderByExpr adds ORDER BY expressions, see ColumnsExpr. Use Asc and Desc
+// to specify the direction.
+func (ss *SelectStatement) OrderByExpr(expressions ...Expression) *SelectStatement {
+	orderBy, args, err := buildExpressions(ss.db.adapter, expressions)
+	if err != nil {
+		ss.error = err
+		return ss
+	}
+	ss.orderBy = append(ss.orderBy, orderBy...)
+	ss.orderByArgs = append(ss.orderByArgs, args...)
+	return ss
+}
+
+// MaxRows sets the maximum count of rows scanned, overriding the default one
+// of the DB (see SetMaxRows). A negative value means no limit.
+func (ss *SelectStatement) MaxRows(maxRows int) *SelectStatement {
+	ss.maxRows = maxRows
+	return ss
+}
+
+// StrictScan enables or disables the strict scan mode for the statement,
+// overriding the one of the DB (see SetStrictScan).
+func (ss *SelectStatement) StrictScan(strict bool) *SelectStatement {
+	ss.strictScan = &strict
+	return ss
+}
+
+// scanMode describes how the rows of the statement are scanned : the strict
+// scan mode, the maximum count of rows, the tolerant scan configuration and
+// the masking mode.
+func (ss *SelectStatement) scanMode() string {
+	strict := ss.db.strictScan
+	if ss.strictScan != nil {
+		strict = *ss.strictScan
+	}
+	maxRows := ss.maxRows
+	if maxRows == 0 {
+		maxRows = ss.db.maxRows
+	}
+	return fmt.Sprintf("strict=%t maxRows=%d tolerant=%p masking=%t", strict, maxRows, ss.db.tolerantScan, ss.db.masking)
+}
+
+// ExpectOne checks that a single row matches when a single instance is
+// requested : Do returns a *MultipleRecordsError (matching
+// ErrMultipleRecords) if there are more. Without it the first row is used.
+func (ss *SelectStatement) ExpectOne() *SelectStatement {
+	ss.expectOne = true
+	return ss
+}
+
+// Offset specifies the count of rows skipped, with an OFFSET clause or its
+// equivalent for the adapter (see adapters.Paginator). ToSQL returns an error
+// if it's negative, or if the adapter can't skip rows.
 func (ss *SelectStatement) Offset(offset int) *SelectStatement {
-	ss.offset = new(int)
-	*ss.offset = offset
+	return ss.OffsetInt64(int64(offset))
+}
+
+// OffsetInt64 is like Offset, with an int64.
+func (ss *SelectStatement) OffsetInt64(offset int64) *SelectStatement {
+	ss.offset = &offset
 	return ss
 }
 
-// Limit specifies the value for the LIMIT clause.
+// Limit specifies the maximum count of rows, with a LIMIT clause or its
+// equivalent for the adapter (see adapters.Paginator). ToSQL returns an
+// error if it's negative.
 func (ss *SelectStatement) Limit(limit int) *SelectStatement {
-	ss.limit = new(int)
-	*ss.limit = limit
+	return ss.LimitInt64(int64(limit))
+}
+
+// LimitInt64 is like Limit, with an int64.
+func (ss *SelectStatement) LimitInt64(limit int64) *SelectStatement {
+	ss.limit = &limit
+	return ss
+}
+
+// Sampling methods of TableSample.
+const (
+	// SampleSystem samples the blocks of the table, it's fast but the rows of
+	// a block are all kept or all skipped.
+	SampleSystem = "SYSTEM"
+	// SampleBernoulli samples each row of the table.
+	SampleBernoulli = "BERNOULLI"
+)
+
+// TableSample reads only a random sample of the rows of the table of the
+// FROM clause, using the given method (SampleSystem or SampleBernoulli) and
+// keeping about the given percentage of rows (0 to 100). The sample is taken
+// before any condition is applied.
+// ToSQL returns an error if the statement has several tables in its FROM
+// clause, or if the adapter does not support the method (PostgreSQL supports
+// both, SQL Server only SampleSystem).
+//
+// Example :
+// 	count, err := db.SelectFrom("events").
+// 		TableSample(godb.SampleBernoulli, 1).
+// 		Where("payload IS NULL").
+// 		Count()
+func (ss *SelectStatement) TableSample(method string, percent float64) *SelectStatement {
+	ss.tableSample = &tableSample{method: method, percent: percent}
+	return ss
+}
+
+// ForUpdate locks the selected rows until the end of the transaction, with a
+// clause given by the adapter (FOR UPDATE with PostgreSQL and MySQL). ToSQL
+// returns an error if the adapter does not support row locks.
+func (ss *SelectStatement) ForUpdate() *SelectStatement {
+	ss.forUpdate = true
+	return ss
+}
+
+// ForUpdateSkipLocked is like ForUpdate, but the rows already locked by
+// others transactions are skipped instead of waited for (FOR UPDATE SKIP
+// LOCKED). It allows concurrent workers to process distinct rows, like a
+// queue.
+func (ss *SelectStatement) ForUpdateSkipLocked() *SelectStatement {
+	ss.forUpdate = true
+	ss.skipLocked = true
+	return ss
+}
+
+// Priority sets the priority of the statement when the concurrent queries
+// are limited, see SetMaxConcurrentQueries.
+func (ss *SelectStatement) Priority(priority Priority) *SelectStatement {
+	ss.options.priority = priority
+	return ss
+}
+
+// Timeout sets the maximum execution time of the query, the reading of its
+// rows included. It's cancelled when the timeout expires, like when the
+// context of the DB is done (see SetContext). The PostgreSQL and SQL Server
+// drivers stop it on the server, and a MAX_EXECUTION_TIME hint is added for
+// MySQL.
+func (ss *SelectStatement) Timeout(timeout time.Duration) *SelectStatement {
+	ss.options.timeout = timeout
+	return ss
+}
+
+// MaxStaleness allows the statement to read from a replica lagging at most
+// the given duration, see AddReplica. It's ignored in a transaction.
+func (ss *SelectStatement) MaxStaleness(maxStaleness time.Duration) *SelectStatement {
+	ss.options.maxStaleness = maxStaleness
 	return ss
 }
 
@@ -199,44 +498,127 @@ func (ss *SelectStatement) ToSQL() (string, []interface{}, error) {
 		return "", nil, err
 	}
 
+	sqlQualifyLength, argsQualifyLength, err := sumOfConditionsLengths(ss.qualify)
+	if err != nil {
+		return "", nil, err
+	}
+
 	sqlBuffer := newSQLBuffer(
 		ss.db.adapter,
-		sqlWhereLength+sqlHavingLength+64,
-		argsWhereLength+argsHavingLength+4,
+		sqlWhereLength+sqlHavingLength+sqlQualifyLength+64,
+		argsWhereLength+argsHavingLength+argsQualifyLength+4,
 	)
 
-	sqlBuffer.Write("SELECT ")
+	style := adapters.LimitOffset
+	if paginator, ok := ss.db.adapter.(adapters.Paginator); ok {
+		style = paginator.PaginationStyle()
+	}
+	qualifyKeyword := ""
+	if qualifier, ok := ss.db.adapter.(adapters.Qualifier); ok {
+		qualifyKeyword = qualifier.QualifyKeyword()
+	}
+	rewriteQualify := len(ss.qualify) > 0 && qualifyKeyword == ""
 
-	if ss.distinct {
-		sqlBuffer.Write("DISTINCT ")
+	sqlBuffer.Write("SELECT ")
+	if !rewriteQualify {
+		ss.writeSelectModifiers(sqlBuffer, style)
 	}
 
 	sqlBuffer.writeColumns(ss.columns).
-		writeFrom(ss.fromTables...).
-		writeJoins(ss.joins).
+		Write("", ss.columnsArgs...)
+	sqlBuffer.writeFrom(ss.fromTables...).
+		Write("", ss.fromArgs...)
+	if ss.tableSample != nil {
+		if err := ss.writeTableSample(sqlBuffer); err != nil {
+			return "", nil, err
+		}
+	}
+	sqlBuffer.writeJoins(ss.joins).
 		writeWhere(ss.where).
-		writeGroupByAndHaving(ss.groupBy, ss.having).
-		writeOrderBy(ss.orderBy)
-
-	offsetFirst := false
-	if limitOffsetOrderer, ok := ss.db.adapter.(adapters.LimitOffsetOrderer); ok {
-		offsetFirst = limitOffsetOrderer.IsOffsetFirst()
+		writeGroupByAndHaving(ss.groupBy, ss.groupByArgs, ss.having)
+
+	if rewriteQualify {
+		// The rows of a subquery are filtered once the window functions are
+		// computed
+		query := sqlBuffer
+		sqlBuffer = newSQLBuffer(ss.db.adapter, query.SQLLen()+sqlQualifyLength+64, len(query.Arguments())+argsQualifyLength+4)
+		sqlBuffer.Write("SELECT ")
+		ss.writeSelectModifiers(sqlBuffer, style)
+		sqlBuffer.Write("* FROM (").
+			Append(query.SQLBuffer).
+			Write(") godb_qualify")
+		sqlBuffer.writeWhere(ss.qualify)
+	} else if len(ss.qualify) > 0 {
+		sqlBuffer.Write(" " + qualifyKeyword + " ")
+		sqlBuffer.writeConditions(ss.qualify)
 	}
-	if offsetFirst {
-		// Offset is before limit
-		sqlBuffer.writeOffset(ss.offset).
-			writeLimit(ss.limit)
-	} else {
-		// Limit is before offset (default case)
-		sqlBuffer.writeLimit(ss.limit).
-			writeOffset(ss.offset)
+
+	sqlBuffer.writeOrderBy(ss.orderBy, ss.orderByArgs).
+		writeLimitOffset(ss.limit, ss.offset, len(ss.orderBy) > 0)
+
+	if style == adapters.RowNum && ss.limit != nil && sqlBuffer.Err() == nil {
+		// The limit applies to the ordered rows of a subquery
+		query := sqlBuffer
+		sqlBuffer = newSQLBuffer(ss.db.adapter, query.SQLLen()+64, len(query.Arguments())+1)
+		sqlBuffer.Write("SELECT * FROM (").
+			Append(query.SQLBuffer).
+			Write(") WHERE ROWNUM <= "+Placeholder, *ss.limit)
 	}
 
+	if ss.forUpdate {
+		rowLockBuilder, ok := ss.db.adapter.(adapters.RowLockBuilder)
+		if !ok {
+			return "", nil, fmt.Errorf("the adapter does not support row locks")
+		}
+		sqlBuffer.writeStringsWithSpaces([]string{rowLockBuilder.BuildRowLock(ss.skipLocked)})
+	}
 	sqlBuffer.writeStringsWithSpaces(ss.suffixes)
 
 	return sqlBuffer.SQL(), sqlBuffer.Arguments(), sqlBuffer.Err()
 }
 
+// writeTableSample writes the TABLESAMPLE clause given by the adapter.
+func (ss *SelectStatement) writeTableSample(sqlBuffer *sqlBuffer) error {
+	if len(ss.fromTables) != 1 {
+		return fmt.Errorf("TableSample needs a single table in the FROM clause")
+	}
+	if ss.tableSample.percent < 0 || ss.tableSample.percent > 100 {
+		return fmt.Errorf("invalid sample percentage %v", ss.tableSample.percent)
+	}
+	builder, ok := ss.db.adapter.(adapters.TableSampleBuilder)
+	if !ok {
+		return fmt.Errorf("the adapter does not support table samples")
+	}
+	clause := builder.BuildTableSample(ss.tableSample.method)
+	if clause == "" {
+		return fmt.Errorf("the adapter does not support the %s sampling method", ss.tableSample.method)
+	}
+	sqlBuffer.Write(" "+clause, ss.tableSample.percent)
+	return nil
+}
+
+// writeSelectModifiers writes the DISTINCT keyword, and the TOP clause for
+// the adapters limiting the rows with it.
+func (ss *SelectStatement) writeSelectModifiers(sqlBuffer *sqlBuffer, style adapters.PaginationStyle) {
+	if ss.distinct {
+		sqlBuffer.Write("DISTINCT ")
+	}
+	if style == adapters.Top && ss.limit != nil && *ss.limit >= 0 {
+		sqlBuffer.Write("TOP ("+Placeholder+") ", *ss.limit)
+	}
+}
+
+// ToInterpolatedSQL returns the SQL query with the arguments written as
+// literals for the adapter, to be pasted into a SQL client. Debug only : the
+// statements are always executed with placeholders.
+func (ss *SelectStatement) ToInterpolatedSQL() (string, error) {
+	query, args, err := ss.ToSQL()
+	if err != nil {
+		return "", err
+	}
+	return interpolateSQL(ss.db.adapter, query, args)
+}
+
 // Do executes the select statement.
 // The record argument has to be a pointer to a struct or a slice.
 // If no columns is defined for current select statement, all columns are
@@ -255,7 +637,7 @@ func (ss *SelectStatement) Do(record interface{}) error {
 	// If no columns defined for selection, get all columns (SELECT * FROM)
 	if len(ss.columns) == 0 {
 		ss.areColumnsFromStruct = true
-		columns := ss.db.quoteAll(recordInfo.structMapping.GetAllColumnsNames())
+		columns := ss.db.quoteAllFor(recordInfo, recordInfo.structMapping.GetAllColumnsNames())
 		ss.columns = append(ss.columns, columns...)
 	}
 
@@ -278,22 +660,27 @@ func (ss *SelectStatement) Do(record interface{}) error {
 		return pointers, err
 	}
 
-	return ss.do(recordInfo, f)
+	err = ss.do(recordInfo, f)
+	if err == sql.ErrNoRows && ss.db.useNotFoundError {
+		err = newNotFoundError(strings.Join(ss.fromTables, ", "), ss.where)
+	}
+	return err
 }
 
 // do executes the statement and fill the struct or slice given through the
 // recordDescription.
 func (ss *SelectStatement) do(recordInfo *recordDescription, pointersGetter pointersGetter) error {
 	if !recordInfo.isSlice {
-		// Only one row is requested
-		ss.Limit(1)
-		// Some DB require an offset if a limit is specified (MS SQL Server)
-		if ss.offset == nil {
-			ss.Offset(0)
+		// Only one row is requested, a second one is read to detect
+		// duplicates if needed
+		if ss.expectOne {
+			ss.Limit(2)
+		} else {
+			ss.Limit(1)
 		}
-		// Some DB require an order by if offset and limit are used
-		// (still MS SQL Server)
-		if len(ss.orderBy) == 0 {
+		// The first row is the one of the lowest key, unless another order
+		// is given
+		if len(ss.orderBy) == 0 && !ss.unordered {
 			keysColumns := recordInfo.structMapping.GetKeyColumnsNames()
 			for _, keyColumn := range keysColumns {
 				ss.OrderBy(keyColumn)
@@ -305,8 +692,14 @@ func (ss *SelectStatement) do(recordInfo *recordDescription, pointersGetter poin
 	if err != nil {
 		return err
 	}
+	if err := ss.checkGuards(sqlQuery, args); err != nil {
+		return err
+	}
 
-	rowsCount, err := ss.db.doSelectOrWithReturning(sqlQuery, args, recordInfo, pointersGetter)
+	rowsCount, err := ss.db.doSelectOnce(sqlQuery, args, ss.scanMode(), recordInfo, ss.db.limitRows(ss.maxRows, ss.db.checkColumns(ss.strictScan, recordInfo, pointersGetter)), ss.options)
+	if _, ok := err.(*MultipleRecordsError); ok {
+		return newMultipleRecordsError(strings.Join(ss.fromTables, ", "), ss.where)
+	}
 	if err != nil {
 		return err
 	}
@@ -326,15 +719,37 @@ func (ss *SelectStatement) Scanx(dest ...interface{}) error {
 	if err != nil {
 		return err
 	}
-	stmt = ss.db.replacePlaceholders(stmt)
+	if err := ss.checkGuards(stmt, args); err != nil {
+		return err
+	}
+	if ss.db.dryRun {
+		ss.db.skipStatement(stmt, args, ss.options)
+		for _, d := range dest {
+			if value := reflect.ValueOf(d); value.Kind() == reflect.Ptr && !value.IsNil() {
+				value.Elem().Set(reflect.Zero(value.Elem().Type()))
+			}
+		}
+		return nil
+	}
+	stmt = ss.db.hintTimeout(ss.db.replacePlaceholders(stmt), ss.options)
 
+	finish, releaseSlot, err := ss.db.startExecution(ss.options)
+	if err != nil {
+		ss.db.logExecutionErr(err, stmt, args)
+		return err
+	}
+	defer releaseSlot()
 	startTime := time.Now()
-	queryable, err := ss.db.getQueryable(stmt)
+	queryable, err := ss.db.getReadQueryable(stmt, false, false, ss.options)
 	if err != nil {
+		finish(err)
 		ss.db.logExecutionErr(err, stmt, args)
 		return err
 	}
-	err = queryable.QueryRow(args...).Scan(dest...)
+	ctx, cancel := ss.db.executionContext(ss.options)
+	err = queryable.QueryRowContext(ctx, args...).Scan(dest...)
+	cancel()
+	finish(err)
 	consumedTime := timeElapsedSince(startTime)
 	ss.db.addConsumedTime(consumedTime)
 	ss.db.logExecution(consumedTime, stmt, args)
@@ -350,6 +765,7 @@ func (ss *SelectStatement) Scanx(dest ...interface{}) error {
 // and returns the count.
 func (ss *SelectStatement) Count() (int64, error) {
 	ss.columns = ss.columns[:0]
+	ss.columnsArgs = nil
 	ss.Columns("COUNT(*)")
 
 	var count int64
@@ -366,6 +782,25 @@ func (ss *SelectStatement) DoWithIterator() (Iterator, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := ss.checkGuards(sqlQuery, args); err != nil {
+		return nil, err
+	}
+
+	return ss.db.doWithIterator(sqlQuery, args, ss.options)
+}
 
-	return ss.db.doWithIterator(sqlQuery, args)
+// checkGuards gives the built statement to the guards of the DB.
+func (ss *SelectStatement) checkGuards(query string, args []interface{}) error {
+	tables := guardedTableNames(ss.fromTables...)
+	for _, join := range ss.joins {
+		tables = append(tables, guardedTableName(join.tableName))
+	}
+	return ss.db.checkGuards(&StatementInfo{
+		Kind:      "SELECT",
+		Tables:    tables,
+		Columns:   ss.columns,
+		HasWhere:  len(ss.where) > 0,
+		Query:     query,
+		Arguments: args,
+	})
 }
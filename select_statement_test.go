@@ -4,6 +4,9 @@ import (
 	"database/sql"
 	"testing"
 
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/adapters/mssql"
+	"github.com/samonzeweb/godb/adapters/postgresql"
 	"github.com/samonzeweb/godb/adapters/sqlite"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -27,6 +30,26 @@ func TestNewSelectStatement(t *testing.T) {
 	})
 }
 
+func TestSelectClone(t *testing.T) {
+	Convey("Given a select statement", t, func() {
+		db := &DB{}
+		base := db.SelectFrom("dummies").Columns("foo").Where("foo > ?", 1)
+
+		Convey("Clone returns an independant copy", func() {
+			clone := base.Clone().Columns("bar").Where("bar = ?", 2).OrderBy("foo").Limit(10)
+			sql, args, err := clone.ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT foo, bar FROM dummies WHERE foo > ? AND bar = ? ORDER BY foo LIMIT ?")
+			So(args, ShouldResemble, []interface{}{1, 2, int64(10)})
+
+			sql, args, err = base.ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT foo FROM dummies WHERE foo > ?")
+			So(args, ShouldResemble, []interface{}{1})
+		})
+	})
+}
+
 func TestSelectColumns(t *testing.T) {
 	Convey("Given a select statement", t, func() {
 		db := &DB{}
@@ -315,14 +338,14 @@ func TestSelectToSQL(t *testing.T) {
 			q.Offset(10)
 			sql, args, _ := q.ToSQL()
 			So(sql, ShouldEndWith, "OFFSET ?")
-			So(args[0].(int), ShouldEqual, 10)
+			So(args[0].(int64), ShouldEqual, 10)
 		})
 
 		Convey("Calling Limit will add the limit clause to SQL", func() {
 			q.Limit(10)
 			sql, args, _ := q.ToSQL()
 			So(sql, ShouldEndWith, "LIMIT ?")
-			So(args[0].(int), ShouldEqual, 10)
+			So(args[0].(int64), ShouldEqual, 10)
 		})
 
 		Convey("Calling Suffix will add the given clause to SQL", func() {
@@ -334,6 +357,33 @@ func TestSelectToSQL(t *testing.T) {
 	})
 }
 
+func TestSelectForUpdate(t *testing.T) {
+	Convey("Given a select query", t, func() {
+		db := &DB{adapter: postgresql.Adapter}
+		q := db.SelectFrom("dummies").
+			Columns("foo").
+			Limit(10)
+
+		Convey("ForUpdate adds the row lock clause of the adapter", func() {
+			sql, _, err := q.ForUpdate().Suffix("/* job */").ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT foo FROM dummies LIMIT ? FOR UPDATE /* job */")
+		})
+
+		Convey("ForUpdateSkipLocked adds the row lock clause skipping the locked rows", func() {
+			sql, _, err := q.ForUpdateSkipLocked().ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEndWith, "FOR UPDATE SKIP LOCKED")
+		})
+
+		Convey("ForUpdate fails with an adapter without row locks", func() {
+			db.adapter = sqlite.Adapter
+			_, _, err := q.ForUpdate().ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
 func TestSelectToSQLErrors(t *testing.T) {
 	Convey("Columns are mandatory", t, func() {
 		db := &DB{}
@@ -605,3 +655,328 @@ func TestSelectDoUsingStructForColumns(t *testing.T) {
 
 	})
 }
+
+func TestSelectWithExpressions(t *testing.T) {
+	Convey("Given a select statement", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+
+		Convey("ColumnsExpr, GroupByExpr and OrderByExpr quote identifiers and keep raw expressions", func() {
+			sql, _, err := db.SelectFrom("books").
+				ColumnsExpr(Ident("author"), Raw("count(*) as count")).
+				GroupByExpr(Ident("author")).
+				OrderByExpr(Desc(Raw("count(*)")), Ident("author")).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT \"author\", count(*) as count FROM books GROUP BY \"author\" ORDER BY count(*) DESC, \"author\"")
+		})
+
+		Convey("Invalid identifiers produce an error", func() {
+			_, _, err := db.SelectFrom("books").
+				ColumnsExpr(Ident("author, password")).
+				ToSQL()
+			So(err, ShouldNotBeNil)
+
+			_, _, err = db.SelectFrom("books").
+				Columns("author").
+				OrderByExpr(Ident("author desc")).
+				ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("ColumnsExpr can't be used after ColumnsFromStruct", func() {
+			_, _, err := db.SelectFrom("books").
+				ColumnsFromStruct(&structWithColumns{}).
+				ColumnsExpr(Ident("author")).
+				ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSelectDoWithNestedStructPointer(t *testing.T) {
+	Convey("Given a test database with a dummy without related row", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		_, err := db.DeleteFrom("relatedtodummies").Where("a_text = ?", "REL_Second").Do()
+		So(err, ShouldBeNil)
+
+		type DummyWithRelated struct {
+			Dummy   `db:",rel=dummies"`
+			Related *RelatedToDummy `db:",rel=relatedtodummies"`
+		}
+
+		Convey("Do sets the nested struct pointer to nil if all its columns are NULL", func() {
+			dummies := make([]DummyWithRelated, 0)
+			err := db.SelectFrom("dummies").
+				ColumnsFromStruct(&DummyWithRelated{}).
+				LeftJoin("relatedtodummies", "relatedtodummies", Q("relatedtodummies.dummies_id = dummies.id")).
+				OrderBy("dummies.id").
+				Do(&dummies)
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+			So(dummies[0].Related, ShouldNotBeNil)
+			So(dummies[0].Related.AText, ShouldEqual, "REL_First")
+			So(dummies[1].AText, ShouldEqual, "Second")
+			So(dummies[1].Related, ShouldBeNil)
+			So(dummies[2].Related, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSelectToInterpolatedSQL(t *testing.T) {
+	Convey("Given a select query of PostgreSQL", t, func() {
+		db := &DB{adapter: postgresql.Adapter}
+		q := db.SelectFrom("dummies").
+			Columns("foo").
+			Where("bar = ? AND active = ?", "it's", true).
+			Limit(10)
+
+		Convey("ToInterpolatedSQL writes the arguments as literals", func() {
+			sql, err := q.ToInterpolatedSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT foo FROM dummies WHERE bar = 'it''s' AND active = TRUE LIMIT 10")
+		})
+	})
+}
+
+// paginatedAdapter gives the pagination style of the wrapped adapter.
+type paginatedAdapter struct {
+	adapters.Adapter
+	style adapters.PaginationStyle
+}
+
+func (a paginatedAdapter) PaginationStyle() adapters.PaginationStyle {
+	return a.style
+}
+
+func TestSelectPagination(t *testing.T) {
+	Convey("Given a select query", t, func() {
+		build := func(style adapters.PaginationStyle, q func(*SelectStatement)) (string, []interface{}, error) {
+			db := &DB{adapter: paginatedAdapter{sqlite.Adapter, style}}
+			ss := db.SelectFrom("dummies").Columns("foo")
+			q(ss)
+			return ss.ToSQL()
+		}
+
+		Convey("LimitOffset writes LIMIT and OFFSET with int64 values", func() {
+			sql, args, err := build(adapters.LimitOffset, func(ss *SelectStatement) {
+				ss.LimitInt64(1 << 40).Offset(20)
+			})
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT foo FROM dummies LIMIT ? OFFSET ?")
+			So(args, ShouldResemble, []interface{}{int64(1 << 40), int64(20)})
+		})
+
+		Convey("OffsetFetch writes OFFSET and FETCH with an ORDER BY", func() {
+			sql, args, err := build(adapters.OffsetFetch, func(ss *SelectStatement) {
+				ss.Limit(10)
+			})
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT foo FROM dummies ORDER BY (SELECT NULL) OFFSET ? ROWS FETCH NEXT ? ROWS ONLY")
+			So(args, ShouldResemble, []interface{}{int64(0), int64(10)})
+
+			sql, _, err = build(adapters.OffsetFetch, func(ss *SelectStatement) {
+				ss.OrderBy("foo").Offset(5)
+			})
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT foo FROM dummies ORDER BY foo OFFSET ? ROWS")
+
+			sql, _, err = build(adapters.OffsetFetch, func(ss *SelectStatement) {})
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT foo FROM dummies")
+		})
+
+		Convey("OffsetFetchUnordered writes OFFSET and FETCH without ORDER BY", func() {
+			sql, args, err := build(adapters.OffsetFetchUnordered, func(ss *SelectStatement) {
+				ss.Limit(10).Offset(5)
+			})
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT foo FROM dummies OFFSET ? ROWS FETCH NEXT ? ROWS ONLY")
+			So(args, ShouldResemble, []interface{}{int64(5), int64(10)})
+		})
+
+		Convey("Top writes TOP after DISTINCT", func() {
+			sql, args, err := build(adapters.Top, func(ss *SelectStatement) {
+				ss.Distinct().Where("bar = ?", 1).Limit(10).Offset(0)
+			})
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT DISTINCT TOP (?) foo FROM dummies WHERE bar = ?")
+			So(args, ShouldResemble, []interface{}{int64(10), 1})
+		})
+
+		Convey("RowNum wraps the query", func() {
+			sql, args, err := build(adapters.RowNum, func(ss *SelectStatement) {
+				ss.Where("bar = ?", 1).OrderBy("foo").Limit(10).Suffix("/* job */")
+			})
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT * FROM (SELECT foo FROM dummies WHERE bar = ? ORDER BY foo) WHERE ROWNUM <= ? /* job */")
+			So(args, ShouldResemble, []interface{}{1, int64(10)})
+		})
+
+		Convey("The invalid limits and offsets are rejected", func() {
+			_, _, err := build(adapters.LimitOffset, func(ss *SelectStatement) {
+				ss.Limit(-1)
+			})
+			So(err, ShouldNotBeNil)
+			_, _, err = build(adapters.OffsetFetch, func(ss *SelectStatement) {
+				ss.Offset(-1)
+			})
+			So(err, ShouldNotBeNil)
+			_, _, err = build(adapters.Top, func(ss *SelectStatement) {
+				ss.Limit(10).Offset(10)
+			})
+			So(err, ShouldNotBeNil)
+			_, _, err = build(adapters.RowNum, func(ss *SelectStatement) {
+				ss.Offset(10)
+			})
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("The SQL Server adapter uses OFFSET and FETCH", t, func() {
+		db := &DB{adapter: mssql.Adapter}
+		sql, _, err := db.SelectFrom("dummies").Columns("foo").OrderBy("foo").Limit(10).Offset(20).ToSQL()
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, "SELECT foo FROM dummies ORDER BY foo OFFSET ? ROWS FETCH NEXT ? ROWS ONLY")
+	})
+}
+
+// qualifyingAdapter supports the QUALIFY clause.
+type qualifyingAdapter struct {
+	adapters.Adapter
+}
+
+func (qualifyingAdapter) QualifyKeyword() string {
+	return "QUALIFY"
+}
+
+func TestSelectQualify(t *testing.T) {
+	Convey("Given a select query filtered on a window function", t, func() {
+		build := func(db *DB) (string, []interface{}, error) {
+			return db.SelectFrom("dummies").
+				Distinct().
+				Columns("foo", "ROW_NUMBER() OVER (PARTITION BY bar ORDER BY foo) AS rn").
+				Where("baz = ?", 1).
+				Qualify("rn = ?", 2).
+				OrderBy("foo").
+				Limit(10).
+				ToSQL()
+		}
+
+		Convey("The QUALIFY clause is written if the adapter supports it", func() {
+			sql, args, err := build(&DB{adapter: qualifyingAdapter{sqlite.Adapter}})
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT DISTINCT foo, ROW_NUMBER() OVER (PARTITION BY bar ORDER BY foo) AS rn FROM dummies WHERE baz = ? QUALIFY rn = ? ORDER BY foo LIMIT ?")
+			So(args, ShouldResemble, []interface{}{1, 2, int64(10)})
+		})
+
+		Convey("The query is wrapped by a subquery otherwise", func() {
+			sql, args, err := build(&DB{adapter: sqlite.Adapter})
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT DISTINCT * FROM (SELECT foo, ROW_NUMBER() OVER (PARTITION BY bar ORDER BY foo) AS rn FROM dummies WHERE baz = ?) godb_qualify WHERE rn = ? ORDER BY foo LIMIT ?")
+			So(args, ShouldResemble, []interface{}{1, 2, int64(10)})
+		})
+
+		Convey("The TOP clause is written by the wrapping query", func() {
+			sql, _, err := build(&DB{adapter: paginatedAdapter{sqlite.Adapter, adapters.Top}})
+			So(err, ShouldBeNil)
+			So(sql, ShouldStartWith, "SELECT DISTINCT TOP (?) * FROM (SELECT foo, ")
+		})
+	})
+
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Qualify keeps the rows matching the window function", func() {
+			type rankedDummy struct {
+				ID   int `db:"id"`
+				Rank int `db:"rn"`
+			}
+			ranked := make([]rankedDummy, 0)
+			err := db.SelectFrom("dummies").
+				Columns("id", "ROW_NUMBER() OVER (ORDER BY an_integer DESC) AS rn").
+				Qualify("rn <= ?", 2).
+				OrderBy("rn").
+				Do(&ranked)
+			So(err, ShouldBeNil)
+			So(ranked, ShouldResemble, []rankedDummy{{3, 1}, {2, 2}})
+		})
+	})
+}
+
+func TestSelectTableSample(t *testing.T) {
+	Convey("Given a select query reading a sample of a table", t, func() {
+		Convey("The TABLESAMPLE clause follows the table", func() {
+			db := &DB{adapter: postgresql.Adapter}
+			sql, args, err := db.SelectFrom("dummies d").
+				Columns("id").
+				InnerJoin("others o", "", Q("o.id = d.id")).
+				TableSample(SampleBernoulli, 2.5).
+				Where("foo = ?", 1).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT id FROM dummies d TABLESAMPLE BERNOULLI (?) INNER JOIN others o ON o.id = d.id WHERE foo = ?")
+			So(args, ShouldResemble, []interface{}{2.5, 1})
+		})
+
+		Convey("ToSQL fails if the sample can't be written", func() {
+			db := &DB{adapter: postgresql.Adapter}
+			_, _, err := db.SelectFrom("dummies", "others").TableSample(SampleSystem, 10).ToSQL()
+			So(err, ShouldNotBeNil)
+			_, _, err = db.SelectFrom("dummies").TableSample(SampleSystem, 110).ToSQL()
+			So(err, ShouldNotBeNil)
+			db = &DB{adapter: mssql.Adapter}
+			_, _, err = db.SelectFrom("dummies").TableSample(SampleBernoulli, 10).ToSQL()
+			So(err, ShouldNotBeNil)
+			db = &DB{adapter: sqlite.Adapter}
+			_, _, err = db.SelectFrom("dummies").TableSample(SampleSystem, 10).ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSelectFromFunction(t *testing.T) {
+	Convey("Given a select query from a set-returning function", t, func() {
+		db := &DB{adapter: postgresql.Adapter}
+
+		Convey("The arguments of the call are merged in order", func() {
+			sql, args, err := db.SelectFrom().
+				ColumnsExpr(Raw("n * 2")).
+				Columns("d.a_text").
+				FromFunction("generate_series(?, ?) AS n", 1, 10).
+				LeftJoin("dummies d", "", Q("d.an_integer = n")).
+				Where("n > ?", 3).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT n * 2, d.a_text FROM generate_series(?, ?) AS n LEFT JOIN dummies d ON d.an_integer = n WHERE n > ?")
+			So(args, ShouldResemble, []interface{}{1, 10, 3})
+		})
+
+		Convey("An empty call is rejected", func() {
+			_, _, err := db.SelectFrom().Columns("*").FromFunction(" ").ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("The rows given by a table-valued function are read", func() {
+			type column struct {
+				Name string `db:"name"`
+			}
+			columns := make([]column, 0)
+			err := db.SelectFrom().
+				Columns("name").
+				FromFunction("pragma_table_info(?)", "dummies").
+				Where("name LIKE ?", "a%").
+				OrderBy("cid").
+				Limit(2).
+				Do(&columns)
+			So(err, ShouldBeNil)
+			So(columns, ShouldResemble, []column{{"a_text"}, {"another_text"}})
+		})
+	})
+}
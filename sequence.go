@@ -0,0 +1,122 @@
+package godb
+
+import (
+	"fmt"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// sequencesTableName is the table emulating the sequences when the adapter
+// has no native sequence (see adapters.SequenceBuilder).
+const sequencesTableName = "godb_sequences"
+
+// CreateSequence creates the sequence having the given name, starting at 1,
+// if it does not exist.
+//
+// The native sequences are used with PostgreSQL and SQL Server. With the
+// others adapters the sequences are emulated with a counter by sequence in
+// the godb_sequences table, created if needed. With MySQL don't create a
+// sequence in a transaction, the creation of the table commits it.
+func (db *DB) CreateSequence(name string) error {
+	if builder, ok := db.adapter.(adapters.SequenceBuilder); ok {
+		_, _, err := db.RawSQL(builder.BuildCreateSequence(db.quote(name))).DoExec()
+		return err
+	}
+
+	tableName := db.quote(sequencesTableName)
+	createTable := "CREATE TABLE IF NOT EXISTS " + tableName + " (" +
+		db.quote("name") + " VARCHAR(255) NOT NULL PRIMARY KEY, " +
+		db.quote("value") + " BIGINT NOT NULL)"
+	if _, _, err := db.RawSQL(createTable).DoExec(); err != nil {
+		return err
+	}
+	count, err := db.SelectFrom(tableName).Where(db.quote("name")+" = ?", name).Count()
+	if err != nil || count > 0 {
+		return err
+	}
+	_, err = db.InsertInto(tableName).Columns(db.quoteAll([]string{"name", "value"})...).Values(name, 0).Do()
+	return err
+}
+
+// NextSequenceValue returns the next value of the sequence having the given
+// name, see CreateSequence.
+//
+// An emulated sequence is incremented in a transaction, the one of the DB if
+// any : unlike a native sequence its value is given back by a rollback, and
+// the concurrent transactions wait for the end of the current one.
+//
+// The sequence fields of a struct are filled on insert when they are zero,
+// they are declared with the sequence option of the db tag.
+//
+// Example :
+// 	type Invoice struct {
+// 		ID     int   `db:"id,key,auto"`
+// 		Number int64 `db:"number,sequence=invoice_seq"`
+// 	}
+//
+// 	number, err := db.NextSequenceValue("invoice_seq")
+func (db *DB) NextSequenceValue(name string) (int64, error) {
+	var value int64
+	if builder, ok := db.adapter.(adapters.SequenceBuilder); ok {
+		iterator, err := db.RawSQL(builder.BuildNextSequenceValue(db.quote(name))).DoWithIterator()
+		if err != nil {
+			return 0, err
+		}
+		defer iterator.Close()
+		if !iterator.Next() {
+			if err := iterator.Err(); err != nil {
+				return 0, err
+			}
+			return 0, fmt.Errorf("the sequence %s gave no value", name)
+		}
+		err = iterator.Scanx(&value)
+		return value, err
+	}
+
+	ownTx := db.sqlTx == nil
+	if ownTx {
+		if err := db.Begin(); err != nil {
+			return 0, err
+		}
+	}
+	err := db.nextEmulatedSequenceValue(name, &value)
+	if ownTx {
+		if err != nil {
+			db.Rollback()
+			return 0, err
+		}
+		err = db.Commit()
+	}
+	return value, err
+}
+
+// nextEmulatedSequenceValue increments the counter of an emulated sequence,
+// and reads its value.
+func (db *DB) nextEmulatedSequenceValue(name string, value *int64) error {
+	tableName := db.quote(sequencesTableName)
+	nameColumn := db.quote("name")
+	valueColumn := db.quote("value")
+
+	updated, err := db.UpdateTable(tableName).
+		SetRaw(valueColumn+" = "+valueColumn+" + 1").
+		Where(nameColumn+" = ?", name).
+		Do()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return fmt.Errorf("the sequence %s does not exist", name)
+	}
+	return db.SelectFrom(tableName).Columns(valueColumn).Where(nameColumn+" = ?", name).Scanx(value)
+}
+
+// setSequences fills the zero sequence fields of all given records.
+func (db *DB) setSequences(recordDescription *recordDescription) error {
+	for i := 0; i < recordDescription.len(); i++ {
+		record := recordDescription.index(i)
+		if err := recordDescription.structMapping.SetSequenceFieldsValues(record, db.NextSequenceValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
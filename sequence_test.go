@@ -0,0 +1,70 @@
+package godb
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type SequencedInvoice struct {
+	ID     int   `db:"id,key,auto"`
+	Number int64 `db:"number,sequence=invoice_seq"`
+}
+
+func (*SequencedInvoice) TableName() string {
+	return "invoices"
+}
+
+func TestSequence(t *testing.T) {
+	Convey("Given a test database with an emulated sequence", t, func() {
+		db := createInMemoryConnection(t)
+		defer db.Close()
+		_, err := db.CurrentDB().Exec(`
+			create table invoices (
+				id integer not null primary key autoincrement,
+				number integer not null);`)
+		So(err, ShouldBeNil)
+		So(db.CreateSequence("invoice_seq"), ShouldBeNil)
+		So(db.CreateSequence("invoice_seq"), ShouldBeNil)
+
+		Convey("NextSequenceValue increments the sequence", func() {
+			value, err := db.NextSequenceValue("invoice_seq")
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 1)
+			value, err = db.NextSequenceValue("invoice_seq")
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 2)
+		})
+
+		Convey("NextSequenceValue is rolled back with the transaction", func() {
+			So(db.Begin(), ShouldBeNil)
+			_, err := db.NextSequenceValue("invoice_seq")
+			So(err, ShouldBeNil)
+			So(db.Rollback(), ShouldBeNil)
+			value, err := db.NextSequenceValue("invoice_seq")
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 1)
+		})
+
+		Convey("NextSequenceValue fails for an unknown sequence", func() {
+			_, err := db.NextSequenceValue("unknown_seq")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("The sequence fields are filled on insert", func() {
+			invoice := &SequencedInvoice{}
+			So(db.Insert(invoice).Do(), ShouldBeNil)
+			So(invoice.Number, ShouldEqual, 1)
+
+			invoices := []SequencedInvoice{{}, {Number: 42}, {}}
+			So(db.BulkInsert(&invoices).Do(), ShouldBeNil)
+			So(invoices[0].Number, ShouldEqual, 2)
+			So(invoices[1].Number, ShouldEqual, 42)
+			So(invoices[2].Number, ShouldEqual, 3)
+
+			count, err := db.SelectFrom("invoices").Where("number = ?", 3).Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+	})
+}
@@ -0,0 +1,259 @@
+package godb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// SessionConn is a connection given to a SessionSetup.
+type SessionConn interface {
+	// Exec executes a statement without argument on the connection.
+	Exec(ctx context.Context, query string) error
+}
+
+// SessionSetup configures the session of a connection.
+type SessionSetup func(ctx context.Context, conn SessionConn) error
+
+// SessionStatements returns a SessionSetup executing the given statements.
+//
+// Example :
+// 	// PostgreSQL
+// 	godb.SessionStatements("SET search_path TO app", "SET statement_timeout = '5s'")
+// 	// MySQL
+// 	godb.SessionStatements("SET sql_mode = 'TRADITIONAL'", "SET time_zone = '+00:00'")
+func SessionStatements(statements ...string) SessionSetup {
+	return func(ctx context.Context, conn SessionConn) error {
+		for _, statement := range statements {
+			if err := conn.Exec(ctx, statement); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// SetSessionSetup executes the given setup on each connection when it's
+// opened, and each time it's taken again from the pool, so a session
+// configuration changed by a previous use does not leak. A connection whose
+// setup fails is discarded.
+//
+// The DB has to be created by Open, OpenWithFailover, OpenConnector or
+// OpenWithDataSourceProvider. Its sql.DB is replaced, call SetSessionSetup
+// before configuring the connections pool, and before cloning the DB.
+//
+// Example :
+// 	err := db.SetSessionSetup(godb.SessionStatements("SET search_path TO app"))
+func (db *DB) SetSessionSetup(setup SessionSetup) error {
+	if db.sqlTx != nil {
+		return fmt.Errorf("SetSessionSetup can't be called in a transaction")
+	}
+
+	connector, err := db.baseConnector("SetSessionSetup")
+	if err != nil {
+		return err
+	}
+	if setup != nil {
+		connector = &sessionConnector{connector: connector, setup: setup}
+	}
+	return db.reopen(connector)
+}
+
+// baseConnector returns the connector opening the connections of the DB,
+// without session setup. The name of the calling method is used by the
+// error.
+func (db *DB) baseConnector(method string) (driver.Connector, error) {
+	switch {
+	case db.connector != nil:
+		return db.connector, nil
+	case db.failover != nil:
+		return db.failover, nil
+	case db.dataSourceName != "":
+		return &dsnConnector{driver: db.sqlDB.Driver(), dataSourceName: db.dataSourceName}, nil
+	}
+	return nil, fmt.Errorf("%s needs a DB created by Open, OpenWithFailover or OpenConnector", method)
+}
+
+// reopen replaces the sql.DB of the DB with one using the given connector.
+func (db *DB) reopen(connector driver.Connector) error {
+	if err := db.stmtCacheDB.Clear(); err != nil {
+		return err
+	}
+	previous := db.sqlDB
+	db.sqlDB = sql.OpenDB(connector)
+	return previous.Close()
+}
+
+// dsnConnector is a driver.Connector for a data source, like the one used
+// by sql.Open.
+type dsnConnector struct {
+	driver         driver.Driver
+	dataSourceName string
+}
+
+// Connect opens a connection. It implements driver.Connector.
+func (c *dsnConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return openDataSource(ctx, c.driver, c.dataSourceName)
+}
+
+// Driver returns the underlying driver. It implements driver.Connector.
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// sessionConnector is a driver.Connector executing the setup on the new
+// connections.
+type sessionConnector struct {
+	connector driver.Connector
+	setup     SessionSetup
+}
+
+// Connect opens a connection and sets its session up. It implements
+// driver.Connector.
+func (c *sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.setup(ctx, execableConn{conn}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &sessionDriverConn{Conn: conn, setup: c.setup}, nil
+}
+
+// Driver returns the underlying driver. It implements driver.Connector.
+func (c *sessionConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// execableConn implements SessionConn for a driver.Conn.
+type execableConn struct {
+	conn driver.Conn
+}
+
+// Exec executes the statement, preparing it if the driver can't execute it
+// directly.
+func (ec execableConn) Exec(ctx context.Context, query string) error {
+	if execer, ok := ec.conn.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, query, nil)
+		if err != driver.ErrSkip {
+			return err
+		}
+	}
+
+	stmt, err := (&sessionDriverConn{Conn: ec.conn}).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	if stmtExecer, ok := stmt.(driver.StmtExecContext); ok {
+		_, err = stmtExecer.ExecContext(ctx, nil)
+		return err
+	}
+	_, err = stmt.Exec(nil)
+	return err
+}
+
+// sessionDriverConn is a driver.Conn executing the setup when it's taken
+// again from the pool, and discarded once its credentials are rotated (see
+// OpenWithCredentialSource). It forwards the optional interfaces of the
+// driver, returning driver.ErrSkip to database/sql if they are not
+// implemented.
+type sessionDriverConn struct {
+	driver.Conn
+	setup SessionSetup
+	// credentials and generation are the source of the credentials used to
+	// open the connection, and their version
+	credentials *credentialConnector
+	generation  int64
+}
+
+// ResetSession is called by database/sql before reusing the connection. It
+// implements driver.SessionResetter.
+func (c *sessionDriverConn) ResetSession(ctx context.Context) error {
+	if c.credentials != nil && !c.credentials.isCurrent(ctx, c.generation) {
+		return driver.ErrBadConn
+	}
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		if err := resetter.ResetSession(ctx); err != nil {
+			return err
+		}
+	}
+	if c.setup == nil {
+		return nil
+	}
+	if err := c.setup(ctx, execableConn{c.Conn}); err != nil {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+// IsValid is called by database/sql before putting the connection back in
+// the pool. It implements driver.Validator.
+func (c *sessionDriverConn) IsValid() bool {
+	if c.credentials != nil && c.generation != c.credentials.currentGeneration() {
+		return false
+	}
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// PrepareContext implements driver.ConnPrepareContext.
+func (c *sessionDriverConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	stmt, err := c.Conn.Prepare(query)
+	if err == nil && ctx.Err() != nil {
+		stmt.Close()
+		return nil, ctx.Err()
+	}
+	return stmt, err
+}
+
+// ExecContext implements driver.ExecerContext.
+func (c *sessionDriverConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if execer, ok := c.Conn.(driver.ExecerContext); ok {
+		return execer.ExecContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+// QueryContext implements driver.QueryerContext.
+func (c *sessionDriverConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if queryer, ok := c.Conn.(driver.QueryerContext); ok {
+		return queryer.QueryContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+// BeginTx implements driver.ConnBeginTx.
+func (c *sessionDriverConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) || opts.ReadOnly {
+		return nil, fmt.Errorf("the driver does not support transaction options")
+	}
+	return c.Conn.Begin()
+}
+
+// Ping implements driver.Pinger.
+func (c *sessionDriverConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// CheckNamedValue implements driver.NamedValueChecker.
+func (c *sessionDriverConn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return driver.ErrSkip
+}
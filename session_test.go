@@ -0,0 +1,68 @@
+package godb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/sqlite"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSetSessionSetup(t *testing.T) {
+	Convey("Given a DB with a session setup", t, func() {
+		db, err := Open(sqlite.Adapter, ":memory:")
+		So(err, ShouldBeNil)
+		defer db.Close()
+
+		setups := 0
+		statements := SessionStatements("PRAGMA foreign_keys = ON")
+		err = db.SetSessionSetup(func(ctx context.Context, conn SessionConn) error {
+			setups++
+			return statements(ctx, conn)
+		})
+		So(err, ShouldBeNil)
+		db.CurrentDB().SetMaxOpenConns(1)
+
+		foreignKeys := func() int {
+			var value int
+			So(db.CurrentDB().QueryRow("PRAGMA foreign_keys").Scan(&value), ShouldBeNil)
+			return value
+		}
+
+		Convey("The setup is executed when a connection is opened", func() {
+			So(foreignKeys(), ShouldEqual, 1)
+			So(setups, ShouldEqual, 1)
+		})
+
+		Convey("The setup is executed again when a connection is reused", func() {
+			_, err := db.CurrentDB().Exec("PRAGMA foreign_keys = OFF")
+			So(err, ShouldBeNil)
+			So(foreignKeys(), ShouldEqual, 1)
+			So(setups, ShouldEqual, 2)
+		})
+
+		Convey("The setup is executed for the transactions", func() {
+			So(db.Begin(), ShouldBeNil)
+			_, err := db.SelectFrom("sqlite_master").Count()
+			So(err, ShouldBeNil)
+			So(db.Rollback(), ShouldBeNil)
+			So(setups, ShouldEqual, 1)
+		})
+
+		Convey("A failing setup is reported", func() {
+			So(db.SetSessionSetup(func(ctx context.Context, conn SessionConn) error {
+				return errors.New("setup failure")
+			}), ShouldBeNil)
+			err := db.CurrentDB().Ping()
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "setup failure")
+		})
+	})
+
+	Convey("SetSessionSetup needs a DB created by Open", t, func() {
+		db := Wrap(sqlite.Adapter, nil)
+		So(db.SetSessionSetup(SessionStatements()), ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,173 @@
+package godb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// UseSingleflight enables or disables the deduplication of the identical
+// reads executed concurrently by the DB and its clones (created after the
+// call). While a select statement is running, the same statement (same SQL
+// once the spaces are normalized, same arguments, same type of target and
+// same options like the timeout or the scan modes) waits for its result
+// instead of being executed again : a stampede of
+// identical reads results in a single round trip.
+//
+// Only the select statements building a new result are deduplicated : the
+// target is a struct or an empty slice, and the DB is not in a transaction.
+// The waiting callers get a copy of the records of the executed statement,
+// the pointers, slices and maps inside the records are shared. A waiting
+// caller gives up when its own context is done (see SetContext and the
+// Timeout of the statements), and executes the statement itself if the
+// executed one was cancelled.
+//
+// Example :
+// 	db.UseSingleflight(true)
+// 	// in several goroutines, a single query is executed
+// 	err := db.Select(&settings).Do()
+func (db *DB) UseSingleflight(enabled bool) {
+	if !enabled {
+		db.singleflight = nil
+		return
+	}
+	db.singleflight = &flightGroup{calls: make(map[string]*flightCall)}
+}
+
+// flightGroup contains the statements being executed, by key.
+type flightGroup struct {
+	mutex sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall is a statement being executed, its channel is closed once the
+// copy of its record is given to the waiters.
+type flightCall struct {
+	done    chan struct{}
+	waiters int
+	// record is a copy of the filled record, given to the waiters
+	record    *recordDescription
+	rowsCount int64
+	err       error
+}
+
+// doSelectOnce executes the given select statement like
+// doSelectOrWithReturning, unless the same one is already running : its
+// result is then copied into the record. The scan mode describes how the
+// rows are scanned, the statements differing by it are not deduplicated.
+func (db *DB) doSelectOnce(query string, arguments []interface{}, scanMode string, recordDescription *recordDescription, pointersGetter pointersGetter, options statementOptions) (int64, error) {
+	group := db.singleflight
+	if group == nil || db.sqlTx != nil || db.dryRun || (recordDescription.isSlice && recordDescription.len() > 0) {
+		return db.doSelectOrWithReturning(query, arguments, recordDescription, pointersGetter, options)
+	}
+
+	key, ok := flightKey(query, arguments, scanMode, recordDescription, options)
+	if !ok {
+		return db.doSelectOrWithReturning(query, arguments, recordDescription, pointersGetter, options)
+	}
+	group.mutex.Lock()
+	if call, ok := group.calls[key]; ok {
+		call.waiters++
+		group.mutex.Unlock()
+		return db.waitSelect(call, query, arguments, recordDescription, pointersGetter, options)
+	}
+	call := &flightCall{done: make(chan struct{})}
+	group.calls[key] = call
+	group.mutex.Unlock()
+
+	rowsCount, err := db.doSelectOrWithReturning(query, arguments, recordDescription, pointersGetter, options)
+
+	group.mutex.Lock()
+	delete(group.calls, key)
+	waiters := call.waiters
+	group.mutex.Unlock()
+	if waiters > 0 {
+		// The waiters get a copy, the record could be changed by the caller
+		// as soon as it's returned.
+		call.rowsCount, call.err = rowsCount, err
+		if err == nil && (recordDescription.isSlice || rowsCount > 0) {
+			call.record = newRecordCopy(recordDescription)
+		}
+	}
+	close(call.done)
+	return rowsCount, err
+}
+
+// waitSelect waits for the result of the given running statement, until the
+// context of the execution is done. The statement is executed again if the
+// running one was cancelled, its context isn't the one of the caller.
+func (db *DB) waitSelect(call *flightCall, query string, arguments []interface{}, recordDescription *recordDescription, pointersGetter pointersGetter, options statementOptions) (int64, error) {
+	ctx, cancel := db.executionContext(options)
+	defer cancel()
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	if errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded) {
+		return db.doSelectOrWithReturning(query, arguments, recordDescription, pointersGetter, options)
+	}
+	if call.record != nil {
+		copyRecord(recordDescription, call.record)
+	}
+	return call.rowsCount, call.err
+}
+
+// flightKey returns the key of a select statement, made of its normalized
+// SQL, its arguments, the type of its target, its scan mode and its options.
+// It returns false if an argument can't be part of a key.
+func flightKey(query string, arguments []interface{}, scanMode string, recordDescription *recordDescription, options statementOptions) (string, bool) {
+	var key strings.Builder
+	fmt.Fprintf(&key, "%T\x00%+v\x00%s\x00%s", recordDescription.record, options, scanMode, strings.Join(strings.Fields(query), " "))
+	for _, argument := range arguments {
+		// The value given to the driver is used, in its Go syntax : the
+		// String method of the arguments is ignored, and the strings are
+		// quoted.
+		if valuer, ok := argument.(driver.Valuer); ok {
+			value, err := valuer.Value()
+			if err != nil {
+				return "", false
+			}
+			argument = value
+		}
+		fmt.Fprintf(&key, "\x00%T:%#v", argument, argument)
+	}
+	return key.String(), true
+}
+
+// newRecordCopy returns a new record description having a copy of the
+// records of the given one.
+func newRecordCopy(source *recordDescription) *recordDescription {
+	recordCopy := *source
+	recordCopy.record = reflect.New(reflect.TypeOf(source.record).Elem()).Interface()
+	copyRecord(&recordCopy, source)
+	return &recordCopy
+}
+
+// copyRecord copies the records of the source into the target, both having
+// the same type.
+func copyRecord(target *recordDescription, source *recordDescription) {
+	sourceValue := reflect.ValueOf(source.record).Elem()
+	targetValue := reflect.ValueOf(target.record).Elem()
+	if !target.isSlice {
+		targetValue.Set(sourceValue)
+		return
+	}
+
+	slice := reflect.MakeSlice(targetValue.Type(), 0, sourceValue.Len())
+	for i := 0; i < sourceValue.Len(); i++ {
+		element := sourceValue.Index(i)
+		if target.isSliceOfPointers {
+			instance := reflect.New(target.instanceType)
+			instance.Elem().Set(element.Elem())
+			element = instance
+		}
+		slice = reflect.Append(slice, element)
+	}
+	targetValue.Set(slice)
+}
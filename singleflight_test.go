@@ -0,0 +1,179 @@
+package godb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// waitForFlightWaiters waits until the running statements of the group have
+// the given count of waiters.
+func waitForFlightWaiters(g *flightGroup, count int) {
+	for {
+		g.mutex.Lock()
+		current := 0
+		for _, call := range g.calls {
+			current += call.waiters
+		}
+		g.mutex.Unlock()
+		if current == count {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSingleflight(t *testing.T) {
+	Convey("Given a test database with the deduplication of the reads", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		db.UseSingleflight(true)
+
+		Convey("The statements are executed as usual", func() {
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).OrderBy("id").Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+			dummy := Dummy{}
+			So(db.Get(&dummy, 2), ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "Second")
+			So(len(db.singleflight.calls), ShouldEqual, 0)
+		})
+
+		Convey("The identical reads wait for the running one", func() {
+			// The first statement waits for the slot of the limiter
+			db.SetMaxConcurrentQueries(1)
+			So(db.limiter.acquire(context.Background(), PriorityNormal), ShouldBeNil)
+
+			results := make(chan []*Dummy, 3)
+			errs := make(chan error, 3)
+			for i := 0; i < 3; i++ {
+				go func(clone *DB) {
+					dummies := make([]*Dummy, 0)
+					errs <- clone.Select(&dummies).Where("id   > ?", 1).OrderBy("id").Do()
+					results <- dummies
+				}(db.Clone())
+				if i == 0 {
+					waitForWaiters(db.limiter, 1)
+				}
+			}
+			waitForFlightWaiters(db.singleflight, 2)
+			db.limiter.release()
+
+			received := make([][]*Dummy, 0, 3)
+			for i := 0; i < 3; i++ {
+				So(<-errs, ShouldBeNil)
+				dummies := <-results
+				So(len(dummies), ShouldEqual, 2)
+				So(dummies[0].AText, ShouldEqual, "Second")
+				received = append(received, dummies)
+			}
+			So(received[0][0], ShouldNotPointTo, received[1][0])
+		})
+
+		Convey("The reads with different options are not deduplicated", func() {
+			db.SetMaxConcurrentQueries(1)
+			So(db.limiter.acquire(context.Background(), PriorityNormal), ShouldBeNil)
+
+			errs := make(chan error, 4)
+			statements := []func(*DB) *SelectStatement{
+				func(clone *DB) *SelectStatement { return clone.SelectFrom("dummies") },
+				func(clone *DB) *SelectStatement { return clone.SelectFrom("dummies").Timeout(time.Minute) },
+				func(clone *DB) *SelectStatement { return clone.SelectFrom("dummies").StrictScan(true) },
+				func(clone *DB) *SelectStatement {
+					clone.UseMasking(true)
+					return clone.SelectFrom("dummies")
+				},
+			}
+			for i, statement := range statements {
+				go func(clone *DB, statement func(*DB) *SelectStatement) {
+					dummies := make([]Dummy, 0)
+					errs <- statement(clone).ColumnsFromStruct(&Dummy{}).Do(&dummies)
+				}(db.Clone(), statement)
+				waitForWaiters(db.limiter, i+1)
+			}
+			So(len(db.singleflight.calls), ShouldEqual, 4)
+			db.limiter.release()
+			for range statements {
+				So(<-errs, ShouldBeNil)
+			}
+		})
+
+		Convey("A waiting read gives up when its context is done", func() {
+			db.SetMaxConcurrentQueries(1)
+			So(db.limiter.acquire(context.Background(), PriorityNormal), ShouldBeNil)
+
+			errs := make(chan error, 1)
+			go func(clone *DB) {
+				dummies := make([]Dummy, 0)
+				errs <- clone.Select(&dummies).Do()
+			}(db.Clone())
+			waitForWaiters(db.limiter, 1)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			waiter := db.Clone()
+			waiter.SetContext(ctx)
+			dummies := make([]Dummy, 0)
+			So(errors.Is(waiter.Select(&dummies).Do(), context.Canceled), ShouldBeTrue)
+
+			db.limiter.release()
+			So(<-errs, ShouldBeNil)
+		})
+
+		Convey("A waiting read is executed again if the running one is cancelled", func() {
+			db.SetMaxConcurrentQueries(1)
+			So(db.limiter.acquire(context.Background(), PriorityNormal), ShouldBeNil)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			leader := db.Clone()
+			leader.SetContext(ctx)
+			leaderErr := make(chan error, 1)
+			go func() {
+				dummies := make([]Dummy, 0)
+				leaderErr <- leader.Select(&dummies).Do()
+			}()
+			waitForWaiters(db.limiter, 1)
+
+			waiterErr := make(chan error, 1)
+			waiterDummies := make([]Dummy, 0)
+			go func(clone *DB) {
+				waiterErr <- clone.Select(&waiterDummies).Do()
+			}(db.Clone())
+			waitForFlightWaiters(db.singleflight, 1)
+
+			cancel()
+			So(errors.Is(<-leaderErr, context.Canceled), ShouldBeTrue)
+			db.limiter.release()
+			So(<-waiterErr, ShouldBeNil)
+			So(len(waiterDummies), ShouldEqual, 3)
+		})
+	})
+}
+
+// sameString is an argument whose values have the same String.
+type sameString int
+
+func (sameString) String() string {
+	return "same"
+}
+
+func TestFlightKey(t *testing.T) {
+	Convey("The keys of the reads use the typed values of the arguments", t, func() {
+		recordDescription, err := buildRecordDescription(&Dummy{})
+		So(err, ShouldBeNil)
+		key := func(arguments ...interface{}) string {
+			k, ok := flightKey("SELECT 1", arguments, "", recordDescription, statementOptions{})
+			So(ok, ShouldBeTrue)
+			return k
+		}
+
+		So(key(sameString(1)), ShouldNotEqual, key(sameString(2)))
+		So(key("a\x00string:b"), ShouldNotEqual, key("a", "b"))
+		So(key(1), ShouldNotEqual, key("1"))
+		So(key(sql.NullString{String: "a", Valid: true}), ShouldEqual, key("a"))
+	})
+}
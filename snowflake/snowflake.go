@@ -0,0 +1,121 @@
+// Package snowflake generates time ordered 64-bit IDs without database
+// round trips, like the Twitter Snowflake IDs. An ID contains the
+// milliseconds elapsed since an epoch (41 bits, about 69 years), the node
+// generating it, and a sequence number for the IDs generated during the same
+// millisecond. The count of bits of the node is configurable, the others are
+// used by the sequence.
+//
+// Give a Generator to godb.UseIDGenerator to fill the keys on insert.
+//
+// Example :
+// 	generator, err := snowflake.NewGenerator(3, snowflake.DefaultNodeBits)
+// 	db.UseIDGenerator(generator)
+package snowflake
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultNodeBits is the usual count of bits of the node, for 1024 nodes
+// generating each 4096 IDs by millisecond.
+const DefaultNodeBits = 10
+
+// DefaultEpoch is the default epoch of the IDs, 2020-01-01 UTC.
+var DefaultEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const (
+	timeBits    = 41
+	counterBits = 22
+	maxTime     = 1<<timeBits - 1
+)
+
+// Generator generates the IDs of a node. It's safe for concurrent use.
+type Generator struct {
+	mutex        sync.Mutex
+	epoch        time.Time
+	node         int64
+	nodeBits     uint
+	sequenceBits uint
+	lastTime     int64
+	sequence     int64
+	now          func() time.Time
+}
+
+// NewGenerator creates the generator of the given node, which has to be
+// unique among the generators of the same IDs. The count of bits of the node
+// is from 0 to 21.
+func NewGenerator(node int64, nodeBits uint) (*Generator, error) {
+	if nodeBits > counterBits-1 {
+		return nil, fmt.Errorf("the node bits must be from 0 to %d, got %d", counterBits-1, nodeBits)
+	}
+	if node < 0 || node >= 1<<nodeBits {
+		return nil, fmt.Errorf("the node must be from 0 to %d, got %d", 1<<nodeBits-1, node)
+	}
+	return &Generator{
+		epoch:        DefaultEpoch,
+		node:         node,
+		nodeBits:     nodeBits,
+		sequenceBits: counterBits - nodeBits,
+		lastTime:     -1,
+		now:          time.Now,
+	}, nil
+}
+
+// SetEpoch sets the epoch of the IDs, DefaultEpoch by default. All the
+// generators of the same IDs must use the same epoch.
+func (g *Generator) SetEpoch(epoch time.Time) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.epoch = epoch
+	g.lastTime = -1
+}
+
+// NextID returns a new ID, greater than the previous ones of the generator.
+// It waits for the next millisecond when the sequence is exhausted. If the
+// clock goes backwards the IDs use the last time until it catches up.
+func (g *Generator) NextID() (int64, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	elapsed := g.elapsed()
+	if elapsed < g.lastTime {
+		elapsed = g.lastTime
+	}
+	if elapsed == g.lastTime {
+		g.sequence = (g.sequence + 1) & (1<<g.sequenceBits - 1)
+		if g.sequence == 0 {
+			for elapsed <= g.lastTime {
+				time.Sleep(time.Millisecond / 10)
+				elapsed = g.elapsed()
+			}
+		}
+	} else {
+		g.sequence = 0
+	}
+	if elapsed < 0 || elapsed > maxTime {
+		return 0, fmt.Errorf("the time is out of the range of the IDs since %s", g.epoch)
+	}
+	g.lastTime = elapsed
+
+	return elapsed<<counterBits | g.node<<g.sequenceBits | g.sequence, nil
+}
+
+// Time returns the time at which the given ID was generated, to the
+// millisecond.
+func (g *Generator) Time(id int64) time.Time {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return g.epoch.Add(time.Duration(id>>counterBits) * time.Millisecond)
+}
+
+// Node returns the node which generated the given ID.
+func (g *Generator) Node(id int64) int64 {
+	return id >> g.sequenceBits & (1<<g.nodeBits - 1)
+}
+
+// elapsed returns the milliseconds elapsed since the epoch.
+func (g *Generator) elapsed() int64 {
+	return int64(g.now().Sub(g.epoch) / time.Millisecond)
+}
@@ -0,0 +1,82 @@
+package snowflake
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewGenerator(t *testing.T) {
+	Convey("NewGenerator checks the node", t, func() {
+		_, err := NewGenerator(1023, DefaultNodeBits)
+		So(err, ShouldBeNil)
+		_, err = NewGenerator(1024, DefaultNodeBits)
+		So(err, ShouldNotBeNil)
+		_, err = NewGenerator(-1, DefaultNodeBits)
+		So(err, ShouldNotBeNil)
+		_, err = NewGenerator(0, 22)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestNextID(t *testing.T) {
+	Convey("Given a generator with a fixed clock", t, func() {
+		generator, err := NewGenerator(5, 4)
+		So(err, ShouldBeNil)
+		now := DefaultEpoch.Add(time.Second)
+		generator.now = func() time.Time { return now }
+
+		Convey("The IDs contain the time, the node and the sequence", func() {
+			id, err := generator.NextID()
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, 1000<<22|5<<18)
+			So(generator.Time(id), ShouldResemble, now)
+			So(generator.Node(id), ShouldEqual, 5)
+
+			next, err := generator.NextID()
+			So(err, ShouldBeNil)
+			So(next, ShouldEqual, id+1)
+		})
+
+		Convey("The IDs keep increasing when the clock goes backwards", func() {
+			id, err := generator.NextID()
+			So(err, ShouldBeNil)
+			now = now.Add(-time.Second)
+			next, err := generator.NextID()
+			So(err, ShouldBeNil)
+			So(next, ShouldBeGreaterThan, id)
+		})
+
+		Convey("The times before the epoch are rejected", func() {
+			now = DefaultEpoch.Add(-time.Second)
+			_, err := generator.NextID()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("The IDs generated concurrently are unique", t, func() {
+		generator, err := NewGenerator(1, 20)
+		So(err, ShouldBeNil)
+		ids := make(chan int64, 1000)
+		wg := sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					id, _ := generator.NextID()
+					ids <- id
+				}
+			}()
+		}
+		wg.Wait()
+		close(ids)
+		known := make(map[int64]bool)
+		for id := range ids {
+			known[id] = true
+		}
+		So(len(known), ShouldEqual, 1000)
+	})
+}
@@ -0,0 +1,28 @@
+package godb
+
+import (
+	"fmt"
+
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/types"
+)
+
+// WithinDistance builds a condition true if the given spatial column is
+// within the given distance in meters of the point, using the adapter
+// spatial functions (PostGIS or MySQL). The point coordinates are a longitude
+// (X) and a latitude (Y).
+//
+// Example :
+// 	paris := types.Point{X: 2.3522, Y: 48.8566}
+// 	err := db.Select(&shops).WhereQ(db.WithinDistance("location", paris, 1000)).Do()
+func (db *DB) WithinDistance(column string, point types.Point, meters float64) *Condition {
+	builder, ok := db.adapter.(adapters.WithinDistanceBuilder)
+	if !ok {
+		return &Condition{err: fmt.Errorf("the adapter does not support spatial conditions")}
+	}
+	if err := checkIdentifier(column); err != nil {
+		return &Condition{err: err}
+	}
+
+	return Q(builder.BuildWithinDistance(db.quote(column)), point.X, point.Y, meters)
+}
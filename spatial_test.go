@@ -0,0 +1,27 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/postgresql"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	"github.com/samonzeweb/godb/types"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWithinDistance(t *testing.T) {
+	Convey("WithinDistance builds a spatial condition", t, func() {
+		db := &DB{adapter: postgresql.Adapter}
+		c := db.WithinDistance("shops.location", types.Point{X: 2.35, Y: 48.85}, 1000)
+		So(c.Err(), ShouldBeNil)
+		So(c.sql, ShouldEqual, `ST_DWithin("shops"."location"::geography, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography, ?)`)
+		So(c.args, ShouldResemble, []interface{}{2.35, 48.85, float64(1000)})
+
+		So(db.WithinDistance("location; --", types.Point{}, 1).Err(), ShouldNotBeNil)
+	})
+
+	Convey("WithinDistance returns an error if the adapter has no spatial support", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+		So(db.WithinDistance("location", types.Point{}, 1000).Err(), ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,81 @@
+package godb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// SQLBundle contains the statements recorded by RecordSQLBundle, with their
+// arguments written as literals, ready to be reviewed before being run.
+type SQLBundle struct {
+	adapter    adapters.Adapter
+	statements []string
+	err        error
+}
+
+// RecordSQLBundle runs the given scenario in dry run mode (see DryRun) on a
+// clone of the DB, and returns the bundle of the statements it would have
+// executed. It allows a review of the SQL of a change (a data migration for
+// example) before running it in production.
+//
+// The placeholders of the statements are replaced with the literals of their
+// arguments, written for the adapter (see adapters.LiteralFormatter). An
+// argument without literal form returns an error, as does the scenario.
+// As nothing is executed, the scenario gets no row and no auto value, and
+// the transactions are not part of the bundle.
+//
+// Example :
+// 	bundle, err := db.RecordSQLBundle(func(db *godb.DB) error {
+// 		return db.Update(&book).Do()
+// 	})
+// 	_, err = bundle.WriteTo(file)
+func (db *DB) RecordSQLBundle(scenario func(db *DB) error) (*SQLBundle, error) {
+	bundle := &SQLBundle{adapter: db.adapter}
+	clone := db.Clone()
+	clone.DryRun(true)
+	clone.sqlBundle = bundle
+	if err := scenario(clone); err != nil {
+		return nil, err
+	}
+	if bundle.err != nil {
+		return nil, bundle.err
+	}
+	return bundle, nil
+}
+
+// Statements returns the statements of the bundle, in the order they were
+// recorded.
+func (bundle *SQLBundle) Statements() []string {
+	return append([]string(nil), bundle.statements...)
+}
+
+// WriteTo writes the bundle as a SQL script : a statement by paragraph,
+// ended by a semicolon.
+func (bundle *SQLBundle) WriteTo(w io.Writer) (int64, error) {
+	var script bytes.Buffer
+	fmt.Fprintf(&script, "-- %d statements generated by godb for %s\n", len(bundle.statements), bundle.adapter.DriverName())
+	for _, statement := range bundle.statements {
+		script.WriteString("\n")
+		script.WriteString(statement)
+		script.WriteString(";\n")
+	}
+	return script.WriteTo(w)
+}
+
+// add records the given statement (using '?' as placeholder), with its
+// arguments written as literals.
+func (bundle *SQLBundle) add(query string, arguments []interface{}) {
+	if bundle.err != nil {
+		return
+	}
+
+	statement, err := interpolateSQL(bundle.adapter, query, arguments)
+	if err != nil {
+		bundle.err = err
+		return
+	}
+	bundle.statements = append(bundle.statements, statement)
+}
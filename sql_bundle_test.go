@@ -0,0 +1,68 @@
+package godb
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRecordSQLBundle(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("RecordSQLBundle records the statements with literals", func() {
+			bundle, err := db.RecordSQLBundle(func(db *DB) error {
+				dummy := Dummy{AText: "It's", AnotherText: "?", AnInteger: 12}
+				if err := db.Insert(&dummy).Do(); err != nil {
+					return err
+				}
+				_, err := db.UpdateTable("dummies").
+					Set("a_nullable_string", "x").
+					Where("an_integer > ?", 1.5).
+					Do()
+				return err
+			})
+			So(err, ShouldBeNil)
+			So(bundle.Statements(), ShouldResemble, []string{
+				`INSERT INTO "dummies" ("a_text", "another_text", "an_integer", "a_nullable_string", "version") VALUES ('It''s', '?', 12, NULL, 0)`,
+				"UPDATE dummies SET a_nullable_string='x' WHERE an_integer > 1.5",
+			})
+
+			var script bytes.Buffer
+			_, err = bundle.WriteTo(&script)
+			So(err, ShouldBeNil)
+			So(script.String(), ShouldStartWith, "-- 2 statements generated by godb for sqlite3\n\nINSERT INTO")
+			So(script.String(), ShouldEndWith, "an_integer > 1.5;\n")
+		})
+
+		Convey("RecordSQLBundle executes nothing", func() {
+			_, err := db.RecordSQLBundle(func(db *DB) error {
+				_, err := db.DeleteFrom("dummies").Do()
+				return err
+			})
+			So(err, ShouldBeNil)
+			So(db.IsDryRun(), ShouldBeFalse)
+			count, err := db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("RecordSQLBundle returns the errors", func() {
+			scenarioErr := errors.New("scenario error")
+			_, err := db.RecordSQLBundle(func(db *DB) error {
+				return scenarioErr
+			})
+			So(err, ShouldEqual, scenarioErr)
+
+			_, err = db.RecordSQLBundle(func(db *DB) error {
+				_, err := db.DeleteFrom("dummies").Where("an_integer = ?", math.NaN()).Do()
+				return err
+			})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -221,7 +221,7 @@ func (b *sqlBuffer) writeJoins(joins []*joinPart) *sqlBuffer {
 		b.WriteIfNotEmpty(" ").
 			Write(join.joinType).
 			Write(" ").
-			Write(join.tableName)
+			Write(join.tableName, join.args...)
 		if join.as != "" {
 			b.Write(" AS ").
 				Write(join.as)
@@ -250,7 +250,8 @@ func (b *sqlBuffer) writeWhere(conditions []*Condition) *sqlBuffer {
 }
 
 // writeGroupByAndHaving writes ORDER BY and HAVING clauses into the buffer.
-func (b *sqlBuffer) writeGroupByAndHaving(columns []string, conditions []*Condition) *sqlBuffer {
+// The given arguments are the ones of GROUP BY expressions.
+func (b *sqlBuffer) writeGroupByAndHaving(columns []string, args []interface{}, conditions []*Condition) *sqlBuffer {
 	if b.Err() != nil {
 		return b
 	}
@@ -259,6 +260,7 @@ func (b *sqlBuffer) writeGroupByAndHaving(columns []string, conditions []*Condit
 		b.WriteIfNotEmpty(" ").
 			Write("GROUP BY ")
 		b.writeNameList(columns)
+		b.Write("", args...)
 	}
 
 	if len(conditions) != 0 {
@@ -274,7 +276,8 @@ func (b *sqlBuffer) writeGroupByAndHaving(columns []string, conditions []*Condit
 }
 
 // writeOrderBy writes ORDER BY clause into the buffer.
-func (b *sqlBuffer) writeOrderBy(columns []string) *sqlBuffer {
+// The given arguments are the ones of ORDER BY expressions.
+func (b *sqlBuffer) writeOrderBy(columns []string, args []interface{}) *sqlBuffer {
 	if b.Err() != nil {
 		return b
 	}
@@ -282,13 +285,72 @@ func (b *sqlBuffer) writeOrderBy(columns []string) *sqlBuffer {
 	if len(columns) != 0 {
 		b.Write(" ORDER BY ")
 		b.writeNameList(columns)
+		b.Write("", args...)
 	}
 
 	return b
 }
 
+// writeLimitOffset writes the clauses limiting and skipping the rows, after
+// the ORDER BY clause, in the pagination style of the adapter (see
+// adapters.Paginator). The Top and RowNum styles are written by the caller,
+// only their lack of offset is checked.
+func (b *sqlBuffer) writeLimitOffset(limit *int64, offset *int64, ordered bool) *sqlBuffer {
+	if b.Err() != nil {
+		return b
+	}
+	if (limit != nil && *limit < 0) || (offset != nil && *offset < 0) {
+		b.err = fmt.Errorf("the limit and the offset must not be negative")
+		return b
+	}
+
+	style := adapters.LimitOffset
+	if paginator, ok := b.adapter.(adapters.Paginator); ok {
+		style = paginator.PaginationStyle()
+	}
+	switch style {
+	case adapters.OffsetFetch, adapters.OffsetFetchUnordered:
+		if limit == nil && offset == nil {
+			return b
+		}
+		if !ordered && style == adapters.OffsetFetch {
+			b.Write(" ORDER BY (SELECT NULL)")
+		}
+		var rowsOffset int64
+		if offset != nil {
+			rowsOffset = *offset
+		}
+		b.Write(" OFFSET "+Placeholder+" ROWS", rowsOffset)
+		if limit != nil {
+			b.Write(" FETCH NEXT "+Placeholder+" ROWS ONLY", *limit)
+		}
+	case adapters.OffsetLimit:
+		b.writeOffset(offset).
+			writeLimit(limit)
+	case adapters.Top, adapters.RowNum:
+		if offset != nil && *offset > 0 {
+			b.err = fmt.Errorf("the adapter does not support offsets")
+		}
+	default:
+		offsetFirst := false
+		if limitOffsetOrderer, ok := b.adapter.(adapters.LimitOffsetOrderer); ok {
+			offsetFirst = limitOffsetOrderer.IsOffsetFirst()
+		}
+		if offsetFirst {
+			// Offset is before limit
+			b.writeOffset(offset).
+				writeLimit(limit)
+		} else {
+			// Limit is before offset (default case)
+			b.writeLimit(limit).
+				writeOffset(offset)
+		}
+	}
+	return b
+}
+
 // writeOffset writes OFFSET clause into the buffer.
-func (b *sqlBuffer) writeOffset(offset *int) *sqlBuffer {
+func (b *sqlBuffer) writeOffset(offset *int64) *sqlBuffer {
 	if b.Err() != nil {
 		return b
 	}
@@ -296,7 +358,7 @@ func (b *sqlBuffer) writeOffset(offset *int) *sqlBuffer {
 	if offset != nil {
 		offsetBuilder, ok := b.adapter.(adapters.OffsetBuilder)
 		if ok {
-			sqlPart := offsetBuilder.BuildOffset(*offset)
+			sqlPart := offsetBuilder.BuildOffset(int(*offset))
 			b.Write(" ").
 				Write(sqlPart.Sql, sqlPart.Arguments...)
 		} else {
@@ -309,7 +371,7 @@ func (b *sqlBuffer) writeOffset(offset *int) *sqlBuffer {
 }
 
 // writeLimit writes LIMIT clauses into the buffer.
-func (b *sqlBuffer) writeLimit(limit *int) *sqlBuffer {
+func (b *sqlBuffer) writeLimit(limit *int64) *sqlBuffer {
 	if b.Err() != nil {
 		return b
 	}
@@ -317,7 +379,7 @@ func (b *sqlBuffer) writeLimit(limit *int) *sqlBuffer {
 	if limit != nil {
 		limitBuilder, ok := b.adapter.(adapters.LimitBuilder)
 		if ok {
-			sqlPart := limitBuilder.BuildLimit(*limit)
+			sqlPart := limitBuilder.BuildLimit(int(*limit))
 			b.Write(" ").
 				Write(sqlPart.Sql, sqlPart.Arguments...)
 		} else {
@@ -375,6 +437,25 @@ func (b *sqlBuffer) writeReturningForPosition(columns []string, position adapter
 	return b
 }
 
+// writeIgnoreConflicts writes the clause skipping the conflicting rows of an
+// INSERT statement, the adapter has to implement the ConflictIgnorer
+// interface.
+func (b *sqlBuffer) writeIgnoreConflicts() *sqlBuffer {
+	if b.Err() != nil {
+		return b
+	}
+
+	conflictIgnorer, ok := b.adapter.(adapters.ConflictIgnorer)
+	if !ok {
+		b.err = fmt.Errorf("the adapter does not support ignoring conflicts")
+		return b
+	}
+
+	b.Write(" ").
+		Write(conflictIgnorer.IgnoreConflictsClause())
+	return b
+}
+
 // writeInsertValues writes all the values to insert to the database into
 // the buffer.
 func (b *sqlBuffer) writeInsertValues(args [][]interface{}, columnsCount int) *sqlBuffer {
@@ -423,7 +504,14 @@ func (b *sqlBuffer) writeSets(sets []*setPart) *sqlBuffer {
 			b.Write(", ")
 		}
 		b.Write(set.column)
-		if set.value != nil {
+		if expression, ok := set.value.(Expression); ok {
+			sql, args, err := expression.BuildSQL(b.adapter)
+			if err != nil {
+				b.err = err
+				return b
+			}
+			b.Write("="+sql, args...)
+		} else if set.value != nil {
 			// column and value are given (not raw sql)
 			b.Write("=").
 				Write(Placeholder, set.value)
@@ -10,19 +10,85 @@ import (
 // a given instance pointer and a columns names list.
 type pointersGetter func(record interface{}, columns []string) ([]interface{}, error)
 
+// limitRows wraps the pointersGetter, which is called once per row, to return
+// a *TooManyRowsError when the count of rows exceeds the given maximum, or the
+// default one of the DB if the given maximum is 0.
+func (db *DB) limitRows(maxRows int, getter pointersGetter) pointersGetter {
+	if maxRows == 0 {
+		maxRows = db.maxRows
+	}
+	if maxRows <= 0 {
+		return getter
+	}
+
+	rowsCount := 0
+	return func(record interface{}, columns []string) ([]interface{}, error) {
+		rowsCount++
+		if rowsCount > maxRows {
+			return nil, &TooManyRowsError{MaxRows: maxRows}
+		}
+		return getter(record, columns)
+	}
+}
+
+// startExecution waits for an execution slot if the concurrent queries are
+// limited, and checks the circuit breaker. It returns the function to call
+// with the result of the execution, and the function releasing the slot once
+// the statement is done (its rows are closed).
+func (db *DB) startExecution(options statementOptions) (func(err error), func(), error) {
+	release := func() {}
+	if db.limiter != nil {
+		if err := db.limiter.acquire(db.Context(), options.priority); err != nil {
+			return nil, nil, err
+		}
+		release = db.limiter.release
+	}
+
+	breaker := db.breaker
+	if breaker == nil {
+		return func(error) {}, release, nil
+	}
+	if err := breaker.allow(); err != nil {
+		release()
+		return nil, nil, err
+	}
+	startTime := time.Now()
+	return func(err error) {
+		breaker.record(time.Since(startTime), err)
+	}, release, nil
+}
+
 // do executes the given query (with its arguments) after replacing the
 // placeholders if neeeded, and returns sql.Result.
-func (db *DB) do(query string, arguments []interface{}) (sql.Result, error) {
-	query = db.replacePlaceholders(query)
+func (db *DB) do(query string, arguments []interface{}, options statementOptions) (sql.Result, error) {
+	adaptedQuery := db.hintTimeout(db.replacePlaceholders(query), options)
+	if err := db.checkWritable(adaptedQuery); err != nil {
+		return nil, err
+	}
+	if db.dryRun {
+		db.skipStatement(query, arguments, options)
+		return dryRunResult{}, nil
+	}
+	query = adaptedQuery
 
 	// Execute the statement
+	finish, releaseSlot, err := db.startExecution(options)
+	if err != nil {
+		db.logExecutionErr(err, query, arguments)
+		return nil, err
+	}
+	defer releaseSlot()
 	startTime := time.Now()
 	queryable, err := db.getQueryable(query)
 	if err != nil {
+		finish(err)
 		db.logExecutionErr(err, query, arguments)
 		return nil, err
 	}
-	result, err := queryable.Exec(arguments...)
+	ctx, cancel := db.executionContext(options)
+	result, err := queryable.ExecContext(ctx, arguments...)
+	cancel()
+	finish(err)
 	consumedTime := timeElapsedSince(startTime)
 	db.addConsumedTime(consumedTime)
 	db.logExecution(consumedTime, query, arguments)
@@ -40,24 +106,19 @@ func (db *DB) do(query string, arguments []interface{}) (sql.Result, error) {
 // doSelectOrWithReturning executes the statement and fills the auto fields.
 // It returns the count of rows returned.
 // It is called when the adapter implements ReturningSuffixer.
-func (db *DB) doSelectOrWithReturning(query string, arguments []interface{}, recordDescription *recordDescription, pointersGetter pointersGetter) (int64, error) {
-	rows, columns, err := db.executeQuery(query, arguments, false, false)
+func (db *DB) doSelectOrWithReturning(query string, arguments []interface{}, recordDescription *recordDescription, pointersGetter pointersGetter, options statementOptions) (int64, error) {
+	if db.dryRun {
+		db.skipStatement(query, arguments, options)
+		return 0, nil
+	}
+	rows, columns, release, err := db.executeQuery(query, arguments, false, false, options)
 	if err != nil {
 		return 0, err
 	}
+	defer release()
 	defer rows.Close()
 
-	// If the given slice is empty, the slice grows as the rows are read.
-	// If the given slice isn't empty it's filled with rows, and both rows and
-	// slice length have to be equals.
-	// If it's a single instance, it's juste filled, and the result must have
-	// only one row.
-	var rowsCount int
-	if recordDescription.len() > 0 {
-		rowsCount, err = db.fillWithValues(recordDescription, pointersGetter, columns, rows)
-	} else {
-		rowsCount, err = db.growAndFillWithValues(recordDescription, pointersGetter, columns, rows)
-	}
+	rowsCount, err := db.fillRecord(recordDescription, pointersGetter, columns, rows)
 	if err != nil {
 		db.logExecutionErr(err, query, arguments)
 		return 0, err
@@ -70,34 +131,111 @@ func (db *DB) doSelectOrWithReturning(query string, arguments []interface{}, rec
 	return int64(rowsCount), err
 }
 
+// doMultiSelect executes the statement and fills each record with a result
+// set, in the same order.
+func (db *DB) doMultiSelect(query string, arguments []interface{}, recordDescriptions []*recordDescription, pointersGetters []pointersGetter, options statementOptions) error {
+	if db.dryRun {
+		db.skipStatement(query, arguments, options)
+		return nil
+	}
+	rows, columns, release, err := db.executeQuery(query, arguments, false, false, options)
+	if err != nil {
+		return err
+	}
+	defer release()
+	defer rows.Close()
+
+	for i, recordDescription := range recordDescriptions {
+		if i > 0 {
+			if !rows.NextResultSet() {
+				err = rows.Err()
+				if err == nil {
+					err = fmt.Errorf("there are fewer result sets than targets : %d", i)
+				}
+				db.logExecutionErr(err, query, arguments)
+				return err
+			}
+			columns, err = rows.Columns()
+			if err != nil {
+				db.logExecutionErr(err, query, arguments)
+				return err
+			}
+		}
+
+		if _, err = db.fillRecord(recordDescription, pointersGetters[i], columns, rows); err != nil {
+			db.logExecutionErr(err, query, arguments)
+			return err
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		db.logExecutionErr(err, query, arguments)
+	}
+	return err
+}
+
+// fillRecord fills the record with the rows of the current result set, and
+// returns the count of rows read.
+//
+// If the given slice is empty, the slice grows as the rows are read.
+// If the given slice isn't empty it's filled with rows, and both rows and
+// slice length have to be equals.
+// If it's a single instance, it's juste filled, and the result must have
+// only one row (a *MultipleRecordsError is returned otherwise).
+func (db *DB) fillRecord(recordDescription *recordDescription, pointersGetter pointersGetter, columns []string, rows *sql.Rows) (int, error) {
+	pointersGetter = db.coercePointers(pointersGetter)
+	if recordDescription.len() > 0 {
+		return db.fillWithValues(recordDescription, pointersGetter, columns, rows)
+	}
+	return db.growAndFillWithValues(recordDescription, pointersGetter, columns, rows)
+}
+
 // executeQuery executes the given query with its arguments and returns the
-// resulting *sql.Rows, the list of columns names, and an error.
-func (db *DB) executeQuery(query string, arguments []interface{}, noTx, noStmtCache bool) (*sql.Rows, []string, error) {
-	query = db.replacePlaceholders(query)
+// resulting *sql.Rows, the list of columns names, the function to call once
+// the rows are closed, and an error.
+func (db *DB) executeQuery(query string, arguments []interface{}, noTx, noStmtCache bool, options statementOptions) (*sql.Rows, []string, func(), error) {
+	query = db.hintTimeout(db.replacePlaceholders(query), options)
 
+	finish, releaseSlot, err := db.startExecution(options)
+	if err != nil {
+		db.logExecutionErr(err, query, arguments)
+		return nil, nil, nil, err
+	}
 	startTime := time.Now()
-	queryable, err := db.getQueryableWithOptions(query, noTx, noStmtCache)
+	queryable, err := db.getReadQueryable(query, noTx, noStmtCache, options)
 	if err != nil {
+		finish(err)
+		releaseSlot()
 		db.logExecutionErr(err, query, arguments)
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+	ctx, cancel := db.executionContext(options)
+	// the slot is kept while the rows are read
+	release := func() {
+		cancel()
+		releaseSlot()
 	}
-	rows, err := queryable.Query(arguments...)
+	rows, err := queryable.QueryContext(ctx, arguments...)
+	finish(err)
 	consumedTime := timeElapsedSince(startTime)
 	db.addConsumedTime(consumedTime)
 	db.logExecution(consumedTime, query, arguments)
 	if err != nil {
+		release()
 		db.logExecutionErr(err, query, arguments)
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	columns, err := rows.Columns()
 	if err != nil {
 		db.logExecutionErr(err, query, arguments)
 		rows.Close()
-		return nil, nil, err
+		release()
+		return nil, nil, nil, err
 	}
 
-	return rows, columns, nil
+	return rows, columns, release, nil
 }
 
 // fillWithReturningValues fill the record with rows, the record size must have
@@ -110,6 +248,9 @@ func (db *DB) fillWithValues(recordDescription *recordDescription, pointersGette
 	for rows.Next() {
 		rowsCount++
 		if rowsCount > recordLength {
+			if !recordDescription.isSlice {
+				return 0, &MultipleRecordsError{}
+			}
 			return 0, fmt.Errorf("there are more rows returned than the target size : %v", recordLength)
 		}
 		instancePtr := recordDescription.index(rowsCount - 1)
@@ -122,6 +263,9 @@ func (db *DB) fillWithValues(recordDescription *recordDescription, pointersGette
 		if err != nil {
 			return 0, err
 		}
+		if err = db.mask(recordDescription, instancePtr, columns); err != nil {
+			return 0, err
+		}
 	}
 
 	return rowsCount, nil
@@ -146,7 +290,7 @@ func (db *DB) growAndFillWithValues(recordDescription *recordDescription, pointe
 				if err != nil {
 					return err
 				}
-				return nil
+				return db.mask(recordDescription, record, columns)
 			})
 
 		if err != nil {
@@ -159,8 +303,12 @@ func (db *DB) growAndFillWithValues(recordDescription *recordDescription, pointe
 
 // doWithIterator executes the given query (with its arguments) and returns
 // an Iterator.
-func (db *DB) doWithIterator(query string, arguments []interface{}) (Iterator, error) {
-	rows, columns, err := db.executeQuery(query, arguments, true, true)
+func (db *DB) doWithIterator(query string, arguments []interface{}, options statementOptions) (Iterator, error) {
+	if db.dryRun {
+		db.skipStatement(query, arguments, options)
+		return dryRunIterator{}, nil
+	}
+	rows, columns, release, err := db.executeQuery(query, arguments, true, true, options)
 	if err != nil {
 		if rows != nil {
 			rows.Close()
@@ -169,8 +317,10 @@ func (db *DB) doWithIterator(query string, arguments []interface{}) (Iterator, e
 	}
 
 	iterator := iteratorInternals{
+		db:      db,
 		rows:    rows,
 		columns: columns,
+		release: release,
 	}
 
 	return &iterator, nil
@@ -0,0 +1,65 @@
+package godb
+
+import "strings"
+
+// checkColumns wraps the pointersGetter to check, for the first row, that
+// the columns match the fields of the struct if the scan is strict (the
+// statement overrides the DB if strict isn't nil).
+func (db *DB) checkColumns(strict *bool, recordDescription *recordDescription, getter pointersGetter) pointersGetter {
+	isStrict := db.strictScan
+	if strict != nil {
+		isStrict = *strict
+	}
+	if !isStrict {
+		return getter
+	}
+
+	checked := false
+	return func(record interface{}, columns []string) ([]interface{}, error) {
+		if !checked {
+			if err := checkUnmappedColumns(recordDescription, columns); err != nil {
+				return nil, err
+			}
+			checked = true
+		}
+		return getter(record, columns)
+	}
+}
+
+// checkUnmappedColumns returns an *UnmappedColumnsError if the given columns
+// don't match the columns of the struct. The columns of the nested structs
+// having a relation are compared without it (dummies.id is the id column of
+// a result), the same column can then be expected several times.
+func checkUnmappedColumns(recordDescription *recordDescription, columns []string) error {
+	structColumns := recordDescription.structMapping.GetAllColumnsNames()
+	expected := make(map[string]int, len(structColumns))
+	for _, column := range structColumns {
+		expected[unqualifiedColumnName(column)]++
+	}
+
+	unmapped := &UnmappedColumnsError{Struct: recordDescription.structMapping.Name}
+	for _, column := range columns {
+		if expected[column] == 0 {
+			unmapped.UnknownColumns = append(unmapped.UnknownColumns, column)
+			continue
+		}
+		expected[column]--
+	}
+	for _, column := range structColumns {
+		name := unqualifiedColumnName(column)
+		if expected[name] > 0 {
+			unmapped.MissingColumns = append(unmapped.MissingColumns, column)
+			expected[name]--
+		}
+	}
+	if len(unmapped.UnknownColumns) > 0 || len(unmapped.MissingColumns) > 0 {
+		return unmapped
+	}
+	return nil
+}
+
+// unqualifiedColumnName returns the name of a column of a struct without
+// the relation of its nested struct, if any.
+func unqualifiedColumnName(column string) string {
+	return column[strings.LastIndex(column, ".")+1:]
+}
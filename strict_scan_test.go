@@ -0,0 +1,98 @@
+package godb
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStrictScan(t *testing.T) {
+	Convey("Given a test database in strict scan mode", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		db.SetStrictScan(true)
+
+		Convey("The results matching the structs are scanned", func() {
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+		})
+
+		Convey("The fields without column are reported", func() {
+			dummies := make([]Dummy, 0)
+			err := db.RawSQL("select id, a_text from dummies").Do(&dummies)
+			So(errors.Is(err, ErrUnmappedColumns), ShouldBeTrue)
+			unmapped := &UnmappedColumnsError{}
+			So(errors.As(err, &unmapped), ShouldBeTrue)
+			So(unmapped.MissingColumns, ShouldResemble, []string{"another_text", "an_integer", "a_nullable_string", "version"})
+			So(len(dummies), ShouldEqual, 0)
+
+			Convey("Unless the statement overrides the mode", func() {
+				err := db.RawSQL("select id, a_text from dummies").StrictScan(false).Do(&dummies)
+				So(err, ShouldBeNil)
+				So(len(dummies), ShouldEqual, 3)
+
+				dummy := Dummy{}
+				err = db.SelectFrom("dummies").Columns("id", "a_text").Where("id = ?", 1).StrictScan(false).Do(&dummy)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("The columns without field are reported", func() {
+			dummies := make([]Dummy, 0)
+			err := db.RawSQL("select dummies.*, 1 as extra from dummies").Do(&dummies)
+			unmapped := &UnmappedColumnsError{}
+			So(errors.As(err, &unmapped), ShouldBeTrue)
+			So(unmapped.UnknownColumns, ShouldResemble, []string{"extra"})
+			So(unmapped.MissingColumns, ShouldBeEmpty)
+
+			rows, err := db.CurrentDB().Query("select dummies.*, 1 as extra from dummies")
+			So(err, ShouldBeNil)
+			err = db.ScanAll(rows, &dummies, ScanOptions{IgnoreUnknownColumns: true})
+			So(errors.As(err, &unmapped), ShouldBeTrue)
+			So(unmapped.UnknownColumns, ShouldResemble, []string{"extra"})
+		})
+	})
+
+	Convey("Given a test database in strict scan mode and a struct with relations", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		db.SetStrictScan(true)
+
+		Convey("The columns are compared without the relations", func() {
+			rows := make([]FromTwoTables, 0)
+			err := db.SelectFrom("dummies").
+				ColumnsFromStruct(&FromTwoTables{}).
+				LeftJoin("relatedtodummies", "relatedtodummies", Q("relatedtodummies.dummies_id = dummies.id")).
+				OrderBy("dummies.id").
+				Do(&rows)
+			So(err, ShouldBeNil)
+			So(len(rows), ShouldEqual, 3)
+		})
+
+		Convey("The missing columns are reported with their relations", func() {
+			rows := make([]FromTwoTables, 0)
+			err := db.SelectFrom("dummies").
+				Columns("dummies.id", "dummies.a_text", "dummies.another_text", "dummies.an_integer",
+					"dummies.a_nullable_string", "dummies.version",
+					"relatedtodummies.id", "relatedtodummies.a_text").
+				LeftJoin("relatedtodummies", "relatedtodummies", Q("relatedtodummies.dummies_id = dummies.id")).
+				Do(&rows)
+			unmapped := &UnmappedColumnsError{}
+			So(errors.As(err, &unmapped), ShouldBeTrue)
+			So(unmapped.UnknownColumns, ShouldBeEmpty)
+			So(unmapped.MissingColumns, ShouldResemble, []string{"relatedtodummies.dummies_id"})
+		})
+	})
+
+	Convey("A statement can enable the strict scan mode", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		dummy := Dummy{}
+		err := db.SelectFrom("dummies").Columns("id").Where("id = ?", 1).StrictScan(true).Do(&dummy)
+		So(errors.Is(err, ErrUnmappedColumns), ShouldBeTrue)
+
+		So(db.Select(&dummy).Where("id = ?", 1).StrictScan(true).Do(), ShouldBeNil)
+	})
+}
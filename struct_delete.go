@@ -29,12 +29,25 @@ func (db *DB) Delete(record interface{}) *StructDelete {
 		sd.error = fmt.Errorf("Delete accept only a single instance, got a slice")
 		return sd
 	}
+	if err = db.checkWritableModel(sd.recordDescription); err != nil {
+		sd.error = err
+		return sd
+	}
 
-	quotedTableName := db.quote(db.defaultTableNamer(sd.recordDescription.getTableName()))
+	quotedTableName := db.quoteFor(sd.recordDescription, db.defaultTableNamer(sd.recordDescription.getTableName()))
 	sd.deleteStatement = db.DeleteFrom(quotedTableName)
 	return sd
 }
 
+// Table sets the table from which the record is deleted, instead of the
+// table of the struct.
+func (sd *StructDelete) Table(name string) *StructDelete {
+	if sd.deleteStatement != nil {
+		sd.deleteStatement.fromTable = sd.deleteStatement.db.quote(name)
+	}
+	return sd
+}
+
 // Do executes the DELETE statement for the struct given to the Delete method,
 // and returns the count of deleted rows and an error.
 func (sd *StructDelete) Do() (int64, error) {
@@ -42,6 +55,18 @@ func (sd *StructDelete) Do() (int64, error) {
 		return 0, sd.error
 	}
 
+	var rowsAffected int64
+	err := sd.deleteStatement.db.audited(sd.recordDescription, AuditDelete, func() error {
+		var err error
+		rowsAffected, err = sd.do()
+		return err
+	})
+	sd.deleteStatement.db.forgetIdentities(sd.recordDescription, false)
+	return rowsAffected, err
+}
+
+// do executes the DELETE statement, see Do.
+func (sd *StructDelete) do() (int64, error) {
 	// Keys
 	keyColumns := sd.recordDescription.structMapping.GetKeyColumnsNames()
 	keyValues := sd.recordDescription.structMapping.GetKeyFieldsValues(sd.recordDescription.record)
@@ -49,9 +74,12 @@ func (sd *StructDelete) Do() (int64, error) {
 		return 0, fmt.Errorf("the object of type %T has no key : ", sd.recordDescription.record)
 	}
 	for i, column := range keyColumns {
-		quotedColumn := sd.deleteStatement.db.quote(column)
+		quotedColumn := sd.deleteStatement.db.quoteFor(sd.recordDescription, column)
 		sd.deleteStatement = sd.deleteStatement.Where(quotedColumn+" = ?", keyValues[i])
 	}
+	if policy := sd.deleteStatement.db.policyCondition(sd.recordDescription); policy != nil {
+		sd.deleteStatement = sd.deleteStatement.WhereQ(policy)
+	}
 
 	// Optimistic Locking
 	opLockColumn := sd.recordDescription.structMapping.GetOpLockSQLFieldName()
@@ -66,7 +94,7 @@ func (sd *StructDelete) Do() (int64, error) {
 	// Executes the query
 	rowsAffected, err := sd.deleteStatement.Do()
 
-	if opLockColumn != "" && rowsAffected == 0 {
+	if opLockColumn != "" && rowsAffected == 0 && !sd.deleteStatement.db.dryRun {
 		err = ErrOpLock
 	}
 
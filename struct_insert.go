@@ -21,6 +21,10 @@ type StructInsert struct {
 	recordDescription *recordDescription
 	whiteList         []string
 	blackList         []string
+	// conflicts are skipped by FindOrCreate, conflicted is true if the
+	// record was skipped
+	ignoreConflicts bool
+	conflicted      bool
 }
 
 // Insert initializes an INSERT sql statement for the given object.
@@ -61,12 +65,26 @@ func (db *DB) buildInsert(record interface{}) *StructInsert {
 		si.error = err
 		return si
 	}
+	if err = db.checkWritableModel(si.recordDescription); err != nil {
+		si.error = err
+		return si
+	}
 
-	quotedTableName := db.quote(db.defaultTableNamer(si.recordDescription.getTableName()))
+	quotedTableName := db.quoteFor(si.recordDescription, db.defaultTableNamer(si.recordDescription.getTableName()))
 	si.insertStatement = db.InsertInto(quotedTableName)
 	return si
 }
 
+// Table sets the table into which the records are inserted, instead of the
+// table of the struct. Use it to load a temporary table, see
+// CreateTempTableFrom.
+func (si *StructInsert) Table(name string) *StructInsert {
+	if si.insertStatement != nil {
+		si.insertStatement.intoTable = si.insertStatement.db.quote(name)
+	}
+	return si
+}
+
 // Whitelist saves columns to be inserted from struct
 // It adds columns to list each time it is called
 // whitelist should not include auto key tagged columns
@@ -108,6 +126,32 @@ func (si *StructInsert) Do() error {
 		return si.error
 	}
 
+	err := si.insertStatement.db.audited(si.recordDescription, AuditInsert, si.do)
+	si.insertStatement.db.forgetIdentities(si.recordDescription, true)
+	return err
+}
+
+// do executes the insert statement, see Do.
+func (si *StructInsert) do() error {
+	if err := setDefaults(si.recordDescription); err != nil {
+		return err
+	}
+	if err := si.insertStatement.db.setAuditors(si.recordDescription, true); err != nil {
+		return err
+	}
+	if err := si.insertStatement.db.setSequences(si.recordDescription); err != nil {
+		return err
+	}
+	if err := si.insertStatement.db.setGeneratedKeys(si.recordDescription); err != nil {
+		return err
+	}
+	if err := setHashes(si.recordDescription); err != nil {
+		return err
+	}
+	if err := si.insertStatement.db.validate(si.recordDescription); err != nil {
+		return err
+	}
+
 	// Columns names
 	var columns []string
 	if len(si.whiteList) > 0 {
@@ -130,18 +174,18 @@ func (si *StructInsert) Do() error {
 
 	hasWB := (len(si.whiteList) + len(si.blackList)) > 0
 	if !hasWB {
-		si.insertStatement = si.insertStatement.Columns(si.insertStatement.db.quoteAll(columns)...)
+		si.insertStatement = si.insertStatement.Columns(si.insertStatement.db.quoteAllFor(si.recordDescription, columns)...)
 	}
 	// Values
 	var values []interface{}
-	len := si.recordDescription.len()
+	recordsCount := si.recordDescription.len()
 	wbColsSet := false
-	for i := 0; i < len; i++ {
+	for i := 0; i < recordsCount; i++ {
 		currentRecord := si.recordDescription.index(i)
 		if hasWB {
 			if !wbColsSet { // order of old columns list and current values list may not be same so, set here:
 				columns, values = si.recordDescription.structMapping.GetNonAutoFieldsValuesFiltered(currentRecord, columns, false)
-				si.insertStatement = si.insertStatement.Columns(si.insertStatement.db.quoteAll(columns)...)
+				si.insertStatement = si.insertStatement.Columns(si.insertStatement.db.quoteAllFor(si.recordDescription, columns)...)
 				wbColsSet = true
 			} else {
 				// as columns are already ordered, just get values in same order
@@ -155,8 +199,12 @@ func (si *StructInsert) Do() error {
 
 	// Use a RETURNING (or similar) clause ?
 	returningBuilder, ok := si.insertStatement.db.adapter.(adapters.ReturningBuilder)
+	autoColumns := si.recordDescription.structMapping.GetAutoColumnsNames()
+	if isUpdatableView(si.recordDescription) || (si.ignoreConflicts && len(autoColumns) == 0) {
+		// without returned rows the skipped conflicts are unknown
+		returningBuilder, ok = nil, false
+	}
 	if ok {
-		autoColumns := si.recordDescription.structMapping.GetAutoColumnsNames()
 		si.insertStatement.Returning(returningBuilder.FormatForNewValues(autoColumns)...)
 	}
 
@@ -167,22 +215,35 @@ func (si *StructInsert) Do() error {
 			pointers, err := si.recordDescription.structMapping.GetAutoFieldsPointers(record)
 			return pointers, err
 		}
-		_, err := si.insertStatement.doWithReturning(si.recordDescription, f)
+		rowsCount, err := si.insertStatement.doWithReturning(si.recordDescription, f)
+		si.conflicted = si.ignoreConflicts && err == nil && rowsCount == 0
 		return err
 	}
 
 	// Case for adapters not implenting ReturningSuffix(), we use the
-	// value given by LastInsertId() (through Do method)
-	insertedID, err := si.insertStatement.Do()
+	// value given by LastInsertId()
+	result, err := si.insertStatement.exec()
 	if err != nil {
 		return err
 	}
+	if si.ignoreConflicts {
+		if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+			si.conflicted = true
+			return nil
+		}
+	}
 
 	// Bulk insert don't update ids with this adater, the insert was done,
-	// without error, but the new ids are unknown.
-	if si.recordDescription.isSlice {
+	// without error, but the new ids are unknown. The id given for a view is
+	// not reliable. The adapters having a RETURNING clause don't give it, as
+	// some drivers.
+	if _, ok := si.insertStatement.db.adapter.(adapters.ReturningBuilder); ok || si.recordDescription.isSlice || isUpdatableView(si.recordDescription) || !supportsLastInsertID(si.insertStatement.db.adapter) {
 		return nil
 	}
+	insertedID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
 
 	// Get the Id
 	pointerToID, err := si.recordDescription.structMapping.GetAutoKeyPointer(si.recordDescription.record)
@@ -4,9 +4,21 @@ import (
 	"database/sql"
 	"testing"
 
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/adapters/postgresql"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
+// noLastInsertIDAdapter tells that its driver does not give the id of an
+// inserted row.
+type noLastInsertIDAdapter struct {
+	adapters.Adapter
+}
+
+func (noLastInsertIDAdapter) SupportsLastInsertID() bool {
+	return false
+}
+
 func TestInsertDo(t *testing.T) {
 	Convey("Given a test database", t, func() {
 		db := fixturesSetup(t)
@@ -36,6 +48,22 @@ func TestInsertDo(t *testing.T) {
 			})
 		})
 
+		Convey("Do leaves the auto key unchanged if the driver does not give it", func() {
+			db.adapter = noLastInsertIDAdapter{db.adapter}
+			dummy := Dummy{AText: "Foo Bar", AnotherText: "Baz"}
+			err := db.Insert(&dummy).Do()
+			So(err, ShouldBeNil)
+			So(dummy.ID, ShouldEqual, 0)
+
+			id, err := db.InsertInto("dummies").Columns("a_text", "another_text", "an_integer").Values("Foo", "Bar", 1).Do()
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, 0)
+
+			count, err := db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 5)
+		})
+
 		Convey("Given an object to insert with whitelist/blacklist", func() {
 			dummy := Dummy{
 				AText:           "Foo Bar2",
@@ -193,3 +221,90 @@ func TestBulkInsertDo(t *testing.T) {
 	})
 
 }
+
+func TestInsertAndSelectWithPointerFields(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		type DummyWithPointers struct {
+			ID              int     `db:"id,key,auto"`
+			AText           string  `db:"a_text"`
+			AnotherText     string  `db:"another_text"`
+			AnInteger       *int64  `db:"an_integer"`
+			ANullableString *string `db:"a_nullable_string"`
+		}
+		tableName := func(string, bool) string { return "dummies" }
+		db.SetDefaultTableNamer(tableName)
+
+		Convey("nil pointers are inserted as NULL and NULL is scanned as nil", func() {
+			anInteger := int64(42)
+			dummy := DummyWithPointers{AText: "Pointers", AnotherText: "Nil", AnInteger: &anInteger}
+			So(db.Insert(&dummy).Do(), ShouldBeNil)
+
+			retrieved := DummyWithPointers{}
+			So(db.Select(&retrieved).Where("id = ?", dummy.ID).Do(), ShouldBeNil)
+			So(retrieved.AnInteger, ShouldNotBeNil)
+			So(*retrieved.AnInteger, ShouldEqual, 42)
+			So(retrieved.ANullableString, ShouldBeNil)
+
+			Convey("Non nil pointers are written and read with their values", func() {
+				text := "Not nil"
+				retrieved.ANullableString = &text
+				So(db.Update(&retrieved).Do(), ShouldBeNil)
+
+				updated := DummyWithPointers{}
+				So(db.Get(&updated, dummy.ID), ShouldBeNil)
+				So(updated.ANullableString, ShouldNotBeNil)
+				So(*updated.ANullableString, ShouldEqual, "Not nil")
+			})
+		})
+	})
+}
+
+type LineItem struct {
+	ID       int `db:"id,key,auto"`
+	Price    int `db:"price"`
+	Quantity int `db:"quantity"`
+	Total    int `db:"total,generated"`
+}
+
+func (LineItem) TableName() string {
+	return "line_items"
+}
+
+func TestGeneratedColumns(t *testing.T) {
+	Convey("Given a table with a generated column", t, func() {
+		db := createInMemoryConnection(t)
+		defer db.Close()
+		_, err := db.CurrentDB().Exec(`
+			create table line_items (
+				id integer not null primary key autoincrement,
+				price int not null,
+				quantity int not null,
+				total int generated always as (price * quantity) virtual);`)
+		So(err, ShouldBeNil)
+
+		Convey("The generated column is read, but never written", func() {
+			item := LineItem{Price: 3, Quantity: 2, Total: 1000}
+			So(db.Insert(&item).Do(), ShouldBeNil)
+
+			item.Quantity = 5
+			So(db.Update(&item).Do(), ShouldBeNil)
+			So(db.Update(&item).Whitelist("quantity", "total").Do(), ShouldBeNil)
+
+			retrieved := LineItem{}
+			So(db.Get(&retrieved, item.ID), ShouldBeNil)
+			So(retrieved, ShouldResemble, LineItem{ID: item.ID, Price: 3, Quantity: 5, Total: 15})
+		})
+
+		Convey("The generated column is read back with RETURNING", func() {
+			pg := &DB{adapter: postgresql.Adapter}
+			pg.defaultTableNamer = func(name string, done bool) string { return name }
+			description, err := pg.DescribeMapping(&LineItem{})
+			So(err, ShouldBeNil)
+			So(description.InsertSQL, ShouldEqual, `INSERT INTO "line_items" ("price", "quantity") VALUES (?, ?) RETURNING "id", "total" `)
+			So(description.String(), ShouldContainSubstring, "generated\n")
+		})
+	})
+}
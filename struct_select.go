@@ -1,5 +1,11 @@
 package godb
 
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
 // StructSelect builds a SELECT statement for the given object.
 //
 // Example (book is a struct instance, books a slice) :
@@ -11,6 +17,9 @@ type StructSelect struct {
 	error             error
 	selectStatement   *SelectStatement
 	recordDescription *recordDescription
+	tableName         string
+	// columns fetched, all the columns if empty (see Projection)
+	columns []string
 }
 
 // Select initializes a SELECT statement with the given pointer as
@@ -24,8 +33,90 @@ func (db *DB) Select(record interface{}) *StructSelect {
 		ss.error = err
 		return ss
 	}
-	quotedTableName := db.quote(db.defaultTableNamer(ss.recordDescription.getTableName()))
-	ss.selectStatement = db.SelectFrom(quotedTableName)
+	ss.tableName = db.defaultTableNamer(ss.recordDescription.getTableName())
+	ss.selectStatement = db.SelectFrom(db.quoteFor(ss.recordDescription, ss.tableName))
+	if policy := db.policyCondition(ss.recordDescription); policy != nil {
+		ss.selectStatement = ss.selectStatement.WhereQ(policy)
+	}
+	return ss
+}
+
+// Get fetches the record having the given key values into the given struct
+// pointer. The values are given in the same order as the key fields of the
+// struct. Like Do with a single instance, it returns sql.ErrNoRows if the
+// record does not exist.
+//
+// Example :
+//
+// 	book := Book{}
+// 	err := db.Get(&book, 123)
+func (db *DB) Get(record interface{}, keyValues ...interface{}) error {
+	ss := db.Select(record)
+	if ss.error != nil {
+		return ss.error
+	}
+	if ss.recordDescription.isSlice {
+		return fmt.Errorf("Get accepts only a single instance, got a slice")
+	}
+
+	condition, err := db.keyCondition(ss.recordDescription, keyValues)
+	if err != nil {
+		return err
+	}
+	if db.fetchIdentity(ss.recordDescription, keyValues) {
+		return nil
+	}
+	return ss.WhereQ(condition).Do()
+}
+
+// Exists returns true if a record having the given key values exists in the
+// table of the given struct. The struct itself is only used for its mapping,
+// it is not filled.
+//
+// Example :
+//
+// 	found, err := db.Exists(&Book{}, 123)
+func (db *DB) Exists(record interface{}, keyValues ...interface{}) (bool, error) {
+	ss := db.Select(record)
+	if ss.error != nil {
+		return false, ss.error
+	}
+
+	condition, err := db.keyCondition(ss.recordDescription, keyValues)
+	if err != nil {
+		return false, err
+	}
+	count, err := ss.WhereQ(condition).Count()
+	return count > 0, err
+}
+
+// keyCondition builds a condition matching the key columns of the given
+// record description with the given values.
+func (db *DB) keyCondition(recordDescription *recordDescription, keyValues []interface{}) (*Condition, error) {
+	keyColumns := recordDescription.structMapping.GetKeyColumnsNames()
+	if len(keyColumns) == 0 {
+		return nil, fmt.Errorf("the struct %s has no key", recordDescription.structMapping.Name)
+	}
+	if len(keyColumns) != len(keyValues) {
+		return nil, fmt.Errorf("wrong number of key values for %s, %d expected, got %d", recordDescription.structMapping.Name, len(keyColumns), len(keyValues))
+	}
+
+	conditions := make([]*Condition, 0, len(keyColumns))
+	for i, column := range keyColumns {
+		conditions = append(conditions, Q(db.quoteFor(recordDescription, column)+" = ?", keyValues[i]))
+	}
+	return And(conditions...), nil
+}
+
+// Table sets the table from which the records are selected, instead of the
+// table of the struct, for example a temporary table (see
+// CreateTempTableFrom).
+func (ss *StructSelect) Table(name string) *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.tableName = name
+	ss.selectStatement.fromTables = []string{ss.selectStatement.db.quote(name)}
 	return ss
 }
 
@@ -57,21 +148,119 @@ func (ss *StructSelect) OrderBy(orderBy string) *StructSelect {
 	return ss
 }
 
-// Offset specifies the value for the OFFSET clause.
+// OrderByExpr adds ORDER BY expressions, see SelectStatement.OrderByExpr.
+func (ss *StructSelect) OrderByExpr(expressions ...Expression) *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.OrderByExpr(expressions...)
+	return ss
+}
+
+// MaxRows sets the maximum count of rows scanned, see
+// SelectStatement.MaxRows.
+func (ss *StructSelect) MaxRows(maxRows int) *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.MaxRows(maxRows)
+	return ss
+}
+
+// StrictScan enables or disables the strict scan mode for the statement, see
+// SelectStatement.StrictScan.
+func (ss *StructSelect) StrictScan(strict bool) *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.StrictScan(strict)
+	return ss
+}
+
+// ExpectOne checks that a single record matches, see
+// SelectStatement.ExpectOne.
+func (ss *StructSelect) ExpectOne() *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.ExpectOne()
+	return ss
+}
+
+// Offset specifies the count of rows skipped, see SelectStatement.Offset.
 func (ss *StructSelect) Offset(offset int) *StructSelect {
+	return ss.OffsetInt64(int64(offset))
+}
+
+// OffsetInt64 is like Offset, with an int64.
+func (ss *StructSelect) OffsetInt64(offset int64) *StructSelect {
 	if ss.error != nil {
 		return ss
 	}
-	ss.selectStatement = ss.selectStatement.Offset(offset)
+	ss.selectStatement = ss.selectStatement.OffsetInt64(offset)
 	return ss
 }
 
-// Limit specifies the value for the LIMIT clause.
+// Limit specifies the maximum count of rows, see SelectStatement.Limit.
 func (ss *StructSelect) Limit(limit int) *StructSelect {
+	return ss.LimitInt64(int64(limit))
+}
+
+// LimitInt64 is like Limit, with an int64.
+func (ss *StructSelect) LimitInt64(limit int64) *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.LimitInt64(limit)
+	return ss
+}
+
+// ForUpdate locks the selected rows, see SelectStatement.ForUpdate.
+func (ss *StructSelect) ForUpdate() *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.ForUpdate()
+	return ss
+}
+
+// ForUpdateSkipLocked locks the selected rows, skipping the locked ones, see
+// SelectStatement.ForUpdateSkipLocked.
+func (ss *StructSelect) ForUpdateSkipLocked() *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.ForUpdateSkipLocked()
+	return ss
+}
+
+// Timeout sets the maximum execution time of the statement, see
+// SelectStatement.Timeout.
+func (ss *StructSelect) Timeout(timeout time.Duration) *StructSelect {
 	if ss.error != nil {
 		return ss
 	}
-	ss.selectStatement = ss.selectStatement.Limit(limit)
+	ss.selectStatement = ss.selectStatement.Timeout(timeout)
+	return ss
+}
+
+// MaxStaleness allows the statement to read from a replica, see
+// SelectStatement.MaxStaleness.
+func (ss *StructSelect) MaxStaleness(maxStaleness time.Duration) *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.MaxStaleness(maxStaleness)
+	return ss
+}
+
+// Priority sets the priority of the statement, see
+// SelectStatement.Priority.
+func (ss *StructSelect) Priority(priority Priority) *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.Priority(priority)
 	return ss
 }
 
@@ -83,15 +272,86 @@ func (ss *StructSelect) Do() error {
 	}
 
 	// Columns names
-	allColumns := ss.recordDescription.structMapping.GetAllColumnsNames()
-	ss.selectStatement = ss.selectStatement.Columns(ss.selectStatement.db.quoteAll(allColumns)...)
+	ss.selectStatement = ss.selectStatement.Columns(ss.selectStatement.db.quoteAllFor(ss.recordDescription, ss.selectedColumns())...)
 
 	f := func(record interface{}, columns []string) ([]interface{}, error) {
+		if len(ss.columns) > 0 {
+			return ss.recordDescription.structMapping.GetPointersForColumns(record, columns...)
+		}
 		pointers := ss.recordDescription.structMapping.GetAllFieldsPointers(record)
 		return pointers, nil
 	}
 
-	return ss.selectStatement.do(ss.recordDescription, f)
+	err := ss.selectStatement.do(ss.recordDescription, f)
+	if err == sql.ErrNoRows && ss.selectStatement.db.useNotFoundError {
+		err = ss.notFoundError()
+	}
+	if multipleRecords, ok := err.(*MultipleRecordsError); ok {
+		multipleRecords.Table = ss.tableName
+	}
+	// the records partly filled by a projection aren't known instances
+	if err == nil && len(ss.columns) == 0 {
+		ss.selectStatement.db.registerIdentities(ss.recordDescription)
+	}
+	return err
+}
+
+// notFoundError returns a NotFoundError for the current statement.
+func (ss *StructSelect) notFoundError() error {
+	return newNotFoundError(ss.tableName, ss.selectStatement.where)
+}
+
+// First fetches the first record ordered by the key columns. If OrderBy was
+// used the key columns are added after the given expressions.
+// It returns a *NotFoundError (matching ErrNotFound) if there is no matching
+// record.
+func (ss *StructSelect) First() error {
+	return ss.doSingle(true, "")
+}
+
+// Last fetches the last record ordered by the key columns. If OrderBy was
+// used the key columns are added after the given expressions.
+// It returns a *NotFoundError (matching ErrNotFound) if there is no matching
+// record.
+func (ss *StructSelect) Last() error {
+	return ss.doSingle(true, " DESC")
+}
+
+// Take fetches a single record without adding any ordering, the database
+// returns the row it wants. It returns a *NotFoundError (matching ErrNotFound)
+// if there is no matching record.
+func (ss *StructSelect) Take() error {
+	return ss.doSingle(false, "")
+}
+
+// doSingle executes the statement for First, Last and Take, ordering or not
+// the rows by the key columns with the given direction.
+func (ss *StructSelect) doSingle(orderByKeys bool, direction string) error {
+	if ss.error != nil {
+		return ss.error
+	}
+	if ss.recordDescription.isSlice {
+		return fmt.Errorf("First, Last and Take accept only a single instance, got a slice")
+	}
+
+	db := ss.selectStatement.db
+	if orderByKeys {
+		keyColumns := ss.recordDescription.structMapping.GetKeyColumnsNames()
+		if len(keyColumns) == 0 {
+			return fmt.Errorf("the struct %s has no key to order by", ss.recordDescription.structMapping.Name)
+		}
+		for _, keyColumn := range keyColumns {
+			ss.selectStatement.OrderBy(db.quoteFor(ss.recordDescription, keyColumn) + direction)
+		}
+	} else {
+		ss.selectStatement.unordered = true
+	}
+
+	err := ss.Do()
+	if err == sql.ErrNoRows {
+		return ss.notFoundError()
+	}
+	return err
 }
 
 // Count run the request with COUNT(*) and returns the count
@@ -112,13 +372,15 @@ func (ss *StructSelect) DoWithIterator() (Iterator, error) {
 		return nil, ss.error
 	}
 
-	allColumns := ss.recordDescription.structMapping.GetAllColumnsNames()
-	ss.selectStatement = ss.selectStatement.Columns(ss.selectStatement.db.quoteAll(allColumns)...)
+	ss.selectStatement = ss.selectStatement.Columns(ss.selectStatement.db.quoteAllFor(ss.recordDescription, ss.selectedColumns())...)
 
-	sqlQuery, args, err := ss.selectStatement.ToSQL()
-	if err != nil {
-		return nil, err
-	}
+	return ss.selectStatement.DoWithIterator()
+}
 
-	return ss.selectStatement.db.doWithIterator(sqlQuery, args)
+// selectedColumns returns the columns fetched by the statement.
+func (ss *StructSelect) selectedColumns() []string {
+	if len(ss.columns) > 0 {
+		return ss.columns
+	}
+	return ss.recordDescription.structMapping.GetAllColumnsNames()
 }
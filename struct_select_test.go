@@ -1,6 +1,8 @@
 package godb
 
 import (
+	"database/sql"
+	"errors"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -116,3 +118,180 @@ func TestSelectDoWithIteratorWithStruct(t *testing.T) {
 		})
 	})
 }
+
+func TestGetAndExists(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		existing := Dummy{}
+		err := db.Select(&existing).Where("an_integer = ?", 12).Do()
+		So(err, ShouldBeNil)
+
+		Convey("Get fills the record having the given key", func() {
+			dummy := Dummy{}
+			err := db.Get(&dummy, existing.ID)
+			So(err, ShouldBeNil)
+			So(dummy.ID, ShouldEqual, existing.ID)
+			So(dummy.AText, ShouldEqual, "Second")
+		})
+
+		Convey("Get returns sql.ErrNoRows if the record does not exist", func() {
+			dummy := Dummy{}
+			err := db.Get(&dummy, 123456)
+			So(err, ShouldEqual, sql.ErrNoRows)
+		})
+
+		Convey("Get returns an error if the key values count is wrong", func() {
+			dummy := Dummy{}
+			err := db.Get(&dummy, existing.ID, 1)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Get returns an error with a slice", func() {
+			dummies := make([]Dummy, 0)
+			err := db.Get(&dummies, existing.ID)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Exists returns true if the record exists", func() {
+			found, err := db.Exists(&Dummy{}, existing.ID)
+			So(err, ShouldBeNil)
+			So(found, ShouldBeTrue)
+		})
+
+		Convey("Exists returns false if the record does not exist", func() {
+			found, err := db.Exists(&Dummy{}, 123456)
+			So(err, ShouldBeNil)
+			So(found, ShouldBeFalse)
+		})
+	})
+}
+
+func TestFirstLastTake(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("First fetches the record with the lowest key", func() {
+			dummy := Dummy{}
+			err := db.Select(&dummy).First()
+			So(err, ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "First")
+		})
+
+		Convey("Last fetches the record with the highest key", func() {
+			dummy := Dummy{}
+			err := db.Select(&dummy).Last()
+			So(err, ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "Third")
+		})
+
+		Convey("First and Last use key ordering after the given one", func() {
+			dummy := Dummy{}
+			err := db.Select(&dummy).OrderBy("an_integer DESC").First()
+			So(err, ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "Third")
+		})
+
+		Convey("Take fetches a single record", func() {
+			dummy := Dummy{}
+			err := db.Select(&dummy).Where("an_integer = ?", 12).Take()
+			So(err, ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "Second")
+		})
+
+		Convey("First, Last and Take return ErrNotFound if there is no record", func() {
+			dummy := Dummy{}
+			err := db.Select(&dummy).Where("an_integer = ?", 123).First()
+			So(errors.Is(err, ErrNotFound), ShouldBeTrue)
+			err = db.Select(&dummy).Where("an_integer = ?", 123).Last()
+			So(errors.Is(err, ErrNotFound), ShouldBeTrue)
+			err = db.Select(&dummy).Where("an_integer = ?", 123).Take()
+			So(errors.Is(err, ErrNotFound), ShouldBeTrue)
+
+			Convey("The error wraps sql.ErrNoRows", func() {
+				So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+			})
+
+			Convey("The error gives the table name and criteria", func() {
+				notFound, ok := err.(*NotFoundError)
+				So(ok, ShouldBeTrue)
+				So(notFound.Table, ShouldEqual, "dummies")
+				So(notFound.Criteria, ShouldEqual, "an_integer = ?")
+			})
+		})
+
+		Convey("First returns an error with a slice", func() {
+			dummies := make([]Dummy, 0)
+			err := db.Select(&dummies).First()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSelectDoWithNotFoundError(t *testing.T) {
+	Convey("Given a test database using NotFoundError", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		db.UseNotFoundError()
+
+		Convey("Do returns a *NotFoundError if a single instance is not found", func() {
+			dummy := Dummy{}
+			err := db.Select(&dummy).Where("an_integer = ?", 123).Do()
+			notFound, ok := err.(*NotFoundError)
+			So(ok, ShouldBeTrue)
+			So(notFound.Table, ShouldEqual, "dummies")
+			So(notFound.Arguments, ShouldResemble, []interface{}{123})
+			So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+		})
+
+		Convey("Do on a statement returns a *NotFoundError if a single instance is not found", func() {
+			dummy := Dummy{}
+			err := db.SelectFrom("dummies").Where("an_integer = ?", 123).Do(&dummy)
+			So(errors.Is(err, ErrNotFound), ShouldBeTrue)
+		})
+
+		Convey("Get returns a *NotFoundError if the record does not exist", func() {
+			dummy := Dummy{}
+			err := db.Get(&dummy, 123456)
+			So(errors.Is(err, ErrNotFound), ShouldBeTrue)
+		})
+	})
+}
+
+func TestSelectDoWithExpectOne(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Do fills the instance if a single record matches", func() {
+			dummy := Dummy{}
+			err := db.Select(&dummy).Where("a_text = ?", "Second").ExpectOne().Do()
+			So(err, ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "Second")
+		})
+
+		Convey("Do returns a *MultipleRecordsError if several records match", func() {
+			dummy := Dummy{}
+			err := db.Select(&dummy).Where("an_integer > ?", 0).ExpectOne().Do()
+			So(errors.Is(err, ErrMultipleRecords), ShouldBeTrue)
+			multipleRecords, ok := err.(*MultipleRecordsError)
+			So(ok, ShouldBeTrue)
+			So(multipleRecords.Table, ShouldEqual, "dummies")
+			So(multipleRecords.Arguments, ShouldResemble, []interface{}{0})
+		})
+
+		Convey("Do on a statement returns a *MultipleRecordsError if several records match", func() {
+			dummy := Dummy{}
+			err := db.SelectFrom("dummies").ExpectOne().Do(&dummy)
+			So(errors.Is(err, ErrMultipleRecords), ShouldBeTrue)
+		})
+
+		Convey("Do without ExpectOne fills the instance with the first record", func() {
+			dummy := Dummy{}
+			err := db.Select(&dummy).Where("an_integer > ?", 0).Do()
+			So(err, ShouldBeNil)
+		})
+	})
+}
@@ -35,12 +35,24 @@ func (db *DB) Update(record interface{}) *StructUpdate {
 		su.error = fmt.Errorf("Update accept only a single instance, got a slice")
 		return su
 	}
+	if err = db.checkWritableModel(su.recordDescription); err != nil {
+		su.error = err
+		return su
+	}
 
-	quotedTableName := db.quote(db.defaultTableNamer(su.recordDescription.getTableName()))
+	quotedTableName := db.quoteFor(su.recordDescription, db.defaultTableNamer(su.recordDescription.getTableName()))
 	su.updateStatement = db.UpdateTable(quotedTableName)
 	return su
 }
 
+// Table sets the updated table, instead of the table of the struct.
+func (su *StructUpdate) Table(name string) *StructUpdate {
+	if su.updateStatement != nil {
+		su.updateStatement.updateTable = su.updateStatement.db.quote(name)
+	}
+	return su
+}
+
 // Whitelist saves columns to be updated from struct
 //
 // whitelist should not include auto key tagged columns
@@ -85,10 +97,28 @@ func (su *StructUpdate) Do() error {
 		return su.error
 	}
 
+	err := su.updateStatement.db.audited(su.recordDescription, AuditUpdate, su.do)
+	su.updateStatement.db.forgetIdentities(su.recordDescription, true)
+	return err
+}
+
+// do executes the UPDATE statement, see Do.
+func (su *StructUpdate) do() error {
+	if err := su.updateStatement.db.setAuditors(su.recordDescription, false); err != nil {
+		return err
+	}
+	if err := setHashes(su.recordDescription); err != nil {
+		return err
+	}
+	if err := su.updateStatement.db.validate(su.recordDescription); err != nil {
+		return err
+	}
+
 	// Which columns to update ?
 	var columnsToUpdate []string
 	if len(su.whiteList) > 0 {
-		columnsToUpdate = su.whiteList
+		hashColumns := su.recordDescription.structMapping.GetHashColumnsNames(su.whiteList)
+		columnsToUpdate = append(append([]string(nil), su.whiteList...), hashColumns...)
 	} else {
 		columnsToUpdate = su.recordDescription.structMapping.GetNonAutoColumnsNames()
 	}
@@ -107,7 +137,7 @@ func (su *StructUpdate) Do() error {
 
 	columns, values := su.recordDescription.structMapping.GetNonAutoFieldsValuesFiltered(su.recordDescription.record, columnsToUpdate, false)
 	for i, column := range columns {
-		quotedColumn := su.updateStatement.db.quote(column)
+		quotedColumn := su.updateStatement.db.quoteFor(su.recordDescription, column)
 		su.updateStatement = su.updateStatement.Set(quotedColumn, values[i])
 	}
 
@@ -118,9 +148,12 @@ func (su *StructUpdate) Do() error {
 		return fmt.Errorf("the object of type %T has no key : ", su.recordDescription.record)
 	}
 	for i, column := range keyColumns {
-		quotedColumn := su.updateStatement.db.quote(column)
+		quotedColumn := su.updateStatement.db.quoteFor(su.recordDescription, column)
 		su.updateStatement = su.updateStatement.Where(quotedColumn+" = ?", keyValues[i])
 	}
+	if policy := su.updateStatement.db.policyCondition(su.recordDescription); policy != nil {
+		su.updateStatement = su.updateStatement.WhereQ(policy)
+	}
 
 	// Optimistic Locking
 	opLockColumn := su.recordDescription.structMapping.GetOpLockSQLFieldName()
@@ -134,6 +167,9 @@ func (su *StructUpdate) Do() error {
 
 	// Use a RETURNING (or similar) clause ?
 	returningBuilder, ok := su.updateStatement.db.adapter.(adapters.ReturningBuilder)
+	if isUpdatableView(su.recordDescription) {
+		returningBuilder, ok = nil, false
+	}
 	if ok {
 		autoColumns := su.recordDescription.structMapping.GetAutoColumnsNames()
 		su.updateStatement.Returning(returningBuilder.FormatForNewValues(autoColumns)...)
@@ -158,7 +194,7 @@ func (su *StructUpdate) Do() error {
 		}
 	}
 
-	if opLockColumn != "" && rowsAffected == 0 {
+	if opLockColumn != "" && rowsAffected == 0 && !su.updateStatement.db.dryRun && !isUpdatableView(su.recordDescription) {
 		err = ErrOpLock
 	}
 
@@ -0,0 +1,152 @@
+package godb
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/samonzeweb/godb/dbreflect"
+)
+
+// SyncResult contains the counts of changes done by Sync.
+type SyncResult struct {
+	Inserted int
+	Updated  int
+	Deleted  int
+}
+
+// Sync makes the table contents match the given records (a slice pointer),
+// in the scope of the given condition (or the whole table if nil) : the
+// missing records are inserted, the different ones are updated, and the
+// records of the scope not given are deleted. The records are matched on
+// their keys, the struct needs at least one key. All changes are done in one
+// transaction (or in the current one). It is useful to synchronize
+// configuration or reference data.
+//
+// The records with an auto key are inserted if their key is zero. The
+// optimistic locking field, if any, is taken from the current row before an
+// update.
+//
+// Example :
+// 	result, err := db.Sync(&countries, godb.Q("region = ?", "europe"))
+func (db *DB) Sync(records interface{}, scope *Condition) (*SyncResult, error) {
+	recordDescription, err := buildRecordDescription(records)
+	if err != nil {
+		return nil, err
+	}
+	if !recordDescription.isSlice {
+		return nil, fmt.Errorf("Sync accepts only a slice")
+	}
+	if err := db.checkWritableModel(recordDescription); err != nil {
+		return nil, err
+	}
+	structMapping := recordDescription.structMapping
+	if len(structMapping.GetKeyColumnsNames()) == 0 {
+		return nil, fmt.Errorf("the struct %s has no key, it can't be synced", structMapping.Name)
+	}
+
+	ownTx := db.sqlTx == nil
+	if ownTx {
+		if err := db.Begin(); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := db.sync(recordDescription, scope)
+	if ownTx {
+		if err != nil {
+			db.Rollback()
+			return nil, err
+		}
+		if err := db.Commit(); err != nil {
+			return nil, err
+		}
+	}
+	return result, err
+}
+
+// sync executes Sync, inside a transaction.
+func (db *DB) sync(recordDescription *recordDescription, scope *Condition) (*SyncResult, error) {
+	structMapping := recordDescription.structMapping
+	result := &SyncResult{}
+
+	current := reflect.New(reflect.TypeOf(recordDescription.record).Elem())
+	selectStatement := db.Select(current.Interface())
+	if scope != nil {
+		selectStatement.WhereQ(scope)
+	}
+	if err := selectStatement.Do(); err != nil {
+		return nil, err
+	}
+	currentDescription, err := buildRecordDescription(current.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	currentByKey := make(map[string]interface{}, currentDescription.len())
+	for i := 0; i < currentDescription.len(); i++ {
+		record := currentDescription.index(i)
+		currentByKey[syncKey(structMapping.GetKeyFieldsValues(record))] = record
+	}
+
+	opLockColumn := structMapping.GetOpLockSQLFieldName()
+	for i := 0; i < recordDescription.len(); i++ {
+		record := recordDescription.index(i)
+		key := syncKey(structMapping.GetKeyFieldsValues(record))
+		currentRecord, exists := currentByKey[key]
+		if !exists {
+			if err := db.Insert(record).Do(); err != nil {
+				return nil, err
+			}
+			result.Inserted++
+			continue
+		}
+		delete(currentByKey, key)
+
+		if opLockColumn != "" {
+			if err := copyColumnValue(structMapping, currentRecord, record, opLockColumn); err != nil {
+				return nil, err
+			}
+		}
+		if reflect.DeepEqual(structMapping.GetAllFieldsValues(currentRecord), structMapping.GetAllFieldsValues(record)) {
+			continue
+		}
+		if err := db.Update(record).Do(); err != nil {
+			return nil, err
+		}
+		result.Updated++
+	}
+
+	// Deleted in the order of the current records, for a predictable
+	// execution
+	for i := 0; i < currentDescription.len(); i++ {
+		record := currentDescription.index(i)
+		if _, stillThere := currentByKey[syncKey(structMapping.GetKeyFieldsValues(record))]; !stillThere {
+			continue
+		}
+		if _, err := db.Delete(record).Do(); err != nil {
+			return nil, err
+		}
+		result.Deleted++
+	}
+
+	return result, nil
+}
+
+// syncKey returns a string identifying the given key values.
+func syncKey(keyValues []interface{}) string {
+	return fmt.Sprintf("%#v", keyValues)
+}
+
+// copyColumnValue copies the value of a column from a record to another one.
+func copyColumnValue(structMapping *dbreflect.StructMapping, from interface{}, to interface{}, column string) error {
+	fromPointers, err := structMapping.GetPointersForColumns(from, column)
+	if err != nil {
+		return err
+	}
+	toPointers, err := structMapping.GetPointersForColumns(to, column)
+	if err != nil {
+		return err
+	}
+	reflect.ValueOf(toPointers[0]).Elem().Set(reflect.ValueOf(fromPointers[0]).Elem())
+	return nil
+}
@@ -0,0 +1,60 @@
+package godb
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSync(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		current := make([]Dummy, 0)
+		So(db.Select(&current).OrderBy("id").Do(), ShouldBeNil)
+
+		Convey("Sync inserts, updates and deletes to match the records", func() {
+			desired := []Dummy{
+				current[0],
+				current[1],
+				{AText: "Fourth", AnInteger: 14},
+			}
+			desired[1].AnotherText = "changed"
+			desired[1].Version = 0
+
+			result, err := db.Sync(&desired, nil)
+			So(err, ShouldBeNil)
+			So(*result, ShouldResemble, SyncResult{Inserted: 1, Updated: 1, Deleted: 1})
+
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).OrderBy("id").Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+			So(dummies[0].AText, ShouldEqual, "First")
+			So(dummies[1].AnotherText, ShouldEqual, "changed")
+			So(dummies[2].AText, ShouldEqual, "Fourth")
+
+			Convey("A second Sync changes nothing", func() {
+				result, err := db.Sync(&dummies, nil)
+				So(err, ShouldBeNil)
+				So(*result, ShouldResemble, SyncResult{})
+			})
+		})
+
+		Convey("Sync deletes only in the scope", func() {
+			desired := []Dummy{current[0]}
+			result, err := db.Sync(&desired, Q("an_integer < ?", 13))
+			So(err, ShouldBeNil)
+			So(*result, ShouldResemble, SyncResult{Deleted: 1})
+
+			count, err := db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+		})
+
+		Convey("Sync accepts only slices", func() {
+			_, err := db.Sync(&current[0], nil)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,65 @@
+package godb
+
+import (
+	"fmt"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// CreateTempTableFrom creates an empty temporary table having the columns of
+// the table of the given struct (used only for its table name). The struct
+// tools target it with their Table method, to load rows in bulk then merge
+// them into the real table with a single statement.
+//
+// A temporary table is bound to a connection, it has to be created in a
+// transaction, and it's dropped at its end. Its columns have no constraint
+// (the defaults are kept by some adapters). With SQL Server its name starts
+// with #.
+//
+// Example :
+// 	err := db.Begin()
+// 	err = db.CreateTempTableFrom(&Book{}, "staging_books")
+// 	err = db.BulkInsert(&books).Table("staging_books").Do()
+// 	_, err = db.InsertInto("books").
+// 		Columns("title", "author").
+// 		FromSelect(db.SelectFrom("staging_books").Columns("title", "author")).
+// 		Do()
+// 	err = db.Commit()
+func (db *DB) CreateTempTableFrom(record interface{}, name string) error {
+	builder, ok := db.adapter.(adapters.TempTableBuilder)
+	if !ok {
+		return fmt.Errorf("the adapter does not support temporary tables")
+	}
+	if db.sqlTx == nil {
+		return fmt.Errorf("CreateTempTableFrom needs a transaction")
+	}
+	recordDescription, err := buildRecordDescription(record)
+	if err != nil {
+		return err
+	}
+
+	source := db.quoteFor(recordDescription, db.defaultTableNamer(recordDescription.getTableName()))
+	query := builder.BuildCreateTempTable(db.quote(name), source)
+	if _, _, err := db.RawSQL(query).DoExec(); err != nil {
+		return err
+	}
+	db.tempTables = append(db.tempTables, name)
+	return nil
+}
+
+// dropTempTables drops the temporary tables created in the current
+// transaction, and returns the first error.
+func (db *DB) dropTempTables() error {
+	var firstErr error
+	builder, ok := db.adapter.(adapters.TempTableBuilder)
+	if ok {
+		for _, name := range db.tempTables {
+			_, _, err := db.RawSQL(builder.BuildDropTempTable(db.quote(name))).DoExec()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	db.tempTables = nil
+	return firstErr
+}
@@ -0,0 +1,63 @@
+package godb
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCreateTempTableFrom(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("CreateTempTableFrom needs a transaction", func() {
+			So(db.CreateTempTableFrom(&Dummy{}, "staging"), ShouldNotBeNil)
+		})
+
+		Convey("The temporary table is loaded then merged", func() {
+			So(db.Begin(), ShouldBeNil)
+			So(db.CreateTempTableFrom(&Dummy{}, "staging"), ShouldBeNil)
+
+			staged := []Dummy{
+				{AText: "Fourth", AnotherText: "Staged", AnInteger: 14},
+				{AText: "Fifth", AnotherText: "Staged", AnInteger: 15},
+			}
+			So(db.BulkInsert(&staged).Table("staging").Do(), ShouldBeNil)
+			_, err := db.CurrentTx().Exec("update staging set id = an_integer")
+			So(err, ShouldBeNil)
+			So(db.Update(&Dummy{ID: 15, AText: "Updated", AnotherText: "Staged", AnInteger: 15}).Table("staging").Do(), ShouldBeNil)
+			count, err := db.Delete(&Dummy{ID: 14}).Table("staging").Do()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+
+			loaded := make([]Dummy, 0)
+			So(db.Select(&loaded).Table("staging").Do(), ShouldBeNil)
+			So(len(loaded), ShouldEqual, 1)
+			So(loaded[0].AText, ShouldEqual, "Updated")
+
+			_, err = db.InsertInto("dummies").
+				Columns("a_text", "another_text", "an_integer").
+				FromSelect(db.SelectFrom("staging").Columns("a_text", "another_text", "an_integer").Where("an_integer > ?", 10)).
+				Do()
+			So(err, ShouldBeNil)
+			So(db.Commit(), ShouldBeNil)
+
+			merged := Dummy{}
+			So(db.Select(&merged).Where("an_integer = ?", 15).Do(), ShouldBeNil)
+			So(merged.AText, ShouldEqual, "Updated")
+			_, err = db.SelectFrom("staging").Count()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("The temporary table is dropped by a rollback", func() {
+			So(db.Begin(), ShouldBeNil)
+			So(db.CreateTempTableFrom(&Dummy{}, "staging"), ShouldBeNil)
+			So(db.Rollback(), ShouldBeNil)
+
+			So(db.Begin(), ShouldBeNil)
+			So(db.CreateTempTableFrom(&Dummy{}, "staging"), ShouldBeNil)
+			So(db.Commit(), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,29 @@
+package godb
+
+import (
+	"context"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// executionContext returns the context of a statement execution, and the
+// function releasing it. It's the context of the DB (see SetContext), with
+// the timeout of the statement as deadline if any.
+func (db *DB) executionContext(options statementOptions) (context.Context, context.CancelFunc) {
+	if options.timeout <= 0 {
+		return db.Context(), func() {}
+	}
+	return context.WithTimeout(db.Context(), options.timeout)
+}
+
+// hintTimeout adds the server side limit of the execution time to the query
+// if the statement has a timeout and the adapter needs it.
+func (db *DB) hintTimeout(query string, options statementOptions) string {
+	if options.timeout <= 0 {
+		return query
+	}
+	if hinter, ok := db.adapter.(adapters.TimeoutHinter); ok {
+		return hinter.HintTimeout(query, options.timeout)
+	}
+	return query
+}
@@ -0,0 +1,68 @@
+package godb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters/mysql"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const slowQuery = `with recursive counter(n) as (
+	select 1 union all select n + 1 from counter where n < 100000000)
+	select count(*) from counter`
+
+func TestTimeout(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("A statement exceeding its timeout is cancelled", func() {
+			var count int
+			err := db.SelectFrom("dummies").Columns("count(*)").
+				Where("an_integer < (" + slowQuery + ")").
+				Timeout(10 * time.Millisecond).
+				Scanx(&count)
+			So(err, ShouldNotBeNil)
+			So(errors.Is(err, context.DeadlineExceeded) || err.Error() == "interrupted", ShouldBeTrue)
+
+			err = db.SelectFrom("dummies").Columns("count(*)").Timeout(time.Minute).Scanx(&count)
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("The timeout is bound to the context of the DB", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			db.SetContext(ctx)
+			_, err := db.DeleteFrom("dummies").Timeout(time.Minute).Do()
+			So(errors.Is(err, context.Canceled), ShouldBeTrue)
+		})
+
+		Convey("The statements without timeout are bound to the context of the DB", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			db.SetContext(ctx)
+			_, err := db.SelectFrom("dummies").Count()
+			So(errors.Is(err, context.Canceled), ShouldBeTrue)
+		})
+
+		Convey("The iterators release the context when they are closed", func() {
+			iter, err := db.SelectFrom("dummies").Columns("id").Timeout(time.Minute).DoWithIterator()
+			So(err, ShouldBeNil)
+			So(iter.Next(), ShouldBeTrue)
+			So(iter.Close(), ShouldBeNil)
+		})
+
+		Convey("A hint is added to the MySQL SELECT statements", func() {
+			db.adapter = mysql.Adapter
+			options := statementOptions{timeout: 1500 * time.Millisecond}
+			So(db.hintTimeout("SELECT * FROM dummies", options), ShouldEqual, "SELECT /*+ MAX_EXECUTION_TIME(1500) */ * FROM dummies")
+			So(db.hintTimeout("DELETE FROM dummies", options), ShouldEqual, "DELETE FROM dummies")
+			So(db.hintTimeout("SELECT * FROM dummies", statementOptions{}), ShouldEqual, "SELECT * FROM dummies")
+		})
+	})
+}
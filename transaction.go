@@ -1,9 +1,13 @@
 package godb
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"time"
+
+	"github.com/samonzeweb/godb/adapters"
 )
 
 // preparableAndQueryable represents either a Tx or DB.
@@ -11,18 +15,40 @@ type preparableAndQueryable interface {
 	Exec(query string, args ...interface{}) (sql.Result, error)
 	Query(query string, args ...interface{}) (*sql.Rows, error)
 	QueryRow(query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 	Prepare(query string) (*sql.Stmt, error)
 }
 
 // Begin starts a new transaction, fails if there is already one.
 func (db *DB) Begin() error {
+	return db.begin(func() (*sql.Tx, error) {
+		return db.sqlDB.Begin()
+	})
+}
 
+// BeginTx starts a new transaction with the given options, in the context of
+// the DB (see SetContext), fails if there is already one. The driver has to
+// support the options, for example a read only transaction, which reads a
+// consistent snapshot without taking locks with Spanner.
+//
+// Example :
+// 	err := db.BeginTx(&sql.TxOptions{ReadOnly: true})
+func (db *DB) BeginTx(opts *sql.TxOptions) error {
+	return db.begin(func() (*sql.Tx, error) {
+		return db.sqlDB.BeginTx(db.Context(), opts)
+	})
+}
+
+// begin starts a new transaction with the given function.
+func (db *DB) begin(beginTx func() (*sql.Tx, error)) error {
 	if db.sqlTx != nil {
 		return fmt.Errorf("Begin was called multiple times, sql transaction already exists")
 	}
 
 	startTime := time.Now()
-	tx, err := db.sqlDB.Begin()
+	tx, err := beginTx()
 	consumedTime := timeElapsedSince(startTime)
 	db.addConsumedTime(consumedTime)
 	db.logExecution(consumedTime, "BEGIN")
@@ -32,15 +58,21 @@ func (db *DB) Begin() error {
 	}
 
 	db.sqlTx = tx
+	db.savepoints = 0
 	return nil
 }
 
-// Commit commits an existing transaction, fails if none exists.
+// Commit commits an existing transaction, fails if none exists. The
+// temporary tables created in the transaction are dropped before, the
+// transaction is not committed if they can't be dropped.
 func (db *DB) Commit() error {
 
 	if db.sqlTx == nil {
 		return fmt.Errorf("Commit was called without existing sql transaction")
 	}
+	if err := db.dropTempTables(); err != nil {
+		return err
+	}
 
 	db.stmtCacheTx.clearWithoutClosingStmt()
 	startTime := time.Now()
@@ -48,7 +80,7 @@ func (db *DB) Commit() error {
 	consumedTime := timeElapsedSince(startTime)
 	db.addConsumedTime(consumedTime)
 	db.logExecution(consumedTime, "COMMIT")
-	db.sqlTx = nil
+	db.endTransaction()
 	if err!=nil {
 		db.logExecutionErr(err, "COMMIT")
 	}
@@ -60,6 +92,9 @@ func (db *DB) Rollback() error {
 	if db.sqlTx == nil {
 		return fmt.Errorf("Rollback was called without existing sql transaction")
 	}
+	// the transaction could be in a failed state, the tables are removed by
+	// the rollback with most databases
+	db.dropTempTables()
 
 	db.stmtCacheTx.clearWithoutClosingStmt()
 	startTime := time.Now()
@@ -70,12 +105,99 @@ func (db *DB) Rollback() error {
 	if err!=nil {
 		db.logExecutionErr(err, "ROLLBACK")
 	}
-	db.sqlTx = nil
+	db.endTransaction()
 	return err
 }
 
+// endTransaction forgets the ended transaction, and the data cached during
+// it.
+func (db *DB) endTransaction() {
+	db.sqlTx = nil
+	db.relationCache = nil
+	db.ClearIdentityMap()
+}
+
 // CurrentTx returns the current Tx (or nil). Don't commit or rollback it
 // directly !
 func (db *DB) CurrentTx() *sql.Tx {
 	return db.sqlTx
 }
+
+// WithSavepoint executes the given function in a savepoint of the current
+// transaction : if the function returns an error (or panics), only its
+// changes are rolled back, and the transaction can continue. It's needed
+// with PostgreSQL, where a failed statement aborts the whole transaction.
+// The savepoints can be nested. The function gets the DB, it must not commit
+// or rollback the transaction.
+//
+// Example :
+// 	db.Begin()
+// 	for _, book := range books {
+// 		err := db.WithSavepoint(func(db *godb.DB) error {
+// 			return db.Insert(&book).Do()
+// 		})
+// 		if err != nil {
+// 			... (the book is skipped, the others are inserted)
+// 		}
+// 	}
+// 	err := db.Commit()
+func (db *DB) WithSavepoint(f func(db *DB) error) (err error) {
+	if db.sqlTx == nil {
+		return fmt.Errorf("WithSavepoint was called without existing sql transaction")
+	}
+
+	db.savepoints++
+	name := "godb_savepoint_" + strconv.Itoa(db.savepoints)
+	savepoint, rollbackTo, release := "SAVEPOINT "+name, "ROLLBACK TO SAVEPOINT "+name, "RELEASE SAVEPOINT "+name
+	if builder, ok := db.adapter.(adapters.SavepointBuilder); ok {
+		savepoint = builder.BuildSavepoint(name)
+		rollbackTo = builder.BuildRollbackToSavepoint(name)
+		release = builder.BuildReleaseSavepoint(name)
+	}
+	if err := db.execInTx(savepoint); err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			db.rollbackToSavepoint(rollbackTo)
+			panic(r)
+		}
+		if err != nil {
+			if rollbackErr := db.rollbackToSavepoint(rollbackTo); rollbackErr != nil {
+				err = fmt.Errorf("%v (and the rollback to the savepoint failed : %v)", err, rollbackErr)
+			}
+			return
+		}
+		if release != "" {
+			err = db.execInTx(release)
+		}
+	}()
+	return f(db)
+}
+
+// rollbackToSavepoint executes the given statement rolling back to a
+// savepoint. The rows read in the savepoint are forgotten, they could have
+// been rolled back.
+func (db *DB) rollbackToSavepoint(rollbackTo string) error {
+	db.relationCache = nil
+	db.ClearIdentityMap()
+	return db.execInTx(rollbackTo)
+}
+
+// execInTx executes a statement controlling the current transaction.
+func (db *DB) execInTx(query string) error {
+	if db.sqlTx == nil {
+		return fmt.Errorf("the transaction ended before %s", query)
+	}
+
+	startTime := time.Now()
+	_, err := db.sqlTx.Exec(query)
+	consumedTime := timeElapsedSince(startTime)
+	db.addConsumedTime(consumedTime)
+	db.logExecution(consumedTime, query)
+	if err != nil {
+		db.logExecutionErr(err, query)
+	}
+	return err
+}
@@ -1,6 +1,8 @@
 package godb
 
 import (
+	"database/sql"
+	"errors"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -24,6 +26,25 @@ func TestBegin(t *testing.T) {
 	})
 }
 
+func TestBeginTx(t *testing.T) {
+	Convey("Given an existing connection", t, func() {
+		db := createInMemoryConnection(t)
+		defer db.Close()
+
+		Convey("BeginTx create a new transaction with the given options", func() {
+			err := db.BeginTx(&sql.TxOptions{ReadOnly: true})
+			So(err, ShouldBeNil)
+			So(db.sqlTx, ShouldNotBeNil)
+			So(db.Rollback(), ShouldBeNil)
+
+			Convey("BeginTx fails is a transactions already exists", func() {
+				So(db.BeginTx(nil), ShouldBeNil)
+				So(db.BeginTx(nil), ShouldNotBeNil)
+			})
+		})
+	})
+}
+
 func TestCommit(t *testing.T) {
 	Convey("Given an existing connexion", t, func() {
 		db := createInMemoryConnection(t)
@@ -75,3 +96,73 @@ func TestCurrentTx(t *testing.T) {
 		})
 	})
 }
+
+func TestWithSavepoint(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		insert := func(db *DB, text string) error {
+			_, err := db.InsertInto("dummies").Columns("a_text", "another_text", "an_integer").Values(text, text, 1).Do()
+			return err
+		}
+		count := func() int64 {
+			count, err := db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			return count
+		}
+
+		Convey("WithSavepoint fails without transaction", func() {
+			So(db.WithSavepoint(func(db *DB) error { return nil }), ShouldNotBeNil)
+		})
+
+		Convey("WithSavepoint keeps the changes of a successful function", func() {
+			So(db.Begin(), ShouldBeNil)
+			So(db.WithSavepoint(func(db *DB) error { return insert(db, "Kept") }), ShouldBeNil)
+			So(db.Commit(), ShouldBeNil)
+			So(count(), ShouldEqual, 4)
+		})
+
+		Convey("WithSavepoint rolls back only the changes of a failed function", func() {
+			failure := errors.New("failure")
+			So(db.Begin(), ShouldBeNil)
+			So(insert(db, "Before"), ShouldBeNil)
+			err := db.WithSavepoint(func(db *DB) error {
+				So(insert(db, "Rolled back"), ShouldBeNil)
+				return failure
+			})
+			So(err, ShouldEqual, failure)
+			So(insert(db, "After"), ShouldBeNil)
+			So(db.Commit(), ShouldBeNil)
+			So(count(), ShouldEqual, 5)
+		})
+
+		Convey("The savepoints can be nested", func() {
+			So(db.Begin(), ShouldBeNil)
+			err := db.WithSavepoint(func(db *DB) error {
+				if err := insert(db, "Outer"); err != nil {
+					return err
+				}
+				db.WithSavepoint(func(db *DB) error {
+					insert(db, "Inner")
+					return errors.New("failure")
+				})
+				return nil
+			})
+			So(err, ShouldBeNil)
+			So(db.Commit(), ShouldBeNil)
+			So(count(), ShouldEqual, 4)
+		})
+
+		Convey("WithSavepoint rolls back the changes of a panicking function", func() {
+			So(db.Begin(), ShouldBeNil)
+			So(func() {
+				db.WithSavepoint(func(db *DB) error {
+					insert(db, "Rolled back")
+					panic("failure")
+				})
+			}, ShouldPanic)
+			So(db.Commit(), ShouldBeNil)
+			So(count(), ShouldEqual, 3)
+		})
+	})
+}
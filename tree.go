@@ -0,0 +1,207 @@
+package godb
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// LoadTree loads the descendants of the given root (a struct pointer, already
+// loaded) of an adjacency list hierarchy, into its children field. The
+// children field is a slice of the root struct (or of pointers) declared as
+// a hasmany relation with a rel tag, see Association.
+//
+// The depth is the number of levels loaded, all levels are loaded if it's
+// lower than 1. A recursive common table expression is used if the adapter
+// supports it (see adapters.RecursiveQueryBuilder), otherwise a query is
+// executed by level. The hierarchy must not have cycles when all the levels
+// are loaded with a recursive query. The children are ordered by key.
+//
+// Example :
+// 	type Category struct {
+// 		ID       int           `db:"id,key,auto"`
+// 		ParentID sql.NullInt64 `db:"parent_id"`
+// 		Children []*Category   `db:"-" rel:"hasmany,foreignkey=parent_id"`
+// 	}
+//
+// 	err := db.LoadTree(&root, 3)
+func (db *DB) LoadTree(root interface{}, depth int) error {
+	rootType := reflect.TypeOf(root)
+	if rootType == nil || rootType.Kind() != reflect.Ptr || rootType.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("LoadTree accepts only a struct pointer, got %T", root)
+	}
+
+	fieldName := ""
+	for i := 0; i < rootType.Elem().NumField(); i++ {
+		field := rootType.Elem().Field(i)
+		childType := field.Type
+		if childType.Kind() != reflect.Slice {
+			continue
+		}
+		childType = childType.Elem()
+		if childType.Kind() == reflect.Ptr {
+			childType = childType.Elem()
+		}
+		tag, ok := field.Tag.Lookup(associationTagName)
+		if ok && childType == rootType.Elem() && parseRelationKind(tag) == hasManyRelation {
+			fieldName = field.Name
+			break
+		}
+	}
+	if fieldName == "" {
+		return fmt.Errorf("the struct %s has no hasmany relation to itself", rootType.Elem().Name())
+	}
+
+	a := db.Association(root, fieldName)
+	if a.error != nil {
+		return a.error
+	}
+
+	var nodes []reflect.Value
+	var err error
+	if builder, ok := db.adapter.(adapters.RecursiveQueryBuilder); ok {
+		nodes, err = a.loadTreeRecursive(builder.RecursiveWith(), depth)
+	} else {
+		nodes, err = a.loadTreeByLevel(depth)
+	}
+	if err != nil {
+		return err
+	}
+
+	children := make(map[string][]reflect.Value)
+	for _, node := range nodes {
+		parentKey, err := a.parentKey(node.Interface())
+		if err != nil {
+			return err
+		}
+		children[parentKey] = append(children[parentKey], node)
+	}
+	visited := map[string]bool{treeKey(a.ownerKey): true}
+	a.attachChildren(reflect.ValueOf(root), children, visited)
+	return nil
+}
+
+// parseRelationKind returns the kind of relation of a rel tag.
+func parseRelationKind(tag string) string {
+	return strings.TrimSpace(strings.Split(tag, ",")[0])
+}
+
+// loadTreeRecursive fetches the descendants of the owner with a recursive
+// query.
+func (a *Association) loadTreeRecursive(recursiveWith string, depth int) ([]reflect.Value, error) {
+	db := a.db
+	tableName := db.quoteFor(a.targetDescription, db.defaultTableNamer(a.targetDescription.getTableName()))
+	keyColumn := db.quoteFor(a.targetDescription, a.targetDescription.structMapping.GetKeyColumnsNames()[0])
+	foreignKey := db.quoteFor(a.targetDescription, a.association.foreignKey)
+	columns := db.quoteAllFor(a.targetDescription, a.targetDescription.structMapping.GetAllColumnsNames())
+
+	query := recursiveWith + " godb_tree (godb_key, godb_depth) AS (" +
+		"SELECT " + keyColumn + ", 1 FROM " + tableName + " WHERE " + foreignKey + " = ?" +
+		" UNION ALL " +
+		"SELECT " + tableName + "." + keyColumn + ", godb_tree.godb_depth + 1 FROM " + tableName +
+		" INNER JOIN godb_tree ON " + tableName + "." + foreignKey + " = godb_tree.godb_key"
+	args := []interface{}{a.ownerKey}
+	if depth > 0 {
+		query += " WHERE godb_tree.godb_depth < ?"
+		args = append(args, depth)
+	}
+	query += ") SELECT " + strings.Join(columns, ", ") + " FROM " + tableName +
+		" WHERE " + keyColumn + " IN (SELECT godb_key FROM godb_tree)"
+	if policy := db.policyCondition(a.targetDescription); policy != nil {
+		if policy.err != nil {
+			return nil, policy.err
+		}
+		query += " AND (" + policy.sql + ")"
+		args = append(args, policy.args...)
+	}
+	query += " ORDER BY " + keyColumn
+
+	nodes := reflect.New(reflect.SliceOf(reflect.PtrTo(a.targetType)))
+	if err := db.RawSQL(query, args...).Do(nodes.Interface()); err != nil {
+		return nil, err
+	}
+	return sliceElements(nodes.Elem()), nil
+}
+
+// loadTreeByLevel fetches the descendants of the owner with a query by
+// level.
+func (a *Association) loadTreeByLevel(depth int) ([]reflect.Value, error) {
+	db := a.db
+	foreignKey := db.quoteFor(a.targetDescription, a.association.foreignKey)
+	keyColumn := db.quoteFor(a.targetDescription, a.targetDescription.structMapping.GetKeyColumnsNames()[0])
+	visited := map[string]bool{treeKey(a.ownerKey): true}
+	keys := []interface{}{a.ownerKey}
+	var all []reflect.Value
+
+	for level := 0; len(keys) > 0 && (depth < 1 || level < depth); level++ {
+		nodes := reflect.New(reflect.SliceOf(reflect.PtrTo(a.targetType)))
+		if err := db.Select(nodes.Interface()).WhereQ(Q(foreignKey+" IN (?)", keys)).OrderBy(keyColumn).Do(); err != nil {
+			return nil, err
+		}
+		keys = make([]interface{}, 0)
+		for _, node := range sliceElements(nodes.Elem()) {
+			key := a.targetDescription.structMapping.GetKeyFieldsValues(node.Interface())[0]
+			if visited[treeKey(key)] {
+				continue
+			}
+			visited[treeKey(key)] = true
+			keys = append(keys, key)
+			all = append(all, node)
+		}
+	}
+	return all, nil
+}
+
+// parentKey returns the tree key of the parent of the given node.
+func (a *Association) parentKey(node interface{}) (string, error) {
+	pointers, err := a.targetDescription.structMapping.GetPointersForColumns(node, a.association.foreignKey)
+	if err != nil {
+		return "", err
+	}
+	return treeKey(reflect.ValueOf(pointers[0]).Elem().Interface()), nil
+}
+
+// attachChildren sets the children field of the given node (a struct
+// pointer) and of its descendants. The visited nodes are skipped.
+func (a *Association) attachChildren(node reflect.Value, children map[string][]reflect.Value, visited map[string]bool) {
+	field := node.Elem().FieldByIndex(a.fieldIndex)
+	nodeChildren := children[treeKey(a.targetDescription.structMapping.GetKeyFieldsValues(node.Interface())[0])]
+	slice := reflect.MakeSlice(field.Type(), 0, len(nodeChildren))
+	for _, child := range nodeChildren {
+		childKey := treeKey(a.targetDescription.structMapping.GetKeyFieldsValues(child.Interface())[0])
+		if visited[childKey] {
+			continue
+		}
+		visited[childKey] = true
+		a.attachChildren(child, children, visited)
+		if field.Type().Elem().Kind() == reflect.Ptr {
+			slice = reflect.Append(slice, child)
+		} else {
+			slice = reflect.Append(slice, child.Elem())
+		}
+	}
+	field.Set(slice)
+}
+
+// treeKey returns a comparable representation of a key value, the same for
+// a key and a foreign key of a nullable type.
+func treeKey(value interface{}) string {
+	if valuer, ok := value.(driver.Valuer); ok {
+		if driverValue, err := valuer.Value(); err == nil {
+			value = driverValue
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// sliceElements returns the elements of the given slice value.
+func sliceElements(slice reflect.Value) []reflect.Value {
+	elements := make([]reflect.Value, 0, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		elements = append(elements, slice.Index(i))
+	}
+	return elements
+}
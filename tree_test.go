@@ -0,0 +1,89 @@
+package godb
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type TreeNode struct {
+	ID       int           `db:"id,key,auto"`
+	ParentID sql.NullInt64 `db:"parent_id"`
+	Name     string        `db:"name"`
+	Children []TreeNode    `db:"-" rel:"hasmany,foreignkey=parent_id"`
+}
+
+func (*TreeNode) TableName() string {
+	return "nodes"
+}
+
+// levelByLevelAdapter hides the optional interfaces of the wrapped adapter.
+type levelByLevelAdapter struct {
+	adapters.Adapter
+}
+
+func treeFixturesSetup(t *testing.T, adapter adapters.Adapter) *DB {
+	db, err := Open(adapter, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.sqlDB.Exec(`
+		create table nodes (
+			id integer not null primary key autoincrement,
+			parent_id integer,
+			name text not null);
+		insert into nodes (parent_id, name) values
+			(NULL, "root"), (1, "a"), (1, "b"), (2, "a.a"), (4, "a.a.a"), (NULL, "other");
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestLoadTree(t *testing.T) {
+	for _, adapter := range []adapters.Adapter{sqlite.Adapter, levelByLevelAdapter{sqlite.Adapter}} {
+		Convey("Given a test database with a hierarchy", t, func() {
+			db := treeFixturesSetup(t, adapter)
+			defer db.Close()
+			root := TreeNode{}
+			So(db.Get(&root, 1), ShouldBeNil)
+
+			Convey("LoadTree loads all the levels", func() {
+				So(db.LoadTree(&root, 0), ShouldBeNil)
+				So(len(root.Children), ShouldEqual, 2)
+				So(root.Children[0].Name, ShouldEqual, "a")
+				So(root.Children[1].Name, ShouldEqual, "b")
+				So(root.Children[1].Children, ShouldBeEmpty)
+				So(len(root.Children[0].Children), ShouldEqual, 1)
+				So(root.Children[0].Children[0].Name, ShouldEqual, "a.a")
+				So(len(root.Children[0].Children[0].Children), ShouldEqual, 1)
+				So(root.Children[0].Children[0].Children[0].Name, ShouldEqual, "a.a.a")
+			})
+
+			Convey("LoadTree stops at the given depth", func() {
+				So(db.LoadTree(&root, 2), ShouldBeNil)
+				So(len(root.Children), ShouldEqual, 2)
+				So(len(root.Children[0].Children), ShouldEqual, 1)
+				So(root.Children[0].Children[0].Children, ShouldBeEmpty)
+			})
+
+			Convey("LoadTree ignores the cycles", func() {
+				_, err := db.CurrentDB().Exec("update nodes set parent_id = 5 where id = 1")
+				So(err, ShouldBeNil)
+				So(db.LoadTree(&root, 10), ShouldBeNil)
+				So(len(root.Children), ShouldEqual, 2)
+				So(root.Children[0].Children[0].Children[0].Children, ShouldBeEmpty)
+			})
+
+			Convey("LoadTree needs a relation to the same struct", func() {
+				So(db.LoadTree(&Dummy{ID: 1}, 0), ShouldNotBeNil)
+				So(db.LoadTree(root, 0), ShouldNotBeNil)
+			})
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package godb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// twoPhaseCommitBuilder returns the adapter as TwoPhaseCommitBuilder, or an
+// error if the adapter does not support the two-phase commit.
+func (db *DB) twoPhaseCommitBuilder() (adapters.TwoPhaseCommitBuilder, error) {
+	builder, ok := db.adapter.(adapters.TwoPhaseCommitBuilder)
+	if !ok {
+		return nil, fmt.Errorf("the adapter does not support the two-phase commit")
+	}
+	return builder, nil
+}
+
+// PrepareTransaction prepares the current transaction for a two-phase commit
+// with the given global identifier, and ends it for the DB. The prepared
+// transaction survives a crash, it has to be committed or rolled back later
+// with CommitPrepared or RollbackPrepared, from any session.
+//
+// Only PostgreSQL supports it (max_prepared_transactions has to be set), the
+// others adapters return an error. The XA transactions of MySQL aren't
+// supported : they have to be started with XA START instead of the START
+// TRANSACTION sent by database/sql.
+//
+// Example :
+// 	db.Begin()
+// 	... (changes)
+// 	err := db.PrepareTransaction("order-42")
+// 	... (once all the participants are prepared)
+// 	err = db.CommitPrepared("order-42")
+func (db *DB) PrepareTransaction(id string) error {
+	builder, err := db.twoPhaseCommitBuilder()
+	if err != nil {
+		return err
+	}
+	if db.sqlTx == nil {
+		return fmt.Errorf("PrepareTransaction was called without existing sql transaction")
+	}
+	if id == "" {
+		return fmt.Errorf("PrepareTransaction needs a transaction identifier")
+	}
+
+	// the temporary tables can't outlive the session, with PostgreSQL they
+	// even forbid the preparation
+	if err := db.dropTempTables(); err != nil {
+		return err
+	}
+
+	query := builder.BuildPrepareTransaction(id)
+	db.stmtCacheTx.clearWithoutClosingStmt()
+	startTime := time.Now()
+	_, err = db.sqlTx.Exec(query)
+	consumedTime := timeElapsedSince(startTime)
+	db.addConsumedTime(consumedTime)
+	db.logExecution(consumedTime, query)
+	if err != nil {
+		db.logExecutionErr(err, query)
+		db.sqlTx.Rollback()
+		db.endTransaction()
+		return err
+	}
+
+	// The session is no longer in a transaction, the rollback only releases
+	// the sql.Tx and its error (no transaction in progress) is irrelevant.
+	db.sqlTx.Rollback()
+	db.endTransaction()
+	return nil
+}
+
+// CommitPrepared commits the prepared transaction with the given identifier.
+// It can't be called in a transaction.
+func (db *DB) CommitPrepared(id string) error {
+	return db.endPrepared(id, func(builder adapters.TwoPhaseCommitBuilder) string {
+		return builder.BuildCommitPrepared(id)
+	})
+}
+
+// RollbackPrepared rolls back the prepared transaction with the given
+// identifier. It can't be called in a transaction.
+func (db *DB) RollbackPrepared(id string) error {
+	return db.endPrepared(id, func(builder adapters.TwoPhaseCommitBuilder) string {
+		return builder.BuildRollbackPrepared(id)
+	})
+}
+
+// endPrepared executes CommitPrepared and RollbackPrepared.
+func (db *DB) endPrepared(id string, build func(adapters.TwoPhaseCommitBuilder) string) error {
+	builder, err := db.twoPhaseCommitBuilder()
+	if err != nil {
+		return err
+	}
+	if db.sqlTx != nil {
+		return fmt.Errorf("a prepared transaction can't be ended in a transaction")
+	}
+	if id == "" {
+		return fmt.Errorf("a prepared transaction needs an identifier")
+	}
+
+	query := build(builder)
+	startTime := time.Now()
+	_, err = db.sqlDB.Exec(query)
+	consumedTime := timeElapsedSince(startTime)
+	db.addConsumedTime(consumedTime)
+	db.logExecution(consumedTime, query)
+	if err != nil {
+		db.logExecutionErr(err, query)
+	}
+	return err
+}
+
+// PreparedTransactions returns the identifiers of the transactions prepared
+// in the database and not yet committed or rolled back, for example to
+// recover after a crash of the transaction manager.
+func (db *DB) PreparedTransactions() ([]string, error) {
+	builder, err := db.twoPhaseCommitBuilder()
+	if err != nil {
+		return nil, err
+	}
+
+	query := builder.BuildListPrepared()
+	startTime := time.Now()
+	rows, err := db.sqlDB.Query(query)
+	consumedTime := timeElapsedSince(startTime)
+	db.addConsumedTime(consumedTime)
+	db.logExecution(consumedTime, query)
+	if err != nil {
+		db.logExecutionErr(err, query)
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,80 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// twoPhaseCommitSQLite fakes the two-phase commit with SQLite, the prepared
+// transaction is only released.
+type twoPhaseCommitSQLite struct {
+	sqlite.SQLite
+}
+
+func (twoPhaseCommitSQLite) BuildPrepareTransaction(id string) string {
+	return "SELECT 1"
+}
+
+func (twoPhaseCommitSQLite) BuildCommitPrepared(id string) string {
+	return "SELECT 1"
+}
+
+func (twoPhaseCommitSQLite) BuildRollbackPrepared(id string) string {
+	return "SELECT 1"
+}
+
+func (twoPhaseCommitSQLite) BuildListPrepared() string {
+	return "SELECT 'tx1' WHERE 1=0"
+}
+
+func TestTwoPhaseCommitWithoutSupport(t *testing.T) {
+	Convey("Given a test database whose adapter does not support the two-phase commit", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("The two-phase commit methods return an error", func() {
+			So(db.Begin(), ShouldBeNil)
+			So(db.PrepareTransaction("tx1"), ShouldNotBeNil)
+			So(db.CurrentTx(), ShouldNotBeNil)
+			So(db.Rollback(), ShouldBeNil)
+
+			So(db.CommitPrepared("tx1"), ShouldNotBeNil)
+			So(db.RollbackPrepared("tx1"), ShouldNotBeNil)
+			_, err := db.PreparedTransactions()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestPrepareTransaction(t *testing.T) {
+	Convey("Given a test database whose adapter supports the two-phase commit", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		db.adapter = twoPhaseCommitSQLite{}
+
+		Convey("PrepareTransaction ends the transaction like a commit", func() {
+			db.UseIdentityMap(true)
+			So(db.Begin(), ShouldBeNil)
+			var first *Dummy
+			So(db.Load(&first, 1), ShouldBeNil)
+			So(db.PrepareTransaction("tx1"), ShouldBeNil)
+			So(db.CurrentTx(), ShouldBeNil)
+
+			var again *Dummy
+			So(db.Load(&again, 1), ShouldBeNil)
+			So(again, ShouldNotEqual, first)
+		})
+
+		Convey("PrepareTransaction drops the temporary tables", func() {
+			So(db.Begin(), ShouldBeNil)
+			So(db.CreateTempTableFrom(&Dummy{}, "staging"), ShouldBeNil)
+			So(db.PrepareTransaction("tx1"), ShouldBeNil)
+
+			So(db.Begin(), ShouldBeNil)
+			So(db.CreateTempTableFrom(&Dummy{}, "staging"), ShouldBeNil)
+			So(db.Commit(), ShouldBeNil)
+		})
+	})
+}
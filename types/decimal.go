@@ -0,0 +1,191 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number, to use with NUMERIC or DECIMAL columns
+// (monetary values, ...) instead of float64. The zero value is 0.
+//
+// Its value is the decimal literal (like '-1234.56'), accepted by all
+// databases, and usable as an argument in conditions. It scans strings and
+// bytes returned by drivers for NUMERIC columns, and also integers and
+// floats (floats being converted with their shortest representation).
+type Decimal struct {
+	rat *big.Rat
+}
+
+var (
+	bigTwo  = big.NewInt(2)
+	bigFive = big.NewInt(5)
+	bigTen  = big.NewInt(10)
+)
+
+// NewDecimal creates a Decimal equal to value * 10^-scale, for example
+// NewDecimal(1999, 2) is 19.99 .
+func NewDecimal(value int64, scale int) Decimal {
+	r := new(big.Rat).SetInt64(value)
+	if scale > 0 {
+		r.Quo(r, new(big.Rat).SetInt(new(big.Int).Exp(bigTen, big.NewInt(int64(scale)), nil)))
+	} else if scale < 0 {
+		r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(bigTen, big.NewInt(int64(-scale)), nil)))
+	}
+	return Decimal{rat: r}
+}
+
+// ParseDecimal parses a decimal literal like '-1234.56' or '1.5e3'.
+func ParseDecimal(s string) (Decimal, error) {
+	if s == "" || strings.ContainsRune(s, '/') {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	return Decimal{rat: r}, nil
+}
+
+// Rat returns a copy of the decimal as a big.Rat, to compute with.
+func (d Decimal) Rat() *big.Rat {
+	if d.rat == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(d.rat)
+}
+
+// Cmp compares d and other, and returns -1, 0 or +1.
+func (d Decimal) Cmp(other Decimal) int {
+	return d.Rat().Cmp(other.Rat())
+}
+
+// Float64 returns the nearest float64 value of the decimal.
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// String returns the decimal literal, without trailing zeros.
+func (d Decimal) String() string {
+	r := d.Rat()
+	return r.FloatString(decimalScale(r.Denom()))
+}
+
+// StringFixed returns the decimal literal with the given number of digits
+// after the decimal point, rounding half away from zero.
+func (d Decimal) StringFixed(places int) string {
+	return d.Rat().FloatString(places)
+}
+
+// decimalScale returns the number of digits needed after the decimal point
+// for a denominator, which is a product of powers of 2 and 5.
+func decimalScale(denominator *big.Int) int {
+	scale := 0
+	d := new(big.Int).Set(denominator)
+	m := new(big.Int)
+	for d.Cmp(big.NewInt(1)) != 0 {
+		if m.Mod(d, bigTen).Sign() == 0 {
+			d.Quo(d, bigTen)
+		} else if m.Mod(d, bigTwo).Sign() == 0 {
+			d.Quo(d, bigTwo)
+		} else if m.Mod(d, bigFive).Sign() == 0 {
+			d.Quo(d, bigFive)
+		} else {
+			// not a decimal, should not happen
+			return 16
+		}
+		scale++
+	}
+	return scale
+}
+
+// Value implements the driver Valuer interface.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implements the Scanner interface.
+func (d *Decimal) Scan(value interface{}) error {
+	var err error
+	switch t := value.(type) {
+	case string:
+		*d, err = ParseDecimal(t)
+	case []byte:
+		*d, err = ParseDecimal(string(t))
+	case int64:
+		*d = NewDecimal(t, 0)
+	case float64:
+		*d, err = ParseDecimal(strconv.FormatFloat(t, 'f', -1, 64))
+	default:
+		err = fmt.Errorf("invalid type %T for Decimal: %v", value, value)
+	}
+	return err
+}
+
+// MarshalJSON serializes a Decimal to JSON, as a string to avoid any loss of
+// precision.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON deserializes a Decimal from a JSON string or number.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	s := string(bytes.Trim(b, `"`))
+	decimal, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = decimal
+	return nil
+}
+
+// NullDecimal can be a Decimal or a null value.
+type NullDecimal struct {
+	Decimal
+	Valid bool // Valid is true if Decimal is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (nd *NullDecimal) Scan(value interface{}) error {
+	if value == nil {
+		nd.Decimal, nd.Valid = Decimal{}, false
+		return nil
+	}
+	nd.Valid = true
+	return nd.Decimal.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (nd NullDecimal) Value() (driver.Value, error) {
+	if !nd.Valid {
+		return nil, nil
+	}
+	return nd.Decimal.Value()
+}
+
+// MarshalJSON serializes a NullDecimal to JSON.
+func (nd NullDecimal) MarshalJSON() ([]byte, error) {
+	if nd.Valid {
+		return nd.Decimal.MarshalJSON()
+	}
+	return []byte("null"), nil
+}
+
+// UnmarshalJSON deserializes a NullDecimal from JSON.
+func (nd *NullDecimal) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		return nd.Scan(nil)
+	}
+	nd.Valid = true
+	return nd.Decimal.UnmarshalJSON(b)
+}
+
+// ToNullDecimal creates a valid NullDecimal
+func ToNullDecimal(d Decimal) NullDecimal {
+	return NullDecimal{Decimal: d, Valid: true}
+}
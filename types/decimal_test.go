@@ -0,0 +1,91 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDecimal(t *testing.T) {
+	Convey("Given a Decimal", t, func() {
+		Convey("The zero value is 0", func() {
+			d := Decimal{}
+			So(d.String(), ShouldEqual, "0")
+			v, err := d.Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, "0")
+		})
+
+		Convey("NewDecimal uses a scale", func() {
+			So(NewDecimal(1999, 2).String(), ShouldEqual, "19.99")
+			So(NewDecimal(-5, 3).String(), ShouldEqual, "-0.005")
+			So(NewDecimal(12, -2).String(), ShouldEqual, "1200")
+		})
+
+		Convey("ParseDecimal is exact", func() {
+			d, err := ParseDecimal("0.1")
+			So(err, ShouldBeNil)
+			sum := d.Rat()
+			sum.Add(sum, d.Rat())
+			sum.Add(sum, d.Rat())
+			So(sum.FloatString(20), ShouldEqual, "0.30000000000000000000")
+
+			d, err = ParseDecimal("1.5e3")
+			So(err, ShouldBeNil)
+			So(d.String(), ShouldEqual, "1500")
+
+			d, err = ParseDecimal("12.50")
+			So(err, ShouldBeNil)
+			So(d.String(), ShouldEqual, "12.5")
+			So(d.StringFixed(2), ShouldEqual, "12.50")
+
+			_, err = ParseDecimal("1/3")
+			So(err, ShouldNotBeNil)
+			_, err = ParseDecimal("abc")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Scan reads drivers values", func() {
+			d := Decimal{}
+			So(d.Scan([]byte("123456789012345678.99")), ShouldBeNil)
+			So(d.String(), ShouldEqual, "123456789012345678.99")
+			So(d.Scan("-0.01"), ShouldBeNil)
+			So(d.Cmp(NewDecimal(-1, 2)), ShouldEqual, 0)
+			So(d.Scan(int64(42)), ShouldBeNil)
+			So(d.String(), ShouldEqual, "42")
+			So(d.Scan(float64(0.1)), ShouldBeNil)
+			So(d.String(), ShouldEqual, "0.1")
+			So(d.Scan(nil), ShouldNotBeNil)
+		})
+
+		Convey("JSON uses strings", func() {
+			b, err := json.Marshal(NewDecimal(1999, 2))
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `"19.99"`)
+
+			d := Decimal{}
+			So(json.Unmarshal([]byte("19.99"), &d), ShouldBeNil)
+			So(d.String(), ShouldEqual, "19.99")
+		})
+	})
+
+	Convey("Given a NullDecimal", t, func() {
+		nd := NullDecimal{}
+		So(nd.Scan(nil), ShouldBeNil)
+		So(nd.Valid, ShouldBeFalse)
+		v, err := nd.Value()
+		So(err, ShouldBeNil)
+		So(v, ShouldBeNil)
+
+		So(nd.Scan("10.5"), ShouldBeNil)
+		So(nd.Valid, ShouldBeTrue)
+		v, err = nd.Value()
+		So(err, ShouldBeNil)
+		So(v, ShouldEqual, "10.5")
+
+		b, err := json.Marshal(NullDecimal{})
+		So(err, ShouldBeNil)
+		So(string(b), ShouldEqual, "null")
+	})
+}
@@ -0,0 +1,168 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Point is a spatial point, usable with PostGIS and MySQL geometry columns.
+// X is the longitude and Y the latitude for geographic coordinates.
+//
+// It scans WKT ('POINT(x y)', optionally prefixed with 'SRID=n;'), WKB, EWKB
+// (and its hexadecimal form returned by PostGIS) and MySQL internal format
+// (SRID followed by WKB).
+//
+// Its value is a WKT string. PostGIS casts it implicitly, with MySQL use
+// ST_GeomFromText(?) in the query.
+type Point struct {
+	X    float64
+	Y    float64
+	SRID uint32
+}
+
+const (
+	wkbPointType   = 1
+	ewkbSRIDFlag   = 0x20000000
+	wkbPointLength = 21
+)
+
+// Value returns the point as WKT.
+func (p Point) Value() (driver.Value, error) {
+	return p.String(), nil
+}
+
+// String returns the point as WKT.
+func (p Point) String() string {
+	return "POINT(" + strconv.FormatFloat(p.X, 'f', -1, 64) + " " +
+		strconv.FormatFloat(p.Y, 'f', -1, 64) + ")"
+}
+
+// Scan implements the Scanner interface.
+func (p *Point) Scan(value interface{}) error {
+	var source []byte
+	switch t := value.(type) {
+	case string:
+		source = []byte(t)
+	case []byte:
+		source = t
+	default:
+		return fmt.Errorf("invalid type %T for Point: %v", value, value)
+	}
+
+	upper := bytes.ToUpper(bytes.TrimSpace(source))
+	if bytes.HasPrefix(upper, []byte("POINT")) || bytes.HasPrefix(upper, []byte("SRID=")) {
+		return p.scanWKT(string(upper))
+	}
+	if decoded, err := hex.DecodeString(string(source)); err == nil && len(decoded) >= wkbPointLength {
+		source = decoded
+	}
+	return p.scanWKB(source)
+}
+
+// scanWKT parses a WKT or EWKT point.
+func (p *Point) scanWKT(wkt string) error {
+	p.SRID = 0
+	if strings.HasPrefix(wkt, "SRID=") {
+		parts := strings.SplitN(wkt[5:], ";", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid WKT point %s", wkt)
+		}
+		srid, err := strconv.ParseUint(parts[0], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid SRID in WKT point %s", wkt)
+		}
+		p.SRID = uint32(srid)
+		wkt = parts[1]
+	}
+
+	wkt = strings.TrimSpace(strings.TrimPrefix(wkt, "POINT"))
+	if !strings.HasPrefix(wkt, "(") || !strings.HasSuffix(wkt, ")") {
+		return fmt.Errorf("invalid WKT point %s", wkt)
+	}
+	coordinates := strings.Fields(wkt[1 : len(wkt)-1])
+	if len(coordinates) != 2 {
+		return fmt.Errorf("invalid WKT point %s", wkt)
+	}
+	var err error
+	if p.X, err = strconv.ParseFloat(coordinates[0], 64); err != nil {
+		return err
+	}
+	p.Y, err = strconv.ParseFloat(coordinates[1], 64)
+	return err
+}
+
+// scanWKB parses a WKB, EWKB or MySQL point.
+func (p *Point) scanWKB(wkb []byte) error {
+	p.SRID = 0
+	// MySQL : 4 bytes SRID (little endian) then WKB
+	if len(wkb) == wkbPointLength+4 && (wkb[4] == 0 || wkb[4] == 1) &&
+		wkbByteOrder(wkb[4]).Uint32(wkb[5:]) == wkbPointType {
+		p.SRID = binary.LittleEndian.Uint32(wkb)
+		wkb = wkb[4:]
+	}
+
+	if len(wkb) < wkbPointLength || wkb[0] > 1 {
+		return fmt.Errorf("invalid WKB point")
+	}
+	order := wkbByteOrder(wkb[0])
+	geometryType := order.Uint32(wkb[1:])
+	wkb = wkb[5:]
+	if geometryType&ewkbSRIDFlag != 0 {
+		if len(wkb) < 20 {
+			return fmt.Errorf("invalid EWKB point")
+		}
+		p.SRID = order.Uint32(wkb)
+		wkb = wkb[4:]
+		geometryType &^= ewkbSRIDFlag
+	}
+	if geometryType != wkbPointType || len(wkb) != 16 {
+		return fmt.Errorf("the WKB geometry is not a point")
+	}
+
+	p.X = math.Float64frombits(order.Uint64(wkb))
+	p.Y = math.Float64frombits(order.Uint64(wkb[8:]))
+	return nil
+}
+
+// wkbByteOrder returns the byte order given its WKB code.
+func wkbByteOrder(code byte) binary.ByteOrder {
+	if code == 0 {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+// NullPoint can be a Point or a null value.
+type NullPoint struct {
+	Point
+	Valid bool // Valid is true if Point is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (np *NullPoint) Scan(value interface{}) error {
+	if value == nil {
+		np.Point, np.Valid = Point{}, false
+		return nil
+	}
+	np.Valid = true
+	return np.Point.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (np NullPoint) Value() (driver.Value, error) {
+	if !np.Valid {
+		return nil, nil
+	}
+	return np.Point.Value()
+}
+
+// ToNullPoint creates a valid NullPoint
+func ToNullPoint(p Point) NullPoint {
+	return NullPoint{Point: p, Valid: true}
+}
@@ -0,0 +1,84 @@
+package types
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func wkbPoint(order binary.ByteOrder, geometryType uint32, srid uint32, x float64, y float64) []byte {
+	wkb := []byte{1}
+	if order == binary.BigEndian {
+		wkb[0] = 0
+	}
+	buf := make([]byte, 8)
+	order.PutUint32(buf, geometryType)
+	wkb = append(wkb, buf[:4]...)
+	if geometryType&ewkbSRIDFlag != 0 {
+		order.PutUint32(buf, srid)
+		wkb = append(wkb, buf[:4]...)
+	}
+	order.PutUint64(buf, math.Float64bits(x))
+	wkb = append(wkb, buf...)
+	order.PutUint64(buf, math.Float64bits(y))
+	return append(wkb, buf...)
+}
+
+func TestPoint(t *testing.T) {
+	Convey("Given a Point", t, func() {
+		Convey("Value returns WKT", func() {
+			v, err := Point{X: 2.35, Y: 48.85}.Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, "POINT(2.35 48.85)")
+		})
+
+		Convey("Scan reads WKT and EWKT", func() {
+			p := Point{}
+			So(p.Scan("POINT(2.35 48.85)"), ShouldBeNil)
+			So(p, ShouldResemble, Point{X: 2.35, Y: 48.85})
+			So(p.Scan([]byte("SRID=4326;point(-1 2)")), ShouldBeNil)
+			So(p, ShouldResemble, Point{X: -1, Y: 2, SRID: 4326})
+		})
+
+		Convey("Scan reads WKB and EWKB", func() {
+			p := Point{}
+			So(p.Scan(wkbPoint(binary.BigEndian, wkbPointType, 0, 2.35, 48.85)), ShouldBeNil)
+			So(p, ShouldResemble, Point{X: 2.35, Y: 48.85})
+			ewkb := wkbPoint(binary.LittleEndian, wkbPointType|ewkbSRIDFlag, 4326, 2.35, 48.85)
+			So(p.Scan(ewkb), ShouldBeNil)
+			So(p, ShouldResemble, Point{X: 2.35, Y: 48.85, SRID: 4326})
+			So(p.Scan(hex.EncodeToString(ewkb)), ShouldBeNil)
+			So(p, ShouldResemble, Point{X: 2.35, Y: 48.85, SRID: 4326})
+		})
+
+		Convey("Scan reads MySQL format", func() {
+			p := Point{}
+			mysql := append([]byte{0xE6, 0x10, 0, 0}, wkbPoint(binary.LittleEndian, wkbPointType, 0, 2.35, 48.85)...)
+			So(p.Scan(mysql), ShouldBeNil)
+			So(p, ShouldResemble, Point{X: 2.35, Y: 48.85, SRID: 4326})
+		})
+
+		Convey("Scan rejects other values", func() {
+			p := Point{}
+			So(p.Scan(nil), ShouldNotBeNil)
+			So(p.Scan("LINESTRING(0 0, 1 1)"), ShouldNotBeNil)
+			So(p.Scan(wkbPoint(binary.LittleEndian, 2, 0, 0, 0)), ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a NullPoint", t, func() {
+		np := NullPoint{}
+		So(np.Scan(nil), ShouldBeNil)
+		So(np.Valid, ShouldBeFalse)
+		v, err := np.Value()
+		So(err, ShouldBeNil)
+		So(v, ShouldBeNil)
+
+		So(np.Scan("POINT(1 2)"), ShouldBeNil)
+		So(np.Valid, ShouldBeTrue)
+		So(np.Point, ShouldResemble, Point{X: 1, Y: 2})
+	})
+}
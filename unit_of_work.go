@@ -0,0 +1,345 @@
+package godb
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// trackedState is the state of a record tracked by a UnitOfWork.
+type trackedState int
+
+const (
+	trackedUnchanged trackedState = iota
+	trackedNew
+	trackedRemoved
+)
+
+// trackedRecord is a record tracked by a UnitOfWork, with the snapshot of
+// its values when it was loaded or flushed.
+type trackedRecord struct {
+	record            interface{}
+	recordDescription *recordDescription
+	snapshot          []interface{}
+	state             trackedState
+}
+
+// UnitOfWork tracks the records loaded, added and removed, and writes the
+// changes with Flush : the new records are inserted, only the changed
+// columns of the tracked records are updated, and the removed records are
+// deleted, in a single transaction.
+//
+// godb does not know the relations between the structs, the order of the
+// statements is given by Order : the inserts and updates follow it, the
+// deletes use the reverse order. The foreign keys of the new records have to
+// be set before the flush, if they depend on auto keys flush in steps.
+//
+// A UnitOfWork is not safe for concurrent use.
+//
+// Example :
+// 	uow := db.UnitOfWork().Order(&Author{}, &Book{})
+// 	var author *Author
+// 	err := uow.Load(&author, 12)
+// 	author.Name = "New name"
+// 	err = uow.Add(&Book{Title: "New book", AuthorID: author.ID})
+// 	err = uow.Flush() // UPDATE authors SET name ..., INSERT INTO books ...
+type UnitOfWork struct {
+	db      *DB
+	order   []reflect.Type
+	records []*trackedRecord
+}
+
+// UnitOfWork creates an empty UnitOfWork using the DB.
+func (db *DB) UnitOfWork() *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// Order sets the order of the struct types for the flush, the types
+// referenced by others first. The records are used only for their types. The
+// types not given come after, in the order they are tracked.
+func (uow *UnitOfWork) Order(records ...interface{}) *UnitOfWork {
+	for _, record := range records {
+		recordType := reflect.TypeOf(record)
+		for recordType.Kind() == reflect.Ptr {
+			recordType = recordType.Elem()
+		}
+		uow.order = append(uow.order, recordType)
+	}
+	return uow
+}
+
+// Load loads a record like DB.Load, and tracks it.
+func (uow *UnitOfWork) Load(record interface{}, keyValues ...interface{}) error {
+	if err := uow.db.Load(record, keyValues...); err != nil {
+		return err
+	}
+	return uow.Track(reflect.ValueOf(record).Elem().Interface())
+}
+
+// Track tracks records already loaded. The records are struct pointers, or
+// pointers to slices (of structs or struct pointers) whose elements are
+// tracked. Don't append to a slice of structs once tracked, its elements
+// could be moved.
+func (uow *UnitOfWork) Track(records ...interface{}) error {
+	return uow.addRecords(records, trackedUnchanged)
+}
+
+// Add adds new records to insert, see Track for the accepted records.
+func (uow *UnitOfWork) Add(records ...interface{}) error {
+	return uow.addRecords(records, trackedNew)
+}
+
+// Remove marks records to delete, see Track for the accepted records. They
+// don't have to be tracked before, a new record is simply forgotten.
+func (uow *UnitOfWork) Remove(records ...interface{}) error {
+	instances, err := uow.instances(records)
+	if err != nil {
+		return err
+	}
+	for _, instance := range instances {
+		tracked, err := uow.track(instance, trackedRemoved)
+		if err != nil {
+			return err
+		}
+		if tracked.state == trackedNew {
+			uow.forget(tracked)
+			continue
+		}
+		tracked.state = trackedRemoved
+	}
+	return nil
+}
+
+// addRecords tracks the given records with the given state.
+func (uow *UnitOfWork) addRecords(records []interface{}, state trackedState) error {
+	instances, err := uow.instances(records)
+	if err != nil {
+		return err
+	}
+	for _, instance := range instances {
+		if _, err := uow.track(instance, state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// instances returns the struct pointers of the given records.
+func (uow *UnitOfWork) instances(records []interface{}) ([]interface{}, error) {
+	instances := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		recordDescription, err := buildRecordDescription(record)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < recordDescription.len(); i++ {
+			instances = append(instances, recordDescription.index(i))
+		}
+	}
+	return instances, nil
+}
+
+// track returns the tracked record of the given struct pointer, tracking it
+// with the given state if it's not already.
+func (uow *UnitOfWork) track(instance interface{}, state trackedState) (*trackedRecord, error) {
+	for _, tracked := range uow.records {
+		if tracked.record == instance {
+			return tracked, nil
+		}
+	}
+
+	recordDescription, err := buildRecordDescription(instance)
+	if err != nil {
+		return nil, err
+	}
+	if state != trackedNew && len(recordDescription.structMapping.GetKeyColumnsNames()) == 0 {
+		return nil, fmt.Errorf("the struct %s has no key, it can't be tracked", recordDescription.structMapping.Name)
+	}
+	tracked := &trackedRecord{
+		record:            instance,
+		recordDescription: recordDescription,
+		state:             state,
+	}
+	if state != trackedNew {
+		tracked.takeSnapshot()
+	}
+	uow.records = append(uow.records, tracked)
+	return tracked, nil
+}
+
+// forget stops the tracking of the given record.
+func (uow *UnitOfWork) forget(tracked *trackedRecord) {
+	for i, current := range uow.records {
+		if current == tracked {
+			uow.records = append(uow.records[:i], uow.records[i+1:]...)
+			return
+		}
+	}
+}
+
+// takeSnapshot copies the values of the fields of the tracked record. The
+// copy shares nothing with the record (slices, maps and values behind the
+// pointers are copied), as they could be changed in place.
+func (tracked *trackedRecord) takeSnapshot() {
+	values := tracked.recordDescription.structMapping.GetAllFieldsValues(tracked.record)
+	tracked.snapshot = make([]interface{}, len(values))
+	for i, value := range values {
+		if value != nil {
+			tracked.snapshot[i] = deepCopy(reflect.ValueOf(value)).Interface()
+		}
+	}
+}
+
+// deepCopy returns a copy of the given value, with copies of its slices, maps
+// and values behind its pointers.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, key := range v.MapKeys() {
+			c.SetMapIndex(key, deepCopy(v.MapIndex(key)))
+		}
+		return c
+	}
+	return v
+}
+
+// changedColumns returns the non auto and non key columns of the tracked
+// record changed since the snapshot.
+func (tracked *trackedRecord) changedColumns() []string {
+	structMapping := tracked.recordDescription.structMapping
+	columns := structMapping.GetAllColumnsNames()
+	values := structMapping.GetAllFieldsValues(tracked.record)
+	autoColumns := structMapping.GetAutoColumnsNames()
+	keyColumns := structMapping.GetKeyColumnsNames()
+	opLockColumn := structMapping.GetOpLockSQLFieldName()
+
+	changed := make([]string, 0)
+	for i, column := range columns {
+		if column == opLockColumn || indexOfString(autoColumns, column) >= 0 || indexOfString(keyColumns, column) >= 0 {
+			continue
+		}
+		if !reflect.DeepEqual(values[i], tracked.snapshot[i]) {
+			changed = append(changed, column)
+		}
+	}
+	return changed
+}
+
+// Flush writes the changes of the tracked records, in a transaction if
+// there is none. The snapshots of the records are taken again, the removed
+// records are no longer tracked.
+func (uow *UnitOfWork) Flush() error {
+	db := uow.db
+	ownTx := db.sqlTx == nil
+	if ownTx {
+		if err := db.Begin(); err != nil {
+			return err
+		}
+	}
+
+	if err := uow.flush(); err != nil {
+		if ownTx {
+			db.Rollback()
+		}
+		return err
+	}
+	if ownTx {
+		if err := db.Commit(); err != nil {
+			return err
+		}
+	}
+
+	remaining := make([]*trackedRecord, 0, len(uow.records))
+	for _, tracked := range uow.records {
+		if tracked.state == trackedRemoved {
+			continue
+		}
+		tracked.state = trackedUnchanged
+		tracked.takeSnapshot()
+		remaining = append(remaining, tracked)
+	}
+	uow.records = remaining
+	return nil
+}
+
+// flush executes the statements of Flush.
+func (uow *UnitOfWork) flush() error {
+	db := uow.db
+	types := uow.orderedTypes()
+
+	for _, recordType := range types {
+		for _, tracked := range uow.records {
+			if tracked.recordDescription.instanceType != recordType {
+				continue
+			}
+			switch tracked.state {
+			case trackedNew:
+				if err := db.Insert(tracked.record).Do(); err != nil {
+					return err
+				}
+			case trackedUnchanged:
+				changed := tracked.changedColumns()
+				if len(changed) == 0 {
+					continue
+				}
+				if opLockColumn := tracked.recordDescription.structMapping.GetOpLockSQLFieldName(); opLockColumn != "" {
+					changed = append(changed, opLockColumn)
+				}
+				if err := db.Update(tracked.record).Whitelist(changed...).Do(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for i := len(types) - 1; i >= 0; i-- {
+		for j := len(uow.records) - 1; j >= 0; j-- {
+			tracked := uow.records[j]
+			if tracked.recordDescription.instanceType != types[i] || tracked.state != trackedRemoved {
+				continue
+			}
+			if _, err := db.Delete(tracked.record).Do(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// orderedTypes returns the types of the tracked records, the ones given to
+// Order first.
+func (uow *UnitOfWork) orderedTypes() []reflect.Type {
+	types := append([]reflect.Type(nil), uow.order...)
+	for _, tracked := range uow.records {
+		known := false
+		for _, recordType := range types {
+			if recordType == tracked.recordDescription.instanceType {
+				known = true
+				break
+			}
+		}
+		if !known {
+			types = append(types, tracked.recordDescription.instanceType)
+		}
+	}
+	return types
+}
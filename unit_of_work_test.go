@@ -0,0 +1,133 @@
+package godb
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// statementsLogger keeps the logged statements changing data.
+type statementsLogger struct {
+	statements []string
+}
+
+func (l *statementsLogger) Println(v ...interface{}) {
+	line := fmt.Sprint(v...)
+	for _, prefix := range []string{"INSERT", "UPDATE", "DELETE"} {
+		if strings.Contains(line, prefix) {
+			l.statements = append(l.statements, line)
+		}
+	}
+}
+
+// BytesDummy maps a column of dummies to a []byte.
+type BytesDummy struct {
+	ID    int    `db:"id,key,auto"`
+	AText []byte `db:"a_text"`
+}
+
+func (*BytesDummy) TableName() string {
+	return "dummies"
+}
+
+func TestUnitOfWork(t *testing.T) {
+	Convey("Given a test database and a unit of work", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		logger := &statementsLogger{}
+		db.SetLogger(logger)
+		uow := db.UnitOfWork().Order(&Dummy{}, &RelatedToDummy{})
+
+		Convey("Flush without change does nothing", func() {
+			dummies := make([]*Dummy, 0)
+			So(db.Select(&dummies).Do(), ShouldBeNil)
+			So(uow.Track(&dummies), ShouldBeNil)
+			So(uow.Flush(), ShouldBeNil)
+			So(logger.statements, ShouldBeEmpty)
+		})
+
+		Convey("Flush updates only the changed columns", func() {
+			var dummy *Dummy
+			So(uow.Load(&dummy, 1), ShouldBeNil)
+			dummy.AnInteger = 42
+			So(uow.Flush(), ShouldBeNil)
+			So(len(logger.statements), ShouldEqual, 1)
+			So(logger.statements[0], ShouldContainSubstring, `SET "an_integer"=?, "version"=?`)
+			So(dummy.Version, ShouldEqual, 1)
+
+			reloaded := Dummy{}
+			So(db.Get(&reloaded, 1), ShouldBeNil)
+			So(reloaded.AnInteger, ShouldEqual, 42)
+			So(reloaded.AText, ShouldEqual, "First")
+
+			Convey("The snapshot is taken again", func() {
+				logger.statements = nil
+				So(uow.Flush(), ShouldBeNil)
+				So(logger.statements, ShouldBeEmpty)
+			})
+		})
+
+		Convey("Flush sees the fields changed in place", func() {
+			var dummy *BytesDummy
+			So(uow.Load(&dummy, 1), ShouldBeNil)
+			dummy.AText[0] = 'f'
+			So(uow.Flush(), ShouldBeNil)
+			So(len(logger.statements), ShouldEqual, 1)
+			So(logger.statements[0], ShouldContainSubstring, `SET "a_text"=?`)
+
+			reloaded := Dummy{}
+			So(db.Get(&reloaded, 1), ShouldBeNil)
+			So(reloaded.AText, ShouldEqual, "first")
+		})
+
+		Convey("Flush inserts, updates and deletes in the order of the types", func() {
+			var dummy *Dummy
+			So(uow.Load(&dummy, 1), ShouldBeNil)
+			related := &RelatedToDummy{DummyID: 1, AText: "Related"}
+			So(uow.Add(related), ShouldBeNil)
+			dummy.AText = "Changed"
+			second := Dummy{}
+			So(db.Get(&second, 2), ShouldBeNil)
+			So(uow.Remove(&second), ShouldBeNil)
+			newDummy := &Dummy{AText: "New", AnotherText: "New"}
+			So(uow.Add(newDummy), ShouldBeNil)
+
+			So(uow.Flush(), ShouldBeNil)
+			So(len(logger.statements), ShouldEqual, 4)
+			So(logger.statements[0], ShouldContainSubstring, `UPDATE "dummies"`)
+			So(logger.statements[1], ShouldContainSubstring, `INSERT INTO "dummies"`)
+			So(logger.statements[2], ShouldContainSubstring, `INSERT INTO "relatedtodummies"`)
+			So(logger.statements[3], ShouldContainSubstring, `DELETE FROM "dummies"`)
+			So(newDummy.ID, ShouldNotEqual, 0)
+			So(related.ID, ShouldNotEqual, 0)
+
+			count, err := db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("A new record removed is not inserted", func() {
+			newDummy := &Dummy{AText: "New", AnotherText: "New"}
+			So(uow.Add(newDummy), ShouldBeNil)
+			So(uow.Remove(newDummy), ShouldBeNil)
+			So(uow.Flush(), ShouldBeNil)
+			So(logger.statements, ShouldBeEmpty)
+		})
+
+		Convey("A failing flush is rolled back", func() {
+			var dummy *Dummy
+			So(uow.Load(&dummy, 1), ShouldBeNil)
+			dummy.AText = "Changed"
+			So(uow.Add(&RelatedToDummy{DummyID: 1}), ShouldBeNil)
+			_, err := db.CurrentDB().Exec("drop table relatedtodummies")
+			So(err, ShouldBeNil)
+
+			So(uow.Flush(), ShouldNotBeNil)
+			reloaded := Dummy{}
+			So(db.Get(&reloaded, 1), ShouldBeNil)
+			So(reloaded.AText, ShouldEqual, "First")
+		})
+	})
+}
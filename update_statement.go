@@ -1,6 +1,10 @@
 package godb
 
-import "github.com/samonzeweb/godb/adapters"
+import (
+	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+)
 
 // UpdateStatement will contains all parts needed to build an UPDATE statement.
 // Initialize it with the UpdateTable method.
@@ -18,10 +22,12 @@ type UpdateStatement struct {
 	where            []*Condition
 	returningColumns []string
 	suffixes         []string
+	options          statementOptions
 }
 
 // setPart contains elements for a single SET clause.
-// The value could be nil for a raw clause (ie count=count+1)
+// The value could be nil for a raw clause (ie count=count+1), or an
+// Expression written as is (ie updated_at=NOW())
 type setPart struct {
 	// The column name, or the full SET clause for a raw clause
 	column string
@@ -37,7 +43,19 @@ func (db *DB) UpdateTable(tableName string) *UpdateStatement {
 	return us
 }
 
-// Set adds a part of SET clause to the query.
+// Clone returns a copy of the statement, allowing to reuse a base query
+// and to branch it without altering the original one.
+func (us *UpdateStatement) Clone() *UpdateStatement {
+	clone := *us
+	clone.sets = append([]*setPart(nil), us.sets...)
+	clone.where = append([]*Condition(nil), us.where...)
+	clone.returningColumns = append([]string(nil), us.returningColumns...)
+	clone.suffixes = append([]string(nil), us.suffixes...)
+	return &clone
+}
+
+// Set adds a part of SET clause to the query. The value could be an
+// expression, like a function call built with Fn.
 func (us *UpdateStatement) Set(column string, value interface{}) *UpdateStatement {
 	setClause := &setPart{
 		column: column,
@@ -76,6 +94,23 @@ func (us *UpdateStatement) Returning(columns ...string) *UpdateStatement {
 	return us
 }
 
+// Priority sets the priority of the statement when the concurrent queries
+// are limited, see SetMaxConcurrentQueries.
+func (us *UpdateStatement) Priority(priority Priority) *UpdateStatement {
+	us.options.priority = priority
+	return us
+}
+
+// Timeout sets the maximum execution time of the update, useful for the
+// updates locking many rows. It's cancelled when the timeout expires, like
+// when the context of the DB is done (see SetContext), and PostgreSQL and SQL
+// Server abort it. MySQL has no limit for the updates : the driver abandons
+// it, but it could still be applied.
+func (us *UpdateStatement) Timeout(timeout time.Duration) *UpdateStatement {
+	us.options.timeout = timeout
+	return us
+}
+
 // Suffix adds an expression to suffix the statement. Use it to add a
 // RETURNING clause with PostgreSQL (or whatever you need).
 func (us *UpdateStatement) Suffix(suffix string) *UpdateStatement {
@@ -130,14 +165,28 @@ func (us *UpdateStatement) ToSQL() (string, []interface{}, error) {
 	return sqlBuffer.SQL(), sqlBuffer.Arguments(), sqlBuffer.Err()
 }
 
+// ToInterpolatedSQL returns the SQL statement with the arguments written as
+// literals for the adapter, to be pasted into a SQL client. Debug only : the
+// statements are always executed with placeholders.
+func (us *UpdateStatement) ToInterpolatedSQL() (string, error) {
+	query, args, err := us.ToSQL()
+	if err != nil {
+		return "", err
+	}
+	return interpolateSQL(us.db.adapter, query, args)
+}
+
 // Do executes the builded query, and return RowsAffected()
 func (us *UpdateStatement) Do() (int64, error) {
 	query, args, err := us.ToSQL()
 	if err != nil {
 		return 0, err
 	}
+	if err := us.checkGuards(query, args); err != nil {
+		return 0, err
+	}
 
-	result, err := us.db.do(query, args)
+	result, err := us.db.do(query, args, us.options)
 	if err != nil {
 		return 0, err
 	}
@@ -170,6 +219,23 @@ func (us *UpdateStatement) doWithReturning(recordDescription *recordDescription,
 	if err != nil {
 		return 0, err
 	}
+	if err := us.checkGuards(query, args); err != nil {
+		return 0, err
+	}
+	if err := us.db.checkWritable(query); err != nil {
+		return 0, err
+	}
+
+	return us.db.doSelectOrWithReturning(query, args, recordDescription, pointersGetter, us.options)
+}
 
-	return us.db.doSelectOrWithReturning(query, args, recordDescription, pointersGetter)
+// checkGuards gives the built statement to the guards of the DB.
+func (us *UpdateStatement) checkGuards(query string, args []interface{}) error {
+	return us.db.checkGuards(&StatementInfo{
+		Kind:      "UPDATE",
+		Tables:    guardedTableNames(us.updateTable),
+		HasWhere:  len(us.where) > 0,
+		Query:     query,
+		Arguments: args,
+	})
 }
@@ -1,8 +1,11 @@
 package godb
 
 import (
+	"database/sql"
 	"testing"
 
+	"github.com/samonzeweb/godb/adapters/sqlite"
+
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -16,6 +19,18 @@ func TestUpdate(t *testing.T) {
 	})
 }
 
+func TestUpdateClone(t *testing.T) {
+	Convey("Clone returns an independant copy of an update statement", t, func() {
+		db := &DB{}
+		base := db.UpdateTable("dummies").Set("foo", 1).Where("id = ?", 1)
+		clone := base.Clone().Set("bar", 2).Where("baz = ?", 3)
+		So(len(clone.sets), ShouldEqual, 2)
+		So(len(clone.where), ShouldEqual, 2)
+		So(len(base.sets), ShouldEqual, 1)
+		So(len(base.where), ShouldEqual, 1)
+	})
+}
+
 func TestSet(t *testing.T) {
 	Convey("Create an update query", t, func() {
 		db := &DB{}
@@ -163,3 +178,16 @@ func TestDoUpdate(t *testing.T) {
 		})
 	})
 }
+
+func TestUpdateToInterpolatedSQL(t *testing.T) {
+	Convey("Given a valid update statement", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+		q := db.UpdateTable("dummies").Set("foo", sql.NullString{}).Set("bar", []byte{1}).Where("id = ?", 123)
+
+		Convey("ToInterpolatedSQL writes the arguments as literals", func() {
+			sql, err := q.ToInterpolatedSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "UPDATE dummies SET foo=NULL, bar=X'01' WHERE id = 123")
+		})
+	})
+}
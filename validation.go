@@ -0,0 +1,122 @@
+package godb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// Validatable is implemented by structs validated before their insert or
+// update by the struct tools. A *ValidationError is returned if Validate
+// fails, see UseValidator for the aggregation of the errors.
+//
+// Example :
+// 	func (b *Book) Validate(ctx context.Context) error {
+// 		if b.Title == "" {
+// 			return &godb.ValidationError{Fields: []godb.FieldError{{Field: "title", Message: "is required"}}}
+// 		}
+// 		return nil
+// 	}
+type Validatable interface {
+	Validate(ctx context.Context) error
+}
+
+// Validator validates the structs before their insert or update by the
+// struct tools, see UseValidator.
+type Validator interface {
+	Validate(ctx context.Context, record interface{}) error
+}
+
+// ValidatorFunc is a function used as a Validator.
+type ValidatorFunc func(ctx context.Context, record interface{}) error
+
+// Validate calls the function.
+func (f ValidatorFunc) Validate(ctx context.Context, record interface{}) error {
+	return f(ctx, record)
+}
+
+// fieldError is an error of a single field, like the errors of the
+// go-playground/validator package.
+type fieldError interface {
+	Field() string
+	Error() string
+}
+
+// UseValidator sets the validator of the structs inserted or updated by the
+// struct tools, or removes it if nil. It's called with the context of the DB
+// (see SetContext), before the Validate method of the Validatable structs.
+// The validator is shared by the clones.
+//
+// The errors are aggregated into a *ValidationError : the FieldError of a
+// returned *ValidationError are kept, and an error being a slice of errors
+// having a Field method (like validator.ValidationErrors) gives a FieldError
+// by element. Any other error gives a FieldError without field.
+//
+// Example :
+// 	validate := validator.New()
+// 	db.UseValidator(godb.ValidatorFunc(func(ctx context.Context, record interface{}) error {
+// 		return validate.StructCtx(ctx, record)
+// 	}))
+func (db *DB) UseValidator(validator Validator) {
+	db.validator = validator
+}
+
+// validate validates all given records, and returns a *ValidationError if
+// some are not valid.
+func (db *DB) validate(recordDescription *recordDescription) error {
+	_, isValidatable := recordDescription.getOneInstancePointer().(Validatable)
+	if db.validator == nil && !isValidatable {
+		return nil
+	}
+
+	ctx := db.Context()
+	validationError := &ValidationError{Struct: recordDescription.structMapping.Name}
+	for i := 0; i < recordDescription.len(); i++ {
+		record := recordDescription.index(i)
+		if db.validator != nil {
+			validationError.add(i, db.validator.Validate(ctx, record))
+		}
+		if validatable, ok := record.(Validatable); ok {
+			validationError.add(i, validatable.Validate(ctx))
+		}
+	}
+	if len(validationError.Fields) > 0 {
+		return validationError
+	}
+	return nil
+}
+
+// add adds the field errors of the given error of a record.
+func (e *ValidationError) add(record int, err error) {
+	if err == nil {
+		return
+	}
+
+	var validationError *ValidationError
+	if errors.As(err, &validationError) {
+		for _, field := range validationError.Fields {
+			field.Record = record
+			e.Fields = append(e.Fields, field)
+		}
+		return
+	}
+
+	errValue := reflect.ValueOf(err)
+	if errValue.Kind() == reflect.Slice {
+		fields := make([]FieldError, 0, errValue.Len())
+		for i := 0; i < errValue.Len(); i++ {
+			element, ok := errValue.Index(i).Interface().(fieldError)
+			if !ok {
+				fields = nil
+				break
+			}
+			fields = append(fields, FieldError{Record: record, Field: element.Field(), Message: element.Error()})
+		}
+		if len(fields) > 0 {
+			e.Fields = append(e.Fields, fields...)
+			return
+		}
+	}
+
+	e.Fields = append(e.Fields, FieldError{Record: record, Message: err.Error()})
+}
@@ -0,0 +1,109 @@
+package godb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type ValidatedDummy struct {
+	Dummy `db:""`
+}
+
+func (*ValidatedDummy) TableName() string {
+	return "dummies"
+}
+
+func (d *ValidatedDummy) Validate(ctx context.Context) error {
+	if d.AText == "" {
+		return &ValidationError{Fields: []FieldError{{Field: "a_text", Message: "is required"}}}
+	}
+	return nil
+}
+
+// testFieldError and testFieldErrors mimic the errors of the
+// go-playground/validator package.
+type testFieldError struct {
+	field string
+}
+
+func (e testFieldError) Field() string {
+	return e.field
+}
+
+func (e testFieldError) Error() string {
+	return e.field + " is invalid"
+}
+
+type testFieldErrors []testFieldError
+
+func (e testFieldErrors) Error() string {
+	return "invalid fields"
+}
+
+func TestValidation(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		countDummies := func() int64 {
+			count, err := db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			return count
+		}
+
+		Convey("The Validatable structs are validated before insert", func() {
+			err := db.Insert(&ValidatedDummy{}).Do()
+			So(errors.Is(err, ErrValidation), ShouldBeTrue)
+			validationError := err.(*ValidationError)
+			So(validationError.Fields, ShouldResemble, []FieldError{{Field: "a_text", Message: "is required"}})
+			So(countDummies(), ShouldEqual, 3)
+
+			So(db.Insert(&ValidatedDummy{Dummy{AText: "Fourth"}}).Do(), ShouldBeNil)
+			So(countDummies(), ShouldEqual, 4)
+		})
+
+		Convey("The errors of all the records are aggregated", func() {
+			dummies := []ValidatedDummy{{Dummy{AText: "Fourth"}}, {}, {}}
+			err := db.BulkInsert(&dummies).Do()
+			validationError, ok := err.(*ValidationError)
+			So(ok, ShouldBeTrue)
+			So(len(validationError.Fields), ShouldEqual, 2)
+			So(validationError.Fields[0].Record, ShouldEqual, 1)
+			So(validationError.Fields[1].Record, ShouldEqual, 2)
+		})
+
+		Convey("The validator is called before update", func() {
+			db.UseValidator(ValidatorFunc(func(ctx context.Context, record interface{}) error {
+				if record.(*Dummy).AnInteger < 0 {
+					return testFieldErrors{{field: "an_integer"}, {field: "a_text"}}
+				}
+				return nil
+			}))
+			dummy := &Dummy{}
+			So(db.Get(dummy, 1), ShouldBeNil)
+			dummy.AnInteger = -1
+			err := db.Update(dummy).Do()
+			validationError, ok := err.(*ValidationError)
+			So(ok, ShouldBeTrue)
+			So(validationError.Fields, ShouldResemble, []FieldError{
+				{Field: "an_integer", Message: "an_integer is invalid"},
+				{Field: "a_text", Message: "a_text is invalid"},
+			})
+
+			dummy.AnInteger = 1
+			So(db.Update(dummy).Do(), ShouldBeNil)
+		})
+
+		Convey("The others errors give a field error without field", func() {
+			db.UseValidator(ValidatorFunc(func(ctx context.Context, record interface{}) error {
+				return errors.New("not allowed")
+			}))
+			err := db.Insert(&Dummy{AText: "Fourth"}).Do()
+			So(err, ShouldNotBeNil)
+			So(err.(*ValidationError).Fields, ShouldResemble, []FieldError{{Message: "not allowed"}})
+			So(err.Error(), ShouldContainSubstring, "not allowed")
+		})
+	})
+}
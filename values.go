@@ -0,0 +1,94 @@
+package godb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// valuesList is a list of rows given by the application, see Values.
+type valuesList struct {
+	rows    [][]interface{}
+	columns []string
+}
+
+// Values returns a list of rows usable as a table with FromExpr,
+// InnerJoinExpr and LeftJoinExpr, for example to join the records with an
+// in-memory list without creating a temporary table. Each row has a value
+// for each of the given columns.
+//
+// The rows are given by a VALUES list if the adapter supports it (see
+// adapters.ValuesListBuilder), or selected one by one and joined with UNION
+// ALL. The values are arguments of the statement, their type could have to
+// be casted by the others clauses with some databases.
+//
+// Example :
+// 	rows := [][]interface{}{{1, "draft"}, {2, "published"}}
+// 	err := db.SelectFrom("books").
+// 		Columns("books.title", "states.label").
+// 		InnerJoinExpr(godb.Values(rows, "id", "label"), "states", godb.Q("states.id = books.state")).
+// 		Do(&titles)
+func Values(rows [][]interface{}, columns ...string) Expression {
+	return &valuesList{rows: rows, columns: columns}
+}
+
+// BuildSQL returns the subquery giving the rows, with their values as
+// arguments.
+func (v *valuesList) BuildSQL(adapter adapters.Adapter) (string, []interface{}, error) {
+	if len(v.columns) == 0 {
+		return "", nil, fmt.Errorf("no column in the values list")
+	}
+	if len(v.rows) == 0 {
+		return "", nil, fmt.Errorf("no row in the values list")
+	}
+	quotedColumns := make([]string, 0, len(v.columns))
+	for _, column := range v.columns {
+		if err := checkIdentifier(column); err != nil {
+			return "", nil, err
+		}
+		quotedColumns = append(quotedColumns, quoteIdentifier(adapter, column))
+	}
+
+	args := make([]interface{}, 0, len(v.rows)*len(v.columns))
+	for i, row := range v.rows {
+		if len(row) != len(v.columns) {
+			return "", nil, fmt.Errorf("the row %d of the values list has %d values, %d expected", i, len(row), len(v.columns))
+		}
+		args = append(args, row...)
+	}
+
+	if builder, ok := adapter.(adapters.ValuesListBuilder); ok {
+		placeholders := buildGroupOfPlaceholders(len(v.columns)).String()
+		rows := make([]string, 0, len(v.rows))
+		for range v.rows {
+			rows = append(rows, placeholders)
+		}
+		return builder.BuildValuesList(rows, quotedColumns), args, nil
+	}
+
+	// The first row names the columns
+	firstRow := make([]string, 0, len(v.columns))
+	for _, column := range quotedColumns {
+		firstRow = append(firstRow, Placeholder+" AS "+column)
+	}
+	otherRow := " UNION ALL SELECT " + strings.TrimPrefix(strings.Repeat(", "+Placeholder, len(v.columns)), ", ")
+	otherRows := strings.Repeat(otherRow, len(v.rows)-1)
+	return "(SELECT " + strings.Join(firstRow, ", ") + otherRows + ")", args, nil
+}
+
+// buildSource builds the given expression used as a data source, followed by
+// the given alias if any.
+func buildSource(adapter adapters.Adapter, source Expression, as string) (string, []interface{}, error) {
+	if source == nil {
+		return "", nil, fmt.Errorf("nil expression")
+	}
+	sql, args, err := source.BuildSQL(adapter)
+	if err != nil {
+		return "", nil, err
+	}
+	if as != "" {
+		sql += " AS " + as
+	}
+	return sql, args, nil
+}
@@ -0,0 +1,73 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/postgresql"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValues(t *testing.T) {
+	Convey("Given a list of rows", t, func() {
+		rows := [][]interface{}{{1, "one"}, {2, "two"}, {3, "three"}}
+
+		Convey("The rows are joined with UNION ALL by default", func() {
+			sql, args, err := Values(rows, "id", "label").BuildSQL(sqlite.Adapter)
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `(SELECT ? AS "id", ? AS "label" UNION ALL SELECT ?, ? UNION ALL SELECT ?, ?)`)
+			So(args, ShouldResemble, []interface{}{1, "one", 2, "two", 3, "three"})
+		})
+
+		Convey("The rows are given by a VALUES list if the adapter supports it", func() {
+			sql, args, err := Values(rows, "id", "label").BuildSQL(postgresql.Adapter)
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `(SELECT * FROM (VALUES (?, ?), (?, ?), (?, ?)) AS godb_values ("id", "label"))`)
+			So(args, ShouldResemble, []interface{}{1, "one", 2, "two", 3, "three"})
+		})
+
+		Convey("The invalid lists are rejected", func() {
+			_, _, err := Values(rows).BuildSQL(sqlite.Adapter)
+			So(err, ShouldNotBeNil)
+			_, _, err = Values(nil, "id").BuildSQL(sqlite.Adapter)
+			So(err, ShouldNotBeNil)
+			_, _, err = Values(rows, "id").BuildSQL(sqlite.Adapter)
+			So(err, ShouldNotBeNil)
+			_, _, err = Values(rows, "id", "la bel").BuildSQL(sqlite.Adapter)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("The list is usable in a FROM clause", func() {
+			db := &DB{adapter: sqlite.Adapter}
+			sql, args, err := db.SelectFrom().
+				Columns("v.label").
+				FromExpr(Values(rows[:1], "id", "label"), "v").
+				Where("v.id > ?", 0).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT v.label FROM (SELECT ? AS "id", ? AS "label") AS v WHERE v.id > ?`)
+			So(args, ShouldResemble, []interface{}{1, "one", 0})
+		})
+	})
+
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("The records are joined with the rows", func() {
+			type labeledDummy struct {
+				Text  string `db:"a_text"`
+				Label string `db:"label"`
+			}
+			rows := [][]interface{}{{11, "eleven"}, {13, "thirteen"}, {99, "unknown"}}
+			labeled := make([]labeledDummy, 0)
+			err := db.SelectFrom("dummies").
+				Columns("dummies.a_text", "labels.label").
+				InnerJoinExpr(Values(rows, "an_integer", "label"), "labels", Q("labels.an_integer = dummies.an_integer")).
+				OrderBy("dummies.id").
+				Do(&labeled)
+			So(err, ShouldBeNil)
+			So(labeled, ShouldResemble, []labeledDummy{{"First", "eleven"}, {"Third", "thirteen"}})
+		})
+	})
+}
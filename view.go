@@ -0,0 +1,54 @@
+package godb
+
+// ReadOnlyModel marks a struct mapped to a view, or to any relation which is
+// not writable. Embed it in the struct : Insert, BulkInsert, Update and
+// Delete fail with a *ReadOnlyModelError without executing anything.
+//
+// Example :
+// 	type BookSummary struct {
+// 		godb.ReadOnlyModel
+// 		ID     int    `db:"id,key"`
+// 		Title  string `db:"title"`
+// 		Author string `db:"author"`
+// 	}
+type ReadOnlyModel struct{}
+
+func (ReadOnlyModel) readOnlyModel() {}
+
+// UpdatableView marks a struct mapped to a view written through INSTEAD OF
+// triggers. Embed it in the struct : the statements are executed as for a
+// table, but the databases don't give reliably the values set by the
+// triggers, then the auto columns are not read back (no RETURNING or OUTPUT
+// clause, no LastInsertId), and ErrOpLock is not returned as the rows
+// changed by the triggers may not be counted.
+type UpdatableView struct{}
+
+func (UpdatableView) updatableView() {}
+
+// readOnlyModel is implemented by the structs embedding ReadOnlyModel.
+type readOnlyModel interface {
+	readOnlyModel()
+}
+
+// updatableView is implemented by the structs embedding UpdatableView.
+type updatableView interface {
+	updatableView()
+}
+
+// checkWritableModel returns a *ReadOnlyModelError if the record is marked as
+// read only.
+func (db *DB) checkWritableModel(recordDescription *recordDescription) error {
+	if _, ok := recordDescription.getOneInstancePointer().(readOnlyModel); !ok {
+		return nil
+	}
+	return &ReadOnlyModelError{
+		Struct: recordDescription.structMapping.Name,
+		Table:  db.defaultTableNamer(recordDescription.getTableName()),
+	}
+}
+
+// isUpdatableView returns true if the record is marked as an updatable view.
+func isUpdatableView(recordDescription *recordDescription) bool {
+	_, ok := recordDescription.getOneInstancePointer().(updatableView)
+	return ok
+}
@@ -0,0 +1,87 @@
+package godb
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type DummyView struct {
+	ReadOnlyModel
+	ID    int    `db:"id,key"`
+	AText string `db:"a_text"`
+}
+
+func (*DummyView) TableName() string {
+	return "dummiesview"
+}
+
+type UpdatableDummyView struct {
+	UpdatableView
+	ID        int    `db:"id,key,auto"`
+	AText     string `db:"a_text"`
+	AnInteger int    `db:"an_integer"`
+	Version   int    `db:"version,oplock"`
+}
+
+func (*UpdatableDummyView) TableName() string {
+	return "dummiesview"
+}
+
+func TestViews(t *testing.T) {
+	Convey("Given a test database with a view", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		_, err := db.CurrentDB().Exec(`
+			create view dummiesview as select id, a_text, an_integer, version from dummies;
+			create trigger dummiesview_insert instead of insert on dummiesview
+			begin
+				insert into dummies (a_text, another_text, an_integer)
+				values (NEW.a_text, "", NEW.an_integer);
+			end;
+			create trigger dummiesview_update instead of update on dummiesview
+			begin
+				update dummies set a_text = NEW.a_text, an_integer = NEW.an_integer
+				where id = OLD.id;
+			end;
+		`)
+		So(err, ShouldBeNil)
+
+		Convey("A read only model can be selected", func() {
+			views := make([]DummyView, 0)
+			So(db.Select(&views).OrderBy("id").Do(), ShouldBeNil)
+			So(len(views), ShouldEqual, 3)
+			So(views[0].AText, ShouldEqual, "First")
+		})
+
+		Convey("A read only model can't be written", func() {
+			view := DummyView{ID: 1, AText: "Changed"}
+			err := db.Insert(&view).Do()
+			So(errors.Is(err, ErrReadOnlyModel), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, "dummiesview")
+			So(errors.Is(db.BulkInsert(&[]DummyView{view}).Do(), ErrReadOnlyModel), ShouldBeTrue)
+			So(errors.Is(db.Update(&view).Do(), ErrReadOnlyModel), ShouldBeTrue)
+			_, err = db.Delete(&view).Do()
+			So(errors.Is(err, ErrReadOnlyModel), ShouldBeTrue)
+			_, err = db.Sync(&[]DummyView{view}, nil)
+			So(errors.Is(err, ErrReadOnlyModel), ShouldBeTrue)
+		})
+
+		Convey("An updatable view is written through its triggers", func() {
+			view := UpdatableDummyView{AText: "Fourth", AnInteger: 14}
+			So(db.Insert(&view).Do(), ShouldBeNil)
+			So(view.ID, ShouldEqual, 0)
+
+			So(db.Get(&view, 2), ShouldBeNil)
+			view.AnInteger = 42
+			So(db.Update(&view).Do(), ShouldBeNil)
+
+			dummies := make([]Dummy, 0)
+			So(db.Select(&dummies).OrderBy("id").Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 4)
+			So(dummies[1].AnInteger, ShouldEqual, 42)
+			So(dummies[3].AText, ShouldEqual, "Fourth")
+		})
+	})
+}
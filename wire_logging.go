@@ -0,0 +1,170 @@
+package godb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// WireObserver is called for each statement executed by the connections of
+// a DB using UseWireLogging, with its arguments, its duration and its error.
+type WireObserver func(query string, arguments []interface{}, duration time.Duration, err error)
+
+// UseWireLogging wraps the connections of the DB with a driver logging every
+// statement they execute, including the ones not built by godb (through
+// CurrentDB for example), with the logger of the DB. The optional observer
+// is also called for each statement, to feed metrics. The statements built
+// by godb are then logged twice, the wire logs having the WIRE prefix.
+//
+// The DB has to be created by Open, OpenWithFailover, OpenConnector or
+// OpenWithDataSourceProvider. Its sql.DB is replaced, call UseWireLogging
+// before SetSessionSetup, before configuring the connections pool, and
+// before cloning the DB.
+//
+// Example :
+// 	err := db.UseWireLogging(func(query string, arguments []interface{}, duration time.Duration, err error) {
+// 		statementDurations.Observe(duration.Seconds())
+// 	})
+func (db *DB) UseWireLogging(observer WireObserver) error {
+	if db.sqlTx != nil {
+		return fmt.Errorf("UseWireLogging can't be called in a transaction")
+	}
+	connector, err := db.baseConnector("UseWireLogging")
+	if err != nil {
+		return err
+	}
+
+	connector = &wireConnector{connector: connector, observe: func(query string, arguments []interface{}, duration time.Duration, err error) {
+		if err != nil {
+			db.logExecutionErr(err, "WIRE", query, arguments)
+		} else {
+			db.logExecution(duration, "WIRE", query, arguments)
+		}
+		if observer != nil {
+			observer(query, arguments, duration, err)
+		}
+	}}
+	db.connector = connector
+	return db.reopen(connector)
+}
+
+// wireConnector is a driver.Connector wrapping the connections with a
+// wireConn.
+type wireConnector struct {
+	connector driver.Connector
+	observe   WireObserver
+}
+
+// Connect opens a connection. It implements driver.Connector.
+func (c *wireConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &wireConn{sessionDriverConn: &sessionDriverConn{Conn: conn}, observe: c.observe}, nil
+}
+
+// Driver returns the underlying driver. It implements driver.Connector.
+func (c *wireConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// wireConn is a driver.Conn observing the statements it executes. The
+// optional interfaces are forwarded by sessionDriverConn.
+type wireConn struct {
+	*sessionDriverConn
+	observe WireObserver
+}
+
+// ExecContext implements driver.ExecerContext.
+func (c *wireConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	startTime := time.Now()
+	result, err := c.sessionDriverConn.ExecContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		c.observe(query, namedValuesArguments(args), time.Since(startTime), err)
+	}
+	return result, err
+}
+
+// QueryContext implements driver.QueryerContext.
+func (c *wireConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	startTime := time.Now()
+	rows, err := c.sessionDriverConn.QueryContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		c.observe(query, namedValuesArguments(args), time.Since(startTime), err)
+	}
+	return rows, err
+}
+
+// PrepareContext implements driver.ConnPrepareContext, the executions of the
+// prepared statement are observed.
+func (c *wireConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.sessionDriverConn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &wireStmt{Stmt: stmt, query: query, observe: c.observe}, nil
+}
+
+// wireStmt is a driver.Stmt observing its executions.
+type wireStmt struct {
+	driver.Stmt
+	query   string
+	observe WireObserver
+}
+
+// ExecContext implements driver.StmtExecContext.
+func (s *wireStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	startTime := time.Now()
+	var result driver.Result
+	var err error
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = execer.ExecContext(ctx, args)
+	} else {
+		result, err = s.Stmt.Exec(namedValuesValues(args))
+	}
+	s.observe(s.query, namedValuesArguments(args), time.Since(startTime), err)
+	return result, err
+}
+
+// QueryContext implements driver.StmtQueryContext.
+func (s *wireStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	startTime := time.Now()
+	var rows driver.Rows
+	var err error
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		rows, err = s.Stmt.Query(namedValuesValues(args))
+	}
+	s.observe(s.query, namedValuesArguments(args), time.Since(startTime), err)
+	return rows, err
+}
+
+// CheckNamedValue implements driver.NamedValueChecker.
+func (s *wireStmt) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return driver.ErrSkip
+}
+
+// namedValuesValues returns the values of the given arguments.
+func namedValuesValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, 0, len(args))
+	for _, arg := range args {
+		values = append(values, arg.Value)
+	}
+	return values
+}
+
+// namedValuesArguments returns the values of the given arguments, as
+// arguments of a statement.
+func namedValuesArguments(args []driver.NamedValue) []interface{} {
+	arguments := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		arguments = append(arguments, arg.Value)
+	}
+	return arguments
+}
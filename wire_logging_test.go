@@ -0,0 +1,81 @@
+package godb
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// wireRecorder records the observed statements.
+type wireRecorder struct {
+	mutex      sync.Mutex
+	statements []string
+	logs       []string
+}
+
+func (r *wireRecorder) observe(query string, arguments []interface{}, duration time.Duration, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.statements = append(r.statements, fmt.Sprint(query, arguments))
+}
+
+func (r *wireRecorder) Println(v ...interface{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.logs = append(r.logs, fmt.Sprint(v...))
+}
+
+func TestUseWireLogging(t *testing.T) {
+	Convey("Given a DB logging the statements at the driver level", t, func() {
+		db, err := Open(sqlite.Adapter, ":memory:")
+		So(err, ShouldBeNil)
+		defer db.Close()
+		recorder := &wireRecorder{}
+		db.SetLogger(recorder)
+		So(db.UseWireLogging(recorder.observe), ShouldBeNil)
+		db.CurrentDB().SetMaxOpenConns(1)
+
+		Convey("The statements executed through CurrentDB are observed", func() {
+			_, err := db.CurrentDB().Exec("create table wires (id integer, label text)")
+			So(err, ShouldBeNil)
+			_, err = db.CurrentDB().Exec("insert into wires values (?, ?)", 1, "first")
+			So(err, ShouldBeNil)
+			So(recorder.statements, ShouldContain, "create table wires (id integer, label text)[]")
+			So(recorder.statements, ShouldContain, "insert into wires values (?, ?)[1 first]")
+
+			Convey("The statements executed by godb are observed", func() {
+				count, err := db.SelectFrom("wires").Where("id = ?", 1).Count()
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 1)
+				So(recorder.statements[len(recorder.statements)-1], ShouldEqual, "SELECT COUNT(*) FROM wires WHERE id = ?[1]")
+			})
+
+			Convey("The statements are logged with the WIRE prefix", func() {
+				wireLogs := 0
+				for _, log := range recorder.logs {
+					if strings.Contains(log, "[WIRE insert into wires") {
+						wireLogs++
+					}
+				}
+				So(wireLogs, ShouldEqual, 1)
+			})
+		})
+
+		Convey("The errors are observed", func() {
+			_, err := db.CurrentDB().Query("select * from missing")
+			So(err, ShouldNotBeNil)
+			So(recorder.statements, ShouldContain, "select * from missing[]")
+			So(recorder.logs[len(recorder.logs)-1], ShouldContainSubstring, "ERROR")
+		})
+	})
+
+	Convey("UseWireLogging needs a DB created by Open", t, func() {
+		db := Wrap(sqlite.Adapter, nil)
+		So(db.UseWireLogging(nil), ShouldNotBeNil)
+	})
+}